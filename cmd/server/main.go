@@ -1,17 +1,22 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/ClareAI/astra-voice-service/internal/config"
 	"github.com/ClareAI/astra-voice-service/internal/handler"
 	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/tracing"
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
 	"go.uber.org/zap"
@@ -22,6 +27,7 @@ type Server struct {
 	config         *config.WhatsAppCallConfig
 	router         *mux.Router
 	handlerManager *handler.HandlerManager
+	httpServer     *http.Server
 }
 
 // NewServer creates a new WhatsApp Call Gateway server
@@ -58,7 +64,7 @@ func NewServer(cfg *config.WhatsAppCallConfig) *Server {
 func (s *Server) Start() error {
 	addr := fmt.Sprintf(":%s", s.config.Port)
 
-	server := &http.Server{
+	s.httpServer = &http.Server{
 		Addr:         addr,
 		Handler:      s.router,
 		ReadTimeout:  15 * time.Second,
@@ -67,7 +73,18 @@ func (s *Server) Start() error {
 	}
 
 	logger.Base().Info("Starting server", zap.String("addr", addr))
-	return server.ListenAndServe()
+	return s.httpServer.ListenAndServe()
+}
+
+// Shutdown stops accepting requests, waits for in-flight ones until ctx is done, then releases
+// the handlers' long-lived resources
+func (s *Server) Shutdown(ctx context.Context) error {
+	var err error
+	if s.httpServer != nil {
+		err = s.httpServer.Shutdown(ctx)
+	}
+	s.handlerManager.Close()
+	return err
 }
 
 // LoadConfigFromEnv loads WhatsApp Call Gateway configuration from environment
@@ -87,6 +104,9 @@ func LoadConfigFromEnv() *config.WhatsAppCallConfig {
 		GeminiBaseURL: getEnvOrDefault("GEMINI_BASE_URL", "https://generativelanguage.googleapis.com"),
 		GeminiModel:   getEnvOrDefault("GEMINI_MODEL", "models/gemini-3-flash"),
 
+		// Text-mode agent sessions
+		TextModel: getEnvOrDefault("TEXT_MODEL", "gpt-4o"),
+
 		// WebRTC configuration - default STUN servers
 		STUNServers: []string{
 			"stun:stun.l.google.com:19302",
@@ -118,6 +138,14 @@ func LoadConfigFromEnv() *config.WhatsAppCallConfig {
 		AudioStorageEnabled: getEnvAsBoolOrDefault("AUDIO_STORAGE_ENABLED", false),
 		AudioStorageType:    getEnvOrDefault("AUDIO_STORAGE_TYPE", "gcs"),
 		AudioStoragePath:    getEnvOrDefault("AUDIO_STORAGE_PATH", ""),
+		AudioSpoolPath:      getEnvOrDefault("AUDIO_SPOOL_PATH", "/var/spool/recordings"),
+
+		// S3-compatible object storage configuration
+		S3Endpoint:        getEnvOrDefault("S3_ENDPOINT", ""),
+		S3Region:          getEnvOrDefault("S3_REGION", "us-east-1"),
+		S3AccessKeyID:     getEnvOrDefault("S3_ACCESS_KEY_ID", os.Getenv("AWS_ACCESS_KEY_ID")),
+		S3SecretAccessKey: getEnvOrDefault("S3_SECRET_ACCESS_KEY", os.Getenv("AWS_SECRET_ACCESS_KEY")),
+		S3ForcePathStyle:  getEnvAsBoolOrDefault("S3_FORCE_PATH_STYLE", false),
 
 		// LiveKit configuration (NEW)
 		LiveKitEnabled:   getEnvAsBoolOrDefault("LIVEKIT_ENABLED", false),
@@ -125,6 +153,42 @@ func LoadConfigFromEnv() *config.WhatsAppCallConfig {
 		LiveKitAPIKey:    getEnvOrDefault("LIVEKIT_API_KEY", ""),
 		LiveKitAPISecret: getEnvOrDefault("LIVEKIT_API_SECRET", ""),
 		LiveKitGCSBucket: getEnvOrDefault("LIVEKIT_GCS_BUCKET", ""), // GCS bucket for egress (GKE auto-configured)
+
+		LiveKitRecordingMode: getEnvOrDefault("LIVEKIT_RECORDING_MODE", "in_process"),
+
+		// SIP trunk configuration
+		SIPEnabled:       getEnvAsBoolOrDefault("SIP_ENABLED", false),
+		SIPListenAddr:    getEnvOrDefault("SIP_LISTEN_ADDR", ":5060"),
+		SIPTransport:     getEnvOrDefault("SIP_TRANSPORT", "both"),
+		SIPPublicHost:    getEnvOrDefault("SIP_PUBLIC_HOST", ""),
+		SIPRTPPortMin:    getEnvAsIntOrDefault("SIP_RTP_PORT_MIN", 10000),
+		SIPRTPPortMax:    getEnvAsIntOrDefault("SIP_RTP_PORT_MAX", 20000),
+		SIPRegistrar:     getEnvOrDefault("SIP_REGISTRAR", ""),
+		SIPUsername:      getEnvOrDefault("SIP_USERNAME", ""),
+		SIPPassword:      getEnvOrDefault("SIP_PASSWORD", ""),
+		SIPDomain:        getEnvOrDefault("SIP_DOMAIN", ""),
+		SIPAgentID:       getEnvOrDefault("SIP_AGENT_ID", ""),
+		SIPTenantID:      getEnvOrDefault("SIP_TENANT_ID", ""),
+		SIPVoiceLanguage: getEnvOrDefault("SIP_VOICE_LANGUAGE", ""),
+		SIPRoutes:        getEnvOrDefault("SIP_ROUTES", ""),
+		SIPCodecs:        getEnvOrDefault("SIP_CODECS", ""),
+		SIPTrustedCIDRs:  getEnvOrDefault("SIP_TRUSTED_CIDRS", ""),
+
+		// Twilio Media Streams channel
+		TwilioMediaStreamsEnabled: getEnvAsBoolOrDefault("TWILIO_MEDIA_STREAMS_ENABLED", false),
+		TwilioPublicURL:           getEnvOrDefault("TWILIO_PUBLIC_URL", ""),
+		TwilioAgentID:             getEnvOrDefault("TWILIO_AGENT_ID", ""),
+		TwilioTenantID:            getEnvOrDefault("TWILIO_TENANT_ID", ""),
+		TwilioVoiceLanguage:       getEnvOrDefault("TWILIO_VOICE_LANGUAGE", ""),
+		TwilioRoutes:              getEnvOrDefault("TWILIO_ROUTES", ""),
+
+		// Outbound campaigns and call backs
+		CampaignWorkerEnabled: getEnvAsBoolOrDefault("CAMPAIGN_WORKER_ENABLED", true),
+		CallbackWorkerEnabled: getEnvAsBoolOrDefault("CALLBACK_WORKER_ENABLED", true),
+
+		// Tracing configuration
+		TracingExporter:    getEnvOrDefault("TRACING_EXPORTER", tracing.ExporterNone),
+		TracingSampleRatio: getEnvAsFloatOrDefault("TRACING_SAMPLE_RATIO", 1.0),
 	}
 
 	// Load custom STUN servers from environment if provided
@@ -154,6 +218,16 @@ func getEnvAsIntOrDefault(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvAsFloatOrDefault gets environment variable as float or returns default
+func getEnvAsFloatOrDefault(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 // getEnvAsBoolOrDefault gets environment variable as bool or returns default
 func getEnvAsBoolOrDefault(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
@@ -209,6 +283,17 @@ func main() {
 	cfg := LoadConfigFromEnv()
 	fmt.Printf("🚀 Starting Astra Voice Service (Instance: %s)\n", cfg.InstanceID)
 
+	// 1.5 Initialize tracing (no-op unless TRACING_EXPORTER is set)
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Config{
+		Exporter:    cfg.TracingExporter,
+		ServiceName: getEnvOrDefault("OTEL_SERVICE_NAME", "astra-voice-service"),
+		InstanceID:  cfg.InstanceID,
+		SampleRatio: cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		log.Printf("Warning: tracing disabled: %v", err)
+	}
+
 	// 2. Create the server
 	server := NewServer(cfg)
 	if server == nil {
@@ -218,8 +303,30 @@ func main() {
 		zap.String("port", cfg.Port),
 		zap.String("instance_id", cfg.InstanceID))
 
-	// 3. Start the server
-	if err := server.Start(); err != nil {
+	// 3. Start the server and stop it gracefully on SIGINT/SIGTERM
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.Start()
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err := <-serverErr:
+		// Flush pending spans before exiting
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		shutdownTracing(ctx)
+		cancel()
 		log.Fatalf("❌ Server failed to start: %v", err)
+	case sig := <-signals:
+		logger.Base().Info("Shutting down", zap.String("signal", sig.String()))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Base().Warn("Server shutdown incomplete", zap.Error(err))
 	}
+	shutdownTracing(ctx)
 }
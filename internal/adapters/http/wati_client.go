@@ -2,6 +2,7 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,6 +12,8 @@ import (
 	"time"
 
 	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/metrics"
+	"github.com/ClareAI/astra-voice-service/pkg/tracing"
 	"github.com/golang-jwt/jwt/v4"
 	"go.uber.org/zap"
 )
@@ -121,7 +124,8 @@ func NewWatiClient(baseURL, tenantID, apiKey, outboundBaseURL string) *WatiClien
 		APIKey:          apiKey,
 		OutboundBaseURL: outboundBaseURL,
 		HTTPClient: &http.Client{
-			Timeout: 60 * time.Second,
+			Timeout:   60 * time.Second,
+			Transport: tracing.NewTransport(nil),
 		},
 	}
 
@@ -144,11 +148,12 @@ func (c *WatiClient) GetOutboundBaseURL() string {
 
 // AcceptCall accepts a WhatsApp call through Wati API
 func (c *WatiClient) AcceptCall(callID string, sdpAnswer string) error {
-	return c.AcceptCallWithTenant(c.TenantID, callID, sdpAnswer)
+	return c.AcceptCallWithTenant(context.Background(), c.TenantID, callID, sdpAnswer)
 }
 
 // AcceptCallWithTenant accepts a WhatsApp call with specific tenant ID
-func (c *WatiClient) AcceptCallWithTenant(tenantID, callID string, sdpAnswer string) error {
+func (c *WatiClient) AcceptCallWithTenant(ctx context.Context, tenantID, callID string, sdpAnswer string) (err error) {
+	defer metrics.ObserveWatiRequest("accept_call", time.Now(), &err)
 	// Try different API endpoint formats
 	// Original format
 	url := fmt.Sprintf("%s/%s/api/v1/openapi/whatsapp/calls/%s/accept",
@@ -170,7 +175,7 @@ func (c *WatiClient) AcceptCallWithTenant(tenantID, callID string, sdpAnswer str
 	logger.Base().Info("Accepting call via Wati API")
 	logger.Base().Info("SDP Answer length", zap.Int("bytes", len(sdpAnswer)))
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %v", err)
 	}
@@ -217,17 +222,18 @@ func (c *WatiClient) AcceptCallWithTenant(tenantID, callID string, sdpAnswer str
 
 // TerminateCall terminates a WhatsApp call through Wati API
 func (c *WatiClient) TerminateCall(callID string) error {
-	return c.TerminateCallWithTenant(c.TenantID, callID)
+	return c.TerminateCallWithTenant(context.Background(), c.TenantID, callID)
 }
 
 // TerminateCallWithTenant terminates a WhatsApp call with specific tenant ID
-func (c *WatiClient) TerminateCallWithTenant(tenantID, callID string) error {
+func (c *WatiClient) TerminateCallWithTenant(ctx context.Context, tenantID, callID string) (err error) {
+	defer metrics.ObserveWatiRequest("terminate_call", time.Now(), &err)
 	url := fmt.Sprintf("%s/%s/api/v1/openapi/whatsapp/calls/%s/terminate",
 		c.BaseURL, tenantID, callID)
 
 	logger.Base().Info("Terminating call via Wati API", zap.String("url", url), zap.String("call_id", callID))
 
-	req, err := http.NewRequest("POST", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %v", err)
 	}
@@ -255,7 +261,8 @@ func (c *WatiClient) TerminateCallWithTenant(tenantID, callID string) error {
 
 // GetCallPermissions gets call permissions for a WhatsApp user from external Wati API
 // tenantID is required - it will be used in the URL path for multi-tenant scenarios
-func (c *WatiClient) GetCallPermissions(waid string, channelPhoneNumber string, tenantID string) (map[string]interface{}, error) {
+func (c *WatiClient) GetCallPermissions(waid string, channelPhoneNumber string, tenantID string) (_ map[string]interface{}, err error) {
+	defer metrics.ObserveWatiRequest("get_call_permissions", time.Now(), &err)
 	if tenantID == "" {
 		return nil, fmt.Errorf("tenant ID is required for outbound call API")
 	}
@@ -314,7 +321,8 @@ type MakeOutboundCallResponse struct {
 
 // MakeOutboundCall makes an outbound call to a WhatsApp user via external Wati API
 // tenantID is required - it will be used in the URL path for multi-tenant scenarios
-func (c *WatiClient) MakeOutboundCall(waid string, sdp string, channelPhoneNumber string, xPartner string, tenantID string) (*MakeOutboundCallResponse, error) {
+func (c *WatiClient) MakeOutboundCall(ctx context.Context, waid string, sdp string, channelPhoneNumber string, xPartner string, tenantID string) (_ *MakeOutboundCallResponse, err error) {
+	defer metrics.ObserveWatiRequest("make_outbound_call", time.Now(), &err)
 	if tenantID == "" {
 		return nil, fmt.Errorf("tenant ID is required for outbound call API")
 	}
@@ -339,7 +347,7 @@ func (c *WatiClient) MakeOutboundCall(waid string, sdp string, channelPhoneNumbe
 		return nil, fmt.Errorf("failed to marshal request: %v", err)
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
@@ -377,7 +385,8 @@ func (c *WatiClient) MakeOutboundCall(waid string, sdp string, channelPhoneNumbe
 
 // SendCallPermissionRequest sends a call permission request to a WhatsApp user via external Wati API
 // tenantID is required - it will be used in the URL path for multi-tenant scenarios
-func (c *WatiClient) SendCallPermissionRequest(waid string, channelPhoneNumber string, tenantID string) error {
+func (c *WatiClient) SendCallPermissionRequest(waid string, channelPhoneNumber string, tenantID string) (err error) {
+	defer metrics.ObserveWatiRequest("send_call_permission_request", time.Now(), &err)
 	if tenantID == "" {
 		return fmt.Errorf("tenant ID is required for outbound call API")
 	}
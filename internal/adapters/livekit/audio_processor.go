@@ -5,7 +5,10 @@ import (
 	"time"
 
 	"github.com/ClareAI/astra-voice-service/internal/services/call"
+	"github.com/ClareAI/astra-voice-service/internal/storage"
+	"github.com/ClareAI/astra-voice-service/pkg/jitter"
 	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/metrics"
 	"github.com/pion/webrtc/v3"
 	"go.uber.org/zap"
 	"layeh.com/gopus"
@@ -25,7 +28,20 @@ func NewAudioProcessor() (*AudioProcessor, error) {
 func (ap *AudioProcessor) ForwardLiveKitAudioToAI(connectionID string, track *webrtc.TrackRemote, connection *call.WhatsAppCallConnection) {
 	logger.Base().Info("🎧 Starting audio processing: LiveKit → AI (connection: )", zap.String("connection_id", connectionID))
 
+	// Record the participant on the left channel, like WhatsApp calls
+	audioCache := storage.GetAudioCache()
+	needsCaching := audioCache != nil && connection.NeedsAudioCaching()
+
+	jitterBuffer := jitter.NewBuffer(jitter.DefaultConfig())
+	jitterReporter := jitter.NewReporter(connection.GetChannelTypeString())
+
 	defer func() {
+		if needsCaching {
+			audioCache.CleanupConnection(connectionID)
+		}
+		stats := jitterBuffer.Stats()
+		jitterReporter.Report(stats)
+		logger.Base().Info("LiveKit inbound RTP stats", zap.String("connection_id", connectionID), zap.Int64("received", stats.Received), zap.Int64("lost", stats.Lost), zap.Int64("late", stats.Late), zap.Int64("fec_recovered", stats.Recovered), zap.Int64("concealed", stats.Concealed), zap.Float64("loss_rate", stats.LossRate()), zap.Duration("jitter", stats.Jitter))
 		logger.Base().Info("🛑 Audio processing stopped for", zap.String("connection_id", connectionID))
 	}()
 
@@ -34,6 +50,12 @@ func (ap *AudioProcessor) ForwardLiveKitAudioToAI(connectionID string, track *we
 	var dtxFrameCount int64
 	var consecutiveDTXCount int64 // 连续 DTX 帧计数
 
+	channelLabel := connection.GetChannelTypeString()
+	forwardedPackets := metrics.AudioPacketsTotal.WithLabelValues(channelLabel, metrics.DirectionInbound, metrics.AudioForwarded)
+	droppedPackets := metrics.AudioPacketsTotal.WithLabelValues(channelLabel, metrics.DirectionInbound, metrics.AudioDropped)
+	gatedPackets := metrics.AudioPacketsTotal.WithLabelValues(channelLabel, metrics.DirectionInbound, metrics.AudioGated)
+	dspDuration := metrics.AudioDSPFrameDuration.WithLabelValues(channelLabel)
+
 	logger.Base().Info("Audio forwarding ready for: (DTX frames will be handled)", zap.String("connection_id", connectionID))
 
 	for {
@@ -51,8 +73,7 @@ func (ap *AudioProcessor) ForwardLiveKitAudioToAI(connectionID string, track *we
 		}
 
 		// Extract Opus payload from RTP packet
-		opusPayload := rtpPacket.Payload
-		if len(opusPayload) == 0 {
+		if len(rtpPacket.Payload) == 0 {
 			continue
 		}
 
@@ -80,92 +101,139 @@ func (ap *AudioProcessor) ForwardLiveKitAudioToAI(connectionID string, track *we
 			connection.Mutex.Unlock()
 		}
 
-		var pcmSamples []int16
-		var decodeErr error
+		// Reorder through the jitter buffer; it releases frames in sequence order,
+		// with lost frames marked for FEC recovery or concealment
+		frames := jitterBuffer.Push(rtpPacket, time.Now())
+		if stats := jitterBuffer.Stats(); stats.Received%50 == 0 {
+			jitterReporter.Report(stats)
+		}
+
+		for _, frame := range frames {
+			var opusPayload []byte
+			if !frame.Lost() {
+				opusPayload = frame.Packet.Payload
+			}
+
+			var pcmSamples []int16
+			var decodeErr error
+			// thinned DTX frames still run through DSP and the local VAD but are not sent to the model
+			thinned := false
 
-		// 🔥 智能处理 DTX/静音帧（< 3 字节）
-		// ✅ 优化策略：稀疏发送 DTX 静音帧，减少对模型 VAD 的干扰
-		if len(opusPayload) < 3 {
-			dtxFrameCount++
-			consecutiveDTXCount++
+			// 🔥 智能处理 DTX/静音帧（< 3 字节）
+			// ✅ 优化策略：稀疏发送 DTX 静音帧，减少对模型 VAD 的干扰
+			if !frame.Lost() && len(opusPayload) < 3 {
+				dtxFrameCount++
+				consecutiveDTXCount++
 
-			// 🎯 关键优化：每 4 个连续 DTX 帧只发送 1 个
-			// 这样可以让模型 VAD 更快识别到连续静音，而不是断续的静音信号
-			if consecutiveDTXCount%4 == 1 {
-				// 只发送每组的第一个 DTX 帧
+				// 每个 DTX 帧都合成静音，本地 VAD 按帧计数的 hangover 才不会被拉长
 				pcmSamples = make([]int16, 960) // 20ms @ 48kHz，全零静音
+				// 🎯 关键优化：每 4 个连续 DTX 帧只发送 1 个给模型
+				// 这样可以让模型 VAD 更快识别到连续静音，而不是断续的静音信号
+				thinned = consecutiveDTXCount%4 != 1
 			} else {
-				// 跳过其他 DTX 帧，不发送给模型
-				continue
+				// 重置连续 DTX 计数（遇到正常音频帧）
+				consecutiveDTXCount = 0
+
+				// Decode normal Opus frames
+				// Use larger buffer to handle variable frame sizes (same as WhatsApp)
+				// Opus can use 20ms, 40ms, or 60ms frames - need larger buffer for safety
+				maxSamples := 1920 // 40ms at 48kHz mono (double buffer for safety)
+				// Lost frames are rebuilt from FEC in the next packet or concealed by the decoder
+				pcmSamples, decodeErr = jitterBuffer.Decode(decoder, frame, maxSamples)
+				if decodeErr != nil {
+					// Log decode failures more verbosely to debug latency issues
+					logger.Base().Error("Decode failed", zap.Uint16("sequence_number", frame.SequenceNumber), zap.Int("size_bytes", len(opusPayload)), zap.Bool("lost", frame.Lost()), zap.Error(decodeErr))
+					droppedPackets.Inc()
+					continue
+				}
+
+				// DTX and lost frames are left out; the recorder fills gaps with silence
+				if needsCaching && !frame.Lost() {
+					audioCache.CacheAudioRTP(connectionID, storage.AudioTypeWhatsAppInput, storage.AudioFormatOpus, frame.Packet)
+				}
 			}
-		} else {
-			// 重置连续 DTX 计数（遇到正常音频帧）
-			consecutiveDTXCount = 0
-
-			// Decode normal Opus frames
-			// Use larger buffer to handle variable frame sizes (same as WhatsApp)
-			// Opus can use 20ms, 40ms, or 60ms frames - need larger buffer for safety
-			maxSamples := 1920 // 40ms at 48kHz mono (double buffer for safety)
-			pcmSamples, decodeErr = decoder.Decode(opusPayload, maxSamples, false)
-			if decodeErr != nil {
-				// Log decode failures more verbosely to debug latency issues
-				logger.Base().Error("Decode failed", zap.Uint16("sequence_number", rtpPacket.SequenceNumber), zap.Int("size_bytes", len(opusPayload)), zap.Error(decodeErr))
-				continue
+
+			// Run the agent's DSP chain on every frame so its filters see continuous audio
+			if chain := connection.GetDSP(); chain != nil && len(pcmSamples) > 0 {
+				start := time.Now()
+				chain.Process(pcmSamples)
+				dspDuration.Observe(time.Since(start).Seconds())
 			}
-		}
 
-		// Send PCM16 samples to the model (fast path - highest priority)
-		if len(pcmSamples) > 0 && connection.AIWebRTC != nil {
-			// Check if we should forward audio to the model
-			// If greeting hasn't been sent/completed yet, and connection is very new,
-			// suppress user audio to prevent interrupting the greeting
-			shouldForward, reason := connection.ShouldForwardAudioToAI()
-
-			if !shouldForward {
-				suppressionCount++
-				// Log suppression occasionally (every 100 suppressed frames)
-				if suppressionCount%100 == 0 {
-					logger.Base().Info("🤫 Suppressing user audio during greeting phase ()", zap.String("reason", reason), zap.String("connection_id", connectionID))
+			// Send PCM16 samples to the model (fast path - highest priority)
+			if len(pcmSamples) > 0 && connection.AIWebRTC != nil {
+				// Check if we should forward audio to the model
+				// If greeting hasn't been sent/completed yet, and connection is very new,
+				// suppress user audio to prevent interrupting the greeting
+				shouldForward, reason := connection.ShouldForwardAudioToAI()
+
+				if !shouldForward {
+					suppressionCount++
+					droppedPackets.Inc()
+					// Log suppression occasionally (every 100 suppressed frames)
+					if suppressionCount%100 == 0 {
+						logger.Base().Info("🤫 Suppressing user audio during greeting phase ()", zap.String("reason", reason), zap.String("connection_id", connectionID))
+					}
+					// Still update activity
+					connection.Mutex.Lock()
+					connection.LastActivity = time.Now()
+					connection.Mutex.Unlock()
+					continue
 				}
-				// Still update activity
-				connection.Mutex.Lock()
-				connection.LastActivity = time.Now()
-				connection.Mutex.Unlock()
-				continue
-			}
 
-			// Send immediately to the model
-			if err := connection.AIWebRTC.SendAudio(pcmSamples); err == nil {
-				frameCount++
-
-				// 定期打印音频流状态和 DTX 统计（降低频率减少日志）
-				if frameCount%200 == 0 {
-					totalFrames := frameCount + dtxFrameCount
-					if dtxFrameCount > 0 {
-						dtxPercentage := float64(dtxFrameCount) / float64(totalFrames) * 100
-						// 计算实际发送的 DTX 静音帧数（每 4 个只发 1 个）
-						sentDTXFrames := (dtxFrameCount + 3) / 4
-						logger.Base().Info("Audio flowing with DTX", zap.Int64("sent_dtx_frames", sentDTXFrames), zap.Int64("detected_dtx_frames", dtxFrameCount), zap.Int64("frame_count", frameCount), zap.Float64("dtx_percentage", dtxPercentage))
+				// Gate through the local VAD if the agent enables it (DTX silence included)
+				toSend := [][]int16{pcmSamples}
+				if detector := connection.GetVAD(); detector != nil {
+					toSend = detector.Process(pcmSamples)
+					if len(toSend) == 0 {
+						gatedPackets.Inc()
+						connection.Mutex.Lock()
+						connection.LastActivity = time.Now()
+						connection.Mutex.Unlock()
+						continue
+					}
+				}
+				if thinned {
+					continue
+				}
+
+				// Send immediately to the model
+				for _, samples := range toSend {
+					if err := connection.AIWebRTC.SendAudio(samples); err == nil {
+						frameCount++
+						forwardedPackets.Inc()
+
+						// 定期打印音频流状态和 DTX 统计（降低频率减少日志）
+						if frameCount%200 == 0 {
+							totalFrames := frameCount + dtxFrameCount
+							if dtxFrameCount > 0 {
+								dtxPercentage := float64(dtxFrameCount) / float64(totalFrames) * 100
+								// 计算实际发送的 DTX 静音帧数（每 4 个只发 1 个）
+								sentDTXFrames := (dtxFrameCount + 3) / 4
+								logger.Base().Info("Audio flowing with DTX", zap.Int64("sent_dtx_frames", sentDTXFrames), zap.Int64("detected_dtx_frames", dtxFrameCount), zap.Int64("frame_count", frameCount), zap.Float64("dtx_percentage", dtxPercentage))
+							} else {
+								logger.Base().Info("Audio flowing", zap.Int64("frame_count", frameCount))
+							}
+							// 不重置 dtxFrameCount，累计统计
+						}
 					} else {
-						logger.Base().Info("Audio flowing", zap.Int64("frame_count", frameCount))
+						// Log send failures
+						droppedPackets.Inc()
+						logger.Base().Error("SendAudio failed")
 					}
-					// 不重置 dtxFrameCount，累计统计
 				}
-			} else {
-				// Log send failures
-				logger.Base().Error("SendAudio failed")
-			}
-		} else if len(pcmSamples) == 0 {
-			// Log zero-sample decodes (shouldn't happen often)
-			if rtpPacket.SequenceNumber%100 == 0 {
-				logger.Base().Warn("Opus decode returned 0 samples", zap.Int("bytes", len(opusPayload)))
+			} else if len(pcmSamples) == 0 {
+				// Log zero-sample decodes (shouldn't happen often)
+				if frame.SequenceNumber%100 == 0 {
+					logger.Base().Warn("Opus decode returned 0 samples", zap.Int("bytes", len(opusPayload)))
+				}
 			}
-		}
 
-		// Update connection activity (with lock protection to prevent concurrent map writes or data races)
-		connection.Mutex.Lock()
-		connection.LastActivity = time.Now()
-		connection.Mutex.Unlock()
+			// Update connection activity (with lock protection to prevent concurrent map writes or data races)
+			connection.Mutex.Lock()
+			connection.LastActivity = time.Now()
+			connection.Mutex.Unlock()
+		}
 	}
 }
 
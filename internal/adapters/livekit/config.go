@@ -4,9 +4,18 @@ import (
 	"errors"
 
 	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/objectstore"
 	"go.uber.org/zap"
 )
 
+// Recording modes for LiveKit calls
+const (
+	RecordingModeInProcess = "in_process" // Dual-channel recorder shared with WhatsApp calls
+	RecordingModeEgress    = "egress"     // LiveKit Egress room composite recording
+	RecordingModeBoth      = "both"
+	RecordingModeOff       = "off"
+)
+
 // LiveKitConfig holds LiveKit server configuration
 type LiveKitConfig struct {
 	ServerURL         string // LiveKit server WebSocket URL
@@ -15,6 +24,11 @@ type LiveKitConfig struct {
 	DefaultRoomPrefix string // Default room name prefix
 	Enabled           bool   // Whether LiveKit integration is enabled
 	GCSBucket         string // GCS bucket name for egress recordings (GKE auto-configured)
+
+	// Storage uploads egress recordings to an S3-compatible object store when GCSBucket is not set
+	Storage *objectstore.Config
+
+	RecordingMode string // One of the RecordingMode* constants
 }
 
 // NewLiveKitConfig creates a new LiveKit configuration with validation
@@ -36,6 +50,7 @@ func NewLiveKitConfig(serverURL, apiKey, apiSecret, gcsBucket string) (*LiveKitC
 		DefaultRoomPrefix: "astra-",
 		Enabled:           true,
 		GCSBucket:         gcsBucket,
+		RecordingMode:     RecordingModeInProcess,
 	}
 
 	logger.Base().Info("LiveKit configuration initialized", zap.String("serverurl", serverURL))
@@ -59,6 +74,27 @@ func (c *LiveKitConfig) Validate() error {
 	return nil
 }
 
+// SetRecordingMode sets how LiveKit calls are recorded, keeping the current mode for unknown values
+func (c *LiveKitConfig) SetRecordingMode(mode string) {
+	switch mode {
+	case RecordingModeInProcess, RecordingModeEgress, RecordingModeBoth, RecordingModeOff:
+		c.RecordingMode = mode
+	case "":
+	default:
+		logger.Base().Warn("Unknown LiveKit recording mode, keeping current", zap.String("mode", mode), zap.String("current", c.RecordingMode))
+	}
+}
+
+// RecordsInProcess returns whether calls are recorded by the in-process recorder
+func (c *LiveKitConfig) RecordsInProcess() bool {
+	return c.RecordingMode == RecordingModeInProcess || c.RecordingMode == RecordingModeBoth
+}
+
+// UsesEgress returns whether calls are recorded through LiveKit Egress
+func (c *LiveKitConfig) UsesEgress() bool {
+	return c.RecordingMode == RecordingModeEgress || c.RecordingMode == RecordingModeBoth
+}
+
 // IsEnabled returns whether LiveKit is enabled
 func (c *LiveKitConfig) IsEnabled() bool {
 	return c.Enabled && c.ServerURL != "" && c.APIKey != "" && c.APISecret != ""
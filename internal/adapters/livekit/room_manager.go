@@ -14,6 +14,7 @@ import (
 	"github.com/ClareAI/astra-voice-service/internal/core/model/provider"
 	"github.com/ClareAI/astra-voice-service/internal/services/call"
 	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/objectstore"
 	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/livekit"
 	lksdk "github.com/livekit/server-sdk-go"
@@ -198,6 +199,11 @@ func (rm *RoomManager) JoinRoomAsBot(connectionID, roomName string, triggerGreet
 	// Start forwarding model audio to LiveKit
 	go rm.forwardAIAudio(connectionID, room)
 
+	// Egress recording is optional; the in-process recorder is fed by the audio forwarders
+	if rm.config.UsesEgress() {
+		go rm.startRecordingForRoom(connectionID, roomName)
+	}
+
 	// JoinRoom mode (triggerGreetingImmediately = true) doesn't need manual trigger
 	// as AI will start conversation automatically after connection
 	if triggerGreetingImmediately {
@@ -293,6 +299,10 @@ func (rm *RoomManager) CleanupRoom(connectionID string) {
 
 	logger.Base().Info("Room finished", zap.String("room_name", room.RoomName), zap.String("connection_id", connectionID), zap.Float64("duration", duration))
 
+	if room.EgressID != "" {
+		go rm.stopEgress(room.EgressID, room.RoomName, connectionID)
+	}
+
 	if room.Room != nil {
 		room.Room.Disconnect()
 	}
@@ -472,34 +482,42 @@ func (rm *RoomManager) startRecordingForRoom(connectionID, roomName string) {
 	logger.Base().Info("Recording started", zap.String("egress_id", egressID), zap.String("room_name", roomName))
 }
 
-// StartEgress starts recording (calls LiveKit API)
-func (rm *RoomManager) StartEgress(connectionID, roomName string) (string, error) {
-	logger.Base().Info("🎬 Starting audio egress for room", zap.String("room_name", roomName))
-
-	// Read base64 encoded GCS credentials from environment variable
-	credentialsBase64 := os.Getenv("GOOGLE_STORAGE_LIVEKIT_CLOUD_ACCOUNT_JSON_BASE64")
-	if credentialsBase64 == "" {
-		logger.Base().Warn("GOOGLE_STORAGE_LIVEKIT_CLOUD_ACCOUNT_JSON_BASE64 not set, skipping GCS upload")
-		return "", fmt.Errorf("GCS credentials not configured")
+// egressMetadata returns the owner of a connection's egress recording
+func (rm *RoomManager) egressMetadata(connectionID string) objectstore.Metadata {
+	var meta objectstore.Metadata
+	if conn, ok := rm.service.GetConnection(connectionID).(*call.WhatsAppCallConnection); ok && conn != nil {
+		meta.TenantID = conn.GetTenantID()
+		meta.AgentID = conn.GetAgentID()
+		meta.ConversationID = conn.GetConversationID()
 	}
+	return meta
+}
 
-	// Base64 decode
-	credentialsJson, err := base64.StdEncoding.DecodeString(credentialsBase64)
-	if err != nil {
-		logger.Base().Error("Failed to decode base64 credentials")
-		return "", fmt.Errorf("failed to decode credentials: %w", err)
+// egressFileOutput builds the audio-only egress output, named like the other stored objects
+// ({prefix}/{tenant}/{conversation}/{connectionID}.ogg) and uploaded to GCS or S3
+func (rm *RoomManager) egressFileOutput(connectionID string) (*livekit.EncodedFileOutput, error) {
+	meta := rm.egressMetadata(connectionID)
+	fileOutput := &livekit.EncodedFileOutput{
+		FileType: livekit.EncodedFileType_OGG, // Output OGG format (Opus)
+		Filepath: objectstore.ObjectKey(strings.TrimSuffix(config.DefaultEgressPathPrefix, "/"), meta, connectionID+config.DefaultEgressExtension),
 	}
 
-	logger.Base().Info("GCS credentials loaded from environment variable")
+	switch {
+	case rm.config.GCSBucket != "":
+		// Read base64 encoded GCS credentials from environment variable
+		credentialsBase64 := os.Getenv("GOOGLE_STORAGE_LIVEKIT_CLOUD_ACCOUNT_JSON_BASE64")
+		if credentialsBase64 == "" {
+			logger.Base().Warn("GOOGLE_STORAGE_LIVEKIT_CLOUD_ACCOUNT_JSON_BASE64 not set, skipping GCS upload")
+			return nil, fmt.Errorf("GCS credentials not configured")
+		}
 
-	// Create egress request, audio only
-	fileOutput := &livekit.EncodedFileOutput{
-		FileType: livekit.EncodedFileType_OGG,                                                                        // Output OGG format (Opus)
-		Filepath: fmt.Sprintf("%s%s%s", config.DefaultEgressPathPrefix, connectionID, config.DefaultEgressExtension), // Use connectionID, consistent directory
-	}
+		credentialsJson, err := base64.StdEncoding.DecodeString(credentialsBase64)
+		if err != nil {
+			logger.Base().Error("Failed to decode base64 credentials")
+			return nil, fmt.Errorf("failed to decode credentials: %w", err)
+		}
 
-	// If GCS bucket is configured, upload to GCS
-	if rm.config.GCSBucket != "" {
+		// GCS egress uploads do not support custom object metadata
 		fileOutput.Output = &livekit.EncodedFileOutput_Gcp{
 			Gcp: &livekit.GCPUpload{
 				Bucket:      rm.config.GCSBucket,
@@ -507,6 +525,36 @@ func (rm *RoomManager) StartEgress(connectionID, roomName string) (string, error
 			},
 		}
 		logger.Base().Info("Egress will upload to GCS bucket", zap.String("gcs_bucket", rm.config.GCSBucket))
+
+	case rm.config.Storage != nil && rm.config.Storage.Backend == objectstore.BackendS3:
+		storage := rm.config.Storage
+		fileOutput.Output = &livekit.EncodedFileOutput_S3{
+			S3: &livekit.S3Upload{
+				AccessKey:      storage.AccessKeyID,
+				Secret:         storage.SecretKey,
+				Region:         storage.Region,
+				Endpoint:       storage.Endpoint,
+				Bucket:         storage.Bucket,
+				ForcePathStyle: storage.ForcePathStyle,
+				Metadata:       meta.Map(),
+			},
+		}
+		logger.Base().Info("Egress will upload to S3 bucket", zap.String("bucket", storage.Bucket))
+
+	default:
+		logger.Base().Info("Egress will write to the egress service's local storage", zap.String("filepath", fileOutput.Filepath))
+	}
+
+	return fileOutput, nil
+}
+
+// StartEgress starts recording (calls LiveKit API)
+func (rm *RoomManager) StartEgress(connectionID, roomName string) (string, error) {
+	logger.Base().Info("🎬 Starting audio egress for room", zap.String("room_name", roomName))
+
+	fileOutput, err := rm.egressFileOutput(connectionID)
+	if err != nil {
+		return "", err
 	}
 
 	req := &livekit.RoomCompositeEgressRequest{
@@ -0,0 +1,243 @@
+package sip
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/internal/storage"
+	"github.com/ClareAI/astra-voice-service/pkg/jitter"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/metrics"
+	"github.com/ClareAI/astra-voice-service/pkg/resample"
+	"github.com/pion/rtp"
+	"go.uber.org/zap"
+	"layeh.com/gopus"
+)
+
+const (
+	readTimeout   = time.Second
+	maxRTPPacket  = 1500
+	opusMaxSample = 1920 // 40ms at 48kHz, room for longer Opus frames
+)
+
+// callerDecoder turns the caller's RTP into 48kHz PCM frames for the model
+type callerDecoder struct {
+	codec    codec
+	opus     *gopus.Decoder
+	upsample *resample.Resampler
+	pending  []int16 // upsampled audio not yet making a full 20ms frame
+	lastSize int     // samples of the last G.711 packet, for concealing lost ones
+
+	// Recording re-encodes G.711 audio as Opus so the recorder sees one format
+	recorder *gopus.Encoder
+}
+
+func newCallerDecoder(c codec) (*callerDecoder, error) {
+	d := &callerDecoder{codec: c, lastSize: c.frameSamples()}
+	var err error
+	if c.name == CodecOpus {
+		d.opus, err = gopus.NewDecoder(modelSampleRate, 1)
+		return d, err
+	}
+	if d.upsample, err = resample.New(c.clockRate, modelSampleRate, resample.Fast); err != nil {
+		return nil, err
+	}
+	if d.recorder, err = gopus.NewEncoder(modelSampleRate, 1, gopus.Voip); err != nil {
+		return nil, fmt.Errorf("failed to create recording encoder: %w", err)
+	}
+	return d, nil
+}
+
+// decode returns the model-rate frames of a released jitter buffer frame
+func (d *callerDecoder) decode(buffer *jitter.Buffer, frame jitter.Frame) ([][]int16, error) {
+	if d.opus != nil {
+		pcm, err := buffer.Decode(d.opus, frame, opusMaxSample)
+		if err != nil {
+			return nil, err
+		}
+		return [][]int16{pcm}, nil
+	}
+
+	var samples []int16
+	if frame.Lost() {
+		// G.711 has no concealment of its own; a silent gap is least disruptive
+		samples = make([]int16, d.lastSize)
+	} else {
+		samples = decodeG711(d.codec.name, frame.Packet.Payload)
+		d.lastSize = len(samples)
+	}
+	d.pending = append(d.pending, d.upsample.Process(samples)...)
+
+	var frames [][]int16
+	for len(d.pending) >= modelFrame {
+		frames = append(frames, append([]int16(nil), d.pending[:modelFrame]...))
+		d.pending = d.pending[modelFrame:]
+	}
+	return frames, nil
+}
+
+// recordingPacket returns the Opus packet recorded for a caller frame. Opus packets are
+// recorded as received; G.711 audio is re-encoded on the 48kHz timeline the recorder expects.
+func (d *callerDecoder) recordingPacket(packet *rtp.Packet, pcm []int16) *rtp.Packet {
+	if d.opus != nil {
+		return packet
+	}
+	if len(pcm) != modelFrame {
+		return nil
+	}
+	payload, err := d.recorder.Encode(pcm, modelFrame, 4000)
+	if err != nil {
+		return nil
+	}
+	recorded := &rtp.Packet{Header: packet.Header, Payload: payload}
+	// Multiplication keeps wrapped 8kHz timestamps consistent modulo 2^32
+	recorded.Timestamp = packet.Timestamp * uint32(modelSampleRate/d.codec.clockRate)
+	return recorded
+}
+
+// forwardCallerAudio reads the caller's RTP until the call ends, forwarding audio to the
+// model and DTMF key presses to the conversation. It also notices the call ending on our
+// side (cleanup by the agent or inactivity) and hangs up the SIP dialog.
+func (c *Call) forwardCallerAudio() {
+	connection := c.connection
+	connectionID := connection.ID
+	channelLabel := connection.GetChannelTypeString()
+
+	negotiatedCodec, _ := c.media.negotiated()
+	decoder, err := newCallerDecoder(negotiatedCodec)
+	if err != nil {
+		logger.Base().Error("Failed to create SIP audio decoder", zap.String("connection_id", connectionID), zap.Error(err))
+		c.Hangup("decoder")
+		return
+	}
+
+	audioCache := storage.GetAudioCache()
+	needsCaching := audioCache != nil && connection.NeedsAudioCaching()
+
+	jitterBuffer := jitter.NewBuffer(jitter.Config{
+		ClockRate:    negotiatedCodec.clockRate,
+		FrameSamples: negotiatedCodec.frameSamples(),
+	})
+	jitterReporter := jitter.NewReporter(channelLabel)
+
+	forwardedPackets := metrics.AudioPacketsTotal.WithLabelValues(channelLabel, metrics.DirectionInbound, metrics.AudioForwarded)
+	droppedPackets := metrics.AudioPacketsTotal.WithLabelValues(channelLabel, metrics.DirectionInbound, metrics.AudioDropped)
+	gatedPackets := metrics.AudioPacketsTotal.WithLabelValues(channelLabel, metrics.DirectionInbound, metrics.AudioGated)
+	dspDuration := metrics.AudioDSPFrameDuration.WithLabelValues(channelLabel)
+
+	defer func() {
+		if needsCaching {
+			audioCache.CleanupConnection(connectionID)
+		}
+		stats := jitterBuffer.Stats()
+		jitterReporter.Report(stats)
+		logger.Base().Info("SIP inbound RTP stats",
+			zap.String("connection_id", connectionID),
+			zap.Int64("received", stats.Received),
+			zap.Int64("lost", stats.Lost),
+			zap.Int64("late", stats.Late),
+			zap.Float64("loss_rate", stats.LossRate()),
+			zap.Duration("jitter", stats.Jitter))
+	}()
+
+	buf := make([]byte, maxRTPPacket)
+	lastPacket := time.Now()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		default:
+		}
+		if connection.IsClosed() {
+			c.Hangup("connection closed")
+			return
+		}
+
+		packet, err := c.media.read(buf, readTimeout)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				if time.Since(lastPacket) > c.ua.config.RTPTimeout {
+					logger.Base().Warn("No RTP from SIP caller, hanging up",
+						zap.String("connection_id", connectionID),
+						zap.Duration("timeout", c.ua.config.RTPTimeout))
+					c.Hangup("rtp timeout")
+					return
+				}
+				continue
+			}
+			// Socket closed by the call ending
+			return
+		}
+		if packet == nil {
+			continue
+		}
+		lastPacket = time.Now()
+
+		if digit, ok := c.media.dtmfEvent(packet); ok {
+			connection.ForwardDTMF(digit)
+			continue
+		}
+		if packet.PayloadType != negotiatedCodec.payloadType || len(packet.Payload) == 0 {
+			continue
+		}
+
+		frames := jitterBuffer.Push(packet, lastPacket)
+		if stats := jitterBuffer.Stats(); stats.Received%50 == 0 {
+			jitterReporter.Report(stats)
+		}
+
+		for _, frame := range frames {
+			pcmFrames, err := decoder.decode(jitterBuffer, frame)
+			if err != nil {
+				logger.Base().Debug("SIP audio decode failed", zap.String("connection_id", connectionID), zap.Error(err))
+				droppedPackets.Inc()
+				continue
+			}
+
+			for _, pcm := range pcmFrames {
+				if needsCaching && !frame.Lost() {
+					if recorded := decoder.recordingPacket(frame.Packet, pcm); recorded != nil {
+						audioCache.CacheAudioRTP(connectionID, storage.AudioTypeWhatsAppInput, storage.AudioFormatOpus, recorded)
+					}
+				}
+
+				// Run the agent's DSP chain on every frame so its filters see continuous audio
+				if chain := connection.GetDSP(); chain != nil {
+					start := time.Now()
+					chain.Process(pcm)
+					dspDuration.Observe(time.Since(start).Seconds())
+				}
+
+				modelClient := connection.GetAIWebRTC()
+				if modelClient == nil {
+					// Model still connecting
+					continue
+				}
+				if shouldForward, _ := connection.ShouldForwardAudioToAI(); !shouldForward {
+					droppedPackets.Inc()
+					continue
+				}
+
+				toSend := [][]int16{pcm}
+				if detector := connection.GetVAD(); detector != nil {
+					if toSend = detector.Process(pcm); len(toSend) == 0 {
+						gatedPackets.Inc()
+						continue
+					}
+				}
+				for _, samples := range toSend {
+					if err := modelClient.SendAudio(samples); err != nil {
+						droppedPackets.Inc()
+						continue
+					}
+					forwardedPackets.Inc()
+				}
+			}
+			connection.UpdateLastActivity()
+		}
+	}
+}
@@ -0,0 +1,377 @@
+package sip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/internal/config"
+	"github.com/ClareAI/astra-voice-service/internal/domain"
+	"github.com/ClareAI/astra-voice-service/internal/services/agent"
+	"github.com/ClareAI/astra-voice-service/internal/services/call"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Call states
+const (
+	callAnswering = iota // INVITE received, answer being prepared
+	callAnswered         // 200 OK sent, waiting for ACK
+	callConfirmed        // ACK received
+	callEnded
+)
+
+// Call is a SIP dialog answered by an agent. Its connection is registered with the call
+// service like WhatsApp and LiveKit calls, so the model bridge, recording and cleanup
+// work unchanged.
+type Call struct {
+	ua     *UserAgent
+	callID string
+	invite *Message
+	peer   *peer // where in-dialog requests go (the carrier's proxy)
+
+	localTag     string
+	remoteFrom   string   // From header of the INVITE (the remote party, with its tag)
+	localTo      string   // To header of the INVITE with our tag
+	remoteTarget string   // Contact URI of the remote party
+	routeSet     []string // Record-Route of the INVITE
+
+	media      *mediaSession
+	connection *call.WhatsAppCallConnection
+	sdpSession uint64
+	sdpVersion uint64
+
+	state    int
+	response *Message // final response to the INVITE, retransmitted until ACK
+	cseq     uint32
+	mutex    sync.Mutex
+
+	acked   chan struct{}
+	done    chan struct{}
+	endOnce sync.Once
+}
+
+func newCall(ua *UserAgent, invite *Message, from *peer) *Call {
+	localTag := randomToken(6)
+	return &Call{
+		ua:           ua,
+		callID:       invite.Get("Call-ID"),
+		invite:       invite,
+		peer:         from,
+		localTag:     localTag,
+		remoteFrom:   invite.Get("From"),
+		localTo:      invite.Get("To") + ";tag=" + localTag,
+		remoteTarget: addressURI(invite.Get("Contact")),
+		routeSet:     invite.Values("Record-Route"),
+		sdpSession:   uint64(rand.Int63()),
+		acked:        make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+// answer negotiates media, creates the connection and accepts the call
+func (c *Call) answer() {
+	offer, err := negotiate(c.invite.Body, c.ua.config.Codecs)
+	if err != nil {
+		logger.Base().Warn("Rejecting SIP call with unusable SDP", zap.String("call_id", c.callID), zap.Error(err))
+		c.reject(488, "Not Acceptable Here")
+		return
+	}
+
+	media, err := newMediaSession(c.ua.config.RTPPortMin, c.ua.config.RTPPortMax)
+	if err == nil {
+		err = media.apply(offer)
+	}
+	if err != nil {
+		logger.Base().Error("Failed to open SIP call media", zap.String("call_id", c.callID), zap.Error(err))
+		if media != nil {
+			media.close()
+		}
+		c.reject(500, "Server Internal Error")
+		return
+	}
+	c.mutex.Lock()
+	if c.state != callAnswering {
+		// Cancelled while the media was opened; end has already run
+		c.mutex.Unlock()
+		media.close()
+		return
+	}
+	c.media = media
+	c.mutex.Unlock()
+
+	connection, err := c.newConnection()
+	if err != nil {
+		logger.Base().Warn("Rejecting SIP call", zap.String("call_id", c.callID), zap.Error(err))
+		c.reject(404, "Not Found")
+		return
+	}
+
+	resp := c.okResponse(c.invite, offer)
+	c.mutex.Lock()
+	if c.state != callAnswering {
+		// Cancelled while the answer was prepared
+		c.mutex.Unlock()
+		return
+	}
+	c.state = callAnswered
+	c.response = resp
+	c.connection = connection
+	c.mutex.Unlock()
+
+	c.ua.reply(c.peer, resp)
+	go c.retransmitUntilAck()
+
+	logger.Base().Info("SIP call answered",
+		zap.String("connection_id", connection.ID),
+		zap.String("call_id", c.callID),
+		zap.String("from", connection.From),
+		zap.String("to", connection.To),
+		zap.String("agent_id", connection.AgentID),
+		zap.String("codec", offer.codec.name),
+		zap.Bool("dtmf", offer.dtmfType >= 0),
+		zap.String("remote_rtp", offer.addr.String()))
+
+	// Same flow as other inbound calls: register, then bring up the model; the model
+	// bridge finds the RTP session as the output track and sends the greeting
+	c.ua.service.AddConnection(connection)
+	if err := connection.InitializeVoiceConversation(); err != nil {
+		logger.Base().Error("Failed to initialize voice conversation", zap.String("connection_id", connection.ID), zap.Error(err))
+		// Continue anyway, AddMessage will create it as fallback
+	}
+	go c.ua.service.InitializeAIConnection(connection)
+	go c.forwardCallerAudio()
+}
+
+// newConnection builds the call's connection for the agent serving the dialled number
+func (c *Call) newConnection() (*call.WhatsAppCallConnection, error) {
+	fromHeader := c.invite.Get("From")
+	caller := uriUser(addressURI(fromHeader))
+	dialled := uriUser(addressURI(c.invite.Get("To")))
+	if dialled == "" {
+		dialled = uriUser(c.invite.RequestURI)
+	}
+
+	agentID := c.ua.config.AgentFor(dialled)
+	var textAgentID string
+	if agentService, err := agent.GetAgentService(); err == nil {
+		agentConfig, err := agentService.GetAgentConfigWithChannelType(context.Background(), agentID, domain.ChannelTypeSIP)
+		if err != nil || agentConfig == nil {
+			return nil, fmt.Errorf("agent %s not found for %s: %w", agentID, dialled, err)
+		}
+		agentID = agentConfig.ID
+		textAgentID = agentConfig.TextAgentID
+	}
+
+	language := c.ua.config.VoiceLanguage
+	if language == "" {
+		language = config.DefaultLanguage
+	}
+
+	now := time.Now()
+	return &call.WhatsAppCallConnection{
+		ID:              fmt.Sprintf("sip-%d", now.UnixNano()),
+		CallID:          c.callID,
+		From:            caller,
+		To:              dialled,
+		CreatedAt:       now,
+		LastActivity:    now,
+		IsActive:        true,
+		ChannelType:     domain.ChannelTypeSIP,
+		RemoteSDP:       string(c.invite.Body),
+		HasInboundAudio: true,
+		VoiceLanguage:   language,
+		ContactName:     displayName(fromHeader),
+		AgentID:         agentID,
+		TextAgentID:     textAgentID,
+		TenantID:        c.ua.config.TenantID,
+		BusinessNumber:  dialled,
+		RepoManager:     c.ua.repoManager,
+		WAOutputTrack:   c.media,
+	}, nil
+}
+
+// okResponse builds the 200 OK with our SDP answer for an INVITE or re-INVITE
+func (c *Call) okResponse(invite *Message, offer *mediaOffer) *Message {
+	c.mutex.Lock()
+	version := c.sdpVersion
+	c.sdpVersion++
+	c.mutex.Unlock()
+
+	resp := NewResponse(invite, 200, "OK", c.localTag)
+	resp.Add("Contact", c.ua.contact("", c.peer.transport))
+	resp.Add("Allow", allowedMethods)
+	resp.Add("User-Agent", userAgentHeader)
+	resp.Add("Content-Type", "application/sdp")
+	resp.Body = buildAnswer(c.ua.host, c.media.port(), c.sdpSession, version, offer)
+	return resp
+}
+
+// reject answers the INVITE with an error and forgets the call
+func (c *Call) reject(code int, reason string) {
+	c.mutex.Lock()
+	if c.state != callAnswering {
+		c.mutex.Unlock()
+		return
+	}
+	c.state = callEnded
+	c.mutex.Unlock()
+
+	c.ua.reply(c.peer, NewResponse(c.invite, code, reason, c.localTag))
+	c.end()
+}
+
+// cancel handles a CANCEL of an unanswered INVITE
+func (c *Call) cancel() {
+	logger.Base().Info("SIP call cancelled by caller", zap.String("call_id", c.callID))
+	c.reject(487, "Request Terminated")
+}
+
+// retransmitAnswer resends the final response when the INVITE is retransmitted
+func (c *Call) retransmitAnswer(from *peer) {
+	c.mutex.Lock()
+	resp := c.response
+	c.mutex.Unlock()
+	if resp != nil {
+		c.ua.reply(from, resp)
+	}
+}
+
+// retransmitUntilAck repeats the 200 OK over UDP until the ACK arrives; without an
+// ACK the call is given up (RFC 3261 §13.3.1.4)
+func (c *Call) retransmitUntilAck() {
+	interval := timerT1
+	deadline := time.After(timerB)
+	for {
+		select {
+		case <-c.acked:
+			return
+		case <-c.done:
+			return
+		case <-deadline:
+			logger.Base().Warn("No ACK for SIP call answer, hanging up", zap.String("call_id", c.callID))
+			c.Hangup("no ack")
+			return
+		case <-time.After(interval):
+			if c.peer.transport == TransportUDP {
+				c.retransmitAnswer(c.peer)
+			}
+			interval = min(interval*2, timerT2)
+		}
+	}
+}
+
+// handleAck confirms the dialog
+func (c *Call) handleAck() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.state == callAnswered {
+		c.state = callConfirmed
+		close(c.acked)
+	}
+}
+
+// handleReinvite re-negotiates media, e.g. for hold or a carrier media change
+func (c *Call) handleReinvite(invite *Message, from *peer) {
+	if len(invite.Body) == 0 {
+		c.ua.respond(from, invite, 488, "Not Acceptable Here")
+		return
+	}
+	offer, err := negotiate(invite.Body, c.ua.config.Codecs)
+	if err == nil {
+		err = c.media.apply(offer)
+	}
+	if err != nil {
+		logger.Base().Warn("Rejecting SIP re-INVITE", zap.String("call_id", c.callID), zap.Error(err))
+		c.ua.respond(from, invite, 488, "Not Acceptable Here")
+		return
+	}
+	logger.Base().Info("SIP call media updated",
+		zap.String("call_id", c.callID),
+		zap.String("codec", offer.codec.name),
+		zap.String("mode", offer.mode))
+	c.ua.reply(from, c.okResponse(invite, offer))
+}
+
+// handleBye ends the call after the remote party hung up
+func (c *Call) handleBye() {
+	logger.Base().Info("SIP call ended by caller", zap.String("call_id", c.callID))
+	c.mutex.Lock()
+	c.state = callEnded
+	c.mutex.Unlock()
+	c.end()
+}
+
+// Hangup ends the call from our side, sending BYE to the caller
+func (c *Call) Hangup(reason string) {
+	c.mutex.Lock()
+	state := c.state
+	c.state = callEnded
+	c.cseq++
+	cseq := c.cseq
+	c.mutex.Unlock()
+
+	switch state {
+	case callEnded:
+		return
+	case callAnswering:
+		c.ua.reply(c.peer, NewResponse(c.invite, 480, "Temporarily Unavailable", c.localTag))
+	default:
+		logger.Base().Info("Hanging up SIP call", zap.String("call_id", c.callID), zap.String("reason", reason))
+		go c.sendBye(cseq)
+	}
+	c.end()
+}
+
+// sendBye sends BYE within the dialog
+func (c *Call) sendBye(cseq uint32) {
+	target := c.remoteTarget
+	if target == "" {
+		target = addressURI(c.remoteFrom)
+	}
+	bye := &Message{Method: MethodBye, RequestURI: target}
+	bye.Add("Max-Forwards", "70")
+	// Route set of a UAS is the Record-Route in received order
+	for _, route := range c.routeSet {
+		bye.Add("Route", route)
+	}
+	bye.Add("From", c.localTo)
+	bye.Add("To", c.remoteFrom)
+	bye.Add("Call-ID", c.callID)
+	// Our CSeq space starts above the caller's INVITE CSeq
+	inviteSeq, _ := c.invite.CSeq()
+	bye.Add("CSeq", fmt.Sprintf("%d %s", inviteSeq+cseq, MethodBye))
+	bye.Add("User-Agent", userAgentHeader)
+
+	resp, err := c.ua.request(c.peer.transport, c.peer.addr.String(), bye)
+	if err != nil {
+		if !errors.Is(err, ErrTransactionTimeout) {
+			logger.Base().Warn("Failed to send SIP BYE", zap.String("call_id", c.callID), zap.Error(err))
+		}
+		return
+	}
+	logger.Base().Debug("SIP BYE answered", zap.String("call_id", c.callID), zap.String("response", resp.String()))
+}
+
+// end releases the call's media and connection
+func (c *Call) end() {
+	c.endOnce.Do(func() {
+		close(c.done)
+		c.ua.removeCall(c.callID)
+
+		c.mutex.Lock()
+		media, connection := c.media, c.connection
+		c.mutex.Unlock()
+		if media != nil {
+			media.close()
+		}
+		if connection != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			c.ua.service.NotifyCleanup(ctx, connection.ID)
+		}
+	})
+}
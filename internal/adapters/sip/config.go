@@ -0,0 +1,235 @@
+package sip
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Transports the user agent listens on
+const (
+	TransportUDP  = "udp"
+	TransportTCP  = "tcp"
+	TransportBoth = "both"
+)
+
+// Codec names in preference lists
+const (
+	CodecOpus = "opus"
+	CodecPCMU = "pcmu"
+	CodecPCMA = "pcma"
+)
+
+// Config holds the SIP trunk configuration
+type Config struct {
+	Enabled    bool
+	ListenAddr string // Signalling address, e.g. ":5060"
+	Transport  string // One of the Transport* constants
+	PublicHost string // Address advertised in Via, Contact and SDP (detected when empty)
+
+	// RTP port range for call media
+	RTPPortMin int
+	RTPPortMax int
+	RTPTimeout time.Duration // Hang up when no RTP arrives for this long
+
+	// Carrier registration; calls are accepted without it (IP-authenticated trunks)
+	Registrar       string // host[:port] of the carrier's registrar
+	Username        string
+	Password        string
+	Domain          string // Registration domain (defaults to the registrar host)
+	RegisterExpires time.Duration
+
+	// Agent answering calls. Routes maps dialled numbers to agents; unmatched numbers use AgentID.
+	AgentID       string
+	TenantID      string
+	VoiceLanguage string
+	Routes        map[string]string
+
+	Codecs []string // Codec preference, most preferred first
+
+	// Networks allowed to send requests, e.g. the carrier's signalling ranges; others get 403
+	TrustedNetworks []*net.IPNet
+}
+
+// NewConfig creates a SIP configuration with defaults
+func NewConfig(listenAddr, agentID string) (*Config, error) {
+	if agentID == "" {
+		return nil, errors.New("SIP default agent ID is required")
+	}
+	if listenAddr == "" {
+		listenAddr = ":5060"
+	}
+
+	config := &Config{
+		Enabled:         true,
+		ListenAddr:      listenAddr,
+		Transport:       TransportBoth,
+		RTPPortMin:      10000,
+		RTPPortMax:      20000,
+		RTPTimeout:      30 * time.Second,
+		RegisterExpires: time.Hour,
+		AgentID:         agentID,
+		Routes:          map[string]string{},
+		Codecs:          []string{CodecOpus, CodecPCMU, CodecPCMA},
+	}
+
+	logger.Base().Info("SIP configuration initialized", zap.String("listen_addr", listenAddr), zap.String("agent_id", agentID))
+	return config, nil
+}
+
+// Validate validates the SIP configuration
+func (c *Config) Validate() error {
+	if c.AgentID == "" {
+		return errors.New("SIP default agent ID is required")
+	}
+	if _, _, err := net.SplitHostPort(c.ListenAddr); err != nil {
+		return fmt.Errorf("invalid SIP listen address %q: %w", c.ListenAddr, err)
+	}
+	switch c.Transport {
+	case TransportUDP, TransportTCP, TransportBoth:
+	default:
+		return fmt.Errorf("unknown SIP transport %q", c.Transport)
+	}
+	if c.RTPPortMin <= 0 || c.RTPPortMax > 65535 || c.RTPPortMin > c.RTPPortMax {
+		return fmt.Errorf("invalid RTP port range %d-%d", c.RTPPortMin, c.RTPPortMax)
+	}
+	if len(c.TrustedNetworks) == 0 {
+		return errors.New("SIP trusted networks are required")
+	}
+	if c.Registrar != "" && c.Username == "" {
+		return errors.New("SIP registrar needs a username")
+	}
+	for _, codec := range c.Codecs {
+		switch codec {
+		case CodecOpus, CodecPCMU, CodecPCMA:
+		default:
+			return fmt.Errorf("unknown SIP codec %q", codec)
+		}
+	}
+	return nil
+}
+
+// SetRoutes parses "number=agentID" pairs separated by commas
+func (c *Config) SetRoutes(routes string) {
+	for _, route := range strings.Split(routes, ",") {
+		number, agentID, ok := strings.Cut(strings.TrimSpace(route), "=")
+		if !ok || number == "" || agentID == "" {
+			if route != "" {
+				logger.Base().Warn("Ignoring malformed SIP route", zap.String("route", route))
+			}
+			continue
+		}
+		c.Routes[normalizeNumber(number)] = strings.TrimSpace(agentID)
+	}
+}
+
+// SetCodecs sets the codec preference from a comma-separated list, keeping the default when empty
+func (c *Config) SetCodecs(codecs string) {
+	var list []string
+	for _, codec := range strings.Split(codecs, ",") {
+		if codec = strings.ToLower(strings.TrimSpace(codec)); codec != "" {
+			list = append(list, codec)
+		}
+	}
+	if len(list) > 0 {
+		c.Codecs = list
+	}
+}
+
+// SetTrustedNetworks parses a comma-separated list of CIDRs; bare addresses trust a single host
+func (c *Config) SetTrustedNetworks(networks string) error {
+	var list []*net.IPNet
+	for _, network := range strings.Split(networks, ",") {
+		if network = strings.TrimSpace(network); network == "" {
+			continue
+		}
+		if !strings.Contains(network, "/") {
+			ip := net.ParseIP(network)
+			if ip == nil {
+				return fmt.Errorf("invalid SIP trusted address %q", network)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			list = append(list, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(network)
+		if err != nil {
+			return fmt.Errorf("invalid SIP trusted network %q: %w", network, err)
+		}
+		list = append(list, ipNet)
+	}
+	c.TrustedNetworks = list
+	return nil
+}
+
+// Trusts returns whether requests from an address are accepted
+func (c *Config) Trusts(addr net.Addr) bool {
+	var ip net.IP
+	switch a := addr.(type) {
+	case *net.UDPAddr:
+		ip = a.IP
+	case *net.TCPAddr:
+		ip = a.IP
+	}
+	if ip == nil {
+		return false
+	}
+	for _, network := range c.TrustedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// AgentFor returns the agent answering calls to a dialled number
+func (c *Config) AgentFor(number string) string {
+	if agentID, ok := c.Routes[normalizeNumber(number)]; ok {
+		return agentID
+	}
+	return c.AgentID
+}
+
+// ListensOn returns whether the user agent listens on a transport
+func (c *Config) ListensOn(transport string) bool {
+	return c.Transport == TransportBoth || c.Transport == transport
+}
+
+// registerTransport is the transport used to reach the registrar
+func (c *Config) registerTransport() string {
+	if c.Transport == TransportTCP {
+		return TransportTCP
+	}
+	return TransportUDP
+}
+
+// registerDomain is the domain of the registered address-of-record
+func (c *Config) registerDomain() string {
+	if c.Domain != "" {
+		return c.Domain
+	}
+	host, _, err := net.SplitHostPort(c.Registrar)
+	if err != nil {
+		return c.Registrar
+	}
+	return host
+}
+
+// normalizeNumber strips formatting so "+1 (555) 010-0000" and "15550100000" match
+func normalizeNumber(number string) string {
+	var b strings.Builder
+	for _, r := range number {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
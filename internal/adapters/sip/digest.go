@@ -0,0 +1,90 @@
+package sip
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// digestChallenge is a parsed WWW-Authenticate / Proxy-Authenticate header
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       string
+}
+
+// parseChallenge parses a Digest challenge (RFC 2617 / RFC 3261 §22.4)
+func parseChallenge(header string) (*digestChallenge, error) {
+	scheme, params, found := strings.Cut(strings.TrimSpace(header), " ")
+	if !found || !strings.EqualFold(scheme, "Digest") {
+		return nil, fmt.Errorf("unsupported authentication scheme in %q", header)
+	}
+
+	challenge := &digestChallenge{}
+	for _, param := range splitHeaderList(params) {
+		key, value, _ := strings.Cut(param, "=")
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "realm":
+			challenge.realm = value
+		case "nonce":
+			challenge.nonce = value
+		case "opaque":
+			challenge.opaque = value
+		case "algorithm":
+			challenge.algorithm = value
+		case "qop":
+			// Only qop=auth is supported; auth-int would need the body hash
+			for _, option := range strings.Split(value, ",") {
+				if strings.TrimSpace(option) == "auth" {
+					challenge.qop = "auth"
+				}
+			}
+		}
+	}
+	if challenge.nonce == "" {
+		return nil, fmt.Errorf("challenge has no nonce")
+	}
+	if challenge.algorithm != "" && !strings.EqualFold(challenge.algorithm, "MD5") {
+		return nil, fmt.Errorf("unsupported digest algorithm %q", challenge.algorithm)
+	}
+	return challenge, nil
+}
+
+// authorization returns the Authorization header value answering the challenge
+func (c *digestChallenge) authorization(method, uri, username, password string, nonceCount int) string {
+	ha1 := md5Hex(username + ":" + c.realm + ":" + password)
+	ha2 := md5Hex(method + ":" + uri)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `Digest username="%s", realm="%s", nonce="%s", uri="%s"`, username, c.realm, c.nonce, uri)
+	if c.qop != "" {
+		nc := fmt.Sprintf("%08x", nonceCount)
+		cnonce := randomToken(8)
+		response := md5Hex(ha1 + ":" + c.nonce + ":" + nc + ":" + cnonce + ":" + c.qop + ":" + ha2)
+		fmt.Fprintf(&b, `, response="%s", qop=%s, nc=%s, cnonce="%s"`, response, c.qop, nc, cnonce)
+	} else {
+		fmt.Fprintf(&b, `, response="%s"`, md5Hex(ha1+":"+c.nonce+":"+ha2))
+	}
+	b.WriteString(", algorithm=MD5")
+	if c.opaque != "" {
+		fmt.Fprintf(&b, `, opaque="%s"`, c.opaque)
+	}
+	return b.String()
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomToken returns n random bytes hex-encoded, for tags, branches and Call-IDs
+func randomToken(n int) string {
+	buf := make([]byte, n)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
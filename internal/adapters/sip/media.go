@@ -0,0 +1,262 @@
+package sip
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/pkg/g711"
+	"github.com/ClareAI/astra-voice-service/pkg/resample"
+	"github.com/pion/rtp"
+	"layeh.com/gopus"
+)
+
+const (
+	modelSampleRate = 48000
+	modelFrame      = 960 // 20ms at 48kHz
+
+	// outputGap is how long model audio may pause before the next frame starts a new
+	// talkspurt (marker bit and a timestamp jump covering the pause)
+	outputGap = 60 * time.Millisecond
+
+	portAttempts = 50
+)
+
+// dtmfDigits maps RFC 4733 event codes to keys
+var dtmfDigits = "0123456789*#ABCD"
+
+// mediaSession is the RTP stream of a call. Inbound packets are read by the call;
+// model audio is written through WriteOpusFrame, which makes the session the
+// connection's output track.
+type mediaSession struct {
+	conn    *net.UDPConn
+	remote  atomic.Pointer[net.UDPAddr]
+	latched atomic.Bool // remote port was learned from the first inbound RTP packet (symmetric RTP behind NAT)
+
+	codec    codec
+	dtmfType int
+
+	// Outbound stream state, guarded by writeMutex
+	writeMutex sync.Mutex
+	ssrc       uint32
+	sequence   uint16
+	timestamp  uint32
+	lastWrite  time.Time
+	decoder    *gopus.Decoder      // model Opus -> PCM for G.711 calls
+	downsample *resample.Resampler // 48kHz -> 8kHz for G.711 calls
+	pending    []int16             // 8kHz samples not yet filling a packet
+	payload    []byte
+
+	// Last DTMF event seen, to report each key press once
+	lastEvent uint32
+	hasEvent  bool
+}
+
+// newMediaSession opens an RTP socket on an even port in [minPort, maxPort]
+func newMediaSession(minPort, maxPort int) (*mediaSession, error) {
+	span := (maxPort - minPort) / 2
+	for attempt := 0; attempt < portAttempts; attempt++ {
+		port := minPort + 2*rand.Intn(span+1)
+		conn, err := net.ListenUDP("udp", &net.UDPAddr{Port: port})
+		if err != nil {
+			continue
+		}
+		return &mediaSession{
+			conn:      conn,
+			dtmfType:  -1,
+			ssrc:      rand.Uint32(),
+			sequence:  uint16(rand.Uint32()),
+			timestamp: rand.Uint32(),
+		}, nil
+	}
+	return nil, fmt.Errorf("no free RTP port in %d-%d", minPort, maxPort)
+}
+
+// port returns the local RTP port
+func (m *mediaSession) port() int {
+	return m.conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+// apply sets the negotiated codec and remote address; a re-INVITE may change either
+func (m *mediaSession) apply(offer *mediaOffer) error {
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
+
+	if m.codec.name != offer.codec.name {
+		m.decoder, m.downsample, m.pending = nil, nil, nil
+		if offer.codec.name != CodecOpus {
+			decoder, err := gopus.NewDecoder(modelSampleRate, 1)
+			if err != nil {
+				return fmt.Errorf("failed to create Opus decoder: %w", err)
+			}
+			downsample, err := resample.New(modelSampleRate, offer.codec.clockRate, resample.Fast)
+			if err != nil {
+				return err
+			}
+			m.decoder, m.downsample = decoder, downsample
+		}
+	}
+	m.codec = offer.codec
+	m.dtmfType = offer.dtmfType
+	// Keep a latched port unless the SDP moves media to another host, which is latched anew
+	if current := m.remote.Load(); !m.latched.Load() || current == nil || !current.IP.Equal(offer.addr.IP) {
+		m.remote.Store(offer.addr)
+		m.latched.Store(false)
+	}
+	return nil
+}
+
+// negotiated returns the codec and telephone-event payload type in use
+func (m *mediaSession) negotiated() (codec, int) {
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
+	return m.codec, m.dtmfType
+}
+
+// read returns the next RTP packet, waiting at most timeout
+func (m *mediaSession) read(buf []byte, timeout time.Duration) (*rtp.Packet, error) {
+	_ = m.conn.SetReadDeadline(time.Now().Add(timeout))
+	n, addr, err := m.conn.ReadFromUDP(buf)
+	if err != nil {
+		return nil, err
+	}
+	if n < 2 || (buf[1] >= 200 && buf[1] <= 207) {
+		// RTCP multiplexed on the RTP port
+		return nil, nil
+	}
+	// Only media from the host negotiated in SDP is accepted, so nobody else can inject
+	// audio or take over the stream
+	remote := m.remote.Load()
+	if remote == nil || !remote.IP.Equal(addr.IP) {
+		return nil, nil
+	}
+	if m.latched.Load() && remote.Port != addr.Port {
+		return nil, nil
+	}
+	// Packets outlive the read buffer in the jitter buffer
+	packet := &rtp.Packet{}
+	if err := packet.Unmarshal(append([]byte(nil), buf[:n]...)); err != nil {
+		return nil, nil
+	}
+	// Symmetric RTP: the first packet fixes the port answers go to (NAT may rewrite it)
+	if !m.latched.Load() {
+		m.remote.Store(addr)
+		m.latched.Store(true)
+	}
+	return packet, nil
+}
+
+// decodeG711 turns a PCMU or PCMA payload into PCM at the codec's 8kHz rate
+func decodeG711(codecName string, payload []byte) []int16 {
+	samples := make([]int16, 0, len(payload))
+	if codecName == CodecPCMA {
+		return g711.DecodeALaw(samples, payload)
+	}
+	return g711.DecodeULaw(samples, payload)
+}
+
+// dtmfEvent parses a telephone-event payload and returns the key once per press, when
+// its end packet arrives (end packets are sent three times)
+func (m *mediaSession) dtmfEvent(packet *rtp.Packet) (string, bool) {
+	m.writeMutex.Lock()
+	dtmfType := m.dtmfType
+	m.writeMutex.Unlock()
+	if len(packet.Payload) < 4 || int(packet.PayloadType) != dtmfType {
+		return "", false
+	}
+	event := packet.Payload[0]
+	end := packet.Payload[1]&0x80 != 0
+	if !end || int(event) >= len(dtmfDigits) {
+		return "", false
+	}
+	if m.hasEvent && m.lastEvent == packet.Timestamp {
+		return "", false
+	}
+	m.lastEvent, m.hasEvent = packet.Timestamp, true
+	return string(dtmfDigits[event]), true
+}
+
+// WriteOpusFrame sends a 20ms frame of model audio to the caller, transcoding it when
+// the call does not use Opus
+func (m *mediaSession) WriteOpusFrame(opusPayload []byte) error {
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
+
+	remote := m.remote.Load()
+	if remote == nil {
+		return errors.New("remote RTP address unknown")
+	}
+
+	now := time.Now()
+	marker := false
+	if !m.lastWrite.IsZero() {
+		if gap := now.Sub(m.lastWrite); gap > outputGap {
+			// Keep the timestamp in step with the wall clock across pauses
+			m.timestamp += uint32(gap / (20 * time.Millisecond) * time.Duration(m.codec.frameSamples()))
+			marker = true
+		}
+	} else {
+		marker = true
+	}
+	m.lastWrite = now
+
+	if m.codec.name == CodecOpus {
+		return m.send(remote, opusPayload, marker, uint32(m.codec.frameSamples()))
+	}
+
+	pcm, err := m.decoder.Decode(opusPayload, modelFrame*2, false)
+	if err != nil {
+		return fmt.Errorf("failed to decode model audio: %w", err)
+	}
+	m.pending = append(m.pending, m.downsample.Process(pcm)...)
+
+	frame := m.codec.frameSamples()
+	for len(m.pending) >= frame {
+		m.payload = m.payload[:0]
+		if m.codec.name == CodecPCMA {
+			m.payload = g711.EncodeALaw(m.payload, m.pending[:frame])
+		} else {
+			m.payload = g711.EncodeULaw(m.payload, m.pending[:frame])
+		}
+		m.pending = m.pending[frame:]
+		if err := m.send(remote, m.payload, marker, uint32(frame)); err != nil {
+			return err
+		}
+		marker = false
+	}
+	return nil
+}
+
+// send writes one RTP packet and advances the stream by samples
+func (m *mediaSession) send(remote *net.UDPAddr, payload []byte, marker bool, samples uint32) error {
+	packet := rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         marker,
+			PayloadType:    m.codec.payloadType,
+			SequenceNumber: m.sequence,
+			Timestamp:      m.timestamp,
+			SSRC:           m.ssrc,
+		},
+		Payload: payload,
+	}
+	data, err := packet.Marshal()
+	if err != nil {
+		return err
+	}
+	m.sequence++
+	m.timestamp += samples
+	if _, err := m.conn.WriteToUDP(data, remote); err != nil {
+		return fmt.Errorf("failed to send RTP: %w", err)
+	}
+	return nil
+}
+
+// close releases the RTP port
+func (m *mediaSession) close() {
+	m.conn.Close()
+}
@@ -0,0 +1,335 @@
+package sip
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SIP methods handled by the user agent
+const (
+	MethodRegister = "REGISTER"
+	MethodInvite   = "INVITE"
+	MethodAck      = "ACK"
+	MethodBye      = "BYE"
+	MethodCancel   = "CANCEL"
+	MethodOptions  = "OPTIONS"
+)
+
+// allowedMethods is advertised in Allow headers
+const allowedMethods = "INVITE, ACK, BYE, CANCEL, OPTIONS"
+
+// branchMagic starts every RFC 3261 Via branch
+const branchMagic = "z9hG4bK"
+
+// compactHeaders maps compact header forms (RFC 3261 §7.3.3) to their full names
+var compactHeaders = map[string]string{
+	"v": "Via",
+	"f": "From",
+	"t": "To",
+	"i": "Call-ID",
+	"m": "Contact",
+	"l": "Content-Length",
+	"c": "Content-Type",
+	"k": "Supported",
+}
+
+// Header is a single header field; a message keeps them in order since Via and
+// Record-Route order matters
+type Header struct {
+	Name  string
+	Value string
+}
+
+// Message is a SIP request or response
+type Message struct {
+	// Request line (requests only)
+	Method     string
+	RequestURI string
+
+	// Status line (responses only)
+	StatusCode int
+	Reason     string
+
+	Headers []Header
+	Body    []byte
+}
+
+// IsRequest reports whether the message is a request
+func (m *Message) IsRequest() bool {
+	return m.Method != ""
+}
+
+// Get returns the first value of a header, or ""
+func (m *Message) Get(name string) string {
+	for _, h := range m.Headers {
+		if strings.EqualFold(h.Name, name) {
+			return h.Value
+		}
+	}
+	return ""
+}
+
+// Values returns every value of a header in order, splitting comma-separated lists
+// for the headers that allow them
+func (m *Message) Values(name string) []string {
+	var values []string
+	for _, h := range m.Headers {
+		if !strings.EqualFold(h.Name, name) {
+			continue
+		}
+		values = append(values, splitHeaderList(h.Value)...)
+	}
+	return values
+}
+
+// Set replaces every value of a header with value
+func (m *Message) Set(name, value string) {
+	m.Del(name)
+	m.Add(name, value)
+}
+
+// Add appends a header value
+func (m *Message) Add(name, value string) {
+	m.Headers = append(m.Headers, Header{Name: name, Value: value})
+}
+
+// Del removes a header
+func (m *Message) Del(name string) {
+	kept := m.Headers[:0]
+	for _, h := range m.Headers {
+		if !strings.EqualFold(h.Name, name) {
+			kept = append(kept, h)
+		}
+	}
+	m.Headers = kept
+}
+
+// CSeq returns the sequence number and method of the CSeq header
+func (m *Message) CSeq() (uint32, string) {
+	fields := strings.Fields(m.Get("CSeq"))
+	if len(fields) != 2 {
+		return 0, ""
+	}
+	seq, _ := strconv.ParseUint(fields[0], 10, 32)
+	return uint32(seq), strings.ToUpper(fields[1])
+}
+
+// TopVia returns the first Via header value
+func (m *Message) TopVia() string {
+	if vias := m.Values("Via"); len(vias) > 0 {
+		return vias[0]
+	}
+	return ""
+}
+
+// Branch returns the branch parameter of the top Via, which identifies the transaction
+func (m *Message) Branch() string {
+	return headerParam(m.TopVia(), "branch")
+}
+
+// Bytes serializes the message, setting Content-Length from the body
+func (m *Message) Bytes() []byte {
+	var buf bytes.Buffer
+	if m.IsRequest() {
+		fmt.Fprintf(&buf, "%s %s SIP/2.0\r\n", m.Method, m.RequestURI)
+	} else {
+		fmt.Fprintf(&buf, "SIP/2.0 %d %s\r\n", m.StatusCode, m.Reason)
+	}
+	for _, h := range m.Headers {
+		if strings.EqualFold(h.Name, "Content-Length") {
+			continue
+		}
+		fmt.Fprintf(&buf, "%s: %s\r\n", h.Name, h.Value)
+	}
+	fmt.Fprintf(&buf, "Content-Length: %d\r\n\r\n", len(m.Body))
+	buf.Write(m.Body)
+	return buf.Bytes()
+}
+
+// String returns the first line of the message, for logging
+func (m *Message) String() string {
+	if m.IsRequest() {
+		return m.Method + " " + m.RequestURI
+	}
+	return strconv.Itoa(m.StatusCode) + " " + m.Reason
+}
+
+// ParseMessage parses one SIP message. The body is cut to Content-Length when present.
+func ParseMessage(data []byte) (*Message, error) {
+	head, body, found := bytes.Cut(data, []byte("\r\n\r\n"))
+	if !found {
+		// Tolerate bare LF line endings
+		head, body, found = bytes.Cut(data, []byte("\n\n"))
+		if !found {
+			return nil, fmt.Errorf("message has no header terminator")
+		}
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(head), "\r\n", "\n"), "\n")
+	msg := &Message{}
+	if err := msg.parseStartLine(lines[0]); err != nil {
+		return nil, err
+	}
+
+	for _, line := range lines[1:] {
+		if line == "" {
+			continue
+		}
+		// Header folding: continuation lines start with whitespace
+		if (line[0] == ' ' || line[0] == '\t') && len(msg.Headers) > 0 {
+			last := &msg.Headers[len(msg.Headers)-1]
+			last.Value += " " + strings.TrimSpace(line)
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("malformed header line %q", line)
+		}
+		name = strings.TrimSpace(name)
+		if full, compact := compactHeaders[strings.ToLower(name)]; compact {
+			name = full
+		}
+		msg.Add(name, strings.TrimSpace(value))
+	}
+
+	if length := msg.Get("Content-Length"); length != "" {
+		n, err := strconv.Atoi(length)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid Content-Length %q", length)
+		}
+		if n > len(body) {
+			return nil, fmt.Errorf("body shorter than Content-Length (%d < %d)", len(body), n)
+		}
+		body = body[:n]
+	}
+	msg.Body = append([]byte(nil), body...)
+	return msg, nil
+}
+
+func (m *Message) parseStartLine(line string) error {
+	parts := strings.SplitN(strings.TrimSpace(line), " ", 3)
+	if len(parts) < 3 {
+		return fmt.Errorf("malformed start line %q", line)
+	}
+	if strings.HasPrefix(parts[0], "SIP/") {
+		code, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return fmt.Errorf("invalid status code in %q", line)
+		}
+		m.StatusCode = code
+		m.Reason = parts[2]
+		return nil
+	}
+	if parts[2] != "SIP/2.0" {
+		return fmt.Errorf("unsupported SIP version in %q", line)
+	}
+	m.Method = strings.ToUpper(parts[0])
+	m.RequestURI = parts[1]
+	return nil
+}
+
+// NewResponse builds a response to req, copying the headers RFC 3261 §8.2.6 requires.
+// toTag is added to the To header when it has none.
+func NewResponse(req *Message, code int, reason, toTag string) *Message {
+	resp := &Message{StatusCode: code, Reason: reason}
+	for _, h := range req.Headers {
+		switch strings.ToLower(h.Name) {
+		case "via", "from", "call-id", "cseq", "record-route":
+			resp.Add(h.Name, h.Value)
+		}
+	}
+	to := req.Get("To")
+	if toTag != "" && headerParam(to, "tag") == "" {
+		to += ";tag=" + toTag
+	}
+	resp.Add("To", to)
+	return resp
+}
+
+// headerParam returns a ;-parameter of a header value such as a Via, From or To,
+// ignoring parameters inside the <...> URI
+func headerParam(value, name string) string {
+	if end := strings.LastIndex(value, ">"); end >= 0 {
+		value = value[end+1:]
+	}
+	for _, param := range strings.Split(value, ";")[1:] {
+		key, val, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if strings.EqualFold(key, name) {
+			return strings.Trim(val, `"`)
+		}
+	}
+	return ""
+}
+
+// addressURI returns the URI of a name-addr or addr-spec header value
+// (`"Bob" <sip:bob@host>;tag=1` -> `sip:bob@host`)
+func addressURI(value string) string {
+	if start := strings.Index(value, "<"); start >= 0 {
+		if end := strings.Index(value[start:], ">"); end > 0 {
+			return value[start+1 : start+end]
+		}
+	}
+	uri, _, _ := strings.Cut(strings.TrimSpace(value), ";")
+	return uri
+}
+
+// displayName returns the quoted or bare display name of a name-addr, or ""
+func displayName(value string) string {
+	start := strings.Index(value, "<")
+	if start <= 0 {
+		return ""
+	}
+	return strings.Trim(strings.TrimSpace(value[:start]), `"`)
+}
+
+// uriUser returns the user part of a SIP URI (`sip:+4930123@host;user=phone` -> `+4930123`)
+func uriUser(uri string) string {
+	uri = strings.TrimPrefix(strings.TrimPrefix(uri, "sips:"), "sip:")
+	uri = strings.TrimPrefix(uri, "tel:")
+	if user, _, found := strings.Cut(uri, "@"); found {
+		user, _, _ = strings.Cut(user, ";")
+		return user
+	}
+	uri, _, _ = strings.Cut(uri, ";")
+	return uri
+}
+
+// uriHostPort returns the host[:port] of a SIP URI
+func uriHostPort(uri string) string {
+	uri = strings.TrimPrefix(strings.TrimPrefix(uri, "sips:"), "sip:")
+	if _, host, found := strings.Cut(uri, "@"); found {
+		uri = host
+	}
+	uri, _, _ = strings.Cut(uri, ";")
+	uri, _, _ = strings.Cut(uri, "?")
+	return uri
+}
+
+// splitHeaderList splits a comma-separated header value, keeping commas inside
+// quotes and <...> intact
+func splitHeaderList(value string) []string {
+	var parts []string
+	depth, quoted, start := 0, false, 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '"':
+			quoted = !quoted
+		case '<':
+			if !quoted {
+				depth++
+			}
+		case '>':
+			if !quoted && depth > 0 {
+				depth--
+			}
+		case ',':
+			if !quoted && depth == 0 {
+				parts = append(parts, strings.TrimSpace(value[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(value[start:]))
+}
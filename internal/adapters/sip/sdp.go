@@ -0,0 +1,174 @@
+package sip
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pion/sdp/v3"
+)
+
+// ErrNoCommonCodec is returned when an offer has no codec the user agent supports
+var ErrNoCommonCodec = errors.New("no common audio codec")
+
+// codec is the negotiated audio codec of a call
+type codec struct {
+	name        string // One of the Codec* constants
+	payloadType uint8
+	clockRate   int
+}
+
+// frameSamples is the number of RTP timestamp units in a 20ms frame
+func (c codec) frameSamples() int {
+	return c.clockRate / 50
+}
+
+// rtpmap returns the SDP rtpmap value of the codec
+func (c codec) rtpmap() string {
+	switch c.name {
+	case CodecOpus:
+		return "opus/48000/2"
+	case CodecPCMA:
+		return "PCMA/8000"
+	default:
+		return "PCMU/8000"
+	}
+}
+
+// mediaOffer is the audio stream a remote SDP offers
+type mediaOffer struct {
+	addr     *net.UDPAddr // where the remote party receives RTP
+	codec    codec
+	dtmfType int    // telephone-event payload type, -1 when not offered
+	dtmfRate uint32 // telephone-event clock rate
+	mode     string // sendrecv, sendonly, recvonly or inactive
+}
+
+// negotiate picks the preferred codec of an SDP offer. preferences lists codec names,
+// most preferred first.
+func negotiate(body []byte, preferences []string) (*mediaOffer, error) {
+	session := &sdp.SessionDescription{}
+	if err := session.Unmarshal(body); err != nil {
+		return nil, fmt.Errorf("invalid SDP: %w", err)
+	}
+
+	var media *sdp.MediaDescription
+	for _, m := range session.MediaDescriptions {
+		if m.MediaName.Media == "audio" && m.MediaName.Port.Value != 0 {
+			media = m
+			break
+		}
+	}
+	if media == nil {
+		return nil, errors.New("SDP has no active audio stream")
+	}
+	if proto := strings.Join(media.MediaName.Protos, "/"); proto != "RTP/AVP" {
+		// SRTP (RTP/SAVP) and WebRTC profiles need keying the trunk does not do
+		return nil, fmt.Errorf("unsupported media profile %s", proto)
+	}
+
+	host := ""
+	if media.ConnectionInformation != nil && media.ConnectionInformation.Address != nil {
+		host = media.ConnectionInformation.Address.Address
+	} else if session.ConnectionInformation != nil && session.ConnectionInformation.Address != nil {
+		host = session.ConnectionInformation.Address.Address
+	}
+	if host == "" {
+		return nil, errors.New("SDP has no connection address")
+	}
+	addr, err := net.ResolveUDPAddr("udp", net.JoinHostPort(host, strconv.Itoa(media.MediaName.Port.Value)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SDP media address: %w", err)
+	}
+
+	offer := &mediaOffer{addr: addr, dtmfType: -1, mode: "sendrecv"}
+	for _, mode := range []string{"sendonly", "recvonly", "inactive"} {
+		if _, ok := media.Attribute(mode); ok {
+			offer.mode = mode
+		}
+	}
+
+	// Offered codecs in the offer's order
+	offered := make(map[string]codec)
+	for _, format := range media.MediaName.Formats {
+		pt, err := strconv.ParseUint(format, 10, 8)
+		if err != nil {
+			continue
+		}
+		rtpCodec, err := session.GetCodecForPayloadType(uint8(pt))
+		if err != nil {
+			continue
+		}
+		switch name := strings.ToLower(rtpCodec.Name); {
+		case name == "telephone-event":
+			// Prefer the event clock rate matching the audio codec; 8000 is the common case
+			if offer.dtmfType < 0 || rtpCodec.ClockRate == 8000 {
+				offer.dtmfType = int(pt)
+				offer.dtmfRate = rtpCodec.ClockRate
+			}
+		case name == CodecOpus && rtpCodec.ClockRate == 48000,
+			name == CodecPCMU && rtpCodec.ClockRate == 8000,
+			name == CodecPCMA && rtpCodec.ClockRate == 8000:
+			if _, seen := offered[name]; !seen {
+				offered[name] = codec{name: name, payloadType: uint8(pt), clockRate: int(rtpCodec.ClockRate)}
+			}
+		}
+	}
+
+	for _, name := range preferences {
+		if c, ok := offered[name]; ok {
+			offer.codec = c
+			return offer, nil
+		}
+	}
+	return nil, ErrNoCommonCodec
+}
+
+// answerMode is the direction answering an offered direction
+func answerMode(offered string) string {
+	switch offered {
+	case "sendonly":
+		return "recvonly"
+	case "recvonly":
+		return "sendonly"
+	case "inactive":
+		return "inactive"
+	default:
+		return "sendrecv"
+	}
+}
+
+// buildAnswer writes the SDP answer for the negotiated stream. version increases with
+// every re-negotiation of the session.
+func buildAnswer(host string, port int, sessionID, version uint64, offer *mediaOffer) []byte {
+	addrType := "IP4"
+	if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+		addrType = "IP6"
+	}
+
+	formats := strconv.Itoa(int(offer.codec.payloadType))
+	if offer.dtmfType >= 0 {
+		formats += " " + strconv.Itoa(offer.dtmfType)
+	}
+
+	var b strings.Builder
+	b.WriteString("v=0\r\n")
+	fmt.Fprintf(&b, "o=astra %d %d IN %s %s\r\n", sessionID, version, addrType, host)
+	b.WriteString("s=astra-voice\r\n")
+	fmt.Fprintf(&b, "c=IN %s %s\r\n", addrType, host)
+	b.WriteString("t=0 0\r\n")
+	fmt.Fprintf(&b, "m=audio %d RTP/AVP %s\r\n", port, formats)
+	fmt.Fprintf(&b, "a=rtpmap:%d %s\r\n", offer.codec.payloadType, offer.codec.rtpmap())
+	if offer.codec.name == CodecOpus {
+		fmt.Fprintf(&b, "a=fmtp:%d minptime=20;useinbandfec=1;usedtx=0\r\n", offer.codec.payloadType)
+	}
+	if offer.dtmfType >= 0 {
+		fmt.Fprintf(&b, "a=rtpmap:%d telephone-event/%d\r\n", offer.dtmfType, offer.dtmfRate)
+		fmt.Fprintf(&b, "a=fmtp:%d 0-16\r\n", offer.dtmfType)
+	}
+	b.WriteString("a=ptime:20\r\n")
+	fmt.Fprintf(&b, "a=%s\r\n", answerMode(offer.mode))
+	return []byte(b.String())
+}
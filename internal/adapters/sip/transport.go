@@ -0,0 +1,297 @@
+package sip
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	maxUDPMessage  = 65535
+	maxTCPMessage  = 1 << 20
+	tcpDialTimeout = 5 * time.Second
+	tcpIdleTimeout = 10 * time.Minute
+)
+
+// peer is where a message came from; responses go back the same way
+type peer struct {
+	transport string
+	addr      net.Addr
+	conn      net.Conn // TCP connection the message arrived on
+}
+
+func (p *peer) String() string {
+	return p.transport + ":" + p.addr.String()
+}
+
+// transport sends and receives SIP messages over UDP and TCP
+type transport struct {
+	udp      *net.UDPConn
+	tcp      net.Listener
+	tcpConns map[string]net.Conn // remote address -> open connection
+	mutex    sync.Mutex
+
+	handler func(msg *Message, from *peer)
+	closed  chan struct{}
+}
+
+// listen opens the configured transports; messages are handled once serve is called
+func listen(config *Config, handler func(msg *Message, from *peer)) (*transport, error) {
+	t := &transport{
+		tcpConns: make(map[string]net.Conn),
+		handler:  handler,
+		closed:   make(chan struct{}),
+	}
+
+	if config.ListensOn(TransportUDP) {
+		addr, err := net.ResolveUDPAddr("udp", config.ListenAddr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SIP UDP address: %w", err)
+		}
+		t.udp, err = net.ListenUDP("udp", addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen on SIP UDP %s: %w", config.ListenAddr, err)
+		}
+	}
+
+	if config.ListensOn(TransportTCP) {
+		listener, err := net.Listen("tcp", config.ListenAddr)
+		if err != nil {
+			t.close()
+			return nil, fmt.Errorf("failed to listen on SIP TCP %s: %w", config.ListenAddr, err)
+		}
+		t.tcp = listener
+	}
+
+	return t, nil
+}
+
+// serve starts handling received messages
+func (t *transport) serve() {
+	if t.udp != nil {
+		go t.serveUDP()
+	}
+	if t.tcp != nil {
+		go t.serveTCP()
+	}
+}
+
+// port returns the local signalling port
+func (t *transport) port() int {
+	if t.udp != nil {
+		return t.udp.LocalAddr().(*net.UDPAddr).Port
+	}
+	return t.tcp.Addr().(*net.TCPAddr).Port
+}
+
+func (t *transport) serveUDP() {
+	buf := make([]byte, maxUDPMessage)
+	for {
+		n, addr, err := t.udp.ReadFromUDP(buf)
+		if err != nil {
+			select {
+			case <-t.closed:
+				return
+			default:
+			}
+			logger.Base().Warn("SIP UDP read failed", zap.Error(err))
+			continue
+		}
+		data := bytes.TrimLeft(buf[:n], "\r\n")
+		if len(data) == 0 {
+			// Keepalive (RFC 5626 CRLF ping)
+			continue
+		}
+		msg, err := ParseMessage(data)
+		if err != nil {
+			logger.Base().Debug("Dropping malformed SIP datagram", zap.String("from", addr.String()), zap.Error(err))
+			continue
+		}
+		t.handler(msg, &peer{transport: TransportUDP, addr: addr})
+	}
+}
+
+func (t *transport) serveTCP() {
+	for {
+		conn, err := t.tcp.Accept()
+		if err != nil {
+			select {
+			case <-t.closed:
+				return
+			default:
+			}
+			logger.Base().Warn("SIP TCP accept failed", zap.Error(err))
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+		t.trackConn(conn)
+		go t.readTCP(conn)
+	}
+}
+
+// readTCP reads a stream of messages, framed by Content-Length
+func (t *transport) readTCP(conn net.Conn) {
+	defer t.dropConn(conn)
+	reader := bufio.NewReader(conn)
+	from := &peer{transport: TransportTCP, addr: conn.RemoteAddr(), conn: conn}
+
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(tcpIdleTimeout))
+		data, err := readStreamMessage(reader)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				logger.Base().Debug("SIP TCP connection closed", zap.String("remote", from.addr.String()), zap.Error(err))
+			}
+			return
+		}
+		if data == nil {
+			continue
+		}
+		msg, err := ParseMessage(data)
+		if err != nil {
+			logger.Base().Debug("Dropping malformed SIP message", zap.String("from", from.addr.String()), zap.Error(err))
+			continue
+		}
+		t.handler(msg, from)
+	}
+}
+
+// readStreamMessage reads one message from a stream transport. It returns nil data for
+// keepalive CRLFs between messages.
+func readStreamMessage(reader *bufio.Reader) ([]byte, error) {
+	var head bytes.Buffer
+	contentLength := 0
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(line) == "" {
+			if head.Len() == 0 {
+				return nil, nil
+			}
+			head.WriteString("\r\n")
+			break
+		}
+		head.WriteString(strings.TrimRight(line, "\r\n") + "\r\n")
+		if head.Len() > maxTCPMessage {
+			return nil, fmt.Errorf("SIP message header too large")
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if name = strings.TrimSpace(name); ok && (strings.EqualFold(name, "Content-Length") || name == "l") {
+			contentLength, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || contentLength < 0 || contentLength > maxTCPMessage {
+				return nil, fmt.Errorf("invalid Content-Length %q", strings.TrimSpace(value))
+			}
+		}
+	}
+
+	body := make([]byte, contentLength)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return nil, err
+	}
+	return append(head.Bytes(), body...), nil
+}
+
+// reply sends a message back to the peer it answers
+func (t *transport) reply(to *peer, msg *Message) error {
+	if to.transport == TransportTCP && to.conn != nil {
+		_, err := to.conn.Write(msg.Bytes())
+		return err
+	}
+	return t.send(to.transport, to.addr.String(), msg)
+}
+
+// send sends a message to host:port, reusing an open TCP connection to it
+func (t *transport) send(transport, address string, msg *Message) error {
+	if transport == TransportTCP {
+		conn, err := t.tcpConn(address)
+		if err != nil {
+			return err
+		}
+		if _, err := conn.Write(msg.Bytes()); err != nil {
+			t.dropConn(conn)
+			return fmt.Errorf("failed to send SIP message over TCP: %w", err)
+		}
+		return nil
+	}
+
+	if t.udp == nil {
+		return errors.New("SIP UDP transport not enabled")
+	}
+	addr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", address, err)
+	}
+	if _, err := t.udp.WriteToUDP(msg.Bytes(), addr); err != nil {
+		return fmt.Errorf("failed to send SIP message over UDP: %w", err)
+	}
+	return nil
+}
+
+// tcpConn returns an open connection to address, dialling one if needed
+func (t *transport) tcpConn(address string) (net.Conn, error) {
+	t.mutex.Lock()
+	conn, ok := t.tcpConns[address]
+	t.mutex.Unlock()
+	if ok {
+		return conn, nil
+	}
+
+	conn, err := net.DialTimeout("tcp", address, tcpDialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	t.mutex.Lock()
+	t.tcpConns[address] = conn
+	t.mutex.Unlock()
+	go t.readTCP(conn)
+	return conn, nil
+}
+
+func (t *transport) trackConn(conn net.Conn) {
+	t.mutex.Lock()
+	t.tcpConns[conn.RemoteAddr().String()] = conn
+	t.mutex.Unlock()
+}
+
+func (t *transport) dropConn(conn net.Conn) {
+	t.mutex.Lock()
+	if t.tcpConns[conn.RemoteAddr().String()] == conn {
+		delete(t.tcpConns, conn.RemoteAddr().String())
+	}
+	t.mutex.Unlock()
+	conn.Close()
+}
+
+// close stops the listeners and open connections
+func (t *transport) close() {
+	select {
+	case <-t.closed:
+		return
+	default:
+		close(t.closed)
+	}
+	if t.udp != nil {
+		t.udp.Close()
+	}
+	if t.tcp != nil {
+		t.tcp.Close()
+	}
+	t.mutex.Lock()
+	for address, conn := range t.tcpConns {
+		conn.Close()
+		delete(t.tcpConns, address)
+	}
+	t.mutex.Unlock()
+}
@@ -0,0 +1,427 @@
+package sip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/internal/repository"
+	"github.com/ClareAI/astra-voice-service/internal/services/call"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// RFC 3261 timers
+	timerT1 = 500 * time.Millisecond
+	timerT2 = 4 * time.Second
+	timerB  = 64 * timerT1 // client transaction timeout
+
+	registerRetry   = 30 * time.Second
+	userAgentHeader = "astra-voice-service"
+)
+
+// ErrTransactionTimeout is returned when a request gets no final response
+var ErrTransactionTimeout = errors.New("SIP transaction timed out")
+
+// UserAgent answers calls from a SIP trunk and registers with the carrier
+type UserAgent struct {
+	config      *Config
+	service     *call.WhatsAppCallService
+	repoManager repository.RepositoryManager
+	transport   *transport
+	host        string // advertised host
+
+	calls map[string]*Call // SIP Call-ID -> call
+	mutex sync.RWMutex
+
+	// Client transactions awaiting responses, by Via branch
+	transactions map[string]chan *Message
+	txMutex      sync.Mutex
+
+	registered atomic.Bool
+	cseq       atomic.Uint32
+	cancel     context.CancelFunc
+}
+
+// NewUserAgent creates the SIP user agent
+func NewUserAgent(config *Config, service *call.WhatsAppCallService, repoManager repository.RepositoryManager) (*UserAgent, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid SIP config: %w", err)
+	}
+
+	host := config.PublicHost
+	if host == "" {
+		var err error
+		if host, err = localAddress(config.Registrar); err != nil {
+			return nil, fmt.Errorf("failed to detect SIP public host: %w", err)
+		}
+	}
+
+	return &UserAgent{
+		config:       config,
+		service:      service,
+		repoManager:  repoManager,
+		host:         host,
+		calls:        make(map[string]*Call),
+		transactions: make(map[string]chan *Message),
+	}, nil
+}
+
+// Start listens for SIP traffic and, with a registrar configured, keeps the trunk registered
+// until ctx is done
+func (ua *UserAgent) Start(ctx context.Context) error {
+	t, err := listen(ua.config, ua.handleMessage)
+	if err != nil {
+		return err
+	}
+	ua.transport = t
+	t.serve()
+
+	ctx, ua.cancel = context.WithCancel(ctx)
+	if ua.config.Registrar != "" {
+		go ua.registerLoop(ctx)
+	}
+
+	logger.Base().Info("SIP user agent started",
+		zap.String("listen_addr", ua.config.ListenAddr),
+		zap.String("transport", ua.config.Transport),
+		zap.String("public_host", ua.host),
+		zap.String("registrar", ua.config.Registrar))
+	return nil
+}
+
+// Close hangs up active calls, unregisters and stops listening
+func (ua *UserAgent) Close() {
+	ua.mutex.RLock()
+	calls := make([]*Call, 0, len(ua.calls))
+	for _, c := range ua.calls {
+		calls = append(calls, c)
+	}
+	ua.mutex.RUnlock()
+	for _, c := range calls {
+		c.Hangup("shutdown")
+	}
+
+	if ua.cancel != nil {
+		ua.cancel()
+	}
+	if ua.registered.Load() {
+		if err := ua.register(0); err != nil {
+			logger.Base().Warn("SIP unregister failed", zap.Error(err))
+		}
+	}
+	if ua.transport != nil {
+		ua.transport.close()
+	}
+	logger.Base().Info("SIP user agent stopped")
+}
+
+// GetCallCount returns the number of active SIP calls
+func (ua *UserAgent) GetCallCount() int {
+	ua.mutex.RLock()
+	defer ua.mutex.RUnlock()
+	return len(ua.calls)
+}
+
+// IsRegistered returns whether the trunk registration is current
+func (ua *UserAgent) IsRegistered() bool {
+	return ua.registered.Load()
+}
+
+// handleMessage dispatches a received message
+func (ua *UserAgent) handleMessage(msg *Message, from *peer) {
+	if !msg.IsRequest() {
+		ua.txMutex.Lock()
+		responses, ok := ua.transactions[msg.Branch()]
+		ua.txMutex.Unlock()
+		if ok {
+			select {
+			case responses <- msg:
+			default:
+			}
+		}
+		return
+	}
+
+	if !ua.config.Trusts(from.addr) {
+		logger.Base().Warn("Rejecting SIP request from untrusted source",
+			zap.String("method", msg.Method),
+			zap.String("from", from.String()))
+		if msg.Method != MethodAck {
+			ua.respond(from, msg, 403, "Forbidden")
+		}
+		return
+	}
+
+	switch msg.Method {
+	case MethodInvite:
+		ua.handleInvite(msg, from)
+	case MethodAck:
+		if c := ua.call(msg.Get("Call-ID")); c != nil {
+			c.handleAck()
+		}
+	case MethodBye:
+		c := ua.call(msg.Get("Call-ID"))
+		if c == nil {
+			ua.respond(from, msg, 481, "Call/Transaction Does Not Exist")
+			return
+		}
+		ua.respond(from, msg, 200, "OK")
+		c.handleBye()
+	case MethodCancel:
+		ua.handleCancel(msg, from)
+	case MethodOptions:
+		resp := NewResponse(msg, 200, "OK", randomToken(4))
+		resp.Add("Allow", allowedMethods)
+		resp.Add("Accept", "application/sdp")
+		resp.Add("User-Agent", userAgentHeader)
+		ua.reply(from, resp)
+	default:
+		resp := NewResponse(msg, 405, "Method Not Allowed", randomToken(4))
+		resp.Add("Allow", allowedMethods)
+		ua.reply(from, resp)
+	}
+}
+
+// handleInvite answers a new call or a re-INVITE of an existing one
+func (ua *UserAgent) handleInvite(msg *Message, from *peer) {
+	callID := msg.Get("Call-ID")
+	if callID == "" {
+		ua.respond(from, msg, 400, "Missing Call-ID")
+		return
+	}
+
+	if existing := ua.call(callID); existing != nil {
+		if headerParam(msg.Get("To"), "tag") == "" {
+			// Retransmission of the initial INVITE
+			existing.retransmitAnswer(from)
+			return
+		}
+		existing.handleReinvite(msg, from)
+		return
+	}
+
+	c := newCall(ua, msg, from)
+	ua.mutex.Lock()
+	ua.calls[callID] = c
+	ua.mutex.Unlock()
+
+	ua.respond(from, msg, 100, "Trying")
+	go c.answer()
+}
+
+// handleCancel cancels a call that has not been answered yet
+func (ua *UserAgent) handleCancel(msg *Message, from *peer) {
+	c := ua.call(msg.Get("Call-ID"))
+	if c == nil {
+		ua.respond(from, msg, 481, "Call/Transaction Does Not Exist")
+		return
+	}
+	ua.respond(from, msg, 200, "OK")
+	c.cancel()
+}
+
+// call returns the call with a SIP Call-ID
+func (ua *UserAgent) call(callID string) *Call {
+	ua.mutex.RLock()
+	defer ua.mutex.RUnlock()
+	return ua.calls[callID]
+}
+
+// removeCall forgets a finished call
+func (ua *UserAgent) removeCall(callID string) {
+	ua.mutex.Lock()
+	delete(ua.calls, callID)
+	ua.mutex.Unlock()
+}
+
+// respond sends a header-only response to req
+func (ua *UserAgent) respond(to *peer, req *Message, code int, reason string) {
+	ua.reply(to, NewResponse(req, code, reason, ""))
+}
+
+func (ua *UserAgent) reply(to *peer, resp *Message) {
+	if err := ua.transport.reply(to, resp); err != nil {
+		logger.Base().Warn("Failed to send SIP response",
+			zap.String("response", resp.String()),
+			zap.String("to", to.String()),
+			zap.Error(err))
+	}
+}
+
+// via returns a Via header value for a new client transaction
+func (ua *UserAgent) via(transport, branch string) string {
+	return fmt.Sprintf("SIP/2.0/%s %s;branch=%s;rport", strings.ToUpper(transport), ua.hostPort(), branch)
+}
+
+// hostPort is the advertised signalling address
+func (ua *UserAgent) hostPort() string {
+	return net.JoinHostPort(ua.host, strconv.Itoa(ua.transport.port()))
+}
+
+// contact returns the Contact URI of the user agent
+func (ua *UserAgent) contact(user, transport string) string {
+	uri := "sip:"
+	if user != "" {
+		uri += user + "@"
+	}
+	uri += ua.hostPort()
+	if transport == TransportTCP {
+		uri += ";transport=tcp"
+	}
+	return "<" + uri + ">"
+}
+
+// request sends a request as a client transaction and returns its final response.
+// Over UDP the request is retransmitted until a response arrives (RFC 3261 §17.1.2).
+func (ua *UserAgent) request(transport, address string, req *Message) (*Message, error) {
+	branch := branchMagic + randomToken(8)
+	req.Headers = append([]Header{{Name: "Via", Value: ua.via(transport, branch)}}, req.Headers...)
+
+	responses := make(chan *Message, 8)
+	ua.txMutex.Lock()
+	ua.transactions[branch] = responses
+	ua.txMutex.Unlock()
+	defer func() {
+		ua.txMutex.Lock()
+		delete(ua.transactions, branch)
+		ua.txMutex.Unlock()
+	}()
+
+	if err := ua.transport.send(transport, address, req); err != nil {
+		return nil, err
+	}
+
+	interval := timerT1
+	retransmit := time.NewTimer(interval)
+	defer retransmit.Stop()
+	timeout := time.NewTimer(timerB)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case resp := <-responses:
+			if resp.StatusCode >= 200 {
+				return resp, nil
+			}
+			// Provisional: the server has the request, stop retransmitting
+			retransmit.Stop()
+		case <-retransmit.C:
+			if transport == TransportUDP {
+				_ = ua.transport.send(transport, address, req)
+				interval = min(interval*2, timerT2)
+				retransmit.Reset(interval)
+			}
+		case <-timeout.C:
+			return nil, ErrTransactionTimeout
+		}
+	}
+}
+
+// registerLoop keeps the registration fresh, retrying after failures
+func (ua *UserAgent) registerLoop(ctx context.Context) {
+	for {
+		expires := ua.config.RegisterExpires
+		if err := ua.register(expires); err != nil {
+			ua.registered.Store(false)
+			logger.Base().Error("SIP registration failed", zap.String("registrar", ua.config.Registrar), zap.Error(err))
+			expires = registerRetry
+		} else {
+			// Refresh before the binding expires
+			expires = expires * 4 / 5
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(expires):
+		}
+	}
+}
+
+// register sends a REGISTER, answering a digest challenge; expires 0 removes the binding
+func (ua *UserAgent) register(expires time.Duration) error {
+	domain := ua.config.registerDomain()
+	transport := ua.config.registerTransport()
+	aor := fmt.Sprintf("<sip:%s@%s>", ua.config.Username, domain)
+	requestURI := "sip:" + domain
+	callID := randomToken(12) + "@" + ua.host
+	tag := randomToken(4)
+
+	build := func(authHeader, authValue string) *Message {
+		req := &Message{Method: MethodRegister, RequestURI: requestURI}
+		req.Add("Max-Forwards", "70")
+		req.Add("From", aor+";tag="+tag)
+		req.Add("To", aor)
+		req.Add("Call-ID", callID)
+		req.Add("CSeq", fmt.Sprintf("%d %s", ua.cseq.Add(1), MethodRegister))
+		req.Add("Contact", ua.contact(ua.config.Username, transport))
+		req.Add("Expires", strconv.Itoa(int(expires.Seconds())))
+		req.Add("User-Agent", userAgentHeader)
+		if authHeader != "" {
+			req.Add(authHeader, authValue)
+		}
+		return req
+	}
+
+	resp, err := ua.request(transport, ua.registrarAddress(), build("", ""))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == 401 || resp.StatusCode == 407 {
+		challengeHeader, authHeader := "WWW-Authenticate", "Authorization"
+		if resp.StatusCode == 407 {
+			challengeHeader, authHeader = "Proxy-Authenticate", "Proxy-Authorization"
+		}
+		challenge, err := parseChallenge(resp.Get(challengeHeader))
+		if err != nil {
+			return err
+		}
+		auth := challenge.authorization(MethodRegister, requestURI, ua.config.Username, ua.config.Password, 1)
+		if resp, err = ua.request(transport, ua.registrarAddress(), build(authHeader, auth)); err != nil {
+			return err
+		}
+	}
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("registrar answered %s", resp.String())
+	}
+	ua.registered.Store(expires > 0)
+	logger.Base().Info("SIP registration updated",
+		zap.String("registrar", ua.config.Registrar),
+		zap.String("aor", aor),
+		zap.Duration("expires", expires))
+	return nil
+}
+
+// registrarAddress is the registrar's host:port, defaulting to port 5060
+func (ua *UserAgent) registrarAddress() string {
+	if _, _, err := net.SplitHostPort(ua.config.Registrar); err == nil {
+		return ua.config.Registrar
+	}
+	return net.JoinHostPort(ua.config.Registrar, "5060")
+}
+
+// localAddress returns the local IP used to reach target (any public address when empty)
+func localAddress(target string) (string, error) {
+	if target == "" {
+		target = "8.8.8.8:53"
+	} else if _, _, err := net.SplitHostPort(target); err != nil {
+		target = net.JoinHostPort(target, "5060")
+	}
+	// UDP "dial" sends nothing; it only selects the outgoing interface
+	conn, err := net.Dial("udp", target)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP.String(), nil
+}
@@ -0,0 +1,239 @@
+package sip
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+)
+
+// standIn plays the carrier side of a trunk over UDP
+type standIn struct {
+	t    *testing.T
+	conn *net.UDPConn
+	ua   *net.UDPAddr
+}
+
+func newStandIn(t *testing.T, ip string, ua *UserAgent) *standIn {
+	t.Helper()
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP(ip)})
+	if err != nil {
+		t.Skipf("cannot bind %s: %v", ip, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &standIn{
+		t:    t,
+		conn: conn,
+		ua:   &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: ua.transport.port()},
+	}
+}
+
+// send sends a request within a dialog identified by callID
+func (s *standIn) send(method, callID, body string) {
+	s.t.Helper()
+	local := s.conn.LocalAddr().String()
+	msg := fmt.Sprintf("%s sip:+15550100000@%s SIP/2.0\r\n"+
+		"Via: SIP/2.0/UDP %s;branch=z9hG4bK-%s-%s\r\n"+
+		"From: <sip:+15550199999@%s>;tag=carrier\r\n"+
+		"To: <sip:+15550100000@%s>\r\n"+
+		"Call-ID: %s\r\n"+
+		"CSeq: 1 %s\r\n"+
+		"Contact: <sip:carrier@%s>\r\n"+
+		"Max-Forwards: 70\r\n",
+		method, s.ua, local, method, callID, local, s.ua, callID, method, local)
+	if body != "" {
+		msg += "Content-Type: application/sdp\r\n"
+	}
+	msg += fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(body), body)
+	if _, err := s.conn.WriteToUDP([]byte(msg), s.ua); err != nil {
+		s.t.Fatalf("send %s: %v", method, err)
+	}
+}
+
+// expect waits for a response with the given status code, skipping provisional ones in between
+func (s *standIn) expect(code int) *Message {
+	s.t.Helper()
+	buf := make([]byte, maxUDPMessage)
+	_ = s.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		n, _, err := s.conn.ReadFromUDP(buf)
+		if err != nil {
+			s.t.Fatalf("waiting for %d: %v", code, err)
+		}
+		msg, err := ParseMessage(buf[:n])
+		if err != nil {
+			s.t.Fatalf("malformed response: %v", err)
+		}
+		if msg.StatusCode == code {
+			return msg
+		}
+		if msg.StatusCode >= 200 {
+			s.t.Fatalf("got %d %s, want %d", msg.StatusCode, msg.Reason, code)
+		}
+	}
+}
+
+func startUserAgent(t *testing.T, trusted string) *UserAgent {
+	t.Helper()
+	config, err := NewConfig("127.0.0.1:0", "agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Transport = TransportUDP
+	config.PublicHost = "127.0.0.1"
+	if err := config.SetTrustedNetworks(trusted); err != nil {
+		t.Fatal(err)
+	}
+	ua, err := NewUserAgent(config, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ua.Start(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(ua.Close)
+	return ua
+}
+
+func TestUserAgentRejectsUntrustedSources(t *testing.T) {
+	ua := startUserAgent(t, "127.0.0.1")
+
+	carrier := newStandIn(t, "127.0.0.1", ua)
+	carrier.send(MethodOptions, "options-1", "")
+	if resp := carrier.expect(200); resp.Get("Allow") == "" {
+		t.Error("OPTIONS response has no Allow header")
+	}
+
+	intruder := newStandIn(t, "127.0.0.2", ua)
+	intruder.send(MethodInvite, "intruder-1", "v=0\r\n")
+	intruder.expect(403)
+	intruder.send(MethodBye, "intruder-2", "")
+	intruder.expect(403)
+	if n := ua.GetCallCount(); n != 0 {
+		t.Errorf("untrusted INVITE created %d calls", n)
+	}
+}
+
+func TestUserAgentRejectsUnusableOffer(t *testing.T) {
+	ua := startUserAgent(t, "127.0.0.0/8")
+	carrier := newStandIn(t, "127.0.0.1", ua)
+
+	// Video only: nothing to answer with
+	offer := "v=0\r\no=- 1 1 IN IP4 127.0.0.1\r\ns=-\r\nc=IN IP4 127.0.0.1\r\nt=0 0\r\n" +
+		"m=video 40000 RTP/AVP 96\r\na=rtpmap:96 VP8/90000\r\n"
+	carrier.send(MethodInvite, "video-1", offer)
+	carrier.expect(100)
+	carrier.expect(488)
+
+	carrier.send(MethodBye, "unknown-1", "")
+	carrier.expect(481)
+}
+
+func TestConfigTrustedNetworks(t *testing.T) {
+	config := &Config{}
+	if err := config.SetTrustedNetworks("203.0.113.0/24, 198.51.100.7,2001:db8::/32"); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		addr    net.Addr
+		trusted bool
+	}{
+		{&net.UDPAddr{IP: net.ParseIP("203.0.113.9"), Port: 5060}, true},
+		{&net.TCPAddr{IP: net.ParseIP("198.51.100.7"), Port: 5060}, true},
+		{&net.UDPAddr{IP: net.ParseIP("198.51.100.8"), Port: 5060}, false},
+		{&net.UDPAddr{IP: net.ParseIP("2001:db8::1"), Port: 5060}, true},
+		{&net.UDPAddr{IP: net.ParseIP("192.0.2.1"), Port: 5060}, false},
+	}
+	for _, test := range tests {
+		if got := config.Trusts(test.addr); got != test.trusted {
+			t.Errorf("Trusts(%s) = %v, want %v", test.addr, got, test.trusted)
+		}
+	}
+
+	if err := config.SetTrustedNetworks("203.0.113.0/33"); err == nil {
+		t.Error("invalid CIDR accepted")
+	}
+	if err := (&Config{AgentID: "agent", ListenAddr: ":5060", Transport: TransportUDP, RTPPortMin: 1, RTPPortMax: 2}).Validate(); err == nil {
+		t.Error("config without trusted networks validated")
+	}
+}
+
+func TestMediaLatchesNegotiatedHost(t *testing.T) {
+	media, err := newMediaSession(30000, 30100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer media.close()
+
+	first, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+	second, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Close()
+	other, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 2)})
+	if err != nil {
+		t.Skipf("cannot bind 127.0.0.2: %v", err)
+	}
+	defer other.Close()
+
+	// SDP names the host but a port NAT will rewrite
+	offer := &mediaOffer{
+		addr:     &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9},
+		codec:    codec{name: CodecOpus},
+		dtmfType: -1,
+	}
+	if err := media.apply(offer); err != nil {
+		t.Fatal(err)
+	}
+
+	local := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: media.port()}
+	sequence := uint16(0)
+	send := func(conn *net.UDPConn) {
+		t.Helper()
+		sequence++
+		data, err := (&rtp.Packet{Header: rtp.Header{Version: 2, SequenceNumber: sequence}, Payload: []byte{1}}).Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := conn.WriteToUDP(data, local); err != nil {
+			t.Fatal(err)
+		}
+	}
+	buf := make([]byte, 1500)
+	receive := func(name string, want bool) {
+		t.Helper()
+		packet, err := media.read(buf, time.Second)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got := packet != nil; got != want {
+			t.Errorf("%s: accepted = %v, want %v", name, got, want)
+		}
+	}
+
+	send(other)
+	receive("other host before latching", false)
+	send(first)
+	receive("first packet from the negotiated host", true)
+	if remote := media.remote.Load(); remote.Port != first.LocalAddr().(*net.UDPAddr).Port {
+		t.Errorf("remote = %s, want the first sender", remote)
+	}
+	send(second)
+	receive("negotiated host on another port", false)
+	send(first)
+	receive("latched port", true)
+
+	// A re-INVITE to the same host keeps the latched port
+	if err := media.apply(offer); err != nil {
+		t.Fatal(err)
+	}
+	send(second)
+	receive("other port after re-INVITE", false)
+}
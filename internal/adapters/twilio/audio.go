@@ -0,0 +1,154 @@
+package twilio
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/internal/services/call"
+	"github.com/ClareAI/astra-voice-service/internal/storage"
+	"github.com/ClareAI/astra-voice-service/pkg/g711"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/metrics"
+	"github.com/ClareAI/astra-voice-service/pkg/resample"
+	"github.com/pion/rtp"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"layeh.com/gopus"
+)
+
+// recordingPayloadType is the Opus payload type of recorded caller audio
+const recordingPayloadType = 111
+
+// callerAudio turns the caller's μ-law media into 48kHz PCM frames for the model. Twilio
+// delivers media in order over the WebSocket, so no jitter buffer is needed.
+type callerAudio struct {
+	connection *call.WhatsAppCallConnection
+	upsample   *resample.Resampler
+	pending    []int16 // upsampled audio not yet making a full 20ms frame
+	samples    []int16
+
+	// Recording re-encodes the caller's audio as Opus so the recorder sees one format
+	audioCache *storage.AudioCacheService
+	recorder   *gopus.Encoder
+	sequence   uint16
+	timestamp  uint32
+
+	forwardedPackets prometheus.Counter
+	droppedPackets   prometheus.Counter
+	gatedPackets     prometheus.Counter
+	dspDuration      prometheus.Observer
+}
+
+func newCallerAudio(connection *call.WhatsAppCallConnection) (*callerAudio, error) {
+	upsample, err := resample.New(g711.SampleRate, modelSampleRate, resample.Fast)
+	if err != nil {
+		return nil, err
+	}
+	channelLabel := connection.GetChannelTypeString()
+	a := &callerAudio{
+		connection:       connection,
+		upsample:         upsample,
+		forwardedPackets: metrics.AudioPacketsTotal.WithLabelValues(channelLabel, metrics.DirectionInbound, metrics.AudioForwarded),
+		droppedPackets:   metrics.AudioPacketsTotal.WithLabelValues(channelLabel, metrics.DirectionInbound, metrics.AudioDropped),
+		gatedPackets:     metrics.AudioPacketsTotal.WithLabelValues(channelLabel, metrics.DirectionInbound, metrics.AudioGated),
+		dspDuration:      metrics.AudioDSPFrameDuration.WithLabelValues(channelLabel),
+	}
+	if audioCache := storage.GetAudioCache(); audioCache != nil && connection.NeedsAudioCaching() {
+		if a.recorder, err = gopus.NewEncoder(modelSampleRate, 1, gopus.Voip); err != nil {
+			return nil, fmt.Errorf("failed to create recording encoder: %w", err)
+		}
+		a.audioCache = audioCache
+	}
+	return a, nil
+}
+
+// handleMedia forwards a media message from the caller to the model
+func (a *callerAudio) handleMedia(media *mediaPayload) {
+	if media.Track != "" && media.Track != "inbound" {
+		return
+	}
+	data, err := base64.StdEncoding.DecodeString(media.Payload)
+	if err != nil || len(data) == 0 {
+		a.droppedPackets.Inc()
+		return
+	}
+
+	a.samples = g711.DecodeULaw(a.samples[:0], data)
+	a.pending = append(a.pending, a.upsample.Process(a.samples)...)
+	for len(a.pending) >= modelFrame {
+		pcm := append([]int16(nil), a.pending[:modelFrame]...)
+		a.pending = a.pending[modelFrame:]
+		a.record(pcm)
+		a.forward(pcm)
+	}
+	a.connection.UpdateLastActivity()
+}
+
+// forward runs a frame through the agent's DSP and VAD and sends it to the model
+func (a *callerAudio) forward(pcm []int16) {
+	connection := a.connection
+
+	// Run the agent's DSP chain on every frame so its filters see continuous audio
+	if chain := connection.GetDSP(); chain != nil {
+		start := time.Now()
+		chain.Process(pcm)
+		a.dspDuration.Observe(time.Since(start).Seconds())
+	}
+
+	modelClient := connection.GetAIWebRTC()
+	if modelClient == nil {
+		// Model still connecting
+		return
+	}
+	if shouldForward, _ := connection.ShouldForwardAudioToAI(); !shouldForward {
+		a.droppedPackets.Inc()
+		return
+	}
+
+	toSend := [][]int16{pcm}
+	if detector := connection.GetVAD(); detector != nil {
+		if toSend = detector.Process(pcm); len(toSend) == 0 {
+			a.gatedPackets.Inc()
+			return
+		}
+	}
+	for _, samples := range toSend {
+		if err := modelClient.SendAudio(samples); err != nil {
+			a.droppedPackets.Inc()
+			continue
+		}
+		a.forwardedPackets.Inc()
+	}
+}
+
+// record caches a frame of caller audio for the call recording
+func (a *callerAudio) record(pcm []int16) {
+	if a.audioCache == nil {
+		return
+	}
+	payload, err := a.recorder.Encode(pcm, modelFrame, 4000)
+	if err != nil {
+		logger.Base().Debug("Failed to encode Twilio audio for recording", zap.String("connection_id", a.connection.ID), zap.Error(err))
+		return
+	}
+	packet := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    recordingPayloadType,
+			SequenceNumber: a.sequence,
+			Timestamp:      a.timestamp,
+		},
+		Payload: payload,
+	}
+	a.sequence++
+	a.timestamp += modelFrame
+	a.audioCache.CacheAudioRTP(a.connection.ID, storage.AudioTypeWhatsAppInput, storage.AudioFormatOpus, packet)
+}
+
+// close releases the recording state of the caller's audio
+func (a *callerAudio) close() {
+	if a.audioCache != nil {
+		a.audioCache.CleanupConnection(a.connection.ID)
+	}
+}
@@ -0,0 +1,123 @@
+package twilio
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Paths of the Twilio endpoints
+const (
+	VoicePath  = "/twilio/voice"        // Voice webhook answering calls with TwiML
+	StreamPath = "/twilio/media-stream" // Media Streams WebSocket
+)
+
+// Config holds the Twilio Media Streams channel configuration
+type Config struct {
+	// Account auth token used to check X-Twilio-Signature on every request
+	AuthToken string
+	// Base URL Twilio reaches the service on, e.g. "https://voice.example.com". Signatures
+	// cover the URL Twilio requested, so this must be set behind proxies that rewrite it.
+	PublicURL string
+
+	// Agent answering calls. Routes maps called numbers to agents; unmatched numbers use AgentID.
+	AgentID       string
+	TenantID      string
+	VoiceLanguage string
+	Routes        map[string]string
+
+	IdleTimeout time.Duration // End the stream when Twilio sends nothing for this long
+}
+
+// NewConfig creates a Twilio Media Streams configuration with defaults
+func NewConfig(authToken, agentID string) (*Config, error) {
+	if agentID == "" {
+		return nil, errors.New("Twilio default agent ID is required")
+	}
+	if authToken == "" {
+		return nil, errors.New("Twilio auth token is required")
+	}
+
+	config := &Config{
+		AuthToken:   authToken,
+		AgentID:     agentID,
+		Routes:      map[string]string{},
+		IdleTimeout: 30 * time.Second,
+	}
+
+	logger.Base().Info("Twilio Media Streams configuration initialized", zap.String("agent_id", agentID))
+	return config, nil
+}
+
+// SetRoutes parses "number=agentID" pairs separated by commas
+func (c *Config) SetRoutes(routes string) {
+	for _, route := range strings.Split(routes, ",") {
+		number, agentID, ok := strings.Cut(strings.TrimSpace(route), "=")
+		if !ok || number == "" || agentID == "" {
+			if route != "" {
+				logger.Base().Warn("Ignoring malformed Twilio route", zap.String("route", route))
+			}
+			continue
+		}
+		c.Routes[normalizeNumber(number)] = strings.TrimSpace(agentID)
+	}
+}
+
+// AgentFor returns the agent answering calls to a number
+func (c *Config) AgentFor(number string) string {
+	if agentID, ok := c.Routes[normalizeNumber(number)]; ok {
+		return agentID
+	}
+	return c.AgentID
+}
+
+// RequestURL returns the URL Twilio requested, as it signed it. WebSocket requests are
+// signed with the wss:// URL given in the TwiML.
+func (c *Config) RequestURL(r *http.Request) string {
+	websocket := strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+	return c.baseURL(r, websocket) + r.URL.RequestURI()
+}
+
+// StreamURL returns the Media Streams WebSocket URL for the TwiML answering a call
+func (c *Config) StreamURL(r *http.Request) string {
+	return c.baseURL(r, true) + StreamPath
+}
+
+// baseURL is the public scheme and host of the service
+func (c *Config) baseURL(r *http.Request, websocket bool) string {
+	base := strings.TrimSuffix(c.PublicURL, "/")
+	if base == "" {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+			scheme = strings.TrimSpace(strings.Split(proto, ",")[0])
+		}
+		base = scheme + "://" + r.Host
+	}
+	if websocket {
+		if rest, ok := strings.CutPrefix(base, "https://"); ok {
+			return "wss://" + rest
+		}
+		if rest, ok := strings.CutPrefix(base, "http://"); ok {
+			return "ws://" + rest
+		}
+	}
+	return base
+}
+
+// normalizeNumber strips formatting so "+1 (555) 010-0000" and "15550100000" match
+func normalizeNumber(number string) string {
+	var b strings.Builder
+	for _, r := range number {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
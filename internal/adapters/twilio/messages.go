@@ -0,0 +1,123 @@
+package twilio
+
+import (
+	"encoding/xml"
+)
+
+// Media Streams events
+const (
+	EventConnected = "connected"
+	EventStart     = "start"
+	EventMedia     = "media"
+	EventMark      = "mark"
+	EventDTMF      = "dtmf"
+	EventStop      = "stop"
+	EventClear     = "clear" // sent to Twilio to drop buffered audio
+)
+
+// mulawEncoding is the only media format Twilio streams use (8kHz mono μ-law)
+const mulawEncoding = "audio/x-mulaw"
+
+// Custom parameters passed from the TwiML to the stream's start event
+const (
+	ParamAgentID    = "agent_id"
+	ParamFrom       = "from"
+	ParamTo         = "to"
+	ParamCallerName = "caller_name"
+)
+
+// message is a Media Streams WebSocket message in either direction
+type message struct {
+	Event          string        `json:"event"`
+	StreamSid      string        `json:"streamSid,omitempty"`
+	SequenceNumber string        `json:"sequenceNumber,omitempty"`
+	Start          *startPayload `json:"start,omitempty"`
+	Media          *mediaPayload `json:"media,omitempty"`
+	Mark           *markPayload  `json:"mark,omitempty"`
+	DTMF           *dtmfPayload  `json:"dtmf,omitempty"`
+	Stop           *stopPayload  `json:"stop,omitempty"`
+}
+
+type startPayload struct {
+	AccountSid       string            `json:"accountSid"`
+	CallSid          string            `json:"callSid"`
+	StreamSid        string            `json:"streamSid"`
+	Tracks           []string          `json:"tracks"`
+	CustomParameters map[string]string `json:"customParameters"`
+	MediaFormat      struct {
+		Encoding   string `json:"encoding"`
+		SampleRate int    `json:"sampleRate"`
+		Channels   int    `json:"channels"`
+	} `json:"mediaFormat"`
+}
+
+type mediaPayload struct {
+	Track     string `json:"track,omitempty"`
+	Chunk     string `json:"chunk,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Payload   string `json:"payload"` // Base64 μ-law audio
+}
+
+type markPayload struct {
+	Name string `json:"name"`
+}
+
+type dtmfPayload struct {
+	Track string `json:"track"`
+	Digit string `json:"digit"`
+}
+
+type stopPayload struct {
+	AccountSid string `json:"accountSid"`
+	CallSid    string `json:"callSid"`
+}
+
+// twiml is the TwiML document answering a call
+type twiml struct {
+	XMLName xml.Name      `xml:"Response"`
+	Connect *twimlConnect `xml:"Connect,omitempty"`
+	Reject  *twimlReject  `xml:"Reject,omitempty"`
+}
+
+type twimlConnect struct {
+	Stream twimlStream `xml:"Stream"`
+}
+
+type twimlStream struct {
+	URL        string           `xml:"url,attr"`
+	Parameters []twimlParameter `xml:"Parameter"`
+}
+
+type twimlParameter struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type twimlReject struct {
+	Reason string `xml:"reason,attr"`
+}
+
+// ConnectTwiML returns TwiML connecting the call to a bidirectional media stream. Parameters
+// reach the stream in its start event.
+func ConnectTwiML(streamURL string, params map[string]string) ([]byte, error) {
+	stream := twimlStream{URL: streamURL}
+	for _, name := range []string{ParamAgentID, ParamFrom, ParamTo, ParamCallerName} {
+		if value := params[name]; value != "" {
+			stream.Parameters = append(stream.Parameters, twimlParameter{Name: name, Value: value})
+		}
+	}
+	return marshalTwiML(twiml{Connect: &twimlConnect{Stream: stream}})
+}
+
+// RejectTwiML returns TwiML rejecting the call
+func RejectTwiML() ([]byte, error) {
+	return marshalTwiML(twiml{Reject: &twimlReject{Reason: "rejected"}})
+}
+
+func marshalTwiML(doc twiml) ([]byte, error) {
+	body, err := xml.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
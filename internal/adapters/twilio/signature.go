@@ -0,0 +1,48 @@
+package twilio
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// SignatureHeader carries Twilio's request signature
+const SignatureHeader = "X-Twilio-Signature"
+
+// ValidateSignature checks a Twilio request signature: the Base64 HMAC-SHA1, keyed with the
+// auth token, of the full request URL followed by each POST parameter's name and value in
+// name order. WebSocket requests have no parameters.
+func ValidateSignature(authToken, requestURL string, params url.Values, signature string) bool {
+	if signature == "" {
+		return false
+	}
+	expected := computeSignature(authToken, requestURL, params)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// computeSignature returns the signature Twilio sends for a request
+func computeSignature(authToken, requestURL string, params url.Values) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(requestURL)
+	for _, name := range names {
+		values := append([]string(nil), params[name]...)
+		sort.Strings(values)
+		for _, value := range values {
+			b.WriteString(name)
+			b.WriteString(value)
+		}
+	}
+
+	mac := hmac.New(sha1.New, []byte(authToken))
+	mac.Write([]byte(b.String()))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
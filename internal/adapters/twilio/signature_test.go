@@ -0,0 +1,61 @@
+package twilio
+
+import (
+	"net/url"
+	"testing"
+)
+
+// Example request from Twilio's webhook security documentation
+var (
+	docAuthToken = "12345"
+	docURL       = "https://mycompany.com/myapp.php?foo=1&bar=2"
+	docParams    = url.Values{
+		"CallSid": {"CA1234567890ABCDE"},
+		"Caller":  {"+12349013030"},
+		"Digits":  {"1234"},
+		"From":    {"+12349013030"},
+		"To":      {"+18005551212"},
+	}
+	docSignature = "0/KCTR6DLpKmkAf8muzZqo1nDgQ="
+)
+
+func TestValidateSignature(t *testing.T) {
+	tampered := url.Values{}
+	for name, values := range docParams {
+		tampered[name] = values
+	}
+	tampered.Set("Digits", "4321")
+
+	tests := []struct {
+		name      string
+		authToken string
+		url       string
+		params    url.Values
+		signature string
+		valid     bool
+	}{
+		{"documented example", docAuthToken, docURL, docParams, docSignature, true},
+		{"missing signature", docAuthToken, docURL, docParams, "", false},
+		{"wrong auth token", "54321", docURL, docParams, docSignature, false},
+		{"different URL", docAuthToken, "https://mycompany.com/myapp.php?foo=1", docParams, docSignature, false},
+		{"tampered parameter", docAuthToken, docURL, tampered, docSignature, false},
+		{"WebSocket request without parameters", docAuthToken, "wss://voice.example.com/twilio/media-stream", nil,
+			computeSignature(docAuthToken, "wss://voice.example.com/twilio/media-stream", nil), true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ValidateSignature(test.authToken, test.url, test.params, test.signature); got != test.valid {
+				t.Errorf("ValidateSignature = %v, want %v", got, test.valid)
+			}
+		})
+	}
+}
+
+func TestNewConfigRequiresAuthToken(t *testing.T) {
+	if _, err := NewConfig("", "agent"); err == nil {
+		t.Error("config without an auth token accepted")
+	}
+	if _, err := NewConfig(docAuthToken, "agent"); err != nil {
+		t.Errorf("NewConfig: %v", err)
+	}
+}
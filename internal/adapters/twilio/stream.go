@@ -0,0 +1,333 @@
+package twilio
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/internal/config"
+	"github.com/ClareAI/astra-voice-service/internal/domain"
+	"github.com/ClareAI/astra-voice-service/internal/repository"
+	"github.com/ClareAI/astra-voice-service/internal/services/agent"
+	"github.com/ClareAI/astra-voice-service/internal/services/call"
+	"github.com/ClareAI/astra-voice-service/pkg/g711"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/resample"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+	"layeh.com/gopus"
+)
+
+const (
+	modelSampleRate = 48000
+	modelFrame      = 960 // 20ms at 48kHz
+	streamFrame     = 160 // 20ms of 8kHz μ-law
+
+	// markInterval is how many frames of audio are sent between marks; Twilio echoes a
+	// mark once the audio before it has played, which tells how much is still buffered
+	markInterval = 5
+
+	writeTimeout = 5 * time.Second
+)
+
+// Stream is a Twilio Media Streams call. Its connection is registered with the call service
+// like the other channels; the stream is the connection's output track, sending model audio
+// to Twilio as μ-law media messages.
+type Stream struct {
+	config      *Config
+	service     *call.WhatsAppCallService
+	repoManager repository.RepositoryManager
+	ws          *websocket.Conn
+
+	connection *call.WhatsAppCallConnection
+	inbound    *callerAudio
+
+	// Outbound state, guarded by writeMutex (a WebSocket has a single writer)
+	writeMutex   sync.Mutex
+	streamSid    string
+	decoder      *gopus.Decoder      // model Opus -> PCM
+	downsample   *resample.Resampler // 48kHz -> 8kHz
+	pending      []int16             // 8kHz samples not yet filling a frame
+	payload      []byte
+	framesSent   uint64
+	framesPlayed atomic.Uint64 // frames Twilio reported played through marks
+
+	done    chan struct{}
+	endOnce sync.Once
+}
+
+// NewStream creates the call for an upgraded Media Streams WebSocket
+func NewStream(ws *websocket.Conn, config *Config, service *call.WhatsAppCallService, repoManager repository.RepositoryManager) (*Stream, error) {
+	decoder, err := gopus.NewDecoder(modelSampleRate, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Opus decoder: %w", err)
+	}
+	downsample, err := resample.New(modelSampleRate, g711.SampleRate, resample.Fast)
+	if err != nil {
+		return nil, err
+	}
+	return &Stream{
+		config:      config,
+		service:     service,
+		repoManager: repoManager,
+		ws:          ws,
+		decoder:     decoder,
+		downsample:  downsample,
+		done:        make(chan struct{}),
+	}, nil
+}
+
+// Run reads the stream's messages until Twilio stops it or the call ends
+func (s *Stream) Run() {
+	defer s.end()
+
+	for {
+		_ = s.ws.SetReadDeadline(time.Now().Add(s.config.IdleTimeout))
+		_, data, err := s.ws.ReadMessage()
+		if err != nil {
+			closedByUs := s.connection != nil && s.connection.IsClosed()
+			if !closedByUs && !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				logger.Base().Warn("Twilio media stream read failed", zap.String("stream_sid", s.streamSid), zap.Error(err))
+			}
+			return
+		}
+
+		var msg message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			logger.Base().Warn("Ignoring malformed Twilio message", zap.Error(err))
+			continue
+		}
+
+		switch msg.Event {
+		case EventConnected:
+			logger.Base().Debug("Twilio media stream connected")
+		case EventStart:
+			if err := s.start(&msg); err != nil {
+				logger.Base().Error("Failed to start Twilio call", zap.String("stream_sid", msg.StreamSid), zap.Error(err))
+				return
+			}
+		case EventMedia:
+			if s.inbound != nil && msg.Media != nil {
+				s.inbound.handleMedia(msg.Media)
+			}
+		case EventMark:
+			if msg.Mark != nil {
+				s.handleMark(msg.Mark.Name)
+			}
+		case EventDTMF:
+			if s.connection != nil && msg.DTMF != nil && msg.DTMF.Digit != "" {
+				s.connection.ForwardDTMF(msg.DTMF.Digit)
+			}
+		case EventStop:
+			logger.Base().Info("Twilio call ended by caller", zap.String("stream_sid", s.streamSid))
+			return
+		}
+	}
+}
+
+// start creates the call's connection from the start event and brings up the model
+func (s *Stream) start(msg *message) error {
+	if s.connection != nil {
+		return nil
+	}
+	start := msg.Start
+	if start == nil {
+		return errors.New("start event without payload")
+	}
+	if encoding := start.MediaFormat.Encoding; encoding != "" && encoding != mulawEncoding {
+		return fmt.Errorf("unsupported media encoding %s", encoding)
+	}
+
+	params := start.CustomParameters
+	to := params[ParamTo]
+	agentID := params[ParamAgentID]
+	if agentID == "" {
+		agentID = s.config.AgentFor(to)
+	}
+	var textAgentID string
+	if agentService, err := agent.GetAgentService(); err == nil {
+		agentConfig, err := agentService.GetAgentConfigWithChannelType(context.Background(), agentID, domain.ChannelTypeTwilio)
+		if err != nil || agentConfig == nil {
+			return fmt.Errorf("agent %s not found: %w", agentID, err)
+		}
+		agentID = agentConfig.ID
+		textAgentID = agentConfig.TextAgentID
+	}
+
+	language := s.config.VoiceLanguage
+	if language == "" {
+		language = config.DefaultLanguage
+	}
+
+	streamSid := msg.StreamSid
+	if streamSid == "" {
+		streamSid = start.StreamSid
+	}
+	s.writeMutex.Lock()
+	s.streamSid = streamSid
+	s.writeMutex.Unlock()
+
+	now := time.Now()
+	connection := &call.WhatsAppCallConnection{
+		ID:              fmt.Sprintf("twilio-%d", now.UnixNano()),
+		CallID:          start.CallSid,
+		From:            params[ParamFrom],
+		To:              to,
+		CreatedAt:       now,
+		LastActivity:    now,
+		IsActive:        true,
+		ChannelType:     domain.ChannelTypeTwilio,
+		HasInboundAudio: true,
+		VoiceLanguage:   language,
+		ContactName:     params[ParamCallerName],
+		AgentID:         agentID,
+		TextAgentID:     textAgentID,
+		TenantID:        s.config.TenantID,
+		BusinessNumber:  to,
+		RepoManager:     s.repoManager,
+		WAOutputTrack:   s,
+	}
+	inbound, err := newCallerAudio(connection)
+	if err != nil {
+		return err
+	}
+	s.connection, s.inbound = connection, inbound
+
+	logger.Base().Info("Twilio call started",
+		zap.String("connection_id", connection.ID),
+		zap.String("call_sid", start.CallSid),
+		zap.String("stream_sid", streamSid),
+		zap.String("from", connection.From),
+		zap.String("to", connection.To),
+		zap.String("agent_id", agentID))
+
+	// Same flow as other inbound calls: register, then bring up the model; the model
+	// bridge finds the stream as the output track and sends the greeting
+	s.service.AddConnection(connection)
+	if err := connection.InitializeVoiceConversation(); err != nil {
+		logger.Base().Error("Failed to initialize voice conversation", zap.String("connection_id", connection.ID), zap.Error(err))
+		// Continue anyway, AddMessage will create it as fallback
+	}
+	go s.service.InitializeAIConnection(connection)
+	go s.watch(connection)
+	return nil
+}
+
+// watch closes the stream when the call ends on our side (cleanup by the agent or
+// inactivity). Twilio then moves past <Connect>, which hangs up the call.
+func (s *Stream) watch(connection *call.WhatsAppCallConnection) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			if connection.IsClosed() {
+				logger.Base().Info("Connection closed, ending Twilio stream", zap.String("connection_id", connection.ID))
+				// Unblocks Run, which ends the call
+				s.ws.Close()
+				return
+			}
+		}
+	}
+}
+
+// WriteOpusFrame sends a 20ms frame of model audio to the caller as μ-law
+func (s *Stream) WriteOpusFrame(opusPayload []byte) error {
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	if s.streamSid == "" {
+		return errors.New("twilio stream not started")
+	}
+	pcm, err := s.decoder.Decode(opusPayload, modelFrame*2, false)
+	if err != nil {
+		return fmt.Errorf("failed to decode model audio: %w", err)
+	}
+	s.pending = append(s.pending, s.downsample.Process(pcm)...)
+
+	for len(s.pending) >= streamFrame {
+		s.payload = g711.EncodeULaw(s.payload[:0], s.pending[:streamFrame])
+		s.pending = s.pending[streamFrame:]
+		media := &mediaPayload{Payload: base64.StdEncoding.EncodeToString(s.payload)}
+		if err := s.send(&message{Event: EventMedia, StreamSid: s.streamSid, Media: media}); err != nil {
+			return err
+		}
+		s.framesSent++
+		if s.framesSent%markInterval == 0 {
+			mark := &markPayload{Name: strconv.FormatUint(s.framesSent, 10)}
+			if err := s.send(&message{Event: EventMark, StreamSid: s.streamSid, Mark: mark}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ClearOutput drops the audio Twilio has buffered but not yet played, on barge-in
+func (s *Stream) ClearOutput() error {
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	played := s.framesPlayed.Load()
+	if s.streamSid == "" || s.framesSent <= played {
+		return nil
+	}
+	if err := s.send(&message{Event: EventClear, StreamSid: s.streamSid}); err != nil {
+		return err
+	}
+	logger.Base().Info("Cleared Twilio playback buffer",
+		zap.String("stream_sid", s.streamSid),
+		zap.Uint64("buffered_ms", (s.framesSent-played)*20))
+
+	// Twilio echoes the outstanding marks after a clear; nothing sent so far is pending
+	s.pending = s.pending[:0]
+	s.framesPlayed.Store(s.framesSent)
+	return nil
+}
+
+// handleMark records playback progress from an echoed mark
+func (s *Stream) handleMark(name string) {
+	frames, err := strconv.ParseUint(name, 10, 64)
+	if err != nil {
+		return
+	}
+	for {
+		played := s.framesPlayed.Load()
+		if frames <= played || s.framesPlayed.CompareAndSwap(played, frames) {
+			return
+		}
+	}
+}
+
+// send writes a message; the caller holds writeMutex
+func (s *Stream) send(msg *message) error {
+	_ = s.ws.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err := s.ws.WriteJSON(msg); err != nil {
+		return fmt.Errorf("failed to write Twilio %s message: %w", msg.Event, err)
+	}
+	return nil
+}
+
+// end closes the stream and releases the call's connection
+func (s *Stream) end() {
+	s.endOnce.Do(func() {
+		close(s.done)
+		s.ws.Close()
+		if s.inbound != nil {
+			s.inbound.close()
+		}
+		if s.connection != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			s.service.NotifyCleanup(ctx, s.connection.ID)
+		}
+	})
+}
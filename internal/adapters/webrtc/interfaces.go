@@ -2,6 +2,8 @@ package webrtc
 
 import (
 	"github.com/ClareAI/astra-voice-service/internal/core/event"
+	"github.com/ClareAI/astra-voice-service/pkg/dsp"
+	"github.com/ClareAI/astra-voice-service/pkg/vad"
 	"layeh.com/gopus"
 )
 
@@ -18,6 +20,8 @@ type ConnectionInterface interface {
 	IsClosed() bool
 	SetOpusDecoder(decoder *gopus.Decoder)
 	GetOpusDecoder() *gopus.Decoder
+	GetVAD() *vad.Detector
+	GetDSP() *dsp.Chain
 	ShouldForwardAudioToAI() (bool, string)
 	GetAIWebRTC() *Client
 	UpdateLastActivity()
@@ -9,7 +9,9 @@ import (
 	"github.com/ClareAI/astra-voice-service/internal/config"
 	"github.com/ClareAI/astra-voice-service/internal/core/event"
 	"github.com/ClareAI/astra-voice-service/internal/storage"
+	"github.com/ClareAI/astra-voice-service/pkg/jitter"
 	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/metrics"
 	"github.com/pion/webrtc/v3"
 	"go.uber.org/zap"
 	"layeh.com/gopus"
@@ -440,15 +442,27 @@ func (p *Processor) continueAudioForwarding(connectionID string, track *webrtc.T
 	audioCache := storage.GetAudioCache()
 	needsCaching := connection.NeedsAudioCaching()
 
+	channelLabel := connection.GetChannelTypeString()
+	forwardedPackets := metrics.AudioPacketsTotal.WithLabelValues(channelLabel, metrics.DirectionInbound, metrics.AudioForwarded)
+	droppedPackets := metrics.AudioPacketsTotal.WithLabelValues(channelLabel, metrics.DirectionInbound, metrics.AudioDropped)
+	gatedPackets := metrics.AudioPacketsTotal.WithLabelValues(channelLabel, metrics.DirectionInbound, metrics.AudioGated)
+	dspDuration := metrics.AudioDSPFrameDuration.WithLabelValues(channelLabel)
+
 	// Read RTP packets from WhatsApp and forward to the model
 	go func() {
 		var frameCount int64       // Count of successfully sent frames
 		var suppressionCount int64 // Count of suppressed frames
+		var gatedCount int64       // Count of frames held back by local VAD
+		jitterBuffer := jitter.NewBuffer(jitter.DefaultConfig())
+		jitterReporter := jitter.NewReporter(channelLabel)
 		defer func() {
 			// Cleanup audio cache for this connection (only if cache is available and needed)
 			if audioCache != nil && needsCaching {
 				audioCache.CleanupConnection(connectionID)
 			}
+			stats := jitterBuffer.Stats()
+			jitterReporter.Report(stats)
+			logger.Base().Info("WhatsApp inbound RTP stats", zap.String("connection_id", connectionID), zap.Int64("received", stats.Received), zap.Int64("lost", stats.Lost), zap.Int64("late", stats.Late), zap.Int64("fec_recovered", stats.Recovered), zap.Int64("concealed", stats.Concealed), zap.Float64("loss_rate", stats.LossRate()), zap.Duration("jitter", stats.Jitter))
 		}()
 
 		for {
@@ -465,108 +479,149 @@ func (p *Processor) continueAudioForwarding(connectionID string, track *webrtc.T
 			}
 
 			// Extract Opus payload from RTP packet
-			opusPayload := rtpPacket.Payload
-			if len(opusPayload) == 0 {
+			if len(rtpPacket.Payload) == 0 {
 				continue
 			}
 
-			// Skip very small frames (likely DTX/silence frames) to reduce processing
-			if len(opusPayload) < 3 {
-				continue
+			// Reorder through the jitter buffer; it releases frames in sequence order,
+			// with lost frames marked for FEC recovery or concealment
+			frames := jitterBuffer.Push(rtpPacket, time.Now())
+			if stats := jitterBuffer.Stats(); stats.Received%50 == 0 {
+				jitterReporter.Report(stats)
 			}
 
-			// Cache Opus audio asynchronously (only Opus format for efficiency)
-
-			// Convert Opus to PCM16 for the model
-			// Use connection-specific decoder to avoid concurrent access issues
-			decoder := connection.GetOpusDecoder()
-
-			if decoder == nil {
-				// Skip if no decoder available for this connection
-				if rtpPacket.SequenceNumber%100 == 0 {
-					logger.Base().Warn("No Opus decoder available for connection - skipping audio conversion", zap.String("connection_id", connectionID))
-				}
-				continue
-			}
-
-			// Decode Opus to PCM16 using connection's dedicated decoder
-			// Opus frame is typically 20ms at 48kHz = 960 samples per channel
-			// Use larger buffer to handle variable frame sizes
-			maxSamples := (config.DefaultSampleRate / 1000) * 40 // 40ms buffer (double typical 20ms for safety)
-			pcmSamples, err := decoder.Decode(opusPayload, maxSamples, false)
-			if err != nil {
-				if rtpPacket.SequenceNumber%100 == 0 {
-					logger.Base().Error("Failed to decode Opus", zap.String("connection_id", connectionID), zap.Int("payload_bytes", len(opusPayload)), zap.Error(err))
+			for _, frame := range frames {
+				var opusPayload []byte
+				if !frame.Lost() {
+					opusPayload = frame.Packet.Payload
+					// Skip very small frames (likely DTX/silence frames) to reduce processing
+					if len(opusPayload) < 3 {
+						continue
+					}
 				}
-				continue
-			}
 
-			// Send PCM16 samples to the model if we got valid samples
-			if len(pcmSamples) > 0 {
-				// Check if connection is closed before processing audio
-				// This is critical because ReadRTP() may still return data from buffer
-				// even after the connection is closed
-				if connection.IsClosed() {
-					logger.Base().Info("🛑 Connection closed, stopping audio forwarding", zap.String("connection_id", connectionID))
-					return
-				}
+				// Convert Opus to PCM16 for the model
+				// Use connection-specific decoder to avoid concurrent access issues
+				decoder := connection.GetOpusDecoder()
 
-				// Cache RTP packet for audio storage (only for WhatsApp/Test channels, not LiveKit)
-				if audioCache != nil && needsCaching {
-					audioCache.CacheAudioRTP(connectionID, storage.AudioTypeWhatsAppInput, storage.AudioFormatOpus, rtpPacket)
+				if decoder == nil {
+					// Skip if no decoder available for this connection
+					if frame.SequenceNumber%100 == 0 {
+						logger.Base().Warn("No Opus decoder available for connection - skipping audio conversion", zap.String("connection_id", connectionID))
+					}
+					continue
 				}
 
-				// Check if we should forward audio to the model
-				// If greeting hasn't been sent/completed yet, and connection is very new,
-				// suppress user audio to prevent interrupting the greeting
-				shouldForward, reason := connection.ShouldForwardAudioToAI()
-
-				if !shouldForward {
-					suppressionCount++
-					// Log suppression occasionally (every 100 suppressed frames)
-					if suppressionCount%100 == 0 {
-						logger.Base().Info("🤫 Suppressing user audio during greeting phase", zap.String("reason", reason), zap.String("connection_id", connectionID))
+				// Decode Opus to PCM16 using connection's dedicated decoder
+				// Opus frame is typically 20ms at 48kHz = 960 samples per channel
+				// Use larger buffer to handle variable frame sizes
+				maxSamples := (config.DefaultSampleRate / 1000) * 40 // 40ms buffer (double typical 20ms for safety)
+				// Lost frames are rebuilt from FEC in the next packet or concealed by the decoder
+				pcmSamples, err := jitterBuffer.Decode(decoder, frame, maxSamples)
+				if err != nil {
+					if frame.SequenceNumber%100 == 0 {
+						logger.Base().Error("Failed to decode Opus", zap.String("connection_id", connectionID), zap.Int("payload_bytes", len(opusPayload)), zap.Bool("lost", frame.Lost()), zap.Error(err))
 					}
-					// Note: LastActivity is updated when audio is successfully forwarded (below)
-					// No need to update here during suppression phase as greeting is short-lived
+					droppedPackets.Inc()
 					continue
 				}
 
-				// Always send all audio frames - let the model handle silence detection
-				modelClient := connection.GetAIWebRTC()
-				if modelClient == nil {
-					logger.Base().Warn("AI WebRTC client not available", zap.String("connection_id", connectionID))
-					continue
+				// Run the agent's DSP chain on every frame so its filters see continuous audio
+				if chain := connection.GetDSP(); chain != nil && len(pcmSamples) > 0 {
+					start := time.Now()
+					chain.Process(pcmSamples)
+					dspDuration.Observe(time.Since(start).Seconds())
 				}
-				if err := modelClient.SendAudio(pcmSamples); err != nil {
-					// If SendAudio fails, check if connection is closed
-					// If so, exit the loop instead of continuing
+
+				// Send PCM16 samples to the model if we got valid samples
+				if len(pcmSamples) > 0 {
+					// Check if connection is closed before processing audio
+					// This is critical because ReadRTP() may still return data from buffer
+					// even after the connection is closed
 					if connection.IsClosed() {
-						logger.Base().Error("🛑 Connection closed, SendAudio failed", zap.String("connection_id", connectionID))
+						logger.Base().Info("🛑 Connection closed, stopping audio forwarding", zap.String("connection_id", connectionID))
 						return
 					}
-					logger.Base().Error("SendAudio failed", zap.String("connection_id", connectionID), zap.Error(err))
-					// Continue processing even if SendAudio fails once
-					// (might be temporary network issue)
-				} else {
-					frameCount++
-					// Log successful decode occasionally
-					if frameCount%100 == 0 {
-						logger.Base().Info("Decoded Opus to PCM16", zap.String("connection_id", connectionID), zap.Int("pcm_samples", len(pcmSamples)), zap.Int("opus_bytes", len(opusPayload)))
+
+					// Cache RTP packet for audio storage; the recorder fills lost frames with silence
+					if audioCache != nil && needsCaching && !frame.Lost() {
+						audioCache.CacheAudioRTP(connectionID, storage.AudioTypeWhatsAppInput, storage.AudioFormatOpus, frame.Packet)
 					}
 
-					// Update LastActivity periodically to prevent connection timeout
-					// Update every ~1 second (50 frames * 20ms)
-					// Check connection state before updating to prevent updating after closure
-					if frameCount%50 == 0 {
-						if !connection.IsClosed() {
-							connection.UpdateLastActivity()
+					// Check if we should forward audio to the model
+					// If greeting hasn't been sent/completed yet, and connection is very new,
+					// suppress user audio to prevent interrupting the greeting
+					shouldForward, reason := connection.ShouldForwardAudioToAI()
+
+					if !shouldForward {
+						suppressionCount++
+						droppedPackets.Inc()
+						// Log suppression occasionally (every 100 suppressed frames)
+						if suppressionCount%100 == 0 {
+							logger.Base().Info("🤫 Suppressing user audio during greeting phase", zap.String("reason", reason), zap.String("connection_id", connectionID))
 						}
+						// Note: LastActivity is updated when audio is successfully forwarded (below)
+						// No need to update here during suppression phase as greeting is short-lived
+						continue
+					}
+
+					// Gate through the local VAD if the agent enables it; otherwise send every frame
+					// and let the model handle silence detection
+					toSend := [][]int16{pcmSamples}
+					if detector := connection.GetVAD(); detector != nil {
+						toSend = detector.Process(pcmSamples)
+						if len(toSend) == 0 {
+							gatedCount++
+							gatedPackets.Inc()
+							// Silence is not forwarded, so keep the connection alive here (~1s)
+							if gatedCount%50 == 0 && !connection.IsClosed() {
+								connection.UpdateLastActivity()
+							}
+							continue
+						}
+					}
+
+					modelClient := connection.GetAIWebRTC()
+					if modelClient == nil {
+						logger.Base().Warn("AI WebRTC client not available", zap.String("connection_id", connectionID))
+						droppedPackets.Inc()
+						continue
+					}
+					for _, samples := range toSend {
+						if err := modelClient.SendAudio(samples); err != nil {
+							droppedPackets.Inc()
+							// If SendAudio fails, check if connection is closed
+							// If so, exit the loop instead of continuing
+							if connection.IsClosed() {
+								logger.Base().Error("🛑 Connection closed, SendAudio failed", zap.String("connection_id", connectionID))
+								return
+							}
+							logger.Base().Error("SendAudio failed", zap.String("connection_id", connectionID), zap.Error(err))
+							// Continue processing even if SendAudio fails once
+							// (might be temporary network issue)
+							continue
+						}
+
+						frameCount++
+						forwardedPackets.Inc()
+						// Log successful decode occasionally
+						if frameCount%100 == 0 {
+							logger.Base().Info("Decoded Opus to PCM16", zap.String("connection_id", connectionID), zap.Int("pcm_samples", len(samples)), zap.Int("opus_bytes", len(opusPayload)))
+						}
+
+						// Update LastActivity periodically to prevent connection timeout
+						// Update every ~1 second (50 frames * 20ms)
+						// Check connection state before updating to prevent updating after closure
+						if frameCount%50 == 0 {
+							if !connection.IsClosed() {
+								connection.UpdateLastActivity()
+							}
+						}
+					}
+				} else {
+					if frame.SequenceNumber%100 == 0 {
+						logger.Base().Warn("Opus decode returned 0 samples", zap.String("connection_id", connectionID), zap.Int("opus_bytes", len(opusPayload)))
 					}
-				}
-			} else {
-				if rtpPacket.SequenceNumber%100 == 0 {
-					logger.Base().Warn("Opus decode returned 0 samples", zap.String("connection_id", connectionID), zap.Int("opus_bytes", len(opusPayload)))
 				}
 			}
 
@@ -0,0 +1,71 @@
+package webrtc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/pion/webrtc/v3"
+	"go.uber.org/zap"
+)
+
+// ErrICERestartUnsupported is returned for a trickle fragment carrying new ICE credentials
+var ErrICERestartUnsupported = errors.New("ice restart not supported")
+
+// ErrUnknownPeerConnection is returned when no peer connection exists for a connection ID
+var ErrUnknownPeerConnection = errors.New("peer connection not found")
+
+// AddICECandidates applies remote candidates from a trickle ICE SDP fragment
+// (application/trickle-ice-sdpfrag, RFC 8840) to the connection's peer connection
+func (p *Processor) AddICECandidates(connectionID, fragment string) error {
+	p.mutex.RLock()
+	pc, exists := p.peerConnections[connectionID]
+	p.mutex.RUnlock()
+	if !exists {
+		return ErrUnknownPeerConnection
+	}
+
+	remoteUfrag := ""
+	if remote := pc.RemoteDescription(); remote != nil {
+		remoteUfrag = sdpAttribute(remote.SDP, "ice-ufrag")
+	}
+
+	var mid string
+	added := 0
+	for _, line := range strings.Split(fragment, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "a=ice-ufrag:"):
+			if ufrag := strings.TrimPrefix(line, "a=ice-ufrag:"); remoteUfrag != "" && ufrag != remoteUfrag {
+				return ErrICERestartUnsupported
+			}
+		case strings.HasPrefix(line, "a=mid:"):
+			mid = strings.TrimPrefix(line, "a=mid:")
+		case strings.HasPrefix(line, "a=candidate:"):
+			candidate := webrtc.ICECandidateInit{Candidate: strings.TrimPrefix(line, "a=")}
+			if mid != "" {
+				sdpMid := mid
+				candidate.SDPMid = &sdpMid
+			}
+			if err := pc.AddICECandidate(candidate); err != nil {
+				return fmt.Errorf("failed to add ICE candidate: %w", err)
+			}
+			added++
+		}
+	}
+
+	logger.Base().Debug("Added trickled ICE candidates", zap.String("connection_id", connectionID), zap.Int("count", added))
+	return nil
+}
+
+// sdpAttribute returns the value of the first a=<name>: line of an SDP
+func sdpAttribute(sdp, name string) string {
+	prefix := "a=" + name + ":"
+	for _, line := range strings.Split(sdp, "\n") {
+		if line = strings.TrimSpace(line); strings.HasPrefix(line, prefix) {
+			return strings.TrimPrefix(line, prefix)
+		}
+	}
+	return ""
+}
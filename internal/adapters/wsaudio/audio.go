@@ -0,0 +1,254 @@
+package wsaudio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/internal/services/call"
+	"github.com/ClareAI/astra-voice-service/internal/storage"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/metrics"
+	"github.com/ClareAI/astra-voice-service/pkg/resample"
+	"github.com/pion/rtp"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"layeh.com/gopus"
+)
+
+const (
+	opusSampleRate = 48000
+	modelFrame     = 960 // 20ms at 48kHz
+
+	// recordingPayloadType is the Opus payload type of recorded caller audio
+	recordingPayloadType = 111
+)
+
+// newDecoder creates the Opus decoder for a session using FormatOpus
+func newDecoder(format string) (*gopus.Decoder, error) {
+	if format != FormatOpus {
+		return nil, nil
+	}
+	decoder, err := gopus.NewDecoder(opusSampleRate, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Opus decoder: %w", err)
+	}
+	return decoder, nil
+}
+
+// newResampler validates the session's format and creates the resampler between its
+// sample rate and the model's
+func newResampler(format string, sampleRate int, from, to int) (*resample.Resampler, error) {
+	switch format {
+	case FormatOpus:
+		return nil, nil
+	case FormatPCM16:
+	default:
+		return nil, fmt.Errorf("unsupported audio format %q", format)
+	}
+	resampler, err := resample.New(from, to, resample.Fast)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported sample rate %d: %w", sampleRate, err)
+	}
+	return resampler, nil
+}
+
+// callerAudio turns the client's binary frames into 48kHz PCM frames for the model.
+// WebSocket frames arrive in order, so no jitter buffer is needed.
+type callerAudio struct {
+	connection *call.WhatsAppCallConnection
+	decoder    *gopus.Decoder      // FormatOpus only
+	upsample   *resample.Resampler // FormatPCM16 only
+	pending    []int16             // 48kHz audio not yet making a full 20ms frame
+	samples    []int16
+
+	// Recording re-encodes the caller's audio as Opus so the recorder sees one format
+	audioCache *storage.AudioCacheService
+	recorder   *gopus.Encoder
+	sequence   uint16
+	timestamp  uint32
+
+	forwardedPackets prometheus.Counter
+	droppedPackets   prometheus.Counter
+	gatedPackets     prometheus.Counter
+	dspDuration      prometheus.Observer
+}
+
+func newCallerAudio(connection *call.WhatsAppCallConnection, format string, sampleRate int) (*callerAudio, error) {
+	upsample, err := newResampler(format, sampleRate, sampleRate, opusSampleRate)
+	if err != nil {
+		return nil, err
+	}
+	decoder, err := newDecoder(format)
+	if err != nil {
+		return nil, err
+	}
+	channelLabel := connection.GetChannelTypeString()
+	a := &callerAudio{
+		connection:       connection,
+		decoder:          decoder,
+		upsample:         upsample,
+		forwardedPackets: metrics.AudioPacketsTotal.WithLabelValues(channelLabel, metrics.DirectionInbound, metrics.AudioForwarded),
+		droppedPackets:   metrics.AudioPacketsTotal.WithLabelValues(channelLabel, metrics.DirectionInbound, metrics.AudioDropped),
+		gatedPackets:     metrics.AudioPacketsTotal.WithLabelValues(channelLabel, metrics.DirectionInbound, metrics.AudioGated),
+		dspDuration:      metrics.AudioDSPFrameDuration.WithLabelValues(channelLabel),
+	}
+	if audioCache := storage.GetAudioCache(); audioCache != nil && connection.NeedsAudioCaching() {
+		if a.recorder, err = gopus.NewEncoder(opusSampleRate, 1, gopus.Voip); err != nil {
+			return nil, fmt.Errorf("failed to create recording encoder: %w", err)
+		}
+		a.audioCache = audioCache
+	}
+	return a, nil
+}
+
+// handleFrame forwards a binary frame from the client to the model
+func (a *callerAudio) handleFrame(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	if a.decoder != nil {
+		pcm, err := a.decoder.Decode(data, modelFrame*2, false)
+		if err != nil {
+			a.droppedPackets.Inc()
+			return
+		}
+		a.pending = append(a.pending, pcm...)
+	} else {
+		// A trailing odd byte is not a whole sample and is dropped
+		a.samples = a.samples[:0]
+		for i := 0; i+1 < len(data); i += 2 {
+			a.samples = append(a.samples, int16(binary.LittleEndian.Uint16(data[i:])))
+		}
+		a.pending = append(a.pending, a.upsample.Process(a.samples)...)
+	}
+
+	for len(a.pending) >= modelFrame {
+		pcm := append([]int16(nil), a.pending[:modelFrame]...)
+		a.pending = a.pending[modelFrame:]
+		a.record(pcm)
+		a.forward(pcm)
+	}
+	a.connection.UpdateLastActivity()
+}
+
+// forward runs a frame through the agent's DSP and VAD and sends it to the model
+func (a *callerAudio) forward(pcm []int16) {
+	connection := a.connection
+
+	// Run the agent's DSP chain on every frame so its filters see continuous audio
+	if chain := connection.GetDSP(); chain != nil {
+		start := time.Now()
+		chain.Process(pcm)
+		a.dspDuration.Observe(time.Since(start).Seconds())
+	}
+
+	modelClient := connection.GetAIWebRTC()
+	if modelClient == nil {
+		// Model still connecting
+		return
+	}
+	if shouldForward, _ := connection.ShouldForwardAudioToAI(); !shouldForward {
+		a.droppedPackets.Inc()
+		return
+	}
+
+	toSend := [][]int16{pcm}
+	if detector := connection.GetVAD(); detector != nil {
+		if toSend = detector.Process(pcm); len(toSend) == 0 {
+			a.gatedPackets.Inc()
+			return
+		}
+	}
+	for _, samples := range toSend {
+		if err := modelClient.SendAudio(samples); err != nil {
+			a.droppedPackets.Inc()
+			continue
+		}
+		a.forwardedPackets.Inc()
+	}
+}
+
+// record caches a frame of caller audio for the call recording
+func (a *callerAudio) record(pcm []int16) {
+	if a.audioCache == nil {
+		return
+	}
+	payload, err := a.recorder.Encode(pcm, modelFrame, 4000)
+	if err != nil {
+		logger.Base().Debug("Failed to encode WebSocket audio for recording", zap.String("connection_id", a.connection.ID), zap.Error(err))
+		return
+	}
+	packet := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    recordingPayloadType,
+			SequenceNumber: a.sequence,
+			Timestamp:      a.timestamp,
+		},
+		Payload: payload,
+	}
+	a.sequence++
+	a.timestamp += modelFrame
+	a.audioCache.CacheAudioRTP(a.connection.ID, storage.AudioTypeWhatsAppInput, storage.AudioFormatOpus, packet)
+}
+
+// close releases the recording state of the caller's audio
+func (a *callerAudio) close() {
+	if a.audioCache != nil {
+		a.audioCache.CleanupConnection(a.connection.ID)
+	}
+}
+
+// agentAudio converts the model's 20ms Opus frames to the client's format. Opus passes
+// through untouched; PCM16 is decoded and resampled to the session's rate.
+type agentAudio struct {
+	sampleRate int
+	decoder    *gopus.Decoder
+	downsample *resample.Resampler
+	pending    []int16 // samples at the session's rate not yet filling a frame
+	frame      []byte
+}
+
+func newAgentAudio(format string, sampleRate int) (*agentAudio, error) {
+	downsample, err := newResampler(format, sampleRate, opusSampleRate, sampleRate)
+	if err != nil {
+		return nil, err
+	}
+	a := &agentAudio{sampleRate: sampleRate, downsample: downsample}
+	if format == FormatPCM16 {
+		if a.decoder, err = gopus.NewDecoder(opusSampleRate, 1); err != nil {
+			return nil, fmt.Errorf("failed to create Opus decoder: %w", err)
+		}
+	}
+	return a, nil
+}
+
+// encode returns the binary frame for a model Opus frame, or nil while the resampler is
+// still filling its first frame. The result is reused by the next call.
+func (a *agentAudio) encode(opusPayload []byte) ([]byte, error) {
+	if a.decoder == nil {
+		return opusPayload, nil
+	}
+	pcm, err := a.decoder.Decode(opusPayload, modelFrame*2, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode model audio: %w", err)
+	}
+	a.pending = append(a.pending, a.downsample.Process(pcm)...)
+
+	frameSamples := a.sampleRate / 50
+	if len(a.pending) < frameSamples {
+		return nil, nil
+	}
+	a.frame = a.frame[:0]
+	for _, sample := range a.pending[:frameSamples] {
+		a.frame = binary.LittleEndian.AppendUint16(a.frame, uint16(sample))
+	}
+	a.pending = a.pending[frameSamples:]
+	return a.frame, nil
+}
+
+// reset drops audio held back for the next frame, after a barge-in
+func (a *agentAudio) reset() {
+	a.pending = a.pending[:0]
+}
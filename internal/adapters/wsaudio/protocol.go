@@ -0,0 +1,62 @@
+package wsaudio
+
+// Path of the WebSocket audio endpoint
+const Path = "/ws/audio"
+
+// Control messages, sent as JSON text frames. Audio travels in binary frames.
+const (
+	// Client to server
+	MessageStart = "start" // authenticates and starts the call; must be the first message
+	MessageText  = "text"  // typed user input for the agent
+	MessageStop  = "stop"  // ends the call
+
+	// Server to client
+	MessageStarted = "started" // call accepted, audio may flow
+	MessageClear   = "clear"   // caller barged in; drop buffered agent audio
+	MessageError   = "error"
+	MessageStopped = "stopped" // call ended, the socket closes next
+)
+
+// Audio formats of binary frames
+const (
+	// FormatPCM16 is signed 16-bit little-endian mono PCM at the session's sample rate.
+	// Inbound frames may have any length; outbound frames hold 20ms.
+	FormatPCM16 = "pcm16"
+	// FormatOpus is one 48kHz mono Opus packet per frame
+	FormatOpus = "opus"
+)
+
+// DefaultSampleRate is the PCM16 sample rate when the client does not pick one
+const DefaultSampleRate = 16000
+
+// clientMessage is a control message from the client
+type clientMessage struct {
+	Type string `json:"type"`
+
+	// start
+	Token         string `json:"token,omitempty"` // agent JWT from /api/agents/{id}/jwt
+	CallID        string `json:"callId,omitempty"`
+	Format        string `json:"format,omitempty"`     // FormatPCM16 (default) or FormatOpus
+	SampleRate    int    `json:"sampleRate,omitempty"` // PCM16 only, e.g. 8000, 16000, 24000 or 48000
+	Language      string `json:"language,omitempty"`
+	Accent        string `json:"accent,omitempty"`
+	ContactName   string `json:"contactName,omitempty"`
+	From          string `json:"from,omitempty"`
+	ModelProvider string `json:"modelProvider,omitempty"`
+
+	Variables map[string]interface{} `json:"variables,omitempty"` // per-call variables declared by the agent
+
+	// text
+	Text string `json:"text,omitempty"`
+}
+
+// serverMessage is a control message to the client
+type serverMessage struct {
+	Type         string `json:"type"`
+	ConnectionID string `json:"connectionId,omitempty"`
+	CallID       string `json:"callId,omitempty"`
+	Format       string `json:"format,omitempty"`
+	SampleRate   int    `json:"sampleRate,omitempty"`
+	Message      string `json:"message,omitempty"` // error description
+	Reason       string `json:"reason,omitempty"`  // why the call stopped
+}
@@ -0,0 +1,364 @@
+package wsaudio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	httpadapter "github.com/ClareAI/astra-voice-service/internal/adapters/http"
+	"github.com/ClareAI/astra-voice-service/internal/config"
+	"github.com/ClareAI/astra-voice-service/internal/core/model/provider"
+	"github.com/ClareAI/astra-voice-service/internal/core/task"
+	"github.com/ClareAI/astra-voice-service/internal/domain"
+	"github.com/ClareAI/astra-voice-service/internal/repository"
+	"github.com/ClareAI/astra-voice-service/internal/services/agent"
+	"github.com/ClareAI/astra-voice-service/internal/services/call"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+const (
+	startTimeout = 10 * time.Second // the client must send start this soon after connecting
+	idleTimeout  = 60 * time.Second // end the call when the client sends nothing for this long
+	writeTimeout = 5 * time.Second
+)
+
+// Session is a call over a plain WebSocket, for clients that cannot do WebRTC (kiosks,
+// embedded devices). It registers a web channel connection like /wati/web-new-call and is
+// the connection's output track, sending model audio back in the client's format.
+type Session struct {
+	ws           *websocket.Conn
+	service      *call.WhatsAppCallService
+	repoManager  repository.RepositoryManager
+	taskBus      task.Bus
+	agentService *agent.AgentService
+
+	connection *call.WhatsAppCallConnection
+	inbound    *callerAudio
+
+	// Outbound state, guarded by writeMutex (a WebSocket has a single writer)
+	writeMutex sync.Mutex
+	output     *agentAudio
+	speaking   bool // agent audio was sent since the last clear
+
+	done    chan struct{}
+	endOnce sync.Once
+}
+
+// NewSession creates the call for an upgraded WebSocket
+func NewSession(ws *websocket.Conn, service *call.WhatsAppCallService, repoManager repository.RepositoryManager, taskBus task.Bus) *Session {
+	agentService, err := agent.GetAgentService()
+	if err != nil {
+		logger.Base().Error("Failed to get agent service")
+	}
+	return &Session{
+		ws:           ws,
+		service:      service,
+		repoManager:  repoManager,
+		taskBus:      taskBus,
+		agentService: agentService,
+		done:         make(chan struct{}),
+	}
+}
+
+// Run reads the client's messages until it stops the call or the call ends
+func (s *Session) Run() {
+	reason := "client disconnected"
+	defer func() {
+		s.end(reason)
+	}()
+
+	_ = s.ws.SetReadDeadline(time.Now().Add(startTimeout))
+	for {
+		messageType, data, err := s.ws.ReadMessage()
+		if err != nil {
+			closedByUs := s.connection != nil && s.connection.IsClosed()
+			if !closedByUs && !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				logger.Base().Warn("WebSocket audio read failed", zap.Error(err))
+			}
+			if closedByUs {
+				reason = "call ended"
+			}
+			return
+		}
+
+		if messageType == websocket.BinaryMessage {
+			if s.inbound == nil {
+				s.sendError("audio received before start")
+				continue
+			}
+			s.inbound.handleFrame(data)
+			_ = s.ws.SetReadDeadline(time.Now().Add(idleTimeout))
+			continue
+		}
+
+		var msg clientMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			s.sendError("invalid control message")
+			continue
+		}
+		_ = s.ws.SetReadDeadline(time.Now().Add(idleTimeout))
+
+		switch msg.Type {
+		case MessageStart:
+			if err := s.start(&msg); err != nil {
+				logger.Base().Warn("Rejecting WebSocket audio call", zap.Error(err))
+				s.sendError(err.Error())
+				reason = "rejected"
+				return
+			}
+		case MessageText:
+			s.handleText(msg.Text)
+		case MessageStop:
+			reason = "stopped by client"
+			return
+		default:
+			s.sendError(fmt.Sprintf("unknown message type %q", msg.Type))
+		}
+	}
+}
+
+// start authenticates the agent JWT and starts the call
+func (s *Session) start(msg *clientMessage) error {
+	if s.connection != nil {
+		return errors.New("call already started")
+	}
+	mapping, err := httpadapter.DecodeJWTFromAPIKey(msg.Token)
+	if err != nil {
+		return fmt.Errorf("invalid agent token: %w", err)
+	}
+	tenantID, agentID := mapping.TenantID, mapping.AgentID
+	if tenantID == "" {
+		tenantID = config.DefaultTenantID
+	}
+
+	format := msg.Format
+	if format == "" {
+		format = FormatPCM16
+	}
+	sampleRate := msg.SampleRate
+	if format == FormatOpus {
+		sampleRate = opusSampleRate
+	} else if sampleRate == 0 {
+		sampleRate = DefaultSampleRate
+	}
+
+	voiceLanguage := config.DefaultLanguage
+	var textAgentID string
+	var declaredVariables []config.CallVariable
+	if s.agentService != nil {
+		// Usage gating: check tenant allowance before proceeding
+		if tenantID != config.DefaultTenantID && tenantID != config.DefaultWatiTenantID {
+			if allowed, reason := s.agentService.CheckTenantUsageAllowed(context.Background(), tenantID); !allowed {
+				return fmt.Errorf("usage not allowed: %s", reason)
+			}
+		}
+		agentConfig, err := s.agentService.GetAgentConfigWithChannelType(context.Background(), agentID, domain.ChannelTypeWeb)
+		if err != nil || agentConfig == nil {
+			return fmt.Errorf("agent %s not found", agentID)
+		}
+		agentID = agentConfig.ID
+		textAgentID = agentConfig.TextAgentID
+		declaredVariables = agentConfig.CallVariables(false)
+		if agentConfig.Language != "" {
+			voiceLanguage = agentConfig.Language
+		}
+	}
+	variables, err := config.ResolveCallVariables(declaredVariables, msg.Variables)
+	if err != nil {
+		return err
+	}
+	if msg.Language != "" {
+		voiceLanguage = msg.Language
+	}
+	modelProvider := provider.ProviderTypeOpenAI
+	if msg.ModelProvider == string(provider.ProviderTypeGemini) {
+		modelProvider = provider.ProviderTypeGemini
+	}
+
+	callID := msg.CallID
+	if callID == "" {
+		callID = fmt.Sprintf("ws-%d", time.Now().UnixNano())
+	}
+	connection := &call.WhatsAppCallConnection{
+		ID:            fmt.Sprintf("%s_%s_%d", domain.ChannelTypeWeb, callID, time.Now().UnixNano()),
+		CallID:        callID,
+		From:          msg.From,
+		CreatedAt:     time.Now(),
+		LastActivity:  time.Now(),
+		IsActive:      true,
+		ChannelType:   domain.ChannelTypeWeb,
+		StopKeepalive: make(chan struct{}),
+		TenantID:      tenantID,
+		AgentID:       agentID,
+		TextAgentID:   textAgentID,
+		VoiceLanguage: voiceLanguage,
+		Accent:        msg.Accent,
+		ContactName:   msg.ContactName,
+		Variables:     variables,
+		RepoManager:   s.repoManager,
+		ModelProvider: modelProvider,
+		WAOutputTrack: s,
+	}
+
+	inbound, err := newCallerAudio(connection, format, sampleRate)
+	if err != nil {
+		return err
+	}
+	output, err := newAgentAudio(format, sampleRate)
+	if err != nil {
+		return err
+	}
+	s.writeMutex.Lock()
+	s.output = output
+	s.writeMutex.Unlock()
+	s.connection, s.inbound = connection, inbound
+
+	s.service.AddConnection(connection)
+	if err := connection.InitializeVoiceConversation(); err != nil {
+		logger.Base().Warn("Failed to initialize voice conversation", zap.String("connection_id", connection.ID), zap.Error(err))
+		// Continue anyway, AddMessage will create it as fallback
+	}
+
+	logger.Base().Info("WebSocket audio call started",
+		zap.String("connection_id", connection.ID),
+		zap.String("tenant_id", tenantID),
+		zap.String("agent_id", agentID),
+		zap.String("format", format),
+		zap.Int("sample_rate", sampleRate))
+
+	// Same path as WebRTC web calls; the owning pod brings up the model
+	if s.taskBus != nil {
+		msg.Token = ""
+		payload, _ := json.Marshal(msg)
+		if err := s.taskBus.Publish(context.Background(), task.SessionTask{
+			Type:         task.TaskTypeWebCall,
+			ConnectionID: connection.ID,
+			Payload:      payload,
+		}); err != nil {
+			logger.Base().Error("Failed to publish web-call task", zap.String("connection_id", connection.ID), zap.Error(err))
+		}
+	} else {
+		connection.HasInboundAudio = true
+		go s.service.InitializeAIConnection(connection)
+	}
+	go s.watch(connection)
+
+	return s.send(serverMessage{
+		Type:         MessageStarted,
+		ConnectionID: connection.ID,
+		CallID:       callID,
+		Format:       format,
+		SampleRate:   sampleRate,
+	})
+}
+
+// handleText passes typed input to the agent
+func (s *Session) handleText(text string) {
+	if s.connection == nil {
+		s.sendError("text received before start")
+		return
+	}
+	if text == "" {
+		return
+	}
+	if err := s.connection.InjectUserText(text); err != nil {
+		logger.Base().Warn("Failed to inject text", zap.String("connection_id", s.connection.ID), zap.Error(err))
+		s.sendError("agent not ready")
+	}
+}
+
+// watch closes the socket when the call ends on our side (cleanup by the agent or
+// inactivity), which unblocks Run
+func (s *Session) watch(connection *call.WhatsAppCallConnection) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			if connection.IsClosed() {
+				logger.Base().Info("Connection closed, ending WebSocket audio call", zap.String("connection_id", connection.ID))
+				_ = s.send(serverMessage{Type: MessageStopped, Reason: "call ended"})
+				s.ws.Close()
+				return
+			}
+		}
+	}
+}
+
+// WriteOpusFrame sends a 20ms frame of model audio to the client in its format
+func (s *Session) WriteOpusFrame(opusPayload []byte) error {
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	if s.output == nil {
+		return errors.New("websocket call not started")
+	}
+	frame, err := s.output.encode(opusPayload)
+	if err != nil || len(frame) == 0 {
+		return err
+	}
+	s.speaking = true
+	_ = s.ws.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err := s.ws.WriteMessage(websocket.BinaryMessage, frame); err != nil {
+		return fmt.Errorf("failed to write audio frame: %w", err)
+	}
+	return nil
+}
+
+// ClearOutput tells the client to drop agent audio it has buffered, on barge-in
+func (s *Session) ClearOutput() error {
+	s.writeMutex.Lock()
+	speaking := s.speaking
+	s.speaking = false
+	if s.output != nil {
+		s.output.reset()
+	}
+	s.writeMutex.Unlock()
+
+	if !speaking {
+		return nil
+	}
+	return s.send(serverMessage{Type: MessageClear})
+}
+
+// sendError reports a problem to the client
+func (s *Session) sendError(message string) {
+	if err := s.send(serverMessage{Type: MessageError, Message: message}); err != nil {
+		logger.Base().Debug("Failed to send WebSocket error", zap.Error(err))
+	}
+}
+
+// send writes a control message
+func (s *Session) send(msg serverMessage) error {
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+	_ = s.ws.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return s.ws.WriteJSON(msg)
+}
+
+// end closes the socket and releases the call's connection
+func (s *Session) end(reason string) {
+	s.endOnce.Do(func() {
+		close(s.done)
+		if s.connection != nil && !s.connection.IsClosed() {
+			_ = s.send(serverMessage{Type: MessageStopped, Reason: reason})
+		}
+		s.ws.Close()
+		if s.inbound != nil {
+			s.inbound.close()
+		}
+		if s.connection != nil {
+			logger.Base().Info("WebSocket audio call ended", zap.String("connection_id", s.connection.ID), zap.String("reason", reason))
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			s.service.NotifyCleanup(ctx, s.connection.ID)
+		}
+	})
+}
@@ -2,9 +2,12 @@ package config
 
 import (
 	"os"
+	"strings"
 	"time"
 
 	"github.com/ClareAI/astra-voice-service/pkg/data/mcp"
+	"github.com/ClareAI/astra-voice-service/pkg/dsp"
+	"github.com/ClareAI/astra-voice-service/pkg/vad"
 )
 
 // DefaultTenantID falls back to "wati" but can be overridden via DEFAULT_ASTRA_TENANT_ID env var.
@@ -89,8 +92,11 @@ type AgentConfig struct {
 	Speed float64 `json:"speed" db:"speed"` // Speech speed: 0.25 to 4.0 (default: 1.0)
 
 	// Call Configuration
-	MaxCallDuration int            `json:"max_call_duration" db:"max_call_duration"` // in seconds
-	SilenceConfig   *SilenceConfig `json:"silence_config" db:"silence_config"`
+	MaxCallDuration int             `json:"max_call_duration" db:"max_call_duration"` // in seconds
+	SilenceConfig   *SilenceConfig  `json:"silence_config" db:"silence_config"`
+	VADConfig       *VADConfig      `json:"vad_config,omitempty" db:"vad_config"` // nil or disabled forwards all caller audio
+	DSPConfig       *DSPConfig      `json:"dsp_config,omitempty" db:"dsp_config"` // nil falls back to the tenant setting
+	AudioCues       *AudioCueConfig `json:"audio_cues,omitempty" db:"audio_cues"` // nil plays the bundled hold music
 
 	// Prompt Configuration
 	PromptConfig *PromptConfig `json:"prompt_config"`
@@ -135,6 +141,266 @@ func (s *SilenceConfig) SetDefaults() {
 	}
 }
 
+// VADConfig controls local voice activity detection, which gates caller audio before it
+// reaches the model. Zero values take the detector defaults.
+type VADConfig struct {
+	Enabled             bool    `json:"enabled" db:"enabled"`
+	EnergyThresholdDb   float64 `json:"energy_threshold_db" db:"energy_threshold_db"`     // minimum level in dBFS, e.g. -50
+	NoiseMarginDb       float64 `json:"noise_margin_db" db:"noise_margin_db"`             // required dB above the noise floor
+	MaxSpectralFlatness float64 `json:"max_spectral_flatness" db:"max_spectral_flatness"` // 0 (tonal) .. 1 (noise)
+	MaxZeroCrossingRate float64 `json:"max_zero_crossing_rate" db:"max_zero_crossing_rate"`
+	OnsetMs             int     `json:"onset_ms" db:"onset_ms"`       // speech needed before audio is forwarded
+	HangoverMs          int     `json:"hangover_ms" db:"hangover_ms"` // keep forwarding this long after speech ends
+	PreRollMs           int     `json:"pre_roll_ms" db:"pre_roll_ms"` // audio sent ahead of the detected onset
+}
+
+// SetDefaults fills missing VADConfig fields with the detector defaults.
+func (v *VADConfig) SetDefaults() {
+	if v == nil {
+		return
+	}
+	def := vad.DefaultConfig()
+	if v.EnergyThresholdDb == 0 {
+		v.EnergyThresholdDb = def.EnergyThresholdDb
+	}
+	if v.NoiseMarginDb <= 0 {
+		v.NoiseMarginDb = def.NoiseMarginDb
+	}
+	if v.MaxSpectralFlatness <= 0 {
+		v.MaxSpectralFlatness = def.MaxSpectralFlatness
+	}
+	if v.MaxZeroCrossingRate <= 0 {
+		v.MaxZeroCrossingRate = def.MaxZeroCrossingRate
+	}
+	if v.OnsetMs <= 0 {
+		v.OnsetMs = def.OnsetMs
+	}
+	if v.HangoverMs <= 0 {
+		v.HangoverMs = def.HangoverMs
+	}
+	if v.PreRollMs <= 0 {
+		v.PreRollMs = def.PreRollMs
+	}
+}
+
+// NewDetector creates a detector for audio at the given sample rate, or nil if VAD is disabled
+func (v *VADConfig) NewDetector(sampleRate int) *vad.Detector {
+	if v == nil || !v.Enabled {
+		return nil
+	}
+	return vad.New(vad.Config{
+		SampleRate:          sampleRate,
+		EnergyThresholdDb:   v.EnergyThresholdDb,
+		NoiseMarginDb:       v.NoiseMarginDb,
+		MaxSpectralFlatness: v.MaxSpectralFlatness,
+		MaxZeroCrossingRate: v.MaxZeroCrossingRate,
+		OnsetMs:             v.OnsetMs,
+		HangoverMs:          v.HangoverMs,
+		PreRollMs:           v.PreRollMs,
+	})
+}
+
+// DSPConfig controls the inbound audio processing chain applied to caller audio before
+// it reaches the model. Stage toggles left unset are enabled; zero values take the defaults.
+type DSPConfig struct {
+	Enabled          bool    `json:"enabled" db:"enabled"`
+	HighPassHz       float64 `json:"high_pass_hz" db:"high_pass_hz"` // negative disables the high-pass filter
+	NoiseSuppression *bool   `json:"noise_suppression" db:"noise_suppression"`
+	SuppressionDb    float64 `json:"suppression_db" db:"suppression_db"`
+	AGC              *bool   `json:"agc" db:"agc"`
+	TargetLevelDb    float64 `json:"target_level_db" db:"target_level_db"` // dBFS, e.g. -20
+	MaxGainDb        float64 `json:"max_gain_db" db:"max_gain_db"`
+	Limiter          *bool   `json:"limiter" db:"limiter"`
+}
+
+// SetDefaults fills missing DSPConfig fields with the chain defaults.
+func (d *DSPConfig) SetDefaults() {
+	if d == nil {
+		return
+	}
+	def := dsp.DefaultConfig()
+	if d.HighPassHz == 0 {
+		d.HighPassHz = def.HighPassHz
+	}
+	if d.NoiseSuppression == nil {
+		d.NoiseSuppression = &def.NoiseSuppression
+	}
+	if d.SuppressionDb <= 0 {
+		d.SuppressionDb = def.SuppressionDb
+	}
+	if d.AGC == nil {
+		d.AGC = &def.AGC
+	}
+	if d.TargetLevelDb == 0 {
+		d.TargetLevelDb = def.TargetLevelDb
+	}
+	if d.MaxGainDb <= 0 {
+		d.MaxGainDb = def.MaxGainDb
+	}
+	if d.Limiter == nil {
+		d.Limiter = &def.Limiter
+	}
+}
+
+// NewChain creates a processing chain for audio at the given sample rate, or nil if DSP is disabled
+func (d *DSPConfig) NewChain(sampleRate int) *dsp.Chain {
+	if d == nil || !d.Enabled {
+		return nil
+	}
+	// Agent configs are shared through the cache, so defaults are applied to a copy
+	cfg := *d
+	cfg.SetDefaults()
+	chain := dsp.New(dsp.Config{
+		SampleRate:       sampleRate,
+		HighPassHz:       max(cfg.HighPassHz, 0),
+		NoiseSuppression: *cfg.NoiseSuppression,
+		SuppressionDb:    cfg.SuppressionDb,
+		AGC:              *cfg.AGC,
+		TargetLevelDb:    cfg.TargetLevelDb,
+		MaxGainDb:        cfg.MaxGainDb,
+		Limiter:          *cfg.Limiter,
+	})
+	if chain.Empty() {
+		return nil
+	}
+	return chain
+}
+
+// Cue modes select what the caller hears while a tool runs
+const (
+	CueModeSilence = "silence" // nothing
+	CueModeBGM     = "bgm"     // hold music, looped
+	CueModeFiller  = "filler"  // a spoken filler, played once
+)
+
+const (
+	// DefaultCueSilenceThresholdMs is how long the model must be silent before a cue starts
+	DefaultCueSilenceThresholdMs = 1000
+	// DefaultAmbienceGainDb keeps background ambience well below speech
+	DefaultAmbienceGainDb = -18
+)
+
+// AudioCueConfig selects the audio played to the caller while tools run. Cue names refer
+// to audio uploaded for the agent (see pkg/audiocue).
+type AudioCueConfig struct {
+	HoldMusic          string                   `json:"hold_music" db:"hold_music"` // empty plays the bundled cue
+	SilenceThresholdMs int                      `json:"silence_threshold_ms" db:"silence_threshold_ms"`
+	DefaultMode        string                   `json:"default_mode" db:"default_mode"`
+	Fillers            map[string]string        `json:"fillers" db:"fillers"` // language code (or "default") -> cue name
+	Tools              map[string]ToolCueConfig `json:"tools" db:"tools"`     // tool name -> cue behaviour
+
+	// Mixing: with Mix set (or an ambience), output runs through pkg/mixer so cues and
+	// ambience play under the model's speech, ducked, instead of alternating with it
+	Mix             bool    `json:"mix" db:"mix"`
+	Ambience        string  `json:"ambience" db:"ambience"`                 // cue looped for the whole call
+	AmbienceGainDb  float64 `json:"ambience_gain_db" db:"ambience_gain_db"` // e.g. -18
+	HoldMusicGainDb float64 `json:"hold_music_gain_db" db:"hold_music_gain_db"`
+	DuckingDb       float64 `json:"ducking_db" db:"ducking_db"` // attenuation of cues and ambience under speech
+
+	// Owner of the uploaded cues, set when the config is loaded
+	TenantID string `json:"-" db:"-"`
+	AgentID  string `json:"-" db:"-"`
+}
+
+// ToolCueConfig overrides the cue behaviour for a single tool
+type ToolCueConfig struct {
+	Mode string `json:"mode" db:"mode"`
+	Cue  string `json:"cue" db:"cue"` // overrides the hold music or filler
+}
+
+// SetDefaults fills missing AudioCueConfig fields with defaults.
+func (a *AudioCueConfig) SetDefaults() {
+	if a == nil {
+		return
+	}
+	if a.DefaultMode == "" {
+		a.DefaultMode = CueModeBGM
+	}
+	if a.SilenceThresholdMs <= 0 {
+		a.SilenceThresholdMs = DefaultCueSilenceThresholdMs
+	}
+	if a.AmbienceGainDb == 0 {
+		a.AmbienceGainDb = DefaultAmbienceGainDb
+	}
+}
+
+// MixingEnabled reports whether output audio runs through the mixer
+func (a *AudioCueConfig) MixingEnabled() bool {
+	return a != nil && (a.Mix || a.Ambience != "")
+}
+
+// ToolCue returns the cue mode and cue name for a call to toolName while the conversation
+// is in language. An empty bgm cue means the bundled hold music; a filler without an
+// uploaded cue for the language falls back to silence.
+func (a *AudioCueConfig) ToolCue(toolName, language string) (mode, cue string) {
+	if a == nil {
+		return CueModeBGM, ""
+	}
+	override := a.Tools[toolName]
+	mode = override.Mode
+	if mode == "" {
+		mode = a.DefaultMode
+	}
+
+	switch mode {
+	case CueModeBGM:
+		cue = override.Cue
+		if cue == "" {
+			cue = a.HoldMusic
+		}
+	case CueModeFiller:
+		cue = override.Cue
+		if cue == "" {
+			cue = a.filler(language)
+		}
+		if cue == "" {
+			return CueModeSilence, ""
+		}
+	default:
+		return CueModeSilence, ""
+	}
+	return mode, cue
+}
+
+// CueNames returns every uploaded cue the config refers to
+func (a *AudioCueConfig) CueNames() []string {
+	if a == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	add(a.HoldMusic)
+	add(a.Ambience)
+	for _, name := range a.Fillers {
+		add(name)
+	}
+	for _, tool := range a.Tools {
+		add(tool.Cue)
+	}
+	return names
+}
+
+// filler picks the filler for a language, trying the exact code, its base language
+// ("es" for "es-MX") and then "default"
+func (a *AudioCueConfig) filler(language string) string {
+	language = strings.ToLower(language)
+	if cue, ok := a.Fillers[language]; ok {
+		return cue
+	}
+	if base, _, found := strings.Cut(language, "-"); found {
+		if cue, ok := a.Fillers[base]; ok {
+			return cue
+		}
+	}
+	return a.Fillers["default"]
+}
+
 // PromptConfig contains all prompt-related configuration for an agent
 type PromptConfig struct {
 	GreetingTemplate     string            `json:"greeting_template" db:"greeting_template"`
@@ -145,9 +411,16 @@ type PromptConfig struct {
 	LanguageInstructions map[string]string `json:"language_instructions" db:"language_instructions"` // Accent configuration per language, e.g., {"en": "india", "zh": "mainland"}
 	CustomVariables      map[string]string `json:"custom_variables" db:"custom_variables"`
 
+	// Per-call variables callers may pass when starting a call, rendered as {{.Variables.name}}
+	CallVariables []CallVariable `json:"call_variables,omitempty"`
+
 	// Language & Accent Adaptation Settings (default: true)
 	AutoLanguageSwitching *bool `json:"auto_language_switching,omitempty"` // Enable automatic language switching based on caller's language
 	AutoAccentAdaptation  *bool `json:"auto_accent_adaptation,omitempty"`  // Enable automatic accent adaptation based on caller's region
+
+	// Play a pre-rendered greeting instead of waiting for the model to speak it. Only
+	// greetings that do not use the contact's name or number are rendered.
+	CacheGreeting bool `json:"cache_greeting,omitempty"`
 }
 
 // RAGConfig contains RAG-specific configuration for each agent
@@ -221,6 +494,7 @@ type AgentRepository interface {
 type PromptGenerator interface {
 	GenerateGreetingInstruction(contactName, contactNumber, language, accent string) string
 	GenerateOutboundGreeting(contactName, contactNumber, language, accent string) string // Self-introduction greeting for outbound calls
+	GenerateGreetingScript(contactName, contactNumber string, isOutbound bool) string    // Rendered greeting template, "" if none
 	GenerateRealtimeInstruction(contactNumber string) string
 	GenerateSessionInstructions(contactNumber, language, accent string, isOutbound bool) string // Complete session-level instructions (RealtimeTemplate, language, accent, contact, phone rules, functions)
 	GenerateAccentInstruction() string
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Call variable types
+const (
+	CallVariableTypeString   = "string"
+	CallVariableTypeNumber   = "number"
+	CallVariableTypeBoolean  = "boolean"
+	CallVariableTypeDate     = "date"     // 2006-01-02
+	CallVariableTypeDateTime = "datetime" // RFC 3339
+)
+
+const (
+	// maxCallVariables bounds how many variables one call may carry
+	maxCallVariables = 50
+
+	// maxCallVariableLength bounds a variable's value, as it is added to the prompt
+	maxCallVariableLength = 1000
+)
+
+// ErrInvalidCallVariables wraps validation failures of per-call variables
+var ErrInvalidCallVariables = errors.New("invalid call variables")
+
+// CallVariable declares a per-call variable, e.g. an order ID or appointment time, that
+// callers pass when starting a call
+type CallVariable struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type,omitempty"` // CallVariableType*, defaults to string
+	Required    bool     `json:"required,omitempty"`
+	Description string   `json:"description,omitempty"` // Shown to the model next to the value
+	Enum        []string `json:"enum,omitempty"`        // Allowed values
+}
+
+// CallVariables returns the per-call variables declared for inbound or outbound calls
+func (a *AgentConfig) CallVariables(isOutbound bool) []CallVariable {
+	promptConfig := a.PromptConfig
+	if isOutbound && a.OutboundPromptConfig != nil {
+		promptConfig = a.OutboundPromptConfig
+	}
+	if promptConfig == nil {
+		return nil
+	}
+	return promptConfig.CallVariables
+}
+
+// ResolveCallVariables validates per-call variables against the declared ones and returns
+// them as strings for prompt rendering. Declared variables reject unknown names; without
+// declarations any scalar values are accepted.
+func ResolveCallVariables(declared []CallVariable, values map[string]interface{}) (map[string]string, error) {
+	if len(values) > maxCallVariables {
+		return nil, fmt.Errorf("%w: at most %d variables are allowed", ErrInvalidCallVariables, maxCallVariables)
+	}
+
+	specs := make(map[string]CallVariable, len(declared))
+	for _, spec := range declared {
+		specs[spec.Name] = spec
+	}
+
+	var problems []string
+	resolved := make(map[string]string, len(values))
+	for name, value := range values {
+		spec, ok := specs[name]
+		if !ok {
+			if len(declared) > 0 {
+				problems = append(problems, fmt.Sprintf("%s is not declared by the agent", name))
+				continue
+			}
+			spec = CallVariable{Name: name}
+		}
+		if isBlankCallVariable(value) {
+			continue
+		}
+
+		str, err := formatCallVariable(spec, value)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s %v", name, err))
+			continue
+		}
+		if len(str) > maxCallVariableLength {
+			problems = append(problems, fmt.Sprintf("%s exceeds %d characters", name, maxCallVariableLength))
+			continue
+		}
+		if len(spec.Enum) > 0 && !containsString(spec.Enum, str) {
+			problems = append(problems, fmt.Sprintf("%s must be one of %s", name, strings.Join(spec.Enum, ", ")))
+			continue
+		}
+		resolved[name] = str
+	}
+
+	for _, spec := range declared {
+		if spec.Required && isBlankCallVariable(values[spec.Name]) {
+			problems = append(problems, fmt.Sprintf("%s is required", spec.Name))
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCallVariables, strings.Join(problems, "; "))
+	}
+	return resolved, nil
+}
+
+// formatCallVariable checks a value against its declared type and formats it as a string.
+// Values may also be given as strings, as they are in CSV uploads.
+func formatCallVariable(spec CallVariable, value interface{}) (string, error) {
+	switch spec.Type {
+	case "", CallVariableTypeString:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		case bool:
+			return strconv.FormatBool(v), nil
+		}
+		return "", fmt.Errorf("must be a string")
+
+	case CallVariableTypeNumber:
+		switch v := value.(type) {
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		case string:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				return strconv.FormatFloat(f, 'f', -1, 64), nil
+			}
+		}
+		return "", fmt.Errorf("must be a number")
+
+	case CallVariableTypeBoolean:
+		switch v := value.(type) {
+		case bool:
+			return strconv.FormatBool(v), nil
+		case string:
+			if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+				return strconv.FormatBool(b), nil
+			}
+		}
+		return "", fmt.Errorf("must be a boolean")
+
+	case CallVariableTypeDate, CallVariableTypeDateTime:
+		layout := time.DateOnly
+		if spec.Type == CallVariableTypeDateTime {
+			layout = time.RFC3339
+		}
+		if v, ok := value.(string); ok {
+			if _, err := time.Parse(layout, strings.TrimSpace(v)); err == nil {
+				return strings.TrimSpace(v), nil
+			}
+		}
+		return "", fmt.Errorf("must be a %s in %s format", spec.Type, layout)
+	}
+	return "", fmt.Errorf("has unsupported type %q", spec.Type)
+}
+
+// isBlankCallVariable reports whether a variable was left out; CSV uploads leave empty cells
+func isBlankCallVariable(value interface{}) bool {
+	return value == nil || value == ""
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -13,6 +13,9 @@ type WhatsAppCallConfig struct {
 	GeminiBaseURL string
 	GeminiModel   string
 
+	// Chat model for text-mode agent sessions (OpenAI-compatible, reached at OpenAIBaseURL)
+	TextModel string
+
 	// WebRTC configuration
 	STUNServers []string
 
@@ -39,8 +42,16 @@ type WhatsAppCallConfig struct {
 
 	// Audio Storage configuration
 	AudioStorageEnabled bool
-	AudioStorageType    string // "local" or "gcs"
-	AudioStoragePath    string // Local directory path or GCS bucket name (based on AudioStorageType)
+	AudioStorageType    string // "local", "gcs" or "s3"
+	AudioStoragePath    string // Local directory path or GCS/S3 bucket name (based on AudioStorageType)
+	AudioSpoolPath      string // Local directory for in-progress recordings, kept across restarts for recovery
+
+	// S3-compatible object storage (AudioStorageType "s3")
+	S3Endpoint        string // Empty for AWS S3, e.g. http://minio:9000 for MinIO
+	S3Region          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3ForcePathStyle  bool // Path-style bucket addressing, required by MinIO
 
 	// LiveKit configuration (NEW - for LiveKit integration)
 	LiveKitEnabled   bool   // Whether LiveKit integration is enabled
@@ -48,4 +59,40 @@ type WhatsAppCallConfig struct {
 	LiveKitAPIKey    string // LiveKit API key
 	LiveKitAPISecret string // LiveKit API secret
 	LiveKitGCSBucket string // GCS bucket for egress recordings (GKE auto-configured)
+
+	LiveKitRecordingMode string // "in_process" (default), "egress", "both" or "off"
+
+	// SIP trunk configuration
+	SIPEnabled       bool
+	SIPListenAddr    string // Signalling address, e.g. ":5060"
+	SIPTransport     string // "udp", "tcp" or "both"
+	SIPPublicHost    string // Address advertised in SIP and SDP (detected when empty)
+	SIPRTPPortMin    int
+	SIPRTPPortMax    int
+	SIPRegistrar     string // Carrier registrar host[:port]; empty for IP-authenticated trunks
+	SIPUsername      string
+	SIPPassword      string
+	SIPDomain        string
+	SIPAgentID       string // Agent answering SIP calls
+	SIPTenantID      string
+	SIPVoiceLanguage string
+	SIPRoutes        string // Per-number agents: "number=agentID,number=agentID"
+	SIPCodecs        string // Codec preference, e.g. "opus,pcmu,pcma"
+	SIPTrustedCIDRs  string // Sources allowed to send SIP requests, e.g. "203.0.113.0/24,198.51.100.7"
+
+	// Twilio Media Streams channel (signatures are checked with TwilioAuthToken)
+	TwilioMediaStreamsEnabled bool
+	TwilioPublicURL           string // Public base URL Twilio reaches us on, e.g. "https://voice.example.com"
+	TwilioAgentID             string // Agent answering Twilio calls
+	TwilioTenantID            string
+	TwilioVoiceLanguage       string
+	TwilioRoutes              string // Per-number agents: "number=agentID,number=agentID"
+
+	// Outbound campaigns and call backs (the workers may run on every pod)
+	CampaignWorkerEnabled bool
+	CallbackWorkerEnabled bool
+
+	// Tracing configuration
+	TracingExporter    string  // "otlp", "stdout" or "none"; OTLP endpoint comes from OTEL_EXPORTER_OTLP_* env vars
+	TracingSampleRatio float64 // Fraction of calls to trace (0 or 1 = all)
 }
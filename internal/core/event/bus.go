@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/metrics"
+	"github.com/ClareAI/astra-voice-service/pkg/tracing"
 	"go.uber.org/zap"
 )
 
@@ -91,6 +93,13 @@ func (b *DefaultEventBus) PublishEvent(event *ConnectionEvent) error {
 	default:
 	}
 
+	metrics.EventsPublishedTotal.WithLabelValues(string(event.Type)).Inc()
+
+	// Default to the call trace so handlers join the connection's trace
+	if event.TraceContext == nil && event.ConnectionID != "" {
+		event.TraceContext = tracing.Inject(tracing.CallContext(event.ConnectionID))
+	}
+
 	b.mutex.RLock()
 	handlers, exists := b.subscribers[event.Type]
 	if !exists {
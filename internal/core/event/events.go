@@ -1,7 +1,10 @@
 package event
 
 import (
+	"context"
 	"time"
+
+	"github.com/ClareAI/astra-voice-service/pkg/tracing"
 )
 
 // EventType represents the type of event
@@ -45,6 +48,9 @@ type ConnectionEvent struct {
 	Timestamp    time.Time   `json:"timestamp"`
 	Data         interface{} `json:"data,omitempty"`
 	Error        error       `json:"error,omitempty"`
+
+	// TraceContext carries the W3C trace context of the originating call
+	TraceContext map[string]string `json:"trace_context,omitempty"`
 }
 
 // WebRTCEventData contains WebRTC-specific event data
@@ -111,6 +117,17 @@ func (e *ConnectionEvent) WithError(err error) *ConnectionEvent {
 	return e
 }
 
+// WithTraceContext attaches the trace context of ctx to the event
+func (e *ConnectionEvent) WithTraceContext(ctx context.Context) *ConnectionEvent {
+	e.TraceContext = tracing.Inject(ctx)
+	return e
+}
+
+// Context returns a context carrying the event's trace context
+func (e *ConnectionEvent) Context() context.Context {
+	return tracing.Extract(context.Background(), e.TraceContext)
+}
+
 // IsError returns true if the event contains an error
 func (e *ConnectionEvent) IsError() bool {
 	return e.Error != nil
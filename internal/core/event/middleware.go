@@ -5,6 +5,9 @@ import (
 	"time"
 
 	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/metrics"
+	"github.com/ClareAI/astra-voice-service/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
@@ -27,21 +30,43 @@ func LoggingMiddleware(next EventHandler) EventHandler {
 	}
 }
 
-// MetricsMiddleware provides metrics collection for events
+// MetricsMiddleware records handler counts and latency in Prometheus. Handlers report no
+// errors, so a handler fails when it panics; error events handled normally count as ok.
 func MetricsMiddleware(next EventHandler) EventHandler {
 	return func(event *ConnectionEvent) {
 		start := time.Now()
 
 		defer func() {
 			duration := time.Since(start)
+			eventType := string(event.Type)
+			metrics.EventHandlerDuration.WithLabelValues(eventType).Observe(duration.Seconds())
 
-			// Here you would typically send metrics to your monitoring system
-			// For now, we'll just log the metrics
-			logger.Base().Info("Event metrics", zap.String("type", string(event.Type)), zap.String("connection_id", event.ConnectionID), zap.Duration("duration", duration))
+			logger.Base().Debug("Event metrics", zap.String("type", eventType), zap.String("connection_id", event.ConnectionID), zap.Duration("duration", duration))
 			if r := recover(); r != nil {
-				logger.Base().Error("Event handler panic", zap.String("type", string(event.Type)), zap.String("connection_id", event.ConnectionID), zap.Any("panic", r))
+				metrics.EventHandlersTotal.WithLabelValues(eventType, metrics.HandlerPanic).Inc()
+				logger.Base().Error("Event handler panic", zap.String("type", eventType), zap.String("connection_id", event.ConnectionID), zap.Any("panic", r))
 				panic(r) // Re-panic to maintain the panic behavior
 			}
+			metrics.EventHandlersTotal.WithLabelValues(eventType, metrics.HandlerOK).Inc()
+		}()
+
+		next(event)
+	}
+}
+
+// TracingMiddleware records a span per handler execution under the event's trace context
+func TracingMiddleware(next EventHandler) EventHandler {
+	return func(event *ConnectionEvent) {
+		_, span := tracing.Start(event.Context(), "event "+string(event.Type),
+			attribute.String("event.type", string(event.Type)),
+			attribute.String("connection.id", event.ConnectionID),
+		)
+		defer func() {
+			if r := recover(); r != nil {
+				tracing.End(span, fmt.Errorf("event handler panic: %v", r))
+				panic(r)
+			}
+			tracing.End(span, event.Error)
 		}()
 
 		next(event)
@@ -8,12 +8,8 @@ import (
 // handleModelText handles text generated by Gemini.
 func (h *Handler) handleModelText(connectionID string, text string) {
 	logger.Base().Info("Gemini generated text", zap.String("connection_id", connectionID), zap.String("text", text))
-	// Add to conversation history if needed
-	if h.ConnectionGetter != nil {
-		if conn := h.ConnectionGetter(connectionID); conn != nil {
-			conn.AddMessage("assistant", text)
-		}
-	}
+	// Written to the conversation history with the turn latency once the turn completes
+	h.appendTranscript(&h.replies, connectionID, text)
 }
 
 // handleModelAudio handles audio generated by Gemini (not used if using audio tracks).
@@ -25,6 +21,8 @@ func (h *Handler) handleModelAudio(connectionID string, data string) {
 // handleInterruption handles Gemini being interrupted by user.
 func (h *Handler) handleInterruption(connectionID string) {
 	logger.Base().Info("Gemini response interrupted", zap.String("connection_id", connectionID))
+	h.InterruptOutput(connectionID)
+	h.flushReply(connectionID)
 }
 
 // onModelReady handles when Gemini is ready for interaction.
@@ -71,6 +71,10 @@ func (h *Handler) initializeConnectionInternal(connectionID, language, accent st
 				fallbackSilenceConfig = agentConfig.SilenceConfig
 			}
 			if agentConfig != nil {
+				// Clean up and gate caller audio with the agent's DSP and local VAD settings
+				callConn.SetDSPConfig(context.Background(), agentConfig.DSPConfig)
+				callConn.SetVADConfig(agentConfig.VADConfig)
+				callConn.SetAudioCueConfig(agentConfig.AudioCues)
 				if initialLanguage == "" && agentConfig.Language != "" {
 					initialLanguage = agentConfig.Language
 				}
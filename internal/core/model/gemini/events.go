@@ -32,9 +32,16 @@ func (h *Handler) handleServerContent(connectionID string, event map[string]inte
 		return
 	}
 
+	// Caller transcript, also used to time the turn
+	if transcription, ok := serverContent["inputTranscription"].(map[string]interface{}); ok {
+		text, _ := transcription["text"].(string)
+		h.handleInputTranscription(connectionID, text)
+	}
+
 	// Extract modelTurn
 	modelTurn, ok := serverContent["modelTurn"].(map[string]interface{})
 	if ok {
+		h.handleReplyStarted(connectionID)
 		parts, _ := modelTurn["parts"].([]interface{})
 		for _, part := range parts {
 			partMap, ok := part.(map[string]interface{})
@@ -56,6 +63,14 @@ func (h *Handler) handleServerContent(connectionID string, event map[string]inte
 		}
 	}
 
+	// Transcript of the model's spoken reply
+	if transcription, ok := serverContent["outputTranscription"].(map[string]interface{}); ok {
+		if text, _ := transcription["text"].(string); text != "" {
+			h.handleReplyStarted(connectionID)
+			h.appendTranscript(&h.replies, connectionID, text)
+		}
+	}
+
 	// Handle interruption
 	if _, ok := serverContent["interrupted"]; ok {
 		h.handleInterruption(connectionID)
@@ -63,6 +78,7 @@ func (h *Handler) handleServerContent(connectionID string, event map[string]inte
 
 	// Manage silence timer
 	if turnComplete, ok := serverContent["turnComplete"].(bool); ok && turnComplete {
+		h.flushReply(connectionID)
 		h.BaseHandler.ResetSilenceTimer(connectionID)
 	}
 }
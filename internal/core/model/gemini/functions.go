@@ -3,6 +3,7 @@ package gemini
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/ClareAI/astra-voice-service/pkg/data/mcp"
 	"github.com/ClareAI/astra-voice-service/pkg/logger"
@@ -38,7 +39,9 @@ func (h *Handler) executeFunctionCall(connectionID string, toolCall map[string]i
 		return
 	}
 
+	toolStart := time.Now()
 	result, err := h.ToolManager.ExecuteTool(name, argsStr, connectionID, modality)
+	h.AddToolDuration(connectionID, time.Since(toolStart))
 
 	// Log action
 	if err != nil {
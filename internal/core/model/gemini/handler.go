@@ -1,6 +1,8 @@
 package gemini
 
 import (
+	"sync"
+
 	"github.com/ClareAI/astra-voice-service/internal/config"
 	"github.com/ClareAI/astra-voice-service/internal/core/model/provider"
 )
@@ -8,6 +10,10 @@ import (
 // Handler inherits from provider.BaseHandler, managing Gemini WebRTC connections and their lifecycle.
 type Handler struct {
 	*provider.BaseHandler
+
+	// Transcripts of the turn in progress per connection (*strings.Builder)
+	inputs  sync.Map
+	replies sync.Map
 }
 
 // NewGeminiHandler creates a new Gemini handler.
@@ -25,6 +31,13 @@ func NewGeminiHandler(cfg *config.WebSocketConfig) provider.ModelHandler {
 	return h
 }
 
+// CloseConnection closes the connection and drops the transcripts of a turn it ended in
+func (h *Handler) CloseConnection(connectionID string) {
+	h.BaseHandler.CloseConnection(connectionID)
+	h.inputs.Delete(connectionID)
+	h.replies.Delete(connectionID)
+}
+
 // InitializeConnectionWithLanguage initializes a model connection with specific language.
 func (h *Handler) InitializeConnectionWithLanguage(connectionID, language, accent string) (provider.ModelConnection, error) {
 	return h.initializeConnectionInternal(connectionID, language, accent)
@@ -99,6 +99,9 @@ func (p *Provider) InitializeConnection(ctx context.Context, connectionID string
 				"generationConfig": map[string]interface{}{
 					"responseModalities": []string{"audio"},
 				},
+				// Transcripts feed the conversation history and turn latency
+				"inputAudioTranscription":  map[string]interface{}{},
+				"outputAudioTranscription": map[string]interface{}{},
 			},
 		}
 
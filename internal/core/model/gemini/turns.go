@@ -0,0 +1,75 @@
+package gemini
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/ClareAI/astra-voice-service/internal/config"
+	"github.com/ClareAI/astra-voice-service/internal/core/model/provider"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Gemini has no speech_stopped or response.created events. A turn is timed from the
+// caller's last transcribed speech to the first model output of the reply.
+
+// handleInputTranscription collects the caller's transcript; until the reply starts,
+// each chunk moves the end of the caller's speech forward
+func (h *Handler) handleInputTranscription(connectionID, text string) {
+	if text == "" {
+		return
+	}
+	if !h.IsTurnResponding(connectionID) {
+		h.RecordSpeechStopped(connectionID)
+	}
+	h.appendTranscript(&h.inputs, connectionID, text)
+}
+
+// handleReplyStarted marks the model's first output of a turn; the caller's turn is
+// committed on Gemini's side once it starts replying
+func (h *Handler) handleReplyStarted(connectionID string) {
+	if h.IsTurnResponding(connectionID) {
+		return
+	}
+	h.RecordTurnCommitted(connectionID)
+	h.RecordResponseCreated(connectionID)
+
+	if text, ok := h.inputs.LoadAndDelete(connectionID); ok {
+		h.writeMessage(connectionID, config.MessageRoleUser, text.(*strings.Builder).String(), nil)
+	}
+}
+
+// flushReply writes the model's reply with the latency of the turn that produced it
+func (h *Handler) flushReply(connectionID string) {
+	latency := h.TakeTurnLatency(connectionID)
+	text, ok := h.replies.LoadAndDelete(connectionID)
+	if !ok {
+		return
+	}
+	h.writeMessage(connectionID, config.MessageRoleAssistant, text.(*strings.Builder).String(), latency)
+}
+
+// appendTranscript appends a transcript chunk for a connection. Events of a connection
+// arrive in order on its data channel, so builders are not shared between goroutines.
+func (h *Handler) appendTranscript(transcripts *sync.Map, connectionID, text string) {
+	builder, _ := transcripts.LoadOrStore(connectionID, &strings.Builder{})
+	builder.(*strings.Builder).WriteString(text)
+}
+
+// writeMessage adds a message to the call's conversation history
+func (h *Handler) writeMessage(connectionID, role, content string, latency *provider.TurnLatency) {
+	content = strings.TrimSpace(content)
+	if content == "" || h.ConnectionGetter == nil {
+		return
+	}
+	conn := h.ConnectionGetter(connectionID)
+	if conn == nil {
+		return
+	}
+	if latency != nil {
+		conn.AddMessageWithLatency(role, content, latency)
+	} else {
+		conn.AddMessage(role, content)
+	}
+	logger.Base().Info("Added message to conversation history", zap.String("connection_id", connectionID), zap.String("role", role))
+}
@@ -3,6 +3,7 @@ package openai
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	webrtcadapter "github.com/ClareAI/astra-voice-service/internal/adapters/webrtc"
 	"github.com/ClareAI/astra-voice-service/internal/config"
@@ -131,8 +132,13 @@ func (h *Handler) InitializeConnectionWithLanguage(connectionID, language, accen
 			if agentConfig != nil && agentConfig.SilenceConfig != nil {
 				fallbackSilenceConfig = agentConfig.SilenceConfig
 			}
-			// Initialize current language/accent from agent config if available
 			if agentConfig != nil {
+				// Clean up and gate caller audio with the agent's DSP and local VAD settings
+				conn.SetDSPConfig(context.Background(), agentConfig.DSPConfig)
+				conn.SetVADConfig(agentConfig.VADConfig)
+				conn.SetAudioCueConfig(agentConfig.AudioCues)
+
+				// Initialize current language/accent from agent config if available
 				if initialLanguage == "" && agentConfig.Language != "" {
 					initialLanguage = agentConfig.Language
 				}
@@ -316,6 +322,18 @@ func (h *Handler) appendSystemTools(tools []interface{}, agentConfig *config.Age
 				return autoAccentEnabled || hasConfiguredAccents
 			},
 		},
+		{
+			name: toolspkg.ToolNameScheduleCallback,
+			condition: func() bool {
+				return agentConfig.BusinessRules != nil && slices.Contains(agentConfig.BusinessRules.AllowedActions, toolspkg.ToolNameScheduleCallback)
+			},
+		},
+		{
+			name: toolspkg.ToolNameEndCall,
+			condition: func() bool {
+				return agentConfig.BusinessRules != nil && slices.Contains(agentConfig.BusinessRules.AllowedActions, toolspkg.ToolNameEndCall)
+			},
+		},
 	}
 
 	for _, sysTool := range systemTools {
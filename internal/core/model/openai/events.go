@@ -41,13 +41,17 @@ func (h *Handler) handleModelEvent(connectionID string, event map[string]interfa
 		// User started speaking - stop silence timer AND reset retry count
 		h.ResetSilenceTimer(connectionID)
 		h.recordSpeechStarted(connectionID)
+		h.AbortGreeting(connectionID)
+		h.InterruptOutput(connectionID)
 
 	case "input_audio_buffer.speech_stopped":
-		// User stopped speaking
+		// User stopped speaking - start measuring voice-to-voice latency for this turn
+		h.RecordSpeechStopped(connectionID)
 
 	case "input_audio_buffer.committed":
 		// Audio buffer committed - user speech ready for transcription
 		h.recordSpeechCommitted(connectionID, event)
+		h.RecordTurnCommitted(connectionID)
 
 	case "conversation.item.added":
 		h.handleConversationItemAdded(connectionID, event)
@@ -60,6 +64,7 @@ func (h *Handler) handleModelEvent(connectionID string, event map[string]interfa
 
 	case "response.created":
 		h.handleResponseCreated(connectionID)
+		h.RecordResponseCreated(connectionID)
 		// Stop silence timer when AI starts responding (PAUSE only, don't reset count)
 		h.PauseSilenceTimer(connectionID)
 
@@ -110,6 +115,24 @@ func (h *Handler) writeMessageWithConfidence(connectionID, role, content string,
 	return messageID
 }
 
+// writeMessageWithLatency writes an assistant reply together with the turn latency that produced it
+func (h *Handler) writeMessageWithLatency(connectionID, role, content string) string {
+	latency := h.TakeTurnLatency(connectionID)
+	if latency == nil {
+		return h.writeMessage(connectionID, role, content)
+	}
+	if h.ConnectionGetter == nil {
+		return ""
+	}
+	conn := h.ConnectionGetter(connectionID)
+	if conn == nil {
+		return ""
+	}
+	messageID := conn.AddMessageWithLatency(role, content, latency)
+	logger.Base().Info("Added message to conversation history", zap.String("connection_id", connectionID), zap.String("role", role), zap.Int("content_length", len(content)))
+	return messageID
+}
+
 // recordSpeechStarted records the start time of the current speech
 func (h *Handler) recordSpeechStarted(connectionID string) {
 	h.Mutex.Lock()
@@ -448,8 +471,9 @@ func (h *Handler) handleAssistantMessageOutput(connectionID string, itemMap map[
 				contentType, _ := contentMap["type"].(string)
 				if contentType == "audio" || contentType == "output_audio" {
 					if transcript, ok := contentMap["transcript"].(string); ok && transcript != "" {
-						// Add assistant message to conversation history
-						h.writeMessage(connectionID, role, transcript)
+						// Add assistant message to conversation history, closing the latency turn
+						h.writeMessageWithLatency(connectionID, role, transcript)
+						h.FinishGreetingCapture(connectionID, transcript)
 					}
 				}
 			}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	agentconfig "github.com/ClareAI/astra-voice-service/internal/config"
 	"github.com/ClareAI/astra-voice-service/internal/core/model/provider"
@@ -42,8 +43,14 @@ func (h *Handler) executeFunctionCall(connectionID, callID, functionName, argume
 		return
 	}
 
-	// Track active function call for silence/BGM gating.
-	cleanup := h.MarkFunctionCallStart(connectionID)
+	// Special handling for hang up: no response follows, the call ends after the goodbye
+	if functionName == tool.ToolNameEndCall {
+		h.handleEndCall(callID, connectionID, arguments)
+		return
+	}
+
+	// Track active function call for silence gating and tool-call audio cues.
+	cleanup := h.MarkFunctionCallStart(connectionID, functionName)
 	defer cleanup()
 
 	// ========================================
@@ -70,7 +77,9 @@ func (h *Handler) executeFunctionCall(connectionID, callID, functionName, argume
 		whatsappConn.AddMessage(agentconfig.MessageRoleFunction, "I'm processing your request now. Please hold on for a moment.")
 	}
 
+	toolStart := time.Now()
 	result, success := h.executeFunction(connectionID, functionName, arguments, modality)
+	h.AddToolDuration(connectionID, time.Since(toolStart))
 
 	// Add system message AFTER tool execution
 	if whatsappConn != nil && isTestMode {
@@ -360,6 +369,61 @@ func (h *Handler) getAgentConfig(connectionID string) *agentconfig.AgentConfig {
 	return nil
 }
 
+// Hang up timing
+const (
+	// endCallQuietPeriod is how long the model must have been silent before hanging up
+	endCallQuietPeriod = 1500 * time.Millisecond
+
+	// endCallDrainTimeout bounds the wait for the goodbye to finish playing
+	endCallDrainTimeout = 15 * time.Second
+)
+
+// handleEndCall ends the call when the model asks to, once its goodbye has played
+func (h *Handler) handleEndCall(callID, connectionID, arguments string) {
+	var params struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(arguments), &params); err != nil || params.Reason == "" {
+		params.Reason = "other"
+	}
+	logger.Base().Info("📴 Model requested to end the call",
+		zap.String("connection_id", connectionID),
+		zap.String("reason", params.Reason))
+
+	if h.ConnectionGetter != nil {
+		if conn := h.ConnectionGetter(connectionID); conn != nil {
+			conn.AddAction(pubsub.Action{
+				ToolName: tool.ToolNameEndCall,
+				Param:    arguments,
+				Result:   true,
+			})
+		}
+	}
+
+	// Acknowledge the call without response.create so the model does not speak again
+	functionOutput := map[string]interface{}{
+		"type": "conversation.item.create",
+		"item": map[string]interface{}{
+			"type":    "function_call_output",
+			"call_id": callID,
+			"output":  `{"success": true, "message": "The call is ending. Do not say anything else."}`,
+		},
+	}
+	if err := h.sendEvent(connectionID, functionOutput); err != nil {
+		logger.Base().Warn("Failed to send end_call output", zap.String("connection_id", connectionID), zap.Error(err))
+	}
+
+	if !h.WaitForOutputDrain(connectionID, endCallQuietPeriod, endCallDrainTimeout) {
+		logger.Base().Warn("Goodbye still playing at hang up timeout", zap.String("connection_id", connectionID))
+	}
+
+	if h.OnHangUp != nil {
+		h.OnHangUp(connectionID, params.Reason)
+		return
+	}
+	h.CloseConnection(connectionID)
+}
+
 // sendFunctionResult sends the function call result back to OpenAI
 func (h *Handler) sendFunctionResult(callID, result, connectionID string) {
 	functionOutput := map[string]interface{}{
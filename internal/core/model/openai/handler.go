@@ -41,6 +41,7 @@ func NewOpenAIHandlerWithProvider(cfg *config.WebSocketConfig, p provider.ModelP
 	h.OnInactivityTimeout = h.sendInactivityMessage
 	h.OnExitTimeout = h.sendExitMessage
 	h.OnSendInitialGreeting = h.sendInitialGreeting
+	h.OnInjectGreeting = h.injectCachedGreeting
 
 	return h
 }
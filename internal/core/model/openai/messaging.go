@@ -63,6 +63,33 @@ func (h *Handler) SendInitialGreetingWithLanguage(connectionID, language string)
 	// Generate greeting instruction using prompt generator
 	greetingText := h.getGreetingText(connectionID, conn)
 
+	if err := h.applySessionInstructions(connectionID, conn, language); err != nil {
+		return err
+	}
+
+	// Create response to trigger AI to speak the greeting
+	// Language information is already in session instructions, so just trigger the response
+	// The greetingText is passed as transient instructions to ensure the model starts the conversation
+	responseMessage := map[string]interface{}{
+		"type": "response.create",
+		"response": map[string]interface{}{
+			"instructions": greetingText,
+		},
+	}
+	if err := h.sendEvent(connectionID, responseMessage); err != nil {
+		return fmt.Errorf("failed to trigger greeting response: %w", err)
+	}
+
+	contactName := ""
+	if conn != nil {
+		contactName = conn.GetContactName()
+	}
+	logger.Base().Info("Initial greeting sent for connection: (language: , contact: )", zap.String("language", language), zap.String("connection_id", connectionID), zap.String("contact_name", contactName))
+	return nil
+}
+
+// applySessionInstructions sends the session-level instructions that precede the greeting
+func (h *Handler) applySessionInstructions(connectionID string, conn provider.CallConnection, language string) error {
 	var sessionInstructions string
 	if conn != nil && h.PromptGenerator != nil {
 		promptGen := h.PromptGenerator(connectionID)
@@ -88,24 +115,48 @@ func (h *Handler) SendInitialGreetingWithLanguage(connectionID, language string)
 	}
 	logger.Base().Info("sessionInstructions", zap.String("sessioninstructions", sessionInstructions))
 
-	// Create response to trigger AI to speak the greeting
-	// Language information is already in session instructions, so just trigger the response
-	// The greetingText is passed as transient instructions to ensure the model starts the conversation
-	responseMessage := map[string]interface{}{
-		"type": "response.create",
-		"response": map[string]interface{}{
-			"instructions": greetingText,
+	return nil
+}
+
+// injectCachedGreeting sets up the session like SendInitialGreetingWithLanguage, but adds
+// the pre-rendered greeting the caller is hearing as an assistant message instead of
+// asking the model to speak it
+func (h *Handler) injectCachedGreeting(connectionID, text string) error {
+	var conn provider.CallConnection
+	if h.ConnectionGetter != nil {
+		conn = h.ConnectionGetter(connectionID)
+	}
+	language := "en"
+	if conn != nil && conn.GetVoiceLanguage() != "" {
+		language = conn.GetVoiceLanguage()
+	}
+
+	if err := h.applySessionInstructions(connectionID, conn, language); err != nil {
+		return err
+	}
+
+	itemEvent := map[string]interface{}{
+		"type": "conversation.item.create",
+		"item": map[string]interface{}{
+			"type": "message",
+			"role": config.MessageRoleAssistant,
+			"content": []map[string]interface{}{
+				{
+					"type": "output_text",
+					"text": text,
+				},
+			},
 		},
 	}
-	if err := h.sendEvent(connectionID, responseMessage); err != nil {
-		return fmt.Errorf("failed to trigger greeting response: %w", err)
+	if err := h.sendEvent(connectionID, itemEvent); err != nil {
+		return fmt.Errorf("failed to add pre-rendered greeting: %w", err)
 	}
 
-	contactName := ""
+	h.writeMessage(connectionID, config.MessageRoleAssistant, text)
 	if conn != nil {
-		contactName = conn.GetContactName()
+		conn.SetSwitchedToRealtime(true)
 	}
-	logger.Base().Info("Initial greeting sent for connection: (language: , contact: )", zap.String("language", language), zap.String("connection_id", connectionID), zap.String("contact_name", contactName))
+	logger.Base().Info("Pre-rendered greeting added to conversation", zap.String("language", language), zap.String("connection_id", connectionID))
 	return nil
 }
 
@@ -338,7 +389,9 @@ func (h *Handler) processUserMessage(connectionID, text string) {
 
 	// Process user input with RAG
 	if h.RAGProcessor != nil {
+		ragStart := time.Now()
 		shouldCallRAG, ragContext, _ := h.RAGProcessor(text, connectionID)
+		h.AddRAGDuration(connectionID, time.Since(ragStart))
 		if shouldCallRAG {
 			h.injectRAGContext(connectionID, ragContext)
 			return
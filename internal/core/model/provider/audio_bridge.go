@@ -9,6 +9,7 @@ import (
 	"github.com/ClareAI/astra-voice-service/internal/core/event"
 	"github.com/ClareAI/astra-voice-service/internal/storage"
 	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/metrics"
 	"github.com/pion/webrtc/v3"
 	"go.uber.org/zap"
 )
@@ -89,11 +90,32 @@ func (h *BaseHandler) ContinueAudioBridge(connectionID string, track *webrtc.Tra
 	needsCaching := connection.NeedsAudioCaching()
 
 	loadedBGMFrames, _ := LoadBGMFrames(strings.TrimSpace(DefaultBGMPath))
+	cueConfig := connection.GetAudioCueConfig()
+	bgmThreshold := DefaultBGMSilenceThreshold
+	if cueConfig != nil {
+		bgmThreshold = time.Duration(cueConfig.SilenceThresholdMs) * time.Millisecond
+	}
+
+	// With mixing enabled, speech, cues and ambience are mixed before reaching the output track
+	ctx, cancel := context.WithCancel(context.Background())
+	modelOutput, cueOutput := outputTrack, OpusWriter(nil)
+	if cueConfig.MixingEnabled() {
+		outputMixer, err := StartOutputMixer(ctx, connection, cueConfig, outputTrack)
+		if err != nil {
+			logger.Base().Error("Failed to start output mixer, writing model audio directly",
+				zap.String("connection_id", connectionID), zap.Error(err))
+		} else {
+			modelOutput = outputMixer.Source(MixerSourceModel)
+			cueOutput = outputMixer.Source(MixerSourceCue)
+		}
+	}
 
 	opts := AudioBridgeOptions{
 		ConnectionID:  connectionID,
+		ChannelLabel:  connection.GetChannelTypeString(),
 		Track:         track,
-		Output:        outputTrack,
+		Output:        modelOutput,
+		CueOutput:     cueOutput,
 		Connection:    connection.(AudioBridgeConnection),
 		AudioCache:    audioCache,
 		NeedsCaching:  needsCaching,
@@ -105,12 +127,29 @@ func (h *BaseHandler) ContinueAudioBridge(connectionID string, track *webrtc.Tra
 			return h.IsFunctionCallActive(id)
 		},
 		BGMFrames:           loadedBGMFrames,
-		BGMSilenceThreshold: DefaultBGMSilenceThreshold,
+		BGMSilenceThreshold: bgmThreshold,
+		ToolCue: func(id string) *ToolCue {
+			return h.ResolveToolCue(id, cueConfig, loadedBGMFrames)
+		},
+		OnModelAudio: func() {
+			h.RecordModelAudio(connectionID)
+		},
+		OnModelFrame: func(payload []byte) {
+			h.CaptureGreetingFrame(connectionID, payload)
+		},
+		OnOutputFrame: func() {
+			h.RecordOutputFrame(connectionID)
+		},
 		OnFirstPacket: func() {
 			connection.SetGreetingAudioStartTime(time.Now())
 			logger.Base().Info("🔊 Model audio started flowing", zap.String("connection_id", connectionID))
 		},
 		OnStop: func(packetCount int64) {
+			if outputMixer := connection.GetOutputMixer(); outputMixer != nil {
+				outputMixer.Close()
+				connection.SetOutputMixer(nil)
+			}
+			cancel()
 			if audioCache != nil && needsCaching {
 				audioCache.CleanupConnection(connectionID)
 			}
@@ -119,7 +158,7 @@ func (h *BaseHandler) ContinueAudioBridge(connectionID string, track *webrtc.Tra
 		LoggerPrefix: "model",
 	}
 
-	StartModelAudioForwarding(context.Background(), opts)
+	StartModelAudioForwarding(ctx, opts)
 }
 
 // WaitAndSendGreeting waits for data channel to be ready and sends greeting.
@@ -142,6 +181,9 @@ func (h *BaseHandler) WaitAndSendGreeting(connectionID string, connection CallCo
 		}
 	}
 
+	// A pre-rendered greeting plays now; the model only needs its text once connected
+	h.PrepareGreeting(connectionID, connection)
+
 	// Quick check
 	if conn, exists := h.GetConnection(connectionID); exists && conn != nil && conn.IsConnected() {
 		h.SendGreetingOnce(connectionID, connection)
@@ -193,7 +235,14 @@ func (h *BaseHandler) SendGreetingOnce(connectionID string, connection CallConne
 	}
 
 	if h.OnSendInitialGreeting != nil {
-		if err := h.OnSendInitialGreeting(connectionID); err != nil {
+		var err error
+		if text := h.cachedGreetingText(connectionID); text != "" {
+			err = h.OnInjectGreeting(connectionID, text)
+		} else {
+			h.startGreetingCapture(connectionID)
+			err = h.OnSendInitialGreeting(connectionID)
+		}
+		if err != nil {
 			logger.Base().Error("Failed to send initial greeting", zap.Error(err))
 			connection.SetGreetingSent(false)
 			return
@@ -202,6 +251,66 @@ func (h *BaseHandler) SendGreetingOnce(connectionID string, connection CallConne
 	logger.Base().Info("Initial greeting triggered successfully", zap.String("connection_id", connectionID))
 }
 
+// InterruptOutput drops model audio still queued for the caller once they start speaking:
+// the mixer's speech queue and whatever the channel itself buffers.
+func (h *BaseHandler) InterruptOutput(connectionID string) {
+	if h.ConnectionGetter == nil {
+		return
+	}
+	connection := h.ConnectionGetter(connectionID)
+	if connection == nil {
+		return
+	}
+	if outputMixer := connection.GetOutputMixer(); outputMixer != nil {
+		if source := outputMixer.Source(MixerSourceModel); source != nil {
+			source.Clear()
+		}
+	}
+	if clearer, ok := connection.GetWAOutputTrack().(OutputClearer); ok {
+		if err := clearer.ClearOutput(); err != nil {
+			logger.Base().Warn("Failed to clear channel output", zap.String("connection_id", connectionID), zap.Error(err))
+		}
+	}
+}
+
+// WaitForOutputDrain blocks until the caller has heard the model's last words: no model
+// audio arrived for quiet and the mixer's speech queue is empty. It gives up after timeout
+// and reports whether the output drained; a closed connection counts as drained.
+func (h *BaseHandler) WaitForOutputDrain(connectionID string, quiet, timeout time.Duration) bool {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	deadline := time.Now().Add(timeout)
+
+	for {
+		var connection CallConnection
+		if h.ConnectionGetter != nil {
+			connection = h.ConnectionGetter(connectionID)
+		}
+		h.Mutex.RLock()
+		state, exists := h.ConnectionStates[connectionID]
+		h.Mutex.RUnlock()
+		if connection == nil || connection.IsClosed() || !exists {
+			return true
+		}
+
+		drained := time.Since(time.Unix(0, atomic.LoadInt64(&state.LastModelOutput))) >= quiet
+		if drained {
+			if outputMixer := connection.GetOutputMixer(); outputMixer != nil {
+				if source := outputMixer.Source(MixerSourceModel); source != nil && source.Buffered() > 0 {
+					drained = false
+				}
+			}
+		}
+		if drained {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		<-ticker.C
+	}
+}
+
 // AudioBridgeConnection defines the minimal surface needed by the audio bridge.
 type AudioBridgeConnection interface {
 	IsClosed() bool
@@ -218,9 +327,16 @@ type OpusWriter interface {
 	WriteOpusFrame(opusPayload []byte) error
 }
 
+// OutputClearer is implemented by outputs that buffer model audio on the far side
+// (e.g. Twilio Media Streams) and can drop it when the caller barges in.
+type OutputClearer interface {
+	ClearOutput() error
+}
+
 // AudioBridgeOptions configures the model->WA audio forwarding bridge.
 type AudioBridgeOptions struct {
 	ConnectionID string
+	ChannelLabel string // channel type used for packet metrics
 	Track        *webrtc.TrackRemote
 	Output       OpusWriter
 	Connection   AudioBridgeConnection
@@ -233,8 +349,13 @@ type AudioBridgeOptions struct {
 	IsFunctionCallActive func(string) bool
 	BGMFrames            [][]byte
 	BGMSilenceThreshold  time.Duration
+	ToolCue              func(string) *ToolCue // selects the cue per tool call; nil loops BGMFrames
+	CueOutput            OpusWriter            // sink for cue frames when it differs from Output (mixer)
 
 	OnFirstPacket func()
+	OnModelAudio  func()               // called for every non-silent model packet (turn latency)
+	OnModelFrame  func(payload []byte) // called with every non-silent model packet (greeting capture)
+	OnOutputFrame func()               // called after every successful write to Output (turn latency)
 	OnStop        func(packetCount int64)
 
 	LoggerPrefix string
@@ -281,13 +402,29 @@ func StartModelAudioForwarding(ctx context.Context, opts AudioBridgeOptions) {
 
 		// Shared last audio activity timestamp (nanoseconds)
 		var lastAudioNano int64 = time.Now().UnixNano()
-		// BGM goroutine (optional)
+		// Tool-call cue goroutine (optional): plays hold music or a filler once the model
+		// has been silent for the threshold while a function call runs
 		stopBGM := make(chan struct{})
-		if len(opts.BGMFrames) > 0 && opts.IsFunctionCallActive != nil {
+		toolCue := opts.ToolCue
+		if toolCue == nil && len(opts.BGMFrames) > 0 {
+			toolCue = func(string) *ToolCue {
+				return &ToolCue{Key: DefaultBGMPath, Frames: opts.BGMFrames, Loop: true}
+			}
+		}
+		cueOutput := opts.CueOutput
+		if cueOutput == nil {
+			cueOutput = opts.Output
+		}
+		if toolCue != nil && opts.IsFunctionCallActive != nil {
 			go func() {
 				ticker := time.NewTicker(20 * time.Millisecond)
 				defer ticker.Stop()
-				var bgmIndex int64
+				threshold := opts.BGMSilenceThreshold
+				if threshold == 0 {
+					threshold = 1 * time.Second
+				}
+				var cueKey string
+				var cueIndex int
 				for {
 					select {
 					case <-stopBGM:
@@ -295,29 +432,37 @@ func StartModelAudioForwarding(ctx context.Context, opts AudioBridgeOptions) {
 					case <-ctx.Done():
 						return
 					case <-ticker.C:
-						last := time.Unix(0, atomic.LoadInt64(&lastAudioNano))
-						threshold := opts.BGMSilenceThreshold
-						if threshold == 0 {
-							threshold = 1 * time.Second
+						if !opts.IsFunctionCallActive(opts.ConnectionID) {
+							// The next tool call starts its cue from the top
+							cueKey = ""
+							continue
 						}
+						// Cue frames do not count as model audio, so playback continues until the model speaks
+						last := time.Unix(0, atomic.LoadInt64(&lastAudioNano))
 						if time.Since(last) < threshold {
 							continue
 						}
-						if !opts.IsFunctionCallActive(opts.ConnectionID) {
+						cue := toolCue(opts.ConnectionID)
+						if cue == nil || len(cue.Frames) == 0 {
 							continue
 						}
-						if len(opts.BGMFrames) == 0 {
-							continue
+						if cue.Key != cueKey {
+							cueKey = cue.Key
+							cueIndex = 0
+						}
+						if cueIndex >= len(cue.Frames) {
+							if !cue.Loop {
+								continue
+							}
+							cueIndex = 0
 						}
-						idx := atomic.AddInt64(&bgmIndex, 1) - 1
-						frame := opts.BGMFrames[int(idx)%len(opts.BGMFrames)]
-						if err := opts.Output.WriteOpusFrame(frame); err != nil {
+						frame := cue.Frames[cueIndex]
+						cueIndex++
+						if err := cueOutput.WriteOpusFrame(frame); err != nil {
 							logger.Base().Error("Failed to write BGM",
 								zap.String("prefix", prefix),
 								zap.String("connection_id", opts.ConnectionID),
 								zap.Error(err))
-						} else {
-							atomic.StoreInt64(&lastAudioNano, time.Now().UnixNano())
 						}
 					}
 				}
@@ -336,6 +481,9 @@ func StartModelAudioForwarding(ctx context.Context, opts AudioBridgeOptions) {
 			}
 		}()
 
+		forwardedPackets := metrics.AudioPacketsTotal.WithLabelValues(opts.ChannelLabel, metrics.DirectionOutbound, metrics.AudioForwarded)
+		droppedPackets := metrics.AudioPacketsTotal.WithLabelValues(opts.ChannelLabel, metrics.DirectionOutbound, metrics.AudioDropped)
+
 		writeErrorCount := 0
 		lastFrame := []byte(nil)
 		repeatFrameCount := 0
@@ -381,6 +529,7 @@ func StartModelAudioForwarding(ctx context.Context, opts AudioBridgeOptions) {
 
 			// Silence / repeat filtering
 			if silenceFilter != nil && silenceFilter(payload) {
+				droppedPackets.Inc()
 				continue
 			}
 			if len(lastFrame) == len(payload) {
@@ -394,6 +543,7 @@ func StartModelAudioForwarding(ctx context.Context, opts AudioBridgeOptions) {
 				if same {
 					repeatFrameCount++
 					if repeatFrameCount >= 3 {
+						droppedPackets.Inc()
 						continue
 					}
 				} else {
@@ -409,6 +559,12 @@ func StartModelAudioForwarding(ctx context.Context, opts AudioBridgeOptions) {
 			if opts.MarkAudioActivity != nil {
 				opts.MarkAudioActivity(opts.ConnectionID)
 			}
+			if opts.OnModelAudio != nil {
+				opts.OnModelAudio()
+			}
+			if opts.OnModelFrame != nil {
+				opts.OnModelFrame(payload)
+			}
 
 			// Write to WA
 			if err := opts.Output.WriteOpusFrame(payload); err != nil {
@@ -419,9 +575,14 @@ func StartModelAudioForwarding(ctx context.Context, opts AudioBridgeOptions) {
 						zap.Error(err))
 				}
 				writeErrorCount++
+				droppedPackets.Inc()
 				continue
 			}
+			forwardedPackets.Inc()
 
+			if opts.OnOutputFrame != nil {
+				opts.OnOutputFrame()
+			}
 			opts.Connection.UpdateLastActivity()
 			atomic.StoreInt64(&lastAudioNano, time.Now().UnixNano())
 		}
@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/internal/config"
+	"github.com/ClareAI/astra-voice-service/pkg/mixer"
+)
+
+// drainConnection is a call without an output mixer; other CallConnection methods are not used
+type drainConnection struct {
+	CallConnection
+	closed atomic.Bool
+}
+
+func (c *drainConnection) IsClosed() bool               { return c.closed.Load() }
+func (c *drainConnection) GetOutputMixer() *mixer.Mixer { return nil }
+
+func newDrainHandler(t *testing.T) (*BaseHandler, *drainConnection) {
+	t.Helper()
+	connection := &drainConnection{}
+	h := NewBaseHandler(nil, nil)
+	h.ConnectionGetter = func(string) CallConnection { return connection }
+	h.InitConnectionState("conn", 3600, &config.SilenceConfig{InactivityCheckDuration: 3600})
+	t.Cleanup(func() {
+		state := h.ConnectionStates["conn"]
+		state.MaxCallTimer.Stop()
+		if state.SilenceTimer != nil {
+			state.SilenceTimer.Stop()
+		}
+	})
+	return h, connection
+}
+
+func TestWaitForOutputDrainWaitsForModelQuiet(t *testing.T) {
+	h, _ := newDrainHandler(t)
+	h.MarkAudioActivity("conn")
+
+	start := time.Now()
+	if !h.WaitForOutputDrain("conn", 300*time.Millisecond, 2*time.Second) {
+		t.Fatal("output did not drain")
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("drained after %v, before the model was quiet", elapsed)
+	}
+}
+
+func TestWaitForOutputDrainIgnoresSilenceTimer(t *testing.T) {
+	h, _ := newDrainHandler(t)
+	h.MarkAudioActivity("conn")
+	time.Sleep(150 * time.Millisecond)
+
+	// The silence timer starts after the model's turn and must not count as model audio
+	h.StartSilenceTimer("conn")
+
+	start := time.Now()
+	if !h.WaitForOutputDrain("conn", 100*time.Millisecond, time.Second) {
+		t.Fatal("output did not drain")
+	}
+	if elapsed := time.Since(start); elapsed >= 100*time.Millisecond {
+		t.Errorf("drained after %v, want at once", elapsed)
+	}
+}
+
+func TestWaitForOutputDrainTimesOut(t *testing.T) {
+	h, _ := newDrainHandler(t)
+	h.MarkAudioActivity("conn")
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				h.MarkAudioActivity("conn")
+			}
+		}
+	}()
+
+	if h.WaitForOutputDrain("conn", 200*time.Millisecond, 300*time.Millisecond) {
+		t.Error("drained while the model was still speaking")
+	}
+}
+
+func TestWaitForOutputDrainClosedConnection(t *testing.T) {
+	h, connection := newDrainHandler(t)
+	h.MarkAudioActivity("conn")
+	connection.closed.Store(true)
+
+	if !h.WaitForOutputDrain("conn", time.Minute, time.Second) {
+		t.Error("closed connection did not count as drained")
+	}
+	if !h.WaitForOutputDrain("unknown", time.Minute, time.Second) {
+		t.Error("unknown connection did not count as drained")
+	}
+}
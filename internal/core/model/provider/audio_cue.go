@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"github.com/ClareAI/astra-voice-service/internal/config"
+	"github.com/ClareAI/astra-voice-service/pkg/audiocue"
+)
+
+// ToolCue is the audio played to the caller while a tool call runs.
+type ToolCue struct {
+	Key    string // identifies the cue; playback restarts from the top when it changes
+	Frames [][]byte
+	Loop   bool
+}
+
+// ResolveToolCue selects the cue for the connection's active tool call from the agent's
+// audio cue config. Hold music without an uploaded cue uses defaultFrames (the bundled
+// cue). It returns nil when the caller should hear silence, including while an uploaded
+// cue is still loading.
+func (h *BaseHandler) ResolveToolCue(connectionID string, cueConfig *config.AudioCueConfig, defaultFrames [][]byte) *ToolCue {
+	toolName := h.ActiveFunctionName(connectionID)
+	language, _ := h.GetCurrentLanguageAccent(connectionID)
+
+	mode, name := cueConfig.ToolCue(toolName, language)
+	if mode == config.CueModeSilence {
+		return nil
+	}
+	loop := mode == config.CueModeBGM
+
+	if name == "" {
+		if len(defaultFrames) == 0 {
+			return nil
+		}
+		return &ToolCue{Key: DefaultBGMPath, Frames: defaultFrames, Loop: loop}
+	}
+
+	library := audiocue.GetLibrary()
+	if library == nil {
+		return nil
+	}
+	key := audiocue.Key(cueConfig.TenantID, cueConfig.AgentID, name)
+	frames := library.Lookup(key)
+	if len(frames) == 0 {
+		return nil
+	}
+	return &ToolCue{Key: key, Frames: frames, Loop: loop}
+}
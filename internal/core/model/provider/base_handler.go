@@ -37,8 +37,10 @@ type ConnectionState struct {
 	RetryCount         int
 	SilenceConfig      *config.SilenceConfig
 	LastAudioActivity  int64 // unix nano
+	LastModelOutput    int64 // unix nano, last model audio sent to the caller
 	CurrentSpeechStart time.Time
 	ItemTimings        map[string]*SpeechTiming
+	CurrentTurn        *TurnLatency // open voice-to-voice latency turn, nil between turns
 }
 
 // BaseHandler holds common connection lifecycle/state logic and external dependencies.
@@ -50,10 +52,15 @@ type BaseHandler struct {
 	GreetingSignals     map[string]chan struct{}
 	ConnectionStates    map[string]*ConnectionState
 	FunctionCallCounts  map[string]int
+	ActiveFunctions     map[string]string // connection -> most recently started function call
 	CurrentLanguages    map[string]string
 	CurrentAccents      map[string]string
+	Greetings           map[string]*PrerenderedGreeting
 	Mutex               sync.RWMutex
 
+	// Audio stage of each open latency turn (*atomic.Int32), read per packet without Mutex
+	turnStages sync.Map
+
 	// Internal engine state
 	Config   *config.WebSocketConfig
 	Provider ModelProvider
@@ -68,11 +75,13 @@ type BaseHandler struct {
 	AgentConfigGetter func(ctx context.Context, agentID string, channelType string) (*config.AgentConfig, error)
 	EventBusGetter    func() event.EventBus
 	OnConnectionClose func(connectionID string)
+	OnHangUp          func(connectionID, reason string) // ends the call on its channel when the model asks to
 
 	// Provider-specific callbacks that must be set by the embedding handler
 	OnInactivityTimeout   func(connectionID string, message string)
 	OnExitTimeout         func(connectionID string, reason ExitReason)
 	OnSendInitialGreeting func(connectionID string) error
+	OnInjectGreeting      func(connectionID, text string) error // adds a pre-rendered greeting to the model's history; nil disables pre-rendering
 }
 
 // NewBaseHandler creates a base handler with common lifecycle/state maps and optional provider.
@@ -84,8 +93,10 @@ func NewBaseHandler(cfg *config.WebSocketConfig, p ModelProvider) *BaseHandler {
 		GreetingSignals:     make(map[string]chan struct{}),
 		ConnectionStates:    make(map[string]*ConnectionState),
 		FunctionCallCounts:  make(map[string]int),
+		ActiveFunctions:     make(map[string]string),
 		CurrentLanguages:    make(map[string]string),
 		CurrentAccents:      make(map[string]string),
+		Greetings:           make(map[string]*PrerenderedGreeting),
 		Config:              cfg,
 		Provider:            p,
 	}
@@ -107,6 +118,10 @@ func (h *BaseHandler) CloseConnection(connectionID string) {
 		}
 		delete(h.ConnectionStates, connectionID)
 	}
+	h.turnStages.Delete(connectionID)
+
+	greeting := h.Greetings[connectionID]
+	delete(h.Greetings, connectionID)
 
 	if exists {
 		delete(h.Connections, connectionID)
@@ -122,6 +137,10 @@ func (h *BaseHandler) CloseConnection(connectionID string) {
 	}
 	h.Mutex.Unlock()
 
+	if greeting != nil && greeting.Greeting != nil {
+		greeting.stopPlayback()
+	}
+
 	// Perform actual closing outside the lock
 	if exists && conn != nil {
 		conn.Close()
@@ -151,11 +170,13 @@ func (h *BaseHandler) StoreConnection(connectionID string, conn ModelConnection)
 	h.Connections[connectionID] = conn
 }
 
-// MarkFunctionCallStart increments the active function call counter for a connection.
+// MarkFunctionCallStart increments the active function call counter for a connection
+// and records the function name for tool-call audio cues.
 // It returns a cleanup function that should be deferred to decrement the counter.
-func (h *BaseHandler) MarkFunctionCallStart(connectionID string) func() {
+func (h *BaseHandler) MarkFunctionCallStart(connectionID, functionName string) func() {
 	h.Mutex.Lock()
 	h.FunctionCallCounts[connectionID]++
+	h.ActiveFunctions[connectionID] = functionName
 	h.Mutex.Unlock()
 
 	return func() {
@@ -163,6 +184,7 @@ func (h *BaseHandler) MarkFunctionCallStart(connectionID string) func() {
 		if count, ok := h.FunctionCallCounts[connectionID]; ok {
 			if count <= 1 {
 				delete(h.FunctionCallCounts, connectionID)
+				delete(h.ActiveFunctions, connectionID)
 			} else {
 				h.FunctionCallCounts[connectionID] = count - 1
 			}
@@ -178,6 +200,17 @@ func (h *BaseHandler) IsFunctionCallActive(connectionID string) bool {
 	return h.FunctionCallCounts[connectionID] > 0
 }
 
+// ActiveFunctionName returns the name of the connection's most recently started
+// function call, or "" if none is running.
+func (h *BaseHandler) ActiveFunctionName(connectionID string) string {
+	h.Mutex.RLock()
+	defer h.Mutex.RUnlock()
+	if h.FunctionCallCounts[connectionID] == 0 {
+		return ""
+	}
+	return h.ActiveFunctions[connectionID]
+}
+
 // EnableGreetingSignalControl enables signal-based greeting control for a connection.
 func (h *BaseHandler) EnableGreetingSignalControl(connectionID string) {
 	h.Mutex.Lock()
@@ -356,7 +389,7 @@ func (h *BaseHandler) GetCurrentLanguageAccent(connectionID string) (string, str
 	return h.CurrentLanguages[connectionID], h.CurrentAccents[connectionID]
 }
 
-// MarkAudioActivity records the latest audio activity time.
+// MarkAudioActivity records that model audio was just sent to the caller.
 func (h *BaseHandler) MarkAudioActivity(connectionID string) {
 	h.Mutex.RLock()
 	state, exists := h.ConnectionStates[connectionID]
@@ -364,7 +397,9 @@ func (h *BaseHandler) MarkAudioActivity(connectionID string) {
 	if !exists {
 		return
 	}
-	atomic.StoreInt64(&state.LastAudioActivity, time.Now().UnixNano())
+	now := time.Now().UnixNano()
+	atomic.StoreInt64(&state.LastAudioActivity, now)
+	atomic.StoreInt64(&state.LastModelOutput, now)
 }
 
 // BuildLanguageAccentInstructions builds a markdown-style instruction string.
@@ -395,3 +430,8 @@ func (h *BaseHandler) BuildLanguageAccentInstructions(connectionID, message stri
 func (h *BaseHandler) SetOnConnectionClose(callback func(connectionID string)) {
 	h.OnConnectionClose = callback
 }
+
+// SetOnHangUp sets the callback that ends the call on its channel when the model calls end_call
+func (h *BaseHandler) SetOnHangUp(callback func(connectionID, reason string)) {
+	h.OnHangUp = callback
+}
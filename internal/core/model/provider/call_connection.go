@@ -1,9 +1,12 @@
 package provider
 
 import (
+	"context"
 	"time"
 
 	webrtcadapter "github.com/ClareAI/astra-voice-service/internal/adapters/webrtc"
+	"github.com/ClareAI/astra-voice-service/internal/config"
+	"github.com/ClareAI/astra-voice-service/pkg/mixer"
 	"github.com/ClareAI/astra-voice-service/pkg/pubsub"
 )
 
@@ -15,6 +18,8 @@ type CallConnection interface {
 	GetVoiceLanguage() string
 	GetAccent() string
 	GetContactName() string
+	GetVariables() map[string]string
+	GetConversationID() string
 	GetAgentID() string
 	GetTextAgentID() string
 	GetTenantID() string
@@ -26,6 +31,7 @@ type CallConnection interface {
 
 	AddMessage(role, content string) string
 	AddMessageWithConfidence(role, content string, confidence float64) string
+	AddMessageWithLatency(role, content string, latency *TurnLatency) string
 	UpdateMessage(messageID string, content string, confidence float64, originalContent string, originalConfidence float64) error
 
 	AddAction(action pubsub.Action)
@@ -34,6 +40,12 @@ type CallConnection interface {
 	// Audio handling
 	GetWAOutputTrack() webrtcadapter.OpusWriter
 	NeedsAudioCaching() bool
+	SetVADConfig(vadConfig *config.VADConfig)
+	SetDSPConfig(ctx context.Context, dspConfig *config.DSPConfig)
+	SetAudioCueConfig(cueConfig *config.AudioCueConfig)
+	GetAudioCueConfig() *config.AudioCueConfig
+	SetOutputMixer(outputMixer *mixer.Mixer)
+	GetOutputMixer() *mixer.Mixer
 
 	// State management
 	SetAIReady(ready bool)
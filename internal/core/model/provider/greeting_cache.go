@@ -0,0 +1,341 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/internal/storage"
+	"github.com/ClareAI/astra-voice-service/pkg/audiocue"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/objectstore"
+	"github.com/pion/rtp"
+	"go.uber.org/zap"
+)
+
+const (
+	greetingLookupTimeout = 1 * time.Second
+	greetingStoreTimeout  = 30 * time.Second
+	// greetingPlayoutTail is how long the model must stay silent after its greeting
+	// response is done before the captured audio is considered complete
+	greetingPlayoutTail = 600 * time.Millisecond
+
+	// Pre-rendered greetings are 20ms Opus frames at 48kHz
+	greetingFrameSamples = 960
+	greetingPayloadType  = 111
+
+	// greetingContactProbe fills the contact fields when checking whether a greeting uses them
+	greetingContactProbe = "\x00contact"
+)
+
+// PrerenderedGreeting tracks a connection's cached greeting: either a greeting played from
+// the cache, or the model's greeting being captured to fill the cache.
+type PrerenderedGreeting struct {
+	Key      string
+	AgentID  string
+	Greeting *audiocue.Greeting // set when the greeting is played from the cache
+
+	Capturing bool
+	Frames    [][]byte
+	LastFrame time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// stopPlayback stops playing the cached greeting
+func (g *PrerenderedGreeting) stopPlayback() {
+	g.stopOnce.Do(func() {
+		close(g.stop)
+	})
+}
+
+// PrepareGreeting looks up the connection's pre-rendered greeting. On a hit it starts
+// playing it at once and returns true; SendGreetingOnce then only adds its text to the
+// model's history. On a miss the model's greeting is captured to fill the cache.
+func (h *BaseHandler) PrepareGreeting(connectionID string, connection CallConnection) bool {
+	key, agentID, ok := h.greetingCacheKey(connectionID, connection)
+	if !ok {
+		return false
+	}
+
+	state := &PrerenderedGreeting{Key: key, AgentID: agentID, stop: make(chan struct{})}
+	ctx, cancel := context.WithTimeout(context.Background(), greetingLookupTimeout)
+	greeting, err := audiocue.GetLibrary().Greeting(ctx, key)
+	cancel()
+	if err != nil && !errors.Is(err, objectstore.ErrNotFound) {
+		logger.Base().Warn("Failed to load pre-rendered greeting, the model will speak it",
+			zap.String("connection_id", connectionID),
+			zap.String("key", key),
+			zap.Error(err))
+	}
+	state.Greeting = greeting
+
+	h.Mutex.Lock()
+	h.Greetings[connectionID] = state
+	h.Mutex.Unlock()
+
+	if greeting == nil {
+		return false
+	}
+	logger.Base().Info("Playing pre-rendered greeting",
+		zap.String("connection_id", connectionID),
+		zap.String("key", key),
+		zap.Int("frames", len(greeting.Frames)))
+	go h.playGreeting(connectionID, connection, state)
+	return true
+}
+
+// cachedGreetingText returns the text of the greeting played from the cache, or "" if the
+// model should speak the greeting itself
+func (h *BaseHandler) cachedGreetingText(connectionID string) string {
+	if h.OnInjectGreeting == nil {
+		return ""
+	}
+	h.Mutex.RLock()
+	defer h.Mutex.RUnlock()
+	if state := h.Greetings[connectionID]; state != nil && state.Greeting != nil {
+		return state.Greeting.Text
+	}
+	return ""
+}
+
+// playGreeting writes the cached greeting to the caller at real-time pace
+func (h *BaseHandler) playGreeting(connectionID string, connection CallConnection, state *PrerenderedGreeting) {
+	output := greetingOutput(connection)
+	if output == nil {
+		logger.Base().Warn("No output track for pre-rendered greeting", zap.String("connection_id", connectionID))
+		return
+	}
+
+	// The greeting is recorded as model output, as its own RTP source
+	audioCache := storage.GetAudioCache()
+	if !connection.NeedsAudioCaching() {
+		audioCache = nil
+	}
+	packet := &rtp.Packet{Header: rtp.Header{Version: 2, PayloadType: greetingPayloadType, SSRC: rand.Uint32()}}
+
+	connection.SetGreetingAudioStartTime(time.Now())
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+
+	for _, frame := range state.Greeting.Frames {
+		select {
+		case <-state.stop:
+			logger.Base().Info("Pre-rendered greeting interrupted", zap.String("connection_id", connectionID))
+			return
+		case <-ticker.C:
+		}
+		if connection.IsClosed() {
+			return
+		}
+		if err := output.WriteOpusFrame(frame); err != nil {
+			logger.Base().Error("Failed to write pre-rendered greeting",
+				zap.String("connection_id", connectionID),
+				zap.Error(err))
+			return
+		}
+		if audioCache != nil {
+			packet.Payload = frame
+			audioCache.CacheAudioRTP(connectionID, storage.AudioTypeAIOutput, storage.AudioFormatOpus, packet)
+			packet.SequenceNumber++
+			packet.Timestamp += greetingFrameSamples
+		}
+		h.MarkAudioActivity(connectionID)
+	}
+
+	// No model response follows the greeting, so the silence timer starts here
+	h.StartSilenceTimer(connectionID)
+}
+
+// greetingOutput returns the writer model speech goes to: the mixer's model source when
+// output is mixed, the output track otherwise
+func greetingOutput(connection CallConnection) OpusWriter {
+	if outputMixer := connection.GetOutputMixer(); outputMixer != nil {
+		if source := outputMixer.Source(MixerSourceModel); source != nil {
+			return source
+		}
+	}
+	if track := connection.GetWAOutputTrack(); track != nil {
+		return track
+	}
+	return nil
+}
+
+// startGreetingCapture starts recording the model's greeting when it should fill the cache
+func (h *BaseHandler) startGreetingCapture(connectionID string) {
+	h.Mutex.Lock()
+	defer h.Mutex.Unlock()
+	if state := h.Greetings[connectionID]; state != nil && state.Greeting == nil {
+		state.Capturing = true
+	}
+}
+
+// CaptureGreetingFrame records a frame of model audio while the greeting is captured
+func (h *BaseHandler) CaptureGreetingFrame(connectionID string, payload []byte) {
+	h.Mutex.Lock()
+	defer h.Mutex.Unlock()
+	state := h.Greetings[connectionID]
+	if state == nil || !state.Capturing {
+		return
+	}
+	if len(state.Frames) >= audiocue.MaxGreetingFrames {
+		logger.Base().Info("Greeting too long to pre-render", zap.String("connection_id", connectionID))
+		delete(h.Greetings, connectionID)
+		return
+	}
+	state.Frames = append(state.Frames, append([]byte(nil), payload...))
+	state.LastFrame = time.Now()
+}
+
+// FinishGreetingCapture is called by providers with the transcript once the greeting
+// response is done. The model's audio is still playing out at that point, so the
+// greeting is stored once the audio has stopped.
+func (h *BaseHandler) FinishGreetingCapture(connectionID, transcript string) {
+	h.Mutex.RLock()
+	state := h.Greetings[connectionID]
+	capturing := state != nil && state.Capturing
+	h.Mutex.RUnlock()
+	if !capturing {
+		return
+	}
+
+	transcript = strings.TrimSpace(transcript)
+	if transcript == "" {
+		h.AbortGreeting(connectionID)
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		deadline := time.Now().Add(time.Duration(audiocue.MaxGreetingFrames) * 20 * time.Millisecond)
+
+		for range ticker.C {
+			h.Mutex.Lock()
+			if h.Greetings[connectionID] != state {
+				// Interrupted or closed
+				h.Mutex.Unlock()
+				return
+			}
+			if time.Now().After(deadline) {
+				delete(h.Greetings, connectionID)
+				h.Mutex.Unlock()
+				return
+			}
+			if len(state.Frames) == 0 || time.Since(state.LastFrame) < greetingPlayoutTail {
+				h.Mutex.Unlock()
+				continue
+			}
+			frames := state.Frames
+			state.Capturing = false
+			state.Frames = nil
+			h.Mutex.Unlock()
+
+			h.storeGreeting(connectionID, state, &audiocue.Greeting{Text: transcript, Frames: frames})
+			return
+		}
+	}()
+}
+
+// storeGreeting saves a captured greeting for later calls
+func (h *BaseHandler) storeGreeting(connectionID string, state *PrerenderedGreeting, greeting *audiocue.Greeting) {
+	library := audiocue.GetLibrary()
+	if library == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), greetingStoreTimeout)
+	defer cancel()
+
+	if err := library.PutGreeting(ctx, state.Key, state.AgentID, greeting); err != nil {
+		logger.Base().Warn("Failed to store pre-rendered greeting",
+			zap.String("connection_id", connectionID),
+			zap.Error(err))
+		return
+	}
+	logger.Base().Info("Pre-rendered greeting stored",
+		zap.String("connection_id", connectionID),
+		zap.String("key", state.Key),
+		zap.Int("frames", len(greeting.Frames)),
+		zap.String("text", greeting.Text))
+}
+
+// AbortGreeting stops a cached greeting when the caller starts speaking and discards a
+// greeting being captured, since the model's greeting was cut short
+func (h *BaseHandler) AbortGreeting(connectionID string) {
+	h.Mutex.Lock()
+	state := h.Greetings[connectionID]
+	if state != nil && state.Greeting == nil {
+		// Playback state is kept: its text may still have to reach the model's history
+		delete(h.Greetings, connectionID)
+	}
+	h.Mutex.Unlock()
+
+	if state != nil && state.Greeting != nil {
+		state.stopPlayback()
+	}
+}
+
+// greetingCacheKey returns the cache key of the connection's greeting, or false if the
+// greeting is not pre-rendered: caching is off for the agent, the provider cannot take
+// the greeting as history, or the greeting uses the contact's name or number.
+func (h *BaseHandler) greetingCacheKey(connectionID string, connection CallConnection) (key, agentID string, ok bool) {
+	if h.OnInjectGreeting == nil || h.AgentConfigGetter == nil || h.PromptGenerator == nil || audiocue.GetLibrary() == nil {
+		return "", "", false
+	}
+	agentID = connection.GetAgentID()
+	if agentID == "" {
+		return "", "", false
+	}
+	agentConfig, err := h.AgentConfigGetter(context.Background(), agentID, connection.GetChannelTypeString())
+	if err != nil || agentConfig == nil {
+		return "", "", false
+	}
+
+	isOutbound := connection.GetIsOutbound()
+	promptConfig := agentConfig.PromptConfig
+	if isOutbound && agentConfig.OutboundPromptConfig != nil {
+		promptConfig = agentConfig.OutboundPromptConfig
+	}
+	if promptConfig == nil || !promptConfig.CacheGreeting {
+		return "", "", false
+	}
+
+	promptGen := h.PromptGenerator(connectionID)
+	if promptGen == nil {
+		return "", "", false
+	}
+	script := promptGen.GenerateGreetingScript("", "", isOutbound)
+	if script == "" || script != promptGen.GenerateGreetingScript(greetingContactProbe, greetingContactProbe, isOutbound) {
+		logger.Base().Debug("Greeting is personalised, not pre-rendering it", zap.String("connection_id", connectionID))
+		return "", "", false
+	}
+
+	// The greeting instruction covers the script, language and accent
+	language := connection.GetVoiceLanguage()
+	accent := connection.GetAccent()
+	var instruction string
+	if isOutbound {
+		instruction = promptGen.GenerateOutboundGreeting("", "", language, accent)
+	} else {
+		instruction = promptGen.GenerateGreetingInstruction("", "", language, accent)
+	}
+	providerName := ""
+	if h.Provider != nil {
+		providerName = h.Provider.GetProviderType().String()
+	}
+
+	key = audiocue.GreetingKey(agentID,
+		providerName,
+		agentConfig.Voice,
+		strconv.FormatFloat(agentConfig.Speed, 'f', -1, 64),
+		language,
+		accent,
+		instruction,
+	)
+	return key, agentID, true
+}
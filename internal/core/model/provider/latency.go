@@ -0,0 +1,189 @@
+package provider
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// TurnLatency captures the voice-to-voice milestones of a single user turn.
+// A turn starts when the user stops speaking and ends when the assistant reply is written.
+type TurnLatency struct {
+	Provider           string
+	SpeechStoppedAt    time.Time
+	CommittedAt        time.Time
+	ResponseCreatedAt  time.Time
+	FirstModelAudioAt  time.Time
+	FirstOutputFrameAt time.Time
+	ToolDuration       time.Duration
+	RAGDuration        time.Duration
+}
+
+// sinceSpeechStopped returns the delay between speech end and t, or nil if either is unknown.
+func (t *TurnLatency) sinceSpeechStopped(at time.Time) *int64 {
+	if t == nil || t.SpeechStoppedAt.IsZero() || at.IsZero() {
+		return nil
+	}
+	ms := at.Sub(t.SpeechStoppedAt).Milliseconds()
+	return &ms
+}
+
+// CommitLatencyMs is the time from speech_stopped to input_audio_buffer.committed.
+func (t *TurnLatency) CommitLatencyMs() *int64 { return t.sinceSpeechStopped(t.CommittedAt) }
+
+// ResponseLatencyMs is the time from speech_stopped to response.created.
+func (t *TurnLatency) ResponseLatencyMs() *int64 { return t.sinceSpeechStopped(t.ResponseCreatedAt) }
+
+// FirstAudioLatencyMs is the time from speech_stopped to the first model RTP packet in the bridge.
+func (t *TurnLatency) FirstAudioLatencyMs() *int64 { return t.sinceSpeechStopped(t.FirstModelAudioAt) }
+
+// VoiceToVoiceMs is the time from speech_stopped to the first frame written to the channel writer.
+func (t *TurnLatency) VoiceToVoiceMs() *int64 { return t.sinceSpeechStopped(t.FirstOutputFrameAt) }
+
+// Per-packet audio milestones of the open turn. The bridge checks the stage without the
+// handler lock and only locks to record the first packet or frame.
+const (
+	turnStageAwaitingModelAudio int32 = iota + 1
+	turnStageAwaitingOutputFrame
+	turnStageDone
+)
+
+// advanceTurnStage moves the connection's turn from one audio stage to the next and reports
+// whether it did; it is lock-free.
+func (h *BaseHandler) advanceTurnStage(connectionID string, from, to int32) bool {
+	stage, ok := h.turnStages.Load(connectionID)
+	return ok && stage.(*atomic.Int32).CompareAndSwap(from, to)
+}
+
+// providerName returns the provider type string for latency attribution.
+func (h *BaseHandler) providerName() string {
+	if h.Provider == nil {
+		return ""
+	}
+	return h.Provider.GetProviderType().String()
+}
+
+// updateTurn applies fn to the open turn of a connection while holding the handler lock.
+func (h *BaseHandler) updateTurn(connectionID string, fn func(turn *TurnLatency)) {
+	h.Mutex.Lock()
+	defer h.Mutex.Unlock()
+	if state, exists := h.ConnectionStates[connectionID]; exists && state.CurrentTurn != nil {
+		fn(state.CurrentTurn)
+	}
+}
+
+// RecordSpeechStopped opens a new turn; any unfinished previous turn is discarded (barge-in).
+func (h *BaseHandler) RecordSpeechStopped(connectionID string) {
+	providerName := h.providerName()
+	h.Mutex.Lock()
+	defer h.Mutex.Unlock()
+	if state, exists := h.ConnectionStates[connectionID]; exists {
+		state.CurrentTurn = &TurnLatency{
+			Provider:        providerName,
+			SpeechStoppedAt: time.Now(),
+		}
+		h.turnStages.Delete(connectionID)
+	}
+}
+
+// IsTurnResponding reports whether the model has started replying in the open turn.
+func (h *BaseHandler) IsTurnResponding(connectionID string) bool {
+	h.Mutex.RLock()
+	defer h.Mutex.RUnlock()
+	state, exists := h.ConnectionStates[connectionID]
+	return exists && state.CurrentTurn != nil && !state.CurrentTurn.ResponseCreatedAt.IsZero()
+}
+
+// RecordTurnCommitted records when the user audio buffer was committed.
+func (h *BaseHandler) RecordTurnCommitted(connectionID string) {
+	h.updateTurn(connectionID, func(turn *TurnLatency) {
+		if turn.CommittedAt.IsZero() {
+			turn.CommittedAt = time.Now()
+		}
+	})
+}
+
+// RecordResponseCreated records when the model started its first response for the turn.
+func (h *BaseHandler) RecordResponseCreated(connectionID string) {
+	h.updateTurn(connectionID, func(turn *TurnLatency) {
+		if turn.ResponseCreatedAt.IsZero() {
+			turn.ResponseCreatedAt = time.Now()
+			stage := &atomic.Int32{}
+			stage.Store(turnStageAwaitingModelAudio)
+			h.turnStages.Store(connectionID, stage)
+		}
+	})
+}
+
+// RecordModelAudio records the first model audio packet of the turn. Called per packet by the bridge.
+func (h *BaseHandler) RecordModelAudio(connectionID string) {
+	if !h.advanceTurnStage(connectionID, turnStageAwaitingModelAudio, turnStageAwaitingOutputFrame) {
+		return
+	}
+	h.updateTurn(connectionID, func(turn *TurnLatency) {
+		if turn.FirstModelAudioAt.IsZero() && !turn.ResponseCreatedAt.IsZero() {
+			turn.FirstModelAudioAt = time.Now()
+		}
+	})
+}
+
+// RecordOutputFrame records the first frame written to the channel writer. Called per frame by the bridge.
+func (h *BaseHandler) RecordOutputFrame(connectionID string) {
+	if !h.advanceTurnStage(connectionID, turnStageAwaitingOutputFrame, turnStageDone) {
+		return
+	}
+	h.updateTurn(connectionID, func(turn *TurnLatency) {
+		if turn.FirstOutputFrameAt.IsZero() && !turn.FirstModelAudioAt.IsZero() {
+			turn.FirstOutputFrameAt = time.Now()
+		}
+	})
+}
+
+// AddToolDuration accumulates tool execution time on the open turn.
+func (h *BaseHandler) AddToolDuration(connectionID string, d time.Duration) {
+	h.updateTurn(connectionID, func(turn *TurnLatency) {
+		turn.ToolDuration += d
+	})
+}
+
+// AddRAGDuration accumulates RAG lookup time on the open turn.
+func (h *BaseHandler) AddRAGDuration(connectionID string, d time.Duration) {
+	h.updateTurn(connectionID, func(turn *TurnLatency) {
+		turn.RAGDuration += d
+	})
+}
+
+// TakeTurnLatency closes the open turn and returns it, or nil if no turn is in progress.
+func (h *BaseHandler) TakeTurnLatency(connectionID string) *TurnLatency {
+	h.Mutex.Lock()
+	state, exists := h.ConnectionStates[connectionID]
+	if !exists || state.CurrentTurn == nil {
+		h.Mutex.Unlock()
+		return nil
+	}
+	turn := state.CurrentTurn
+	state.CurrentTurn = nil
+	h.turnStages.Delete(connectionID)
+	h.Mutex.Unlock()
+
+	fields := []zap.Field{
+		zap.String("connection_id", connectionID),
+		zap.String("provider", turn.Provider),
+		zap.Duration("tool_duration", turn.ToolDuration),
+		zap.Duration("rag_duration", turn.RAGDuration),
+	}
+	if ms := turn.ResponseLatencyMs(); ms != nil {
+		fields = append(fields, zap.Int64("response_latency_ms", *ms))
+	}
+	if ms := turn.FirstAudioLatencyMs(); ms != nil {
+		fields = append(fields, zap.Int64("first_audio_latency_ms", *ms))
+	}
+	if ms := turn.VoiceToVoiceMs(); ms != nil {
+		fields = append(fields, zap.Int64("voice_to_voice_ms", *ms))
+	}
+	logger.Base().Info("⏱️ Turn latency", fields...)
+
+	return turn
+}
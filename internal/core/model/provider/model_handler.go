@@ -25,4 +25,7 @@ type ModelHandler interface {
 
 	// SetOnConnectionClose sets the callback when connection is closed by logic
 	SetOnConnectionClose(callback func(connectionID string))
+
+	// SetOnHangUp sets the callback that ends the call on its channel when the model calls end_call
+	SetOnHangUp(callback func(connectionID, reason string))
 }
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ClareAI/astra-voice-service/internal/config"
+	"github.com/ClareAI/astra-voice-service/pkg/audiocue"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/mixer"
+	"go.uber.org/zap"
+)
+
+// Names of the output mixer sources
+const (
+	MixerSourceModel    = "model"    // model speech; ducks the other sources
+	MixerSourceCue      = "cue"      // hold music and fillers while tools run
+	MixerSourceAmbience = "ambience" // background loop for the whole call
+)
+
+// StartOutputMixer puts a mixer in front of outputTrack with sources for model speech,
+// tool-call cues and, if configured, ambience. The mixer is recorded on the connection
+// so further sources can join the call; it runs until ctx is done or it is closed.
+func StartOutputMixer(ctx context.Context, connection CallConnection, cueConfig *config.AudioCueConfig, outputTrack OpusWriter) (*mixer.Mixer, error) {
+	outputMixer, err := mixer.New(outputTrack, mixer.Config{DuckingDb: cueConfig.DuckingDb})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output mixer: %w", err)
+	}
+
+	// Two frames of start buffer absorb jitter between the model track and the mixer tick
+	outputMixer.AddSource(MixerSourceModel, mixer.SourceConfig{Ducks: true, StartFrames: 2})
+	outputMixer.AddSource(MixerSourceCue, mixer.SourceConfig{Ducked: true, GainDb: cueConfig.HoldMusicGainDb, StartFrames: 2})
+
+	if cueConfig.Ambience != "" {
+		ambience := outputMixer.AddSource(MixerSourceAmbience, mixer.SourceConfig{Ducked: true, GainDb: cueConfig.AmbienceGainDb})
+		go loadAmbience(ctx, ambience, audiocue.Key(cueConfig.TenantID, cueConfig.AgentID, cueConfig.Ambience))
+	}
+
+	connection.SetOutputMixer(outputMixer)
+	outputMixer.Start(ctx)
+	return outputMixer, nil
+}
+
+// loadAmbience fetches the ambience cue and loops it on source
+func loadAmbience(ctx context.Context, source *mixer.Source, key string) {
+	library := audiocue.GetLibrary()
+	if library == nil {
+		logger.Base().Warn("Ambience configured but audio cue storage is not", zap.String("key", key))
+		return
+	}
+	frames, err := library.Frames(ctx, key)
+	if err != nil {
+		logger.Base().Warn("Failed to load ambience", zap.String("key", key), zap.Error(err))
+		return
+	}
+	source.Loop(frames)
+}
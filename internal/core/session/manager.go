@@ -3,6 +3,7 @@ package session
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -66,6 +67,24 @@ func (m *Manager) Unregister(ctx context.Context, sessionID string) error {
 	return m.redisSvc.DelValue(ctx, key)
 }
 
+// Lookup returns a registered session, or nil when no pod holds it
+func (m *Manager) Lookup(ctx context.Context, sessionID string) (*SessionInfo, error) {
+	key := fmt.Sprintf("%s:%s", SessionKeyPrefix, sessionID)
+	data, err := m.redisSvc.GetValue(ctx, key)
+	if errors.Is(err, redis.ErrKeyNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var info SessionInfo
+	if err := json.Unmarshal([]byte(data), &info); err != nil {
+		return nil, fmt.Errorf("failed to decode session %s: %w", sessionID, err)
+	}
+	return &info, nil
+}
+
 // NotifyCleanup broadcasts a cleanup request to all pods
 func (m *Manager) NotifyCleanup(ctx context.Context, sessionID string) error {
 	logger.Base().Info("Broadcasting cleanup request", zap.String("session_id", sessionID))
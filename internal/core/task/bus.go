@@ -6,6 +6,7 @@ import (
 
 	"github.com/ClareAI/astra-voice-service/pkg/logger"
 	"github.com/ClareAI/astra-voice-service/pkg/redis"
+	"github.com/ClareAI/astra-voice-service/pkg/tracing"
 	"go.uber.org/zap"
 )
 
@@ -25,6 +26,13 @@ func NewRedisBus(redisSvc redis.RedisServiceInterface) *RedisBus {
 
 // Publish sends a task to the bus
 func (b *RedisBus) Publish(ctx context.Context, task SessionTask) error {
+	// Propagate the call trace so the owning pod continues the same trace
+	if task.TraceContext == nil {
+		task.TraceContext = tracing.Inject(tracing.CallContext(task.ConnectionID))
+	}
+	if task.TraceContext == nil {
+		task.TraceContext = tracing.Inject(ctx)
+	}
 	logger.Base().Debug("Publishing task", zap.String("type", string(task.Type)), zap.String("conn_id", task.ConnectionID))
 	return b.redisSvc.Publish(ctx, TaskChannel, task)
 }
@@ -2,6 +2,8 @@ package task
 
 import (
 	"context"
+
+	"github.com/ClareAI/astra-voice-service/pkg/tracing"
 )
 
 // TaskType defines the type of asynchronous task
@@ -12,6 +14,10 @@ const (
 	TaskTypeWebCall      TaskType = "web_call"      // Process Web/Test inbound call
 	TaskTypeOutboundCall TaskType = "outbound_call" // Process Outbound call setup
 	TaskTypeLiveKitRoom  TaskType = "livekit_room"  // Process LiveKit room setup & AI Init
+
+	TaskTypeOutboundSDPAnswer  TaskType = "outbound_sdp_answer"  // Apply a Wati SDP answer on the pod owning the outbound call
+	TaskTypeOutboundCallStatus TaskType = "outbound_call_status" // Apply a Wati call status on the pod owning the outbound call
+	TaskTypeWHIPTrickle        TaskType = "whip_trickle"         // Apply trickled ICE candidates on the pod owning a WHIP session
 )
 
 // SessionTask represents an asynchronous task payload
@@ -19,6 +25,14 @@ type SessionTask struct {
 	Type         TaskType `json:"type"`
 	ConnectionID string   `json:"connection_id"`
 	Payload      []byte   `json:"payload"` // JSON payload of the original request
+
+	// TraceContext carries the W3C trace context of the call across pods
+	TraceContext map[string]string `json:"trace_context,omitempty"`
+}
+
+// Context returns a context carrying the task's trace context
+func (t SessionTask) Context() context.Context {
+	return tracing.Extract(context.Background(), t.TraceContext)
 }
 
 // Bus defines the interface for the task bus
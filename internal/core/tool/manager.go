@@ -4,11 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	agentconfig "github.com/ClareAI/astra-voice-service/internal/config"
 	"github.com/ClareAI/astra-voice-service/internal/domain"
 	"github.com/ClareAI/astra-voice-service/pkg/data/mcp"
 	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/metrics"
+	"github.com/ClareAI/astra-voice-service/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
@@ -49,10 +53,25 @@ var AccentChangeSchema = map[string]interface{}{
 	"required": []string{"language", "accent"},
 }
 
+// EndCallSchema defines the schema for the end_call tool
+var EndCallSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"reason": map[string]interface{}{
+			"type":        "string",
+			"enum":        []string{"completed", "user_requested", "wrong_number", "voicemail", "abusive", "other"},
+			"description": "Why the call is ending: 'completed' when the user's needs are handled, 'user_requested' when the user asks to end the call, 'wrong_number', 'voicemail' when an answering machine picked up, 'abusive' for abusive callers, or 'other'.",
+		},
+	},
+	"required": []string{"reason"},
+}
+
 // Tool name constants
 const (
 	ToolNameNotifyLanguageSwitch = "notify_language_switch"
 	ToolNameNotifyAccentChange   = "notify_accent_change"
+	ToolNameScheduleCallback     = "schedule_callback"
+	ToolNameEndCall              = "end_call"
 )
 
 /*
@@ -115,6 +134,7 @@ type ToolManager struct {
 	ConnectionGetter func(connectionID string) ToolConnection
 	registry         map[string]*ToolDefinition // Tool registry
 	ComposioService  *mcp.ComposioService       // Optional MCP service
+	Callbacks        CallbackScheduler          // Optional, stores schedule_callback requests
 }
 
 // ToolConnection provides connection information for tool execution
@@ -126,6 +146,9 @@ type ToolConnection interface {
 	GetAgentID() string     // Added GetAgentID for MCP support
 	GetTextAgentID() string // Added GetTextAgentID for MCP support
 	GetChannelType() string // Added GetChannelType for MCP support
+
+	// GetVariables returns the per-call variables, which are passed to MCP tools
+	GetVariables() map[string]string
 }
 
 // NewToolManager creates a new tool manager instance
@@ -178,6 +201,28 @@ func (m *ToolManager) registerBuiltInTools() {
 		Executor:     nil, // Special handling in functions.go
 	})
 
+	// Register call back scheduling tool
+	// Enabled per agent through allowed_actions; see schedule_callback.go
+	m.RegisterTool(&ToolDefinition{
+		Name:         ToolNameScheduleCallback,
+		Description:  "Schedule a phone call back to the user when they ask to be called later, e.g. \"call me back tomorrow at 3\" or \"call me in an hour\". Confirm the day and time with the user first, then call this function once. Convert the time to 24-hour HH:MM in the user's timezone. Tell the user the time in the result, which may be moved into business hours.",
+		Parameters:   ScheduleCallbackSchema,
+		TemplateName: "",
+		Executor:     m.ExecuteScheduleCallback,
+	})
+
+	// Register hang up tool
+	// Enabled per agent through allowed_actions
+	// Note: This tool has special handling in functions.go (handleEndCall), as the call
+	// is ended only after the goodbye has been played
+	m.RegisterTool(&ToolDefinition{
+		Name:         ToolNameEndCall,
+		Description:  "End the phone call. Call this when the conversation is over: the user's needs are handled and they have nothing else, the user asks to hang up, it is a wrong number, an answering machine picked up, or the caller is abusive. Say a short goodbye in the same response BEFORE calling this function, and do not say anything after it; the call is hung up once your goodbye has played.",
+		Parameters:   EndCallSchema,
+		TemplateName: "",  // No template needed for system actions
+		Executor:     nil, // Special handling in functions.go
+	})
+
 	// ========================================
 	// Examples: Add more tools with default executors
 	// ========================================
@@ -277,11 +322,31 @@ func (m *ToolManager) GetMcpToolDefinitions(ctx context.Context, agentID string,
 	return tools, nil
 }
 
+// metricsLabel bounds tool metric labels to the registered tools; MCP tool names vary per agent
+func (m *ToolManager) metricsLabel(toolName string) string {
+	if _, ok := m.registry[toolName]; ok {
+		return toolName
+	}
+	return metrics.ToolOther
+}
+
 // ExecuteTool is the unified entry point for all tool executions
 // Routes to the appropriate executor based on tool registration
 // If tool is not registered, uses default booking executor
-func (m *ToolManager) ExecuteTool(toolName string, argumentsJSON string, connectionID string, modality string) (string, error) {
+func (m *ToolManager) ExecuteTool(toolName string, argumentsJSON string, connectionID string, modality string) (_ string, err error) {
 	logger.Base().Info("ExecuteTool called: for connection", zap.String("toolname", toolName), zap.String("connection_id", connectionID))
+	defer metrics.ObserveToolCall(m.metricsLabel(toolName), time.Now(), &err)
+
+	ctx, span := tracing.Start(tracing.CallContext(connectionID), "tool "+toolName,
+		attribute.String("tool.name", toolName),
+		attribute.String("tool.modality", modality),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	// Built-in tools with an executor run in-process
+	if registered, ok := m.registry[toolName]; ok && registered.Executor != nil {
+		return registered.Executor(toolName, registered.TemplateName, argumentsJSON, connectionID)
+	}
 
 	// Try executing with MCP first
 	if m.ComposioService == nil {
@@ -290,6 +355,7 @@ func (m *ToolManager) ExecuteTool(toolName string, argumentsJSON string, connect
 
 	// Get agent ID from connection
 	agentID := ""
+	var callVariables map[string]string
 	mode := agentconfig.AgentConfigModePublished // Default mode
 	if modality == "" {
 		modality = mcp.ModalityVoiceInbound // Default modality
@@ -298,6 +364,7 @@ func (m *ToolManager) ExecuteTool(toolName string, argumentsJSON string, connect
 	if m.ConnectionGetter != nil {
 		if conn := m.ConnectionGetter(connectionID); conn != nil {
 			agentID = conn.GetAgentID()
+			callVariables = conn.GetVariables()
 			// Use TextAgentID if available for MCP calls
 			if textAgentID := conn.GetTextAgentID(); textAgentID != "" {
 				logger.Base().Info("Using TextAgentID for MCP call: (VoiceAgentID: )", zap.String("agent_id", agentID), zap.String("textagentid", textAgentID))
@@ -328,8 +395,13 @@ func (m *ToolManager) ExecuteTool(toolName string, argumentsJSON string, connect
 	argsJSON, _ := json.Marshal(args)
 	logger.Base().Info("MCP tool call", zap.String("tool_name", toolName), zap.String("arguments", string(argsJSON)))
 
-	// Call MCP tool
-	mcpResult, err := m.ComposioService.CallToolMCP(context.Background(), agentID, mode, toolName, args, modality)
+	// Call MCP tool; the call's variables travel in the request metadata so tools can use
+	// them without the model repeating them as arguments
+	var meta map[string]interface{}
+	if len(callVariables) > 0 {
+		meta = map[string]interface{}{"call_variables": callVariables}
+	}
+	mcpResult, err := m.ComposioService.CallToolMCP(ctx, agentID, mode, toolName, args, meta, modality)
 	if err != nil {
 		logger.Base().Error("MCP tool execution skipped/failed: .")
 		return "", fmt.Errorf("MCP tool execution failed: %w", err)
@@ -0,0 +1,144 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/internal/domain"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// scheduleTimeout bounds storing a call back while the caller waits for the reply
+const scheduleTimeout = 10 * time.Second
+
+// ScheduleCallbackSchema defines the schema for the schedule_callback tool
+var ScheduleCallbackSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"date": map[string]interface{}{
+			"type":        "string",
+			"description": "Day of the call back in the user's timezone: 'today', 'tomorrow', a weekday name such as 'friday' (the next one; today's weekday means next week), or a date as YYYY-MM-DD. Omit for the next occurrence of the time.",
+		},
+		"time": map[string]interface{}{
+			"type":        "string",
+			"description": "Time of the call back in the user's timezone as 24-hour HH:MM, e.g. '15:00' for 3 pm. Required unless in_minutes is given.",
+		},
+		"in_minutes": map[string]interface{}{
+			"type":        "integer",
+			"description": "For relative requests such as 'in half an hour': minutes from now. Takes precedence over date and time.",
+		},
+		"timezone": map[string]interface{}{
+			"type":        "string",
+			"description": "The user's IANA timezone, e.g. 'Asia/Singapore'. Infer it from where the user says they are or from their phone number's country code; omit if unsure to use the business timezone.",
+		},
+		"reason": map[string]interface{}{
+			"type":        "string",
+			"description": "Why the user wants to be called back, in a few words.",
+		},
+		"summary": map[string]interface{}{
+			"type":        "string",
+			"description": "A brief summary of this call so far: who the user is, what they need and what was already discussed or agreed. It is given to the agent on the call back.",
+		},
+	},
+	"required": []string{"summary"},
+}
+
+// CallbackScheduler stores call backs requested during calls
+type CallbackScheduler interface {
+	Schedule(ctx context.Context, req *domain.ScheduleCallbackRequest) (*domain.CallbackJob, error)
+}
+
+// callDetails is implemented by call connections that know the call's voice settings and
+// stored conversation
+type callDetails interface {
+	GetVoiceLanguage() string
+	GetAccent() string
+	GetConversationID() string
+}
+
+// scheduleCallbackArgs are the arguments of the schedule_callback tool
+type scheduleCallbackArgs struct {
+	Date      string `json:"date"`
+	Time      string `json:"time"`
+	InMinutes int    `json:"in_minutes"`
+	Timezone  string `json:"timezone"`
+	Reason    string `json:"reason"`
+	Summary   string `json:"summary"`
+}
+
+// ExecuteScheduleCallback schedules an outbound call back to the caller. Problems the model
+// can fix, such as a time in the past, are returned as unsuccessful results to relay.
+func (m *ToolManager) ExecuteScheduleCallback(toolName, templateName, argumentsJSON, connectionID string) (string, error) {
+	if m.Callbacks == nil {
+		return callbackResult(false, "Call backs are not available. Tell the user you cannot schedule a call back."), nil
+	}
+	if m.ConnectionGetter == nil {
+		return "", fmt.Errorf("connection getter not configured")
+	}
+	conn := m.ConnectionGetter(connectionID)
+	if conn == nil {
+		return "", fmt.Errorf("connection not found: %s", connectionID)
+	}
+
+	// Call backs are placed as WhatsApp outbound calls
+	if conn.GetChannelType() != string(domain.ChannelTypeWhatsApp) {
+		return callbackResult(false, "Call backs can only be scheduled on WhatsApp calls. Tell the user you cannot schedule a call back on this call."), nil
+	}
+
+	var args scheduleCallbackArgs
+	if err := json.Unmarshal([]byte(argumentsJSON), &args); err != nil {
+		return callbackResult(false, "Invalid arguments. Give the call back time and a summary of the call."), nil
+	}
+
+	req := &domain.ScheduleCallbackRequest{
+		AgentID:            conn.GetAgentID(),
+		TenantID:           conn.GetTenantID(),
+		ChannelType:        conn.GetChannelType(),
+		WAID:               conn.GetFrom(),
+		ChannelPhoneNumber: conn.GetBusinessNumber(),
+		ContactName:        conn.GetContactName(),
+		SourceConnectionID: connectionID,
+		Variables:          conn.GetVariables(),
+		Date:               args.Date,
+		Time:               args.Time,
+		InMinutes:          args.InMinutes,
+		Timezone:           args.Timezone,
+		Reason:             args.Reason,
+		Summary:            args.Summary,
+	}
+	if details, ok := conn.(callDetails); ok {
+		req.VoiceLanguage = details.GetVoiceLanguage()
+		req.Accent = details.GetAccent()
+		req.SourceConversationID = details.GetConversationID()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), scheduleTimeout)
+	defer cancel()
+
+	job, err := m.Callbacks.Schedule(ctx, req)
+	if err != nil {
+		logger.Base().Warn("Failed to schedule callback", zap.String("connection_id", connectionID), zap.Error(err))
+		return callbackResult(false, fmt.Sprintf("The call back was not scheduled: %v. Ask the user for another time if needed.", err)), nil
+	}
+
+	location, err := time.LoadLocation(job.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	scheduled := job.ScheduledAt.In(location).Format("Monday, 2 January at 15:04")
+	message := fmt.Sprintf("Call back scheduled for %s (%s). Confirm the time to the user.", scheduled, job.Timezone)
+	if !job.ScheduledAt.Equal(job.RequestedAt) {
+		requested := job.RequestedAt.In(location).Format("Monday, 2 January at 15:04")
+		message = fmt.Sprintf("%s is outside working hours, so the call back was scheduled for the next available time, %s (%s). Tell the user.", requested, scheduled, job.Timezone)
+	}
+	return callbackResult(true, message), nil
+}
+
+// callbackResult formats a schedule_callback result for the model
+func callbackResult(success bool, message string) string {
+	result, _ := json.Marshal(map[string]interface{}{"success": success, "message": message})
+	return string(result)
+}
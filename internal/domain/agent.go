@@ -86,6 +86,9 @@ type AgentConfigData struct {
 	// Call Configuration
 	MaxCallDuration int                `json:"max_call_duration,omitempty"` // in seconds
 	SilenceConfig   *SilenceConfigData `json:"silence_config,omitempty"`
+	VADConfig       *VADConfigData     `json:"vad_config,omitempty"`
+	DSPConfig       *DSPConfigData     `json:"dsp_config,omitempty"`
+	AudioCues       *AudioCueData      `json:"audio_cues,omitempty"`
 
 	// Prompt Configuration
 	PromptConfig *PromptConfigData `json:"prompt_config,omitempty"`
@@ -116,9 +119,22 @@ type PromptConfigData struct {
 	LanguageInstructions map[string]string `json:"language_instructions,omitempty"` // Accent configuration per language, e.g., {"en": "india", "zh": "mainland"}
 	CustomVariables      map[string]string `json:"custom_variables,omitempty"`
 
+	CallVariables []CallVariableData `json:"call_variables,omitempty"` // Per-call variables callers may pass when starting a call
+
 	// Language & Accent Adaptation Settings (default: true)
 	AutoLanguageSwitching *bool `json:"auto_language_switching,omitempty"` // Enable automatic language switching based on caller's language
 	AutoAccentAdaptation  *bool `json:"auto_accent_adaptation,omitempty"`  // Enable automatic accent adaptation based on caller's region
+
+	CacheGreeting bool `json:"cache_greeting,omitempty"` // Play a pre-rendered greeting when it does not use contact details
+}
+
+// CallVariableData declares a per-call variable
+type CallVariableData struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type,omitempty"` // string (default), number, boolean, date or datetime
+	Required    bool     `json:"required,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Enum        []string `json:"enum,omitempty"` // allowed values
 }
 
 // RAGConfigData contains RAG-specific configuration
@@ -178,6 +194,52 @@ type SilenceConfigData struct {
 	InactivityMessage       string `json:"inactivity_message,omitempty"`
 }
 
+// VADConfigData contains configuration for local voice activity detection
+type VADConfigData struct {
+	Enabled             bool    `json:"enabled"`
+	EnergyThresholdDb   float64 `json:"energy_threshold_db,omitempty"` // dBFS
+	NoiseMarginDb       float64 `json:"noise_margin_db,omitempty"`
+	MaxSpectralFlatness float64 `json:"max_spectral_flatness,omitempty"`
+	MaxZeroCrossingRate float64 `json:"max_zero_crossing_rate,omitempty"`
+	OnsetMs             int     `json:"onset_ms,omitempty"`
+	HangoverMs          int     `json:"hangover_ms,omitempty"`
+	PreRollMs           int     `json:"pre_roll_ms,omitempty"`
+}
+
+// AudioCueData selects the audio played to the caller while tools run.
+// Cue names refer to audio uploaded for the agent.
+type AudioCueData struct {
+	HoldMusic          string                 `json:"hold_music,omitempty"` // empty plays the bundled cue
+	SilenceThresholdMs int                    `json:"silence_threshold_ms,omitempty"`
+	DefaultMode        string                 `json:"default_mode,omitempty"` // silence, bgm or filler
+	Fillers            map[string]string      `json:"fillers,omitempty"`      // language code (or "default") -> cue name
+	Tools              map[string]ToolCueData `json:"tools,omitempty"`        // tool name -> cue behaviour
+	Mix                bool                   `json:"mix,omitempty"`          // mix cues and ambience under speech
+	Ambience           string                 `json:"ambience,omitempty"`     // cue looped for the whole call
+	AmbienceGainDb     float64                `json:"ambience_gain_db,omitempty"`
+	HoldMusicGainDb    float64                `json:"hold_music_gain_db,omitempty"`
+	DuckingDb          float64                `json:"ducking_db,omitempty"`
+}
+
+// ToolCueData overrides the cue behaviour for a single tool
+type ToolCueData struct {
+	Mode string `json:"mode,omitempty"` // silence, bgm or filler
+	Cue  string `json:"cue,omitempty"`  // overrides the hold music or filler
+}
+
+// DSPConfigData contains configuration for the inbound audio processing chain.
+// Unset stage toggles are enabled.
+type DSPConfigData struct {
+	Enabled          bool    `json:"enabled"`
+	HighPassHz       float64 `json:"high_pass_hz,omitempty"`
+	NoiseSuppression *bool   `json:"noise_suppression,omitempty"`
+	SuppressionDb    float64 `json:"suppression_db,omitempty"`
+	AGC              *bool   `json:"agc,omitempty"`
+	TargetLevelDb    float64 `json:"target_level_db,omitempty"` // dBFS
+	MaxGainDb        float64 `json:"max_gain_db,omitempty"`
+	Limiter          *bool   `json:"limiter,omitempty"`
+}
+
 // Implement driver.Valuer interface for AgentConfigData
 func (a AgentConfigData) Value() (driver.Value, error) {
 	// Check if the struct is empty by marshaling and checking if it's just "{}"
@@ -0,0 +1,78 @@
+package domain
+
+import "time"
+
+// Callback job statuses
+const (
+	CallbackStatusScheduled = "scheduled" // waiting for its time
+	CallbackStatusDialing   = "dialing"   // claimed by a worker
+	CallbackStatusPlaced    = "placed"    // outbound call started; its outcome is on the call attempt
+	CallbackStatusFailed    = "failed"
+	CallbackStatusCancelled = "cancelled"
+)
+
+// CallbackJob is a call back the caller asked for during a call, placed as an outbound
+// call to the same contact with the same agent
+type CallbackJob struct {
+	ID                   string     `json:"id" gorm:"column:id;primaryKey"`
+	TenantID             string     `json:"tenant_id" gorm:"column:tenant_id;index"`
+	AgentID              string     `json:"agent_id" gorm:"column:agent_id;index"`
+	ChannelType          string     `json:"channel_type" gorm:"column:channel_type"`
+	WAID                 string     `json:"waid" gorm:"column:waid;index"`
+	ChannelPhoneNumber   string     `json:"channel_phone_number" gorm:"column:channel_phone_number"` // Business number the caller reached
+	ContactName          string     `json:"contact_name" gorm:"column:contact_name"`
+	VoiceLanguage        string     `json:"voice_language" gorm:"column:voice_language"`
+	Accent               string     `json:"accent" gorm:"column:accent"`
+	Timezone             string     `json:"timezone" gorm:"column:timezone"`                   // IANA name the requested time was given in
+	RequestedAt          time.Time  `json:"requested_at" gorm:"column:requested_at"`           // Time the caller asked for
+	ScheduledAt          time.Time  `json:"scheduled_at" gorm:"column:scheduled_at;index"`     // Next time to call, inside working hours
+	Reason               string     `json:"reason,omitempty" gorm:"column:reason"`             // Why the caller wants a call back
+	Summary              string     `json:"summary,omitempty" gorm:"column:summary;type:text"` // Summary of the call, given to the agent on the call back
+	SourceConnectionID   string     `json:"source_connection_id" gorm:"column:source_connection_id"`
+	SourceConversationID string     `json:"source_conversation_id,omitempty" gorm:"column:source_conversation_id"`
+	Variables            JSONB      `json:"variables,omitempty" gorm:"column:variables;type:jsonb"` // Per-call variables of the source call
+	Status               string     `json:"status" gorm:"column:status;index"`
+	Attempts             int        `json:"attempts" gorm:"column:attempts"`
+	ConnectionID         string     `json:"connection_id,omitempty" gorm:"column:connection_id"` // Connection of the placed call
+	LastError            string     `json:"last_error,omitempty" gorm:"column:last_error"`
+	PlacedAt             *time.Time `json:"placed_at,omitempty" gorm:"column:placed_at"`
+	CreatedAt            time.Time  `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt            time.Time  `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func (CallbackJob) TableName() string {
+	return "callback_jobs"
+}
+
+// ScheduleCallbackRequest is a call back requested through the schedule_callback tool.
+// Date and Time are local to Timezone; InMinutes, when set, takes precedence.
+type ScheduleCallbackRequest struct {
+	AgentID              string
+	TenantID             string
+	ChannelType          string
+	WAID                 string
+	ChannelPhoneNumber   string
+	ContactName          string
+	VoiceLanguage        string
+	Accent               string
+	SourceConnectionID   string
+	SourceConversationID string
+	Variables            map[string]string
+
+	Date      string // today, tomorrow, a weekday name or YYYY-MM-DD
+	Time      string // HH:MM
+	InMinutes int
+	Timezone  string
+	Reason    string
+	Summary   string
+}
+
+// CallbackFilter filters listed callback jobs; empty fields match any value
+type CallbackFilter struct {
+	TenantID string
+	AgentID  string
+	WAID     string
+	Status   string
+	Limit    int
+	Offset   int
+}
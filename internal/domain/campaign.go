@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Campaign statuses
+const (
+	CampaignStatusDraft     = "draft"     // created, contacts may still be added
+	CampaignStatusRunning   = "running"   // the worker dials due contacts inside the schedule
+	CampaignStatusPaused    = "paused"    // no new calls; calls in progress continue
+	CampaignStatusCompleted = "completed" // every contact reached a final status
+	CampaignStatusCancelled = "cancelled" // stopped; undialled contacts were cancelled
+)
+
+// Campaign contact statuses
+const (
+	ContactStatusPending            = "pending"
+	ContactStatusAwaitingPermission = "awaiting_permission" // WhatsApp call permission requested from the contact
+	ContactStatusDialing            = "dialing"
+	ContactStatusInCall             = "in_call"
+	ContactStatusRetryScheduled     = "retry_scheduled"
+	ContactStatusDone               = "done"
+	ContactStatusCancelled          = "cancelled"
+)
+
+// Campaign contact dispositions, the outcome of the latest attempt
+const (
+	DispositionAnswered         = "answered"
+	DispositionNoAnswer         = "no_answer"
+	DispositionRejected         = "rejected"
+	DispositionPermissionDenied = "permission_denied"
+	DispositionFailed           = "failed"
+)
+
+// OutboundCampaign is a list of contacts an agent calls within schedule windows
+type OutboundCampaign struct {
+	ID                   string          `json:"id" gorm:"column:id;primaryKey"`
+	Name                 string          `json:"name" gorm:"column:name"`
+	TenantID             string          `json:"tenant_id" gorm:"column:tenant_id;index"`
+	VoiceAgentID         string          `json:"voice_agent_id" gorm:"column:voice_agent_id"`
+	ChannelPhoneNumber   string          `json:"channel_phone_number" gorm:"column:channel_phone_number"` // Business number calls are placed from
+	VoiceLanguage        string          `json:"voice_language" gorm:"column:voice_language"`
+	Accent               string          `json:"accent" gorm:"column:accent"`
+	Status               string          `json:"status" gorm:"column:status;index"`
+	Timezone             string          `json:"timezone" gorm:"column:timezone"` // IANA name; schedule windows are local to it
+	Schedule             ScheduleWindows `json:"schedule" gorm:"column:schedule;type:jsonb"`
+	StartAt              *time.Time      `json:"start_at,omitempty" gorm:"column:start_at"`
+	EndAt                *time.Time      `json:"end_at,omitempty" gorm:"column:end_at"`
+	MaxAttempts          int             `json:"max_attempts" gorm:"column:max_attempts"`
+	RetryIntervalMinutes int             `json:"retry_interval_minutes" gorm:"column:retry_interval_minutes"`
+	CreatedAt            time.Time       `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt            time.Time       `json:"updated_at" gorm:"column:updated_at"`
+	CompletedAt          *time.Time      `json:"completed_at,omitempty" gorm:"column:completed_at"`
+}
+
+func (OutboundCampaign) TableName() string {
+	return "outbound_campaigns"
+}
+
+// CampaignNumberLimit bounds the campaign calls in progress from a tenant's business number,
+// across its campaigns
+type CampaignNumberLimit struct {
+	TenantID           string    `json:"tenant_id" gorm:"column:tenant_id;primaryKey"`
+	ChannelPhoneNumber string    `json:"channel_phone_number" gorm:"column:channel_phone_number;primaryKey"` // Empty for the tenant's default number
+	MaxConcurrent      int       `json:"max_concurrent" gorm:"column:max_concurrent"`
+	UpdatedAt          time.Time `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func (CampaignNumberLimit) TableName() string {
+	return "campaign_number_limits"
+}
+
+// CampaignContact is one contact of a campaign and the state of its calls
+type CampaignContact struct {
+	ID            string     `json:"id" gorm:"column:id;primaryKey"`
+	CampaignID    string     `json:"campaign_id" gorm:"column:campaign_id;index"`
+	WAID          string     `json:"waid" gorm:"column:waid"`
+	Name          string     `json:"name" gorm:"column:name"`
+	Variables     JSONB      `json:"variables,omitempty" gorm:"column:variables;type:jsonb"`
+	Status        string     `json:"status" gorm:"column:status;index"`
+	Disposition   string     `json:"disposition,omitempty" gorm:"column:disposition"`
+	Attempts      int        `json:"attempts" gorm:"column:attempts"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty" gorm:"column:next_attempt_at"`
+	LastAttemptAt *time.Time `json:"last_attempt_at,omitempty" gorm:"column:last_attempt_at"`
+	ConnectionID  string     `json:"connection_id,omitempty" gorm:"column:connection_id;index"`
+	CallID        string     `json:"call_id,omitempty" gorm:"column:call_id;index"`
+	LastError     string     `json:"last_error,omitempty" gorm:"column:last_error"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func (CampaignContact) TableName() string {
+	return "campaign_contacts"
+}
+
+// ScheduleWindow is a daily calling window, e.g. {"days": ["mon", "fri"], "start": "09:00", "end": "17:30"}.
+// An end before the start spans midnight; no days means every day.
+type ScheduleWindow struct {
+	Days  []string `json:"days,omitempty"`
+	Start string   `json:"start"`
+	End   string   `json:"end"`
+}
+
+// ScheduleWindows is a campaign's calling schedule; empty means any time
+type ScheduleWindows []ScheduleWindow
+
+// Implement driver.Valuer interface for ScheduleWindows
+func (s ScheduleWindows) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return json.Marshal(s)
+}
+
+// Implement sql.Scanner interface for ScheduleWindows
+func (s *ScheduleWindows) Scan(value interface{}) error {
+	if value == nil {
+		*s = nil
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into ScheduleWindows", value)
+	}
+
+	return json.Unmarshal(bytes, s)
+}
+
+// CreateCampaignRequest represents the request to create a campaign
+type CreateCampaignRequest struct {
+	Name                 string          `json:"name" validate:"required"`
+	TenantID             string          `json:"tenant_id"` // Defaults to the agent's tenant
+	VoiceAgentID         string          `json:"voice_agent_id" validate:"required"`
+	ChannelPhoneNumber   string          `json:"channel_phone_number"`
+	VoiceLanguage        string          `json:"voice_language"`
+	Accent               string          `json:"accent"`
+	Timezone             string          `json:"timezone"`
+	Schedule             ScheduleWindows `json:"schedule"`
+	StartAt              *time.Time      `json:"start_at"`
+	EndAt                *time.Time      `json:"end_at"`
+	MaxAttempts          int             `json:"max_attempts"`
+	RetryIntervalMinutes int             `json:"retry_interval_minutes"`
+}
+
+// CampaignContactInput is a contact to add to a campaign
+type CampaignContactInput struct {
+	WAID      string                 `json:"waid"`
+	Name      string                 `json:"name"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// CampaignWithStats is a campaign with its contact counts by status
+type CampaignWithStats struct {
+	OutboundCampaign
+	ContactCounts map[string]int64 `json:"contact_counts"`
+}
@@ -32,6 +32,34 @@ func (j *JSONB) Scan(value interface{}) error {
 	return json.Unmarshal(bytes, j)
 }
 
+// StringMapJSONB converts a string map to JSONB, returning nil for an empty map
+func StringMapJSONB(m map[string]string) JSONB {
+	if len(m) == 0 {
+		return nil
+	}
+	j := make(JSONB, len(m))
+	for k, v := range m {
+		j[k] = v
+	}
+	return j
+}
+
+// StringMap returns the string values of a JSONB field, formatting other values
+func (j JSONB) StringMap() map[string]string {
+	if len(j) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(j))
+	for k, v := range j {
+		if s, ok := v.(string); ok {
+			m[k] = s
+		} else if v != nil {
+			m[k] = fmt.Sprint(v)
+		}
+	}
+	return m
+}
+
 // CallStatus constants for call session status
 const (
 	CallStatusActive    = "active"
@@ -45,7 +73,9 @@ type ChannelType string
 
 const (
 	ChannelTypeWhatsApp ChannelType = "whatsapp" // WhatsApp channel (needs audio caching)
-	ChannelTypeLiveKit  ChannelType = "livekit"  // LiveKit channel (no audio caching)
+	ChannelTypeLiveKit  ChannelType = "livekit"  // LiveKit channel
 	ChannelTypeTest     ChannelType = "test"     // Test channel
 	ChannelTypeWeb      ChannelType = "web"      // Web channel
+	ChannelTypeSIP      ChannelType = "sip"      // SIP trunk (PSTN) channel
+	ChannelTypeTwilio   ChannelType = "twilio"   // Twilio Media Streams (PSTN) channel
 )
@@ -0,0 +1,104 @@
+package domain
+
+import "time"
+
+// Outbound call attempt states
+const (
+	OutboundCallStateCreated             = "created"
+	OutboundCallStatePermissionRequested = "permission_requested" // WhatsApp call permission requested from the user
+	OutboundCallStatePermissionGranted   = "permission_granted"
+	OutboundCallStatePermissionDenied    = "permission_denied"
+	OutboundCallStateDialing             = "dialing" // call placed with Wati, call ID known
+	OutboundCallStateRinging             = "ringing"
+	OutboundCallStateAnswered            = "answered"
+	OutboundCallStateRejected            = "rejected"
+	OutboundCallStateEnded               = "ended"
+	OutboundCallStateFailed              = "failed"
+)
+
+// outboundCallTransitions lists the states each outbound call state may move to;
+// states without an entry are final
+var outboundCallTransitions = map[string][]string{
+	OutboundCallStateCreated: {
+		OutboundCallStatePermissionRequested,
+		OutboundCallStatePermissionDenied,
+		OutboundCallStateDialing,
+		OutboundCallStateFailed,
+	},
+	OutboundCallStatePermissionRequested: {
+		OutboundCallStatePermissionGranted,
+		OutboundCallStatePermissionDenied,
+		OutboundCallStateFailed,
+	},
+	OutboundCallStatePermissionGranted: {
+		OutboundCallStateDialing,
+		OutboundCallStateFailed,
+	},
+	OutboundCallStateDialing: {
+		OutboundCallStateRinging,
+		OutboundCallStateAnswered,
+		OutboundCallStateRejected,
+		OutboundCallStateEnded,
+		OutboundCallStateFailed,
+	},
+	OutboundCallStateRinging: {
+		OutboundCallStateAnswered,
+		OutboundCallStateRejected,
+		OutboundCallStateEnded,
+		OutboundCallStateFailed,
+	},
+	OutboundCallStateAnswered: {
+		OutboundCallStateEnded,
+		OutboundCallStateFailed,
+	},
+}
+
+// OutboundCallStatesBefore returns the states an outbound call may move to a state from
+func OutboundCallStatesBefore(to string) []string {
+	var states []string
+	for from, nexts := range outboundCallTransitions {
+		for _, next := range nexts {
+			if next == to {
+				states = append(states, from)
+			}
+		}
+	}
+	return states
+}
+
+// IsOutboundCallFinal reports whether an outbound call state is final
+func IsOutboundCallFinal(state string) bool {
+	_, ok := outboundCallTransitions[state]
+	return !ok
+}
+
+// OutboundCallAttempt is the persisted state of one outbound call, shared by all pods so
+// Wati webhooks can be resolved wherever they land. Its ID is the call's connection ID.
+type OutboundCallAttempt struct {
+	ID                 string     `json:"id" gorm:"column:id;primaryKey"`
+	State              string     `json:"state" gorm:"column:state;index"`
+	TenantID           string     `json:"tenant_id" gorm:"column:tenant_id"`
+	AgentID            string     `json:"agent_id" gorm:"column:agent_id"`
+	ChannelType        string     `json:"channel_type" gorm:"column:channel_type"`
+	WAID               string     `json:"waid" gorm:"column:waid;index"`
+	ChannelPhoneNumber string     `json:"channel_phone_number" gorm:"column:channel_phone_number"`
+	VoiceLanguage      string     `json:"voice_language" gorm:"column:voice_language"`
+	Accent             string     `json:"accent" gorm:"column:accent"`
+	ContactName        string     `json:"contact_name" gorm:"column:contact_name"`
+	Variables          JSONB      `json:"variables,omitempty" gorm:"column:variables;type:jsonb"` // Per-call variables, validated when the call was requested
+	CallID             string     `json:"call_id,omitempty" gorm:"column:call_id;index"`
+	PodID              string     `json:"pod_id,omitempty" gorm:"column:pod_id"` // Pod holding the call's WebRTC connection
+	LastError          string     `json:"last_error,omitempty" gorm:"column:last_error"`
+	StateChangedAt     time.Time  `json:"state_changed_at" gorm:"column:state_changed_at;index"`
+	AnsweredAt         *time.Time `json:"answered_at,omitempty" gorm:"column:answered_at"`
+	EndedAt            *time.Time `json:"ended_at,omitempty" gorm:"column:ended_at"`
+	CreatedAt          time.Time  `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt          time.Time  `json:"updated_at" gorm:"column:updated_at"`
+
+	// Summary of an earlier call when this call is a call back the user asked for
+	PreviousCallSummary string `json:"previous_call_summary,omitempty" gorm:"column:previous_call_summary;type:text"`
+}
+
+func (OutboundCallAttempt) TableName() string {
+	return "outbound_call_attempts"
+}
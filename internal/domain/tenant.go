@@ -1,9 +1,19 @@
 package domain
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
 )
 
+// TenantConfigRecordingFormats is the custom_config key listing extra recording export formats
+// (e.g. ["wav_stereo", "mp3"] or "wav_mono,mp3"); the stereo Opus recording is always produced
+const TenantConfigRecordingFormats = "recording_formats"
+
+// TenantConfigAudioDSP is the custom_config key enabling the inbound audio DSP chain for the
+// tenant's agents, either true or a DSP config object; an agent's own dsp_config takes precedence
+const TenantConfigAudioDSP = "audio_dsp"
+
 // VoiceTenant represents a tenant in the voice system
 type VoiceTenant struct {
 	ID           string    `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
@@ -21,6 +31,44 @@ func (VoiceTenant) TableName() string {
 	return "voice_tenants"
 }
 
+// RecordingFormats returns the extra recording export formats configured for the tenant
+func (t *VoiceTenant) RecordingFormats() []string {
+	switch value := t.CustomConfig[TenantConfigRecordingFormats].(type) {
+	case []interface{}:
+		formats := make([]string, 0, len(value))
+		for _, item := range value {
+			if format, ok := item.(string); ok {
+				formats = append(formats, format)
+			}
+		}
+		return formats
+	case string:
+		return strings.Split(value, ",")
+	default:
+		return nil
+	}
+}
+
+// AudioDSPConfig returns the tenant-wide inbound audio DSP settings, or nil if not configured
+func (t *VoiceTenant) AudioDSPConfig() *DSPConfigData {
+	switch value := t.CustomConfig[TenantConfigAudioDSP].(type) {
+	case bool:
+		return &DSPConfigData{Enabled: value}
+	case map[string]interface{}:
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil
+		}
+		var cfg DSPConfigData
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			return nil
+		}
+		return &cfg
+	default:
+		return nil
+	}
+}
+
 // CreateVoiceTenantRequest represents the request to create a new voice tenant
 type CreateVoiceTenantRequest struct {
 	TenantID     string `json:"tenant_id" validate:"required"`
@@ -24,6 +24,8 @@ type VoiceConversation struct {
 	BusinessNumber         string             `json:"business_number" db:"business_number" gorm:"column:business_number"`
 	StartedAt              time.Time          `json:"started_at" db:"started_at" gorm:"column:started_at"`
 	EndedAt                time.Time          `json:"ended_at" db:"ended_at" gorm:"column:ended_at"`
+	EndReason              string             `json:"end_reason,omitempty" db:"end_reason" gorm:"column:end_reason"`         // Why the agent ended the call, if it did
+	Variables              JSONB              `json:"variables,omitempty" db:"variables" gorm:"column:variables;type:jsonb"` // Per-call variables the call was started with
 	CreatedAt              time.Time          `json:"created_at" db:"created_at" gorm:"column:created_at"`
 	UpdatedAt              time.Time          `json:"updated_at" db:"updated_at" gorm:"column:updated_at"`
 }
@@ -44,8 +46,52 @@ type VoiceMessage struct {
 	Confidence         float64   `json:"confidence" db:"confidence" gorm:"column:confidence"`
 	CreatedAt          time.Time `json:"created_at" db:"created_at" gorm:"column:created_at"`
 	UpdatedAt          time.Time `json:"updated_at" db:"updated_at" gorm:"column:updated_at"`
+
+	// Turn latency (assistant replies only); all values are measured from the user's speech_stopped
+	ModelProvider       string `json:"model_provider,omitempty" db:"model_provider" gorm:"column:model_provider"`
+	CommitLatencyMs     *int64 `json:"commit_latency_ms,omitempty" db:"commit_latency_ms" gorm:"column:commit_latency_ms"`
+	ResponseLatencyMs   *int64 `json:"response_latency_ms,omitempty" db:"response_latency_ms" gorm:"column:response_latency_ms"`
+	FirstAudioLatencyMs *int64 `json:"first_audio_latency_ms,omitempty" db:"first_audio_latency_ms" gorm:"column:first_audio_latency_ms"`
+	VoiceToVoiceMs      *int64 `json:"voice_to_voice_ms,omitempty" db:"voice_to_voice_ms" gorm:"column:voice_to_voice_ms"`
+	ToolDurationMs      int64  `json:"tool_duration_ms,omitempty" db:"tool_duration_ms" gorm:"column:tool_duration_ms"`
+	RAGDurationMs       int64  `json:"rag_duration_ms,omitempty" db:"rag_duration_ms" gorm:"column:rag_duration_ms"`
 }
 
 func (VoiceMessage) TableName() string {
 	return "voice_messages"
 }
+
+// VoiceRecording is a recording file produced for a voice conversation
+type VoiceRecording struct {
+	ID             string    `json:"id" db:"id" gorm:"column:id;primaryKey"`
+	ConversationID string    `json:"conversation_id" db:"conversation_id" gorm:"column:conversation_id;index"`
+	Format         string    `json:"format" db:"format" gorm:"column:format"` // opus, wav_mono, wav_stereo, mp3
+	Channels       int       `json:"channels" db:"channels" gorm:"column:channels"`
+	SampleRate     int       `json:"sample_rate" db:"sample_rate" gorm:"column:sample_rate"`
+	DurationMs     int64     `json:"duration_ms" db:"duration_ms" gorm:"column:duration_ms"`
+	SizeBytes      int64     `json:"size_bytes" db:"size_bytes" gorm:"column:size_bytes"`
+	StorageType    string    `json:"storage_type" db:"storage_type" gorm:"column:storage_type"` // local, gcs, s3
+	Location       string    `json:"location" db:"location" gorm:"column:location"`             // Object URL or local file path
+	CreatedAt      time.Time `json:"created_at" db:"created_at" gorm:"column:created_at"`
+}
+
+func (VoiceRecording) TableName() string {
+	return "voice_recordings"
+}
+
+// VoiceLatencyStats aggregates per-turn latency percentiles for one agent and model provider
+type VoiceLatencyStats struct {
+	VoiceAgentID         string  `json:"voice_agent_id" gorm:"column:voice_agent_id"`
+	ModelProvider        string  `json:"model_provider" gorm:"column:model_provider"`
+	TurnCount            int64   `json:"turn_count" gorm:"column:turn_count"`
+	VoiceToVoiceP50Ms    float64 `json:"voice_to_voice_p50_ms" gorm:"column:voice_to_voice_p50_ms"`
+	VoiceToVoiceP95Ms    float64 `json:"voice_to_voice_p95_ms" gorm:"column:voice_to_voice_p95_ms"`
+	FirstAudioP50Ms      float64 `json:"first_audio_p50_ms" gorm:"column:first_audio_p50_ms"`
+	FirstAudioP95Ms      float64 `json:"first_audio_p95_ms" gorm:"column:first_audio_p95_ms"`
+	ResponseLatencyP50Ms float64 `json:"response_latency_p50_ms" gorm:"column:response_latency_p50_ms"`
+	ResponseLatencyP95Ms float64 `json:"response_latency_p95_ms" gorm:"column:response_latency_p95_ms"`
+	ToolDurationP50Ms    float64 `json:"tool_duration_p50_ms" gorm:"column:tool_duration_p50_ms"`
+	ToolDurationP95Ms    float64 `json:"tool_duration_p95_ms" gorm:"column:tool_duration_p95_ms"`
+	RAGDurationP50Ms     float64 `json:"rag_duration_p50_ms" gorm:"column:rag_duration_p50_ms"`
+	RAGDurationP95Ms     float64 `json:"rag_duration_p95_ms" gorm:"column:rag_duration_p95_ms"`
+}
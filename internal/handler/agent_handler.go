@@ -1031,6 +1031,10 @@ func (h *AgentHandler) SetupAgentRoutes(router *mux.Router) {
 	router.HandleFunc("/agents/count", h.GetAgentCount).Methods("GET")
 	router.HandleFunc("/agents/{id}/jwt", h.GenerateJWT).Methods("GET")
 
+	// Hold music and tool-call audio cues
+	router.HandleFunc("/agents/{id}/audio-cues/{name}", h.UploadAudioCue).Methods("PUT")
+	router.HandleFunc("/agents/{id}/audio-cues/{name}", h.DeleteAudioCue).Methods("DELETE")
+
 	// Quick create route
 	router.HandleFunc("/agents/quick-create", h.QuickCreateAgent).Methods("POST")
 
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ClareAI/astra-voice-service/internal/domain"
+	"github.com/ClareAI/astra-voice-service/pkg/audiocue"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// UploadAudioCue godoc
+// @Summary Upload an audio cue
+// @Description Upload hold music or a short cue (MP3 or WAV) for an agent. The audio is transcoded to Opus frames and can be referenced by name in the agent's audio_cues config.
+// @Tags agents
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Agent ID (UUID)" format(uuid)
+// @Param name path string true "Cue name (letters, digits, '-' and '_')"
+// @Param file formData file true "MP3 or WAV audio (the raw request body is accepted too)"
+// @Success 201 {object} audiocue.Cue "Cue stored"
+// @Failure 400 {object} map[string]string "Invalid cue name or audio"
+// @Failure 404 {object} map[string]string "Agent not found"
+// @Failure 503 {object} map[string]string "Object storage not configured"
+// @Router /api/agents/{id}/audio-cues/{name} [put]
+func (h *AgentHandler) UploadAudioCue(w http.ResponseWriter, r *http.Request) {
+	voiceAgent, name, library, ok := h.audioCueTarget(w, r)
+	if !ok {
+		return
+	}
+
+	audio, err := readAudioUpload(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cue, err := library.Put(r.Context(), voiceAgent.VoiceTenantID, voiceAgent.ID, name, audio)
+	if err != nil {
+		logger.Base().Warn("failed to store audio cue",
+			zap.Error(err),
+			zap.String("agent_id", voiceAgent.ID),
+			zap.String("cue", name),
+		)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	logger.Base().Info("audio cue stored",
+		zap.String("agent_id", voiceAgent.ID),
+		zap.String("tenant_id", voiceAgent.VoiceTenantID),
+		zap.String("cue", name),
+		zap.Int64("duration_ms", cue.DurationMs),
+	)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(cue)
+}
+
+// DeleteAudioCue godoc
+// @Summary Delete an audio cue
+// @Description Delete an uploaded audio cue. Configs still referring to it fall back to silence.
+// @Tags agents
+// @Param id path string true "Agent ID (UUID)" format(uuid)
+// @Param name path string true "Cue name"
+// @Success 204 "Cue deleted"
+// @Failure 404 {object} map[string]string "Agent not found"
+// @Failure 503 {object} map[string]string "Object storage not configured"
+// @Router /api/agents/{id}/audio-cues/{name} [delete]
+func (h *AgentHandler) DeleteAudioCue(w http.ResponseWriter, r *http.Request) {
+	voiceAgent, name, library, ok := h.audioCueTarget(w, r)
+	if !ok {
+		return
+	}
+
+	if err := library.Delete(r.Context(), voiceAgent.VoiceTenantID, voiceAgent.ID, name); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// audioCueTarget resolves the agent and cue name of an audio cue request, writing the
+// error response if they are invalid
+func (h *AgentHandler) audioCueTarget(w http.ResponseWriter, r *http.Request) (*domain.VoiceAgent, string, *audiocue.Library, bool) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	name := vars["name"]
+
+	library := audiocue.GetLibrary()
+	if library == nil {
+		http.Error(w, "Audio cue storage not configured", http.StatusServiceUnavailable)
+		return nil, "", nil, false
+	}
+	if !audiocue.ValidName(name) {
+		http.Error(w, "Invalid cue name: use up to 64 letters, digits, '-' or '_'", http.StatusBadRequest)
+		return nil, "", nil, false
+	}
+
+	voiceAgent, err := h.repoMgr.VoiceAgent().GetByID(r.Context(), id)
+	if err != nil {
+		if err.Error() == "voice agent not found: "+id {
+			http.Error(w, "Agent not found", http.StatusNotFound)
+			return nil, "", nil, false
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, "", nil, false
+	}
+	if voiceAgent == nil {
+		http.Error(w, "Agent not found", http.StatusNotFound)
+		return nil, "", nil, false
+	}
+	return voiceAgent, name, library, true
+}
+
+// readAudioUpload reads the "file" part of a multipart upload, or the raw body otherwise
+func readAudioUpload(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	// Leave room for the multipart envelope around the audio
+	r.Body = http.MaxBytesReader(w, r.Body, audiocue.MaxUploadBytes+1<<20)
+
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			return nil, errors.New("missing file field")
+		}
+		defer file.Close()
+		body = file
+	}
+
+	audio, err := io.ReadAll(io.LimitReader(body, audiocue.MaxUploadBytes+1))
+	if err != nil {
+		return nil, errors.New("failed to read audio upload")
+	}
+	if len(audio) == 0 {
+		return nil, errors.New("empty audio upload")
+	}
+	if len(audio) > audiocue.MaxUploadBytes {
+		return nil, errors.New("audio upload too large")
+	}
+	return audio, nil
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ClareAI/astra-voice-service/internal/domain"
+	"github.com/ClareAI/astra-voice-service/internal/services/callback"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// CallbackHandler manages call backs callers asked for during calls
+type CallbackHandler struct {
+	service *callback.Service
+}
+
+// NewCallbackHandler creates a new callback handler
+func NewCallbackHandler(service *callback.Service) *CallbackHandler {
+	return &CallbackHandler{service: service}
+}
+
+// SetupCallbackRoutes registers callback routes on the API router
+func (h *CallbackHandler) SetupCallbackRoutes(router *mux.Router) {
+	router.HandleFunc("/callbacks", h.GetCallbacks).Methods("GET")
+	router.HandleFunc("/callbacks/{id}", h.GetCallback).Methods("GET")
+	router.HandleFunc("/callbacks/{id}/cancel", h.CancelCallback).Methods("POST")
+
+	logger.Base().Info("callback routes registered")
+}
+
+// GetCallbacks godoc
+// @Summary List call backs
+// @Description List call backs scheduled through the schedule_callback tool, soonest first
+// @Tags callbacks
+// @Produce json
+// @Param tenant_id query string false "Filter by tenant ID"
+// @Param agent_id query string false "Filter by agent ID"
+// @Param waid query string false "Filter by contact phone number"
+// @Param status query string false "Filter by status (scheduled, dialing, placed, failed, cancelled)"
+// @Param limit query int false "Page size" default(100)
+// @Param offset query int false "Page offset" default(0)
+// @Success 200 {array} domain.CallbackJob "Call backs"
+// @Router /api/callbacks [get]
+func (h *CallbackHandler) GetCallbacks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	callbacks, err := h.service.ListCallbacks(r.Context(), domain.CallbackFilter{
+		TenantID: query.Get("tenant_id"),
+		AgentID:  query.Get("agent_id"),
+		WAID:     query.Get("waid"),
+		Status:   query.Get("status"),
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		writeCallbackError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(callbacks)
+}
+
+// GetCallback godoc
+// @Summary Get a call back
+// @Description Get a call back with its status, the call summary and the placed call's connection ID
+// @Tags callbacks
+// @Produce json
+// @Param id path string true "Callback ID"
+// @Success 200 {object} domain.CallbackJob "Call back"
+// @Failure 404 {string} string "Callback not found"
+// @Router /api/callbacks/{id} [get]
+func (h *CallbackHandler) GetCallback(w http.ResponseWriter, r *http.Request) {
+	job, err := h.service.GetCallback(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeCallbackError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+// CancelCallback godoc
+// @Summary Cancel a call back
+// @Description Cancel a call back that has not been placed yet
+// @Tags callbacks
+// @Param id path string true "Callback ID"
+// @Success 204 "Call back cancelled"
+// @Failure 404 {string} string "Callback not found"
+// @Failure 409 {string} string "Call back is no longer scheduled"
+// @Router /api/callbacks/{id}/cancel [post]
+func (h *CallbackHandler) CancelCallback(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.CancelCallback(r.Context(), mux.Vars(r)["id"]); err != nil {
+		writeCallbackError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeCallbackError maps callback service errors to HTTP statuses
+func writeCallbackError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, callback.ErrCallbackNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, callback.ErrInvalidCallback):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, callback.ErrInvalidTransition):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		logger.Base().Error("Callback request failed", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
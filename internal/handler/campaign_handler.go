@@ -0,0 +1,311 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ClareAI/astra-voice-service/internal/domain"
+	"github.com/ClareAI/astra-voice-service/internal/services/campaign"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// maxContactUploadBytes bounds a contact list upload
+const maxContactUploadBytes = 20 << 20
+
+// CampaignHandler manages outbound calling campaigns
+type CampaignHandler struct {
+	service *campaign.Service
+}
+
+// NewCampaignHandler creates a new campaign handler
+func NewCampaignHandler(service *campaign.Service) *CampaignHandler {
+	return &CampaignHandler{service: service}
+}
+
+// SetupCampaignRoutes registers campaign routes on the API router
+func (h *CampaignHandler) SetupCampaignRoutes(router *mux.Router) {
+	router.HandleFunc("/campaigns", h.CreateCampaign).Methods("POST")
+	router.HandleFunc("/campaigns", h.GetCampaigns).Methods("GET")
+	router.HandleFunc("/campaigns/{id}", h.GetCampaign).Methods("GET")
+	router.HandleFunc("/campaigns/{id}/contacts", h.UploadContacts).Methods("POST")
+	router.HandleFunc("/campaigns/{id}/contacts", h.GetContacts).Methods("GET")
+	router.HandleFunc("/campaigns/{id}/start", h.StartCampaign).Methods("POST")
+	router.HandleFunc("/campaigns/{id}/pause", h.PauseCampaign).Methods("POST")
+	router.HandleFunc("/campaigns/{id}/resume", h.ResumeCampaign).Methods("POST")
+	router.HandleFunc("/campaigns/{id}/cancel", h.CancelCampaign).Methods("POST")
+	router.HandleFunc("/campaign-limits", h.SetNumberLimit).Methods("PUT")
+	router.HandleFunc("/campaign-limits", h.GetNumberLimits).Methods("GET")
+
+	logger.Base().Info("campaign routes registered")
+}
+
+// CreateCampaign godoc
+// @Summary Create a campaign
+// @Description Create a draft outbound calling campaign with schedule windows and retry settings
+// @Tags campaigns
+// @Accept json
+// @Produce json
+// @Param campaign body domain.CreateCampaignRequest true "Campaign"
+// @Success 201 {object} domain.OutboundCampaign "Created campaign"
+// @Failure 400 {string} string "Invalid campaign"
+// @Router /api/campaigns [post]
+func (h *CampaignHandler) CreateCampaign(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateCampaignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.service.CreateCampaign(r.Context(), &req)
+	if err != nil {
+		writeCampaignError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// GetCampaigns godoc
+// @Summary List campaigns
+// @Description List campaigns, newest first
+// @Tags campaigns
+// @Produce json
+// @Param tenant_id query string false "Filter by tenant ID"
+// @Param status query string false "Filter by status (draft, running, paused, completed, cancelled)"
+// @Success 200 {array} domain.OutboundCampaign "Campaigns"
+// @Router /api/campaigns [get]
+func (h *CampaignHandler) GetCampaigns(w http.ResponseWriter, r *http.Request) {
+	campaigns, err := h.service.ListCampaigns(r.Context(), r.URL.Query().Get("tenant_id"), r.URL.Query().Get("status"))
+	if err != nil {
+		writeCampaignError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(campaigns)
+}
+
+// GetCampaign godoc
+// @Summary Get a campaign
+// @Description Get a campaign with its contact counts by status
+// @Tags campaigns
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} domain.CampaignWithStats "Campaign"
+// @Failure 404 {string} string "Campaign not found"
+// @Router /api/campaigns/{id} [get]
+func (h *CampaignHandler) GetCampaign(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.service.GetCampaign(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeCampaignError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// UploadContacts godoc
+// @Summary Add contacts to a campaign
+// @Description Upload a contact list as CSV (header row with a waid/phone column, optional name column, other columns become variables) or JSON, either as the raw body or the "file" field of a multipart form
+// @Tags campaigns
+// @Accept json,text/csv,multipart/form-data
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} map[string]int "Number of contacts added"
+// @Failure 400 {string} string "Invalid contact list"
+// @Failure 404 {string} string "Campaign not found"
+// @Router /api/campaigns/{id}/contacts [post]
+func (h *CampaignHandler) UploadContacts(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxContactUploadBytes)
+
+	var body io.Reader = r.Body
+	contentType := r.Header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "multipart/form-data") {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "missing file field", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		body = file
+		contentType = header.Header.Get("Content-Type")
+		if strings.EqualFold(filepath.Ext(header.Filename), ".csv") {
+			contentType = "text/csv"
+		}
+	}
+
+	contacts, err := campaign.ParseContacts(contentType, body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	added, err := h.service.AddContacts(r.Context(), mux.Vars(r)["id"], contacts)
+	if err != nil {
+		writeCampaignError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"added": added})
+}
+
+// GetContacts godoc
+// @Summary List campaign contacts
+// @Description List a campaign's contacts with their status, disposition and attempts
+// @Tags campaigns
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Param status query string false "Filter by contact status"
+// @Param limit query int false "Page size" default(100)
+// @Param offset query int false "Page offset" default(0)
+// @Success 200 {array} domain.CampaignContact "Contacts"
+// @Failure 404 {string} string "Campaign not found"
+// @Router /api/campaigns/{id}/contacts [get]
+func (h *CampaignHandler) GetContacts(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	contacts, err := h.service.ListContacts(r.Context(), mux.Vars(r)["id"], r.URL.Query().Get("status"), limit, offset)
+	if err != nil {
+		writeCampaignError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(contacts)
+}
+
+// StartCampaign godoc
+// @Summary Start a campaign
+// @Description Start dialing a draft campaign's contacts within its schedule
+// @Tags campaigns
+// @Param id path string true "Campaign ID"
+// @Success 204 "Campaign started"
+// @Failure 409 {string} string "Campaign cannot be started"
+// @Router /api/campaigns/{id}/start [post]
+func (h *CampaignHandler) StartCampaign(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.service.StartCampaign)
+}
+
+// PauseCampaign godoc
+// @Summary Pause a campaign
+// @Description Stop placing new calls; calls in progress continue
+// @Tags campaigns
+// @Param id path string true "Campaign ID"
+// @Success 204 "Campaign paused"
+// @Failure 409 {string} string "Campaign is not running"
+// @Router /api/campaigns/{id}/pause [post]
+func (h *CampaignHandler) PauseCampaign(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.service.PauseCampaign)
+}
+
+// ResumeCampaign godoc
+// @Summary Resume a campaign
+// @Description Resume a paused campaign
+// @Tags campaigns
+// @Param id path string true "Campaign ID"
+// @Success 204 "Campaign resumed"
+// @Failure 409 {string} string "Campaign is not paused"
+// @Router /api/campaigns/{id}/resume [post]
+func (h *CampaignHandler) ResumeCampaign(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.service.ResumeCampaign)
+}
+
+// CancelCampaign godoc
+// @Summary Cancel a campaign
+// @Description Cancel a campaign and its undialled contacts; calls in progress continue
+// @Tags campaigns
+// @Param id path string true "Campaign ID"
+// @Success 204 "Campaign cancelled"
+// @Failure 409 {string} string "Campaign already finished"
+// @Router /api/campaigns/{id}/cancel [post]
+func (h *CampaignHandler) CancelCampaign(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.service.CancelCampaign)
+}
+
+// SetNumberLimit godoc
+// @Summary Set a business number's concurrency limit
+// @Description Set how many campaign calls may be in progress from a tenant's business number, across its campaigns. Numbers without a limit allow one call at a time.
+// @Tags campaigns
+// @Accept json
+// @Produce json
+// @Param limit body domain.CampaignNumberLimit true "Limit"
+// @Success 200 {object} domain.CampaignNumberLimit "Limit"
+// @Failure 400 {string} string "Invalid limit"
+// @Router /api/campaign-limits [put]
+func (h *CampaignHandler) SetNumberLimit(w http.ResponseWriter, r *http.Request) {
+	var limit domain.CampaignNumberLimit
+	if err := json.NewDecoder(r.Body).Decode(&limit); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.SetNumberLimit(r.Context(), &limit); err != nil {
+		writeCampaignError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(limit)
+}
+
+// GetNumberLimits godoc
+// @Summary List business number concurrency limits
+// @Description List the concurrency limits set for a tenant's business numbers
+// @Tags campaigns
+// @Produce json
+// @Param tenant_id query string true "Tenant ID"
+// @Success 200 {array} domain.CampaignNumberLimit "Limits"
+// @Failure 400 {string} string "Missing tenant ID"
+// @Router /api/campaign-limits [get]
+func (h *CampaignHandler) GetNumberLimits(w http.ResponseWriter, r *http.Request) {
+	limits, err := h.service.ListNumberLimits(r.Context(), r.URL.Query().Get("tenant_id"))
+	if err != nil {
+		writeCampaignError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(limits)
+}
+
+// changeStatus runs a campaign status change
+func (h *CampaignHandler) changeStatus(w http.ResponseWriter, r *http.Request, change func(ctx context.Context, campaignID string) error) {
+	if err := change(r.Context(), mux.Vars(r)["id"]); err != nil {
+		writeCampaignError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeCampaignError maps campaign service errors to HTTP statuses
+func writeCampaignError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, campaign.ErrCampaignNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, campaign.ErrInvalidCampaign):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, campaign.ErrInvalidTransition):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		logger.Base().Error("Campaign request failed", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -171,11 +171,13 @@ func (h *LiveKitHandler) HandleCreateRoom(w http.ResponseWriter, r *http.Request
 		CreatedAt:     time.Now(),
 		LastActivity:  time.Now(),
 		IsActive:      true,
-		ChannelType:   domain.ChannelTypeLiveKit, // LiveKit channel doesn't need audio caching
+		ChannelType:   domain.ChannelTypeLiveKit,
 		TenantID:      request.TenantID,
 		AgentID:       request.AgentID,
 		TextAgentID:   textAgentID,
 		VoiceLanguage: request.VoiceLanguage,
+
+		InProcessRecording: h.roomManager.GetConfigInternal().RecordsInProcess(),
 	}
 
 	// Register connection in service
@@ -314,11 +316,13 @@ func (h *LiveKitHandler) HandleJoinRoom(w http.ResponseWriter, r *http.Request)
 		CreatedAt:     time.Now(),
 		LastActivity:  time.Now(),
 		IsActive:      true,
-		ChannelType:   domain.ChannelTypeLiveKit, // LiveKit channel doesn't need audio caching
+		ChannelType:   domain.ChannelTypeLiveKit,
 		TenantID:      request.TenantID,
 		AgentID:       request.AgentID,
 		TextAgentID:   textAgentID,
 		VoiceLanguage: request.VoiceLanguage,
+
+		InProcessRecording: h.roomManager.GetConfigInternal().RecordsInProcess(),
 	}
 
 	// Register connection in service
@@ -1,7 +1,11 @@
 package handler
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"mime"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -10,6 +14,8 @@ import (
 
 	"github.com/ClareAI/astra-voice-service/pkg/logger"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.uber.org/zap"
 )
 
@@ -40,7 +46,7 @@ func ValidationMiddleware(next http.Handler) http.Handler {
 		// For example, check Content-Type for POST/PUT requests
 		if r.Method == "POST" || r.Method == "PUT" {
 			contentType := r.Header.Get("Content-Type")
-			if contentType != "" && contentType != "application/json" {
+			if contentType != "" && !acceptedContentType(contentType) {
 				http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
 				return
 			}
@@ -50,6 +56,20 @@ func ValidationMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// acceptedContentType reports whether an API request body type is accepted: JSON, plus the
+// uploads some routes take (CSV contact lists, audio cues as raw or multipart bodies)
+func acceptedContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch mediaType {
+	case "application/json", "text/csv", "multipart/form-data", "application/octet-stream":
+		return true
+	}
+	return strings.HasPrefix(mediaType, "audio/")
+}
+
 // responseWriter wraps http.ResponseWriter to capture status code
 type responseWriter struct {
 	http.ResponseWriter
@@ -61,6 +81,22 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Hijack lets WebSocket upgrades (Twilio Media Streams) take over the connection
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := rw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
+
+// Flush lets streamed responses (server-sent events) reach the client as they are written
+func (rw *responseWriter) Flush() {
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
 // CORSMiddleware adds CORS headers to all requests
 func CORSMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -77,6 +113,24 @@ func CORSMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// TracingMiddleware starts a server span per request, named after the matched route
+// template and continuing any incoming traceparent header
+func TracingMiddleware(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, "http.server",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			if route := mux.CurrentRoute(r); route != nil {
+				if tmpl, err := route.GetPathTemplate(); err == nil {
+					return r.Method + " " + tmpl
+				}
+			}
+			return r.Method
+		}),
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			return r.URL.Path != "/metrics"
+		}),
+	)
+}
+
 // GlobalLoggingMiddleware logs all HTTP requests (not just API)
 func GlobalLoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -132,6 +132,11 @@ func (h *OpenAIHandler) configureOpenAIHandler(service *call.WhatsAppCallService
 	// Set up ComposioService in tool manager
 	toolManager.ComposioService = composioService
 
+	// Keep the callback scheduler wired by the handler manager when reconfiguring
+	if h.openaiHandler.ToolManager != nil {
+		toolManager.Callbacks = h.openaiHandler.ToolManager.Callbacks
+	}
+
 	h.openaiHandler.ToolManager = toolManager
 	h.openaiHandler.PromptGenerator = func(connectionID string) whatsappconfig.PromptGenerator {
 		// Get agent ID from connection
@@ -152,6 +157,14 @@ func (h *OpenAIHandler) configureOpenAIHandler(service *call.WhatsAppCallService
 			return defaultGenerator
 		}
 
+		// Bind the call's variables so templates can render them
+		if callConn, ok := conn.(*call.WhatsAppCallConnection); ok {
+			if agentGenerator, ok := promptGenerator.(*prompts.AgentPromptGenerator); ok {
+				agentGenerator.Variables = callConn.GetVariables()
+				agentGenerator.PreviousCallSummary = callConn.PreviousCallSummary
+			}
+		}
+
 		return promptGenerator
 	}
 
@@ -652,3 +665,19 @@ func (a *toolConnectionAdapter) GetChannelType() string {
 func (a *toolConnectionAdapter) GetTextAgentID() string {
 	return a.conn.GetTextAgentID()
 }
+
+func (a *toolConnectionAdapter) GetVariables() map[string]string {
+	return a.conn.GetVariables()
+}
+
+func (a *toolConnectionAdapter) GetVoiceLanguage() string {
+	return a.conn.GetVoiceLanguage()
+}
+
+func (a *toolConnectionAdapter) GetAccent() string {
+	return a.conn.GetAccent()
+}
+
+func (a *toolConnectionAdapter) GetConversationID() string {
+	return a.conn.GetConversationID()
+}
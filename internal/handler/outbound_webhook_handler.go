@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -18,33 +19,39 @@ import (
 	"github.com/ClareAI/astra-voice-service/internal/repository"
 	"github.com/ClareAI/astra-voice-service/internal/services/agent"
 	"github.com/ClareAI/astra-voice-service/internal/services/call"
+	"github.com/ClareAI/astra-voice-service/internal/services/campaign"
+	"github.com/ClareAI/astra-voice-service/internal/services/outbound"
 	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/tracing"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 )
 
 // OutboundWebhookHandler handles webhook callbacks from Wati for outbound calls
 type OutboundWebhookHandler struct {
-	service      *call.WhatsAppCallService
-	watiClient   *httpadapter.WatiClient
-	repoManager  repository.RepositoryManager // Add repository manager for database operations
-	agentService *agent.AgentService          // Add agent service for agent config retrieval
-	taskBus      task.Bus                     // Task bus for asynchronous processing
+	service       *call.WhatsAppCallService
+	watiClient    *httpadapter.WatiClient
+	repoManager   repository.RepositoryManager // Add repository manager for database operations
+	agentService  *agent.AgentService          // Add agent service for agent config retrieval
+	taskBus       task.Bus                     // Task bus for asynchronous processing
+	outboundCalls *outbound.Service            // Persisted outbound call state shared across pods
+	campaigns     *campaign.Service            // Campaign call outcome tracking (optional)
 }
 
 // NewOutboundWebhookHandler creates a new outbound webhook handler
-func NewOutboundWebhookHandler(service *call.WhatsAppCallService, watiClient *httpadapter.WatiClient, repoManager repository.RepositoryManager, taskBus task.Bus) *OutboundWebhookHandler {
+func NewOutboundWebhookHandler(service *call.WhatsAppCallService, watiClient *httpadapter.WatiClient, repoManager repository.RepositoryManager, taskBus task.Bus, outboundCalls *outbound.Service) *OutboundWebhookHandler {
 	agentService, err := agent.GetAgentService()
 	if err != nil {
 		logger.Base().Error("Failed to get agent service")
 	}
 
 	return &OutboundWebhookHandler{
-		service:      service,
-		watiClient:   watiClient,
-		repoManager:  repoManager,
-		agentService: agentService,
-		taskBus:      taskBus,
+		service:       service,
+		watiClient:    watiClient,
+		repoManager:   repoManager,
+		agentService:  agentService,
+		taskBus:       taskBus,
+		outboundCalls: outboundCalls,
 	}
 }
 
@@ -110,6 +117,13 @@ type InitiateOutboundCallRequest struct {
 	VoiceLanguage      string `json:"voiceLanguage,omitempty"`      // Voice language (optional, default: "en")
 	Accent             string `json:"accent,omitempty"`             // Voice accent (optional)
 	TenantID           string `json:"tenantId,omitempty"`           // Tenant ID (optional, will be cached for outbound calls)
+	ContactName        string `json:"contactName,omitempty"`        // Contact name (optional, default: WAID)
+
+	// Per-call variables (optional), validated against the agent's declared call variables
+	Variables map[string]interface{} `json:"variables,omitempty"`
+
+	// Summary of an earlier call (optional), given to the agent when calling the user back
+	PreviousCallSummary string `json:"previousCallSummary,omitempty"`
 }
 
 // InitiateOutboundCallResponse represents the response from initiating an outbound call
@@ -180,6 +194,44 @@ func (h *OutboundWebhookHandler) handleInitiateOutboundCallInternal(w http.Respo
 		return
 	}
 
+	responseData, err := h.placeOutboundCall(context.Background(), request, channelType)
+	if err != nil {
+		status := http.StatusInternalServerError
+		var callErr *outboundCallError
+		if errors.As(err, &callErr) {
+			status = callErr.status
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// outboundCallError is an outbound call failure with the HTTP status it maps to
+type outboundCallError struct {
+	status  int
+	message string
+	err     error
+}
+
+func (e *outboundCallError) Error() string {
+	return e.message
+}
+
+func (e *outboundCallError) Unwrap() error {
+	return e.err
+}
+
+// errCallPermissionDenied is returned when the user has not allowed calls and permission cannot be requested
+var errCallPermissionDenied = errors.New("Cannot start call: permission denied and cannot request permission (limit reached or not allowed)")
+
+// placeOutboundCall checks usage and call permission, then either places the call or
+// requests call permission from the user. The attempt is persisted first so whichever pod
+// receives the permission and call status webhooks can continue it.
+func (h *OutboundWebhookHandler) placeOutboundCall(ctx context.Context, request InitiateOutboundCallRequest, channelType domain.ChannelType) (*InitiateOutboundCallResponse, error) {
 	// Set default voice language
 	if request.VoiceLanguage == "" {
 		request.VoiceLanguage = config.DefaultLanguage
@@ -195,14 +247,25 @@ func (h *OutboundWebhookHandler) handleInitiateOutboundCallInternal(w http.Respo
 		}
 
 		if tenantID != "" && tenantID != config.DefaultTenantID && tenantID != config.DefaultWatiTenantID {
-			if allowed, msg := h.agentService.CheckTenantUsageAllowed(context.Background(), tenantID); !allowed {
+			if allowed, msg := h.agentService.CheckTenantUsageAllowed(ctx, tenantID); !allowed {
 				logger.Base().Error("[OutboundCall] Usage not allowed for tenant", zap.String("tenant_id", tenantID), zap.String("agent_id", request.AgentID))
-				http.Error(w, fmt.Sprintf("Usage not allowed: %s", msg), http.StatusForbidden)
-				return
+				return nil, &outboundCallError{status: http.StatusForbidden, message: fmt.Sprintf("Usage not allowed: %s", msg)}
 			}
 		}
 	}
 
+	// Validate per-call variables against those the agent declares for outbound calls
+	var declaredVariables []config.CallVariable
+	if h.agentService != nil && request.AgentID != "" {
+		if agentConfig, err := h.agentService.GetAgentConfigWithChannelType(ctx, request.AgentID, channelType); err == nil && agentConfig != nil {
+			declaredVariables = agentConfig.CallVariables(true)
+		}
+	}
+	variables, err := config.ResolveCallVariables(declaredVariables, request.Variables)
+	if err != nil {
+		return nil, &outboundCallError{status: http.StatusBadRequest, message: err.Error(), err: err}
+	}
+
 	// Sanitize WAID and ChannelPhoneNumber by trimming non-digit characters from prefix and suffix
 	sanitize := func(s string) string {
 		return strings.TrimFunc(s, func(r rune) bool {
@@ -215,24 +278,35 @@ func (h *OutboundWebhookHandler) handleInitiateOutboundCallInternal(w http.Respo
 
 	logger.Base().Info("Initiating call to WAID: , channel: , agent: , language: , tenant", zap.String("waid", request.WAID), zap.String("channelphonenumber", request.ChannelPhoneNumber), zap.String("agent_id", request.AgentID), zap.String("voice_language", request.VoiceLanguage), zap.String("tenant_id", request.TenantID))
 
-	// Step 1: Create a connection for this outbound call
-	connection, err := h.createOutboundConnection(request, channelType)
-	if err != nil {
-		logger.Base().Error("Failed to create connection")
-		http.Error(w, fmt.Sprintf("Failed to create connection: %v", err), http.StatusInternalServerError)
-		return
+	// Step 1: Record the attempt; its ID becomes the connection ID once the call is placed
+	attempt := &domain.OutboundCallAttempt{
+		ID:                 fmt.Sprintf("outbound-%d", time.Now().UnixNano()),
+		TenantID:           request.TenantID,
+		AgentID:            request.AgentID,
+		ChannelType:        string(channelType),
+		WAID:               request.WAID,
+		ChannelPhoneNumber: request.ChannelPhoneNumber,
+		VoiceLanguage:      request.VoiceLanguage,
+		Accent:             request.Accent,
+		ContactName:        request.ContactName,
+		Variables:          domain.StringMapJSONB(variables),
+
+		PreviousCallSummary: strings.TrimSpace(request.PreviousCallSummary),
+	}
+	if err := h.outboundCalls.Create(ctx, attempt); err != nil {
+		logger.Base().Error("Failed to record outbound call", zap.Error(err))
+		return nil, fmt.Errorf("Failed to record outbound call: %v", err)
 	}
 
-	logger.Base().Info("Created connection: for WAID", zap.String("id", connection.ID), zap.String("waid", request.WAID))
+	logger.Base().Info("Recorded outbound call for WAID", zap.String("attempt_id", attempt.ID), zap.String("waid", request.WAID))
 
 	// Step 2: Check call permissions
 	logger.Base().Debug("🔐 Checking call permissions for WAID", zap.String("waid", request.WAID))
 	permissionResp, err := h.watiClient.GetCallPermissions(request.WAID, request.ChannelPhoneNumber, request.TenantID)
 	if err != nil {
 		logger.Base().Error("Failed to check permissions")
-		h.service.CleanupConnection(connection.ID)
-		http.Error(w, fmt.Sprintf("Failed to check permissions: %v", err), http.StatusInternalServerError)
-		return
+		h.outboundCalls.Fail(ctx, attempt.ID, err)
+		return nil, fmt.Errorf("Failed to check permissions: %v", err)
 	}
 
 	// Extract permission status from response
@@ -245,9 +319,9 @@ func (h *OutboundWebhookHandler) handleInitiateOutboundCallInternal(w http.Respo
 	if !ok {
 		logger.Base().Error("Invalid response format: missing 'result' field")
 		logger.Base().Info("Full permission response", zap.Any("permission_resp", permissionResp))
-		h.service.CleanupConnection(connection.ID)
-		http.Error(w, "Invalid permission response format", http.StatusInternalServerError)
-		return
+		err := errors.New("Invalid permission response format")
+		h.outboundCalls.Fail(ctx, attempt.ID, err)
+		return nil, err
 	}
 
 	// Check both "start_call" and "send_call_permission_request" actions
@@ -279,94 +353,137 @@ func (h *OutboundWebhookHandler) handleInitiateOutboundCallInternal(w http.Respo
 		if !canRequestPermission {
 			// Cannot request permission either - return error
 			logger.Base().Error("Cannot start call and cannot request permission for WAID", zap.String("waid", request.WAID))
-			h.service.CleanupConnection(connection.ID)
-			http.Error(w, "Cannot start call: permission denied and cannot request permission (limit reached or not allowed)", http.StatusForbidden)
-			return
+			h.transitionAttempt(ctx, attempt.ID, domain.OutboundCallStatePermissionDenied, errCallPermissionDenied.Error())
+			return nil, &outboundCallError{status: http.StatusForbidden, message: errCallPermissionDenied.Error(), err: errCallPermissionDenied}
 		}
 
 		// Can request permission - send permission request and wait for webhook
 		logger.Base().Warn("No permission for WAID: , requesting permission", zap.String("waid", request.WAID))
 
+		// Record the request first so a quick permission webhook finds it. No connection is
+		// kept while waiting; the webhook places the call on whichever pod receives it.
+		h.transitionAttempt(ctx, attempt.ID, domain.OutboundCallStatePermissionRequested, "")
 		err := h.watiClient.SendCallPermissionRequest(request.WAID, request.ChannelPhoneNumber, request.TenantID)
 		if err != nil {
 			logger.Base().Error("Failed to request permission")
-			h.service.CleanupConnection(connection.ID)
-			http.Error(w, fmt.Sprintf("Failed to request permission: %v", err), http.StatusInternalServerError)
-			return
+			h.outboundCalls.Fail(ctx, attempt.ID, err)
+			return nil, fmt.Errorf("Failed to request permission: %v", err)
 		}
 
-		// Store a temporary message ID for tracking (will be updated by webhook)
-		connection.PermissionMessageID = fmt.Sprintf("perm-req-%d", time.Now().UnixNano())
 		logger.Base().Info("Permission request sent, waiting for webhook")
+		logger.Base().Info("Waiting for permission webhook for outbound call", zap.String("attempt_id", attempt.ID))
 
 		// Return response indicating waiting for permission
-		responseData := InitiateOutboundCallResponse{
+		return &InitiateOutboundCallResponse{
 			CallID:       "", // No call ID yet
-			ConnectionID: connection.ID,
+			ConnectionID: attempt.ID,
 			Status:       "waiting_permission",
-		}
-
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(responseData)
-
-		logger.Base().Info("Waiting for permission webhook for connection", zap.String("id", connection.ID))
-		return
+		}, nil
 	}
 
 	// Step 4: Has permission - proceed to make call
 	logger.Base().Info("Permission granted for WAID: , proceeding with call", zap.String("waid", request.WAID))
-	err = h.proceedWithOutboundCall(connection, request.TenantID)
-	if err != nil {
+	if err := h.dialAttempt(ctx, attempt); err != nil {
 		logger.Base().Error("Failed to proceed with call")
-		h.service.CleanupConnection(connection.ID)
-		http.Error(w, fmt.Sprintf("Failed to make call: %v", err), http.StatusInternalServerError)
-		return
+		return nil, fmt.Errorf("Failed to make call: %v", err)
 	}
 
-	// Return response (ai model will be initialized when phone starts ringing)
-	responseData := InitiateOutboundCallResponse{
-		CallID:       connection.CallID,
-		ConnectionID: connection.ID,
+	// ai model will be initialized when phone starts ringing
+	logger.Base().Info("Call initiated, ai will be ready when user answers")
+	return &InitiateOutboundCallResponse{
+		CallID:       attempt.CallID,
+		ConnectionID: attempt.ID,
 		Status:       "calling",
+	}, nil
+}
+
+// transitionAttempt moves an outbound call attempt to a state, reporting whether it did
+func (h *OutboundWebhookHandler) transitionAttempt(ctx context.Context, attemptID, state, lastError string) bool {
+	if err := h.outboundCalls.Transition(ctx, attemptID, state, lastError); err != nil {
+		logger.Base().Warn("Outbound call state not changed", zap.String("attempt_id", attemptID), zap.String("state", state), zap.Error(err))
+		return false
 	}
+	return true
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(responseData)
+// dialAttempt creates an attempt's connection on this pod and places its call
+func (h *OutboundWebhookHandler) dialAttempt(ctx context.Context, attempt *domain.OutboundCallAttempt) error {
+	connection := h.createOutboundConnection(attempt)
+	if err := h.proceedWithOutboundCall(connection, attempt.TenantID); err != nil {
+		h.outboundCalls.Fail(ctx, attempt.ID, err)
+		h.service.CleanupConnection(connection.ID)
+		return err
+	}
 
-	logger.Base().Info("Call initiated, ai will be ready when user answers")
+	attempt.CallID = connection.CallID
+	if err := h.outboundCalls.MarkDialing(ctx, attempt.ID, attempt.CallID); err != nil {
+		logger.Base().Warn("Failed to record outbound call as dialing", zap.String("attempt_id", attempt.ID), zap.Error(err))
+	}
+	return nil
+}
+
+// Dial places a campaign call or a call back with the published agent config
+func (h *OutboundWebhookHandler) Dial(ctx context.Context, req campaign.DialRequest) (*campaign.DialResult, error) {
+	response, err := h.placeOutboundCall(ctx, InitiateOutboundCallRequest{
+		WAID:               req.WAID,
+		ChannelPhoneNumber: req.ChannelPhoneNumber,
+		AgentID:            req.AgentID,
+		VoiceLanguage:      req.VoiceLanguage,
+		Accent:             req.Accent,
+		TenantID:           req.TenantID,
+		ContactName:        req.ContactName,
+		Variables:          req.Variables,
+
+		PreviousCallSummary: req.PreviousCallSummary,
+	}, domain.ChannelTypeWhatsApp)
+	if errors.Is(err, errCallPermissionDenied) {
+		return nil, fmt.Errorf("%w: %v", campaign.ErrPermissionDenied, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &campaign.DialResult{
+		ConnectionID:       response.ConnectionID,
+		CallID:             response.CallID,
+		AwaitingPermission: response.Status == "waiting_permission",
+	}, nil
 }
 
-// createOutboundConnection creates a new connection for outbound call
-func (h *OutboundWebhookHandler) createOutboundConnection(request InitiateOutboundCallRequest, channelType domain.ChannelType) (*call.WhatsAppCallConnection, error) {
-	connectionID := fmt.Sprintf("outbound-%d", time.Now().UnixNano())
+// createOutboundConnection creates the connection for an outbound call attempt
+func (h *OutboundWebhookHandler) createOutboundConnection(attempt *domain.OutboundCallAttempt) *call.WhatsAppCallConnection {
+	channelType := domain.ChannelType(attempt.ChannelType)
 
 	connection := &call.WhatsAppCallConnection{
-		ID:             connectionID,
-		From:           request.WAID,
-		To:             request.ChannelPhoneNumber,
+		ID:             attempt.ID,
+		From:           attempt.WAID,
+		To:             attempt.ChannelPhoneNumber,
 		IsActive:       true,
 		IsOutboundCall: true,        // Mark as outbound call for signal-controlled greeting
 		ChannelType:    channelType, // WhatsApp channel needs audio caching
-		VoiceLanguage:  request.VoiceLanguage,
-		Accent:         request.Accent,
+		VoiceLanguage:  attempt.VoiceLanguage,
+		Accent:         attempt.Accent,
 		CallID:         "", // Will be set after MakeOutboundCall
-		TenantID:       request.TenantID,
+		TenantID:       attempt.TenantID,
 		CreatedAt:      time.Now(),
 		LastActivity:   time.Now(),
 		RepoManager:    h.repoManager, // Add repository manager for database operations
-		ContactName:    request.WAID,
+		ContactName:    attempt.WAID,
+		Variables:      attempt.Variables.StringMap(),
+
+		PreviousCallSummary: attempt.PreviousCallSummary,
+	}
+	if attempt.ContactName != "" {
+		connection.ContactName = attempt.ContactName
 	}
 
 	// Set agent ID if provided
-	if request.AgentID != "" {
-		connection.SetAgentID(request.AgentID)
+	if attempt.AgentID != "" {
+		connection.SetAgentID(attempt.AgentID)
 		if h.agentService != nil {
 			// Use connection's channelType to get appropriate config (Draft for test, Published for production)
-			agentConfig, err := h.agentService.GetAgentConfigWithChannelType(context.Background(), request.AgentID, channelType)
+			agentConfig, err := h.agentService.GetAgentConfigWithChannelType(context.Background(), attempt.AgentID, channelType)
 			if err != nil {
-				logger.Base().Error("Failed to get agent config for ID", zap.String("agent_id", request.AgentID))
+				logger.Base().Error("Failed to get agent config for ID", zap.String("agent_id", attempt.AgentID))
 			} else {
 				connection.SetAgentID(agentConfig.ID)
 				connection.SetTextAgentID(agentConfig.TextAgentID)
@@ -376,7 +493,7 @@ func (h *OutboundWebhookHandler) createOutboundConnection(request InitiateOutbou
 	// Add connection to service
 	h.service.AddConnection(connection)
 
-	return connection, nil
+	return connection
 }
 
 // proceedWithOutboundCall generates SDP offer and makes the outbound call
@@ -393,7 +510,7 @@ func (h *OutboundWebhookHandler) proceedWithOutboundCall(connection *call.WhatsA
 	// Call Wati API to make outbound call
 	logger.Base().Info("Calling Wati API for connection ...", zap.String("id", connection.ID))
 	startTime := time.Now()
-	response, err := h.watiClient.MakeOutboundCall(connection.From, sdpOffer, connection.To, "", tenantID)
+	response, err := h.watiClient.MakeOutboundCall(tracing.CallContext(connection.ID), connection.From, sdpOffer, connection.To, "", tenantID)
 	if err != nil {
 		return fmt.Errorf("failed to make outbound call: %v", err)
 	}
@@ -415,22 +532,6 @@ func (h *OutboundWebhookHandler) proceedWithOutboundCall(connection *call.WhatsA
 	return nil
 }
 
-// findPendingConnectionByWAID finds a pending outbound connection by WAID and optionally by channel
-func (h *OutboundWebhookHandler) findPendingConnectionByWAID(waid, channelPhoneNumber string) (*call.WhatsAppCallConnection, string) {
-	connections := h.service.GetAllConnections()
-	for id, conn := range connections {
-		// Find outbound connection that's waiting for permission
-		// Match by WAID and optionally by channelPhoneNumber (for multi-channel support)
-		if conn.From == waid &&
-			(channelPhoneNumber == "" || conn.To == channelPhoneNumber) &&
-			conn.CallID == "" &&
-			conn.PermissionMessageID != "" {
-			return conn, id
-		}
-	}
-	return nil, ""
-}
-
 // HandlePermissionWebhook handles permission webhook from Wati
 // POST /wati/outbound/permission
 func (h *OutboundWebhookHandler) HandlePermissionWebhook(w http.ResponseWriter, r *http.Request) {
@@ -448,44 +549,55 @@ func (h *OutboundWebhookHandler) HandlePermissionWebhook(w http.ResponseWriter,
 
 	logger.Base().Info("Permission status", zap.String("waid", request.WAID), zap.String("channel_phone_number", request.ChannelPhoneNumber), zap.Bool("has_permission", request.HasPermission), zap.String("status", request.Status))
 
-	// Find pending connection by WAID and optionally by channel
-	connection, connectionID := h.findPendingConnectionByWAID(request.WAID, request.ChannelPhoneNumber)
-	if connection == nil {
-		logger.Base().Warn("No pending connection found for WAID", zap.String("waid", request.WAID))
+	// Find the attempt waiting for this user's permission, optionally by channel
+	ctx := r.Context()
+	attempt, err := h.outboundCalls.GetAwaitingPermission(ctx, request.WAID, request.ChannelPhoneNumber)
+	if err != nil {
+		logger.Base().Error("Failed to look up outbound call", zap.String("waid", request.WAID), zap.Error(err))
+		http.Error(w, "Failed to look up outbound call", http.StatusInternalServerError)
+		return
+	}
+	if attempt == nil {
+		logger.Base().Warn("No outbound call waiting for permission for WAID", zap.String("waid", request.WAID))
 		http.Error(w, "Connection not found", http.StatusNotFound)
 		return
 	}
 
-	logger.Base().Info("Found pending connection: for WAID", zap.String("waid", request.WAID))
+	logger.Base().Info("Found outbound call waiting for permission", zap.String("attempt_id", attempt.ID), zap.String("waid", request.WAID))
 
 	// Handle permission status
 	if !request.HasPermission {
-		// Permission denied - cleanup connection
-		logger.Base().Error("Permission denied for connection: , WAID", zap.String("from", connection.From))
-		h.service.CleanupConnection(connectionID)
+		logger.Base().Error("Permission denied for WAID", zap.String("waid", attempt.WAID))
+		if h.transitionAttempt(ctx, attempt.ID, domain.OutboundCallStatePermissionDenied, "") && h.campaigns != nil {
+			h.campaigns.HandlePermissionDenied(ctx, attempt.ID)
+		}
 		h.sendOKResponse(w)
 		return
 	}
 
-	// Permission granted - proceed with outbound call
-	logger.Base().Info("Permission granted for connection: , WAID", zap.String("from", connection.From))
+	// Permission granted - claim the attempt so a repeated webhook does not call twice
+	logger.Base().Info("Permission granted for WAID", zap.String("waid", attempt.WAID))
+	if !h.transitionAttempt(ctx, attempt.ID, domain.OutboundCallStatePermissionGranted, "") {
+		h.sendOKResponse(w)
+		return
+	}
 
-	// Use tenant ID from connection (stored during initiation) or from webhook request
-	tenantID := connection.TenantID
-	if tenantID == "" && request.TenantID != "" {
-		tenantID = request.TenantID
-		connection.SetTenantID(tenantID)
+	// Use tenant ID stored during initiation or from webhook request
+	if attempt.TenantID == "" && request.TenantID != "" {
+		attempt.TenantID = request.TenantID
 	}
 
-	err := h.proceedWithOutboundCall(connection, tenantID)
-	if err != nil {
-		logger.Base().Error("Failed to proceed with call after permission granted")
-		h.service.CleanupConnection(connectionID)
+	if err := h.dialAttempt(ctx, attempt); err != nil {
+		logger.Base().Error("Failed to proceed with call after permission granted", zap.Error(err))
 		http.Error(w, fmt.Sprintf("Failed to make call: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	logger.Base().Info("Call initiated after permission granted, CallID: , waiting for SDP answer", zap.String("call_id", connection.CallID))
+	if h.campaigns != nil {
+		h.campaigns.HandlePermissionGranted(ctx, attempt.ID, attempt.CallID)
+	}
+
+	logger.Base().Info("Call initiated after permission granted, CallID: , waiting for SDP answer", zap.String("call_id", attempt.CallID))
 	h.sendOKResponse(w)
 }
 
@@ -509,6 +621,12 @@ func (h *OutboundWebhookHandler) HandleSDPAnswerWebhook(w http.ResponseWriter, r
 	// Find the connection by CallID
 	connection, connectionID := h.service.GetConnectionByCallID(request.CallID)
 	if connection == nil {
+		// The call may have been placed by another pod
+		if h.forwardToOwner(r.Context(), request.CallID, task.TaskTypeOutboundSDPAnswer, bodyBytes) {
+			h.sendOKResponse(w)
+			return
+		}
+
 		logger.Base().Warn("Connection not found for callId", zap.String("call_id", request.CallID))
 
 		// Debug: List all active connections
@@ -526,6 +644,20 @@ func (h *OutboundWebhookHandler) HandleSDPAnswerWebhook(w http.ResponseWriter, r
 
 	logger.Base().Info("Found connection: for callId", zap.String("call_id", request.CallID))
 
+	if err := h.applySDPAnswer(connection, connectionID, request.SDP); err != nil {
+		logger.Base().Error("Failed to process SDP answer")
+		http.Error(w, "Failed to process SDP answer", http.StatusInternalServerError)
+		return
+	}
+
+	logger.Base().Info("WebRTC ready for CallID: , waiting for user to accept call", zap.String("call_id", request.CallID))
+	logger.Base().Info("AI will be initialized when call is accepted")
+
+	h.sendOKResponse(w)
+}
+
+// applySDPAnswer applies the user's SDP answer to an outbound call's WebRTC connection
+func (h *OutboundWebhookHandler) applySDPAnswer(connection *call.WhatsAppCallConnection, connectionID, sdp string) error {
 	// Apply SDP answer to WebRTC connection
 	logger.Base().Info("Processing SDP answer for connection", zap.String("connection_id", connectionID))
 
@@ -536,18 +668,12 @@ func (h *OutboundWebhookHandler) HandleSDPAnswerWebhook(w http.ResponseWriter, r
 	}
 
 	webrtcProcessor := h.service.GetWebRTCProcessor()
-	err := webrtcProcessor.ProcessSDPAnswer(connectionID, request.SDP)
-	if err != nil {
-		logger.Base().Error("Failed to process SDP answer")
-		http.Error(w, "Failed to process SDP answer", http.StatusInternalServerError)
-		return
+	if err := webrtcProcessor.ProcessSDPAnswer(connectionID, sdp); err != nil {
+		return err
 	}
 
 	logger.Base().Info("Successfully established WebRTC connection", zap.String("connection_id", connectionID))
-	logger.Base().Info("WebRTC ready for CallID: , waiting for user to accept call", zap.String("call_id", request.CallID))
-	logger.Base().Info("AI will be initialized when call is accepted")
-
-	h.sendOKResponse(w)
+	return nil
 }
 
 // HandleCallStatusWebhook handles call status webhook from Wati
@@ -567,12 +693,158 @@ func (h *OutboundWebhookHandler) HandleCallStatusWebhook(w http.ResponseWriter,
 
 	logger.Base().Info("Call status: callId=, status=", zap.String("call_id", request.CallID), zap.String("status", request.Status))
 
+	// The connection may live on another pod, which then handles the status. This runs
+	// before the status is recorded, as a final state would stop the forward.
+	forwarded := false
+	if connection, _ := h.service.GetConnectionByCallID(request.CallID); connection == nil {
+		forwarded = h.forwardToOwner(r.Context(), request.CallID, task.TaskTypeOutboundCallStatus, bodyBytes)
+	}
+
+	// Call state lives in the database, so any pod records it
+	h.recordCallStatus(r.Context(), request.CallID, request.Status)
+
+	if forwarded {
+		h.sendOKResponse(w)
+		return
+	}
+
 	// Handle different call statuses
 	h.handleCallStatus(request.CallID, request.Status)
 
 	h.sendOKResponse(w)
 }
 
+// callStatusStates maps Wati call statuses to outbound call states
+var callStatusStates = map[string]string{
+	"RINGING":  domain.OutboundCallStateRinging,
+	"ACCEPTED": domain.OutboundCallStateAnswered,
+	"REJECTED": domain.OutboundCallStateRejected,
+	"ENDED":    domain.OutboundCallStateEnded,
+}
+
+// recordCallStatus moves the call's attempt to the state a call status reports and
+// records campaign call outcomes
+func (h *OutboundWebhookHandler) recordCallStatus(ctx context.Context, callID, status string) {
+	state, ok := callStatusStates[status]
+	if !ok {
+		return
+	}
+
+	attempt, err := h.outboundCalls.GetByCallID(ctx, callID)
+	if err != nil {
+		logger.Base().Error("Failed to look up outbound call", zap.String("call_id", callID), zap.Error(err))
+	} else if attempt != nil {
+		h.transitionAttempt(ctx, attempt.ID, state, "")
+	}
+
+	if h.campaigns == nil {
+		return
+	}
+	switch status {
+	case "ACCEPTED":
+		h.campaigns.HandleCallAccepted(ctx, callID)
+	case "REJECTED":
+		h.campaigns.HandleCallRejected(ctx, callID)
+	}
+}
+
+// forwardToOwner hands a webhook for a call whose connection is not on this pod to the
+// pod that placed it, reporting whether it did
+func (h *OutboundWebhookHandler) forwardToOwner(ctx context.Context, callID string, taskType task.TaskType, payload []byte) bool {
+	if h.taskBus == nil {
+		return false
+	}
+	attempt, err := h.outboundCalls.GetByCallID(ctx, callID)
+	if err != nil {
+		logger.Base().Error("Failed to look up outbound call", zap.String("call_id", callID), zap.Error(err))
+		return false
+	}
+	// A call placed by this pod whose connection is gone has nobody to forward to
+	if attempt == nil || domain.IsOutboundCallFinal(attempt.State) || attempt.PodID == h.outboundCalls.PodID() {
+		return false
+	}
+
+	if err := h.taskBus.Publish(ctx, task.SessionTask{
+		Type:         taskType,
+		ConnectionID: attempt.ID,
+		Payload:      payload,
+	}); err != nil {
+		logger.Base().Error("Failed to forward outbound call webhook", zap.String("call_id", callID), zap.Error(err))
+		return false
+	}
+
+	logger.Base().Info("Forwarded outbound call webhook to owning pod",
+		zap.String("call_id", callID),
+		zap.String("type", string(taskType)),
+		zap.String("pod_id", attempt.PodID))
+	return true
+}
+
+// StartTaskProcessor subscribes to webhooks forwarded by other pods for outbound calls
+// whose connection lives on this pod
+func (h *OutboundWebhookHandler) StartTaskProcessor(ctx context.Context) error {
+	if h.taskBus == nil {
+		return nil
+	}
+	return h.taskBus.Subscribe(ctx, h.handleForwardedTask)
+}
+
+// handleForwardedTask applies a forwarded webhook if the call's connection is on this pod
+func (h *OutboundWebhookHandler) handleForwardedTask(t task.SessionTask) {
+	if t.Type != task.TaskTypeOutboundSDPAnswer && t.Type != task.TaskTypeOutboundCallStatus {
+		return
+	}
+	connection, ok := h.service.GetConnection(t.ConnectionID).(*call.WhatsAppCallConnection)
+	if !ok {
+		// Not on this pod
+		return
+	}
+
+	switch t.Type {
+	case task.TaskTypeOutboundSDPAnswer:
+		var request SDPAnswerWebhookRequest
+		if err := json.Unmarshal(t.Payload, &request); err != nil {
+			logger.Base().Error("Failed to unmarshal forwarded SDP answer", zap.Error(err))
+			return
+		}
+		if err := h.applySDPAnswer(connection, t.ConnectionID, request.SDP); err != nil {
+			logger.Base().Error("Failed to process forwarded SDP answer", zap.String("connection_id", t.ConnectionID), zap.Error(err))
+		}
+
+	case task.TaskTypeOutboundCallStatus:
+		var request CallStatusWebhookRequest
+		if err := json.Unmarshal(t.Payload, &request); err != nil {
+			logger.Base().Error("Failed to unmarshal forwarded call status", zap.Error(err))
+			return
+		}
+		h.handleCallStatus(request.CallID, request.Status)
+	}
+}
+
+// handleExpiredAttempt ends the call of an attempt that timed out: Wati hangs up, the pod
+// holding its connection cleans it up and a campaign contact it dialled is settled
+func (h *OutboundWebhookHandler) handleExpiredAttempt(ctx context.Context, attempt *domain.OutboundCallAttempt) {
+	if attempt.State == domain.OutboundCallStatePermissionRequested {
+		// Nothing was placed yet
+		if h.campaigns != nil {
+			h.campaigns.HandlePermissionDenied(ctx, attempt.ID)
+		}
+		return
+	}
+	if attempt.CallID != "" {
+		if err := h.watiClient.TerminateCallWithTenant(ctx, attempt.TenantID, attempt.CallID); err != nil {
+			logger.Base().Warn("Failed to terminate expired outbound call", zap.String("call_id", attempt.CallID), zap.Error(err))
+		}
+	}
+	if err := h.service.NotifyCleanup(ctx, attempt.ID); err != nil {
+		logger.Base().Warn("Failed to clean up expired outbound call", zap.String("attempt_id", attempt.ID), zap.Error(err))
+	}
+	// The connection may already be gone, e.g. after a restart, so cleanup cannot be relied on
+	if h.campaigns != nil {
+		h.campaigns.HandleConnectionEnded(attempt.ID, attempt.State == domain.OutboundCallStateAnswered)
+	}
+}
+
 // handleCallStatus handles different call status types
 func (h *OutboundWebhookHandler) handleCallStatus(callID, status string) {
 	connection, _ := h.service.GetConnectionByCallID(callID)
@@ -627,17 +899,40 @@ func (h *OutboundWebhookHandler) handleCallStatus(callID, status string) {
 	case "REJECTED":
 		logger.Base().Error("Call was rejected by user", zap.String("call_id", callID))
 		logger.Base().Info("AI was not initialized (avoided resource waste)")
-		h.service.NotifyCleanupByCallID(context.Background(), callID)
+		h.cleanupCall(context.Background(), callID, connection)
 
 	case "ENDED":
 		logger.Base().Info("🔚 Call has ended", zap.String("call_id", callID))
-		h.service.NotifyCleanupByCallID(context.Background(), callID)
+		h.cleanupCall(context.Background(), callID, connection)
 
 	default:
 		logger.Base().Warn("Unknown call status: for CallID", zap.String("call_id", callID), zap.String("status", status))
 	}
 }
 
+// cleanupCall cleans up a call's connection on whichever pod holds it. Cleanup
+// subscribers look connections up by ID, which for outbound calls is the attempt ID,
+// so a connection that is not on this pod is cleaned up through its attempt.
+func (h *OutboundWebhookHandler) cleanupCall(ctx context.Context, callID string, connection *call.WhatsAppCallConnection) {
+	if connection != nil {
+		h.service.NotifyCleanup(ctx, connection.ID)
+		return
+	}
+
+	attempt, err := h.outboundCalls.GetByCallID(ctx, callID)
+	if err != nil {
+		logger.Base().Error("Failed to look up outbound call", zap.String("call_id", callID), zap.Error(err))
+		return
+	}
+	if attempt == nil {
+		logger.Base().Warn("No outbound call to clean up for CallID", zap.String("call_id", callID))
+		return
+	}
+	if err := h.service.NotifyCleanup(ctx, attempt.ID); err != nil {
+		logger.Base().Warn("Failed to clean up outbound call", zap.String("attempt_id", attempt.ID), zap.Error(err))
+	}
+}
+
 // waitAndTriggerGreeting waits for AI to be ready and triggers greeting signal
 func (h *OutboundWebhookHandler) waitAndTriggerGreeting(connectionID, callID string) {
 	logger.Base().Info("Waiting for AI ready", zap.String("call_id", callID))
@@ -8,6 +8,8 @@ import (
 
 	httpadapter "github.com/ClareAI/astra-voice-service/internal/adapters/http"
 	"github.com/ClareAI/astra-voice-service/internal/adapters/livekit"
+	"github.com/ClareAI/astra-voice-service/internal/adapters/sip"
+	"github.com/ClareAI/astra-voice-service/internal/adapters/twilio"
 	"github.com/ClareAI/astra-voice-service/internal/config"
 	whatsappconfig "github.com/ClareAI/astra-voice-service/internal/config"
 	"github.com/ClareAI/astra-voice-service/internal/core/model"
@@ -15,12 +17,21 @@ import (
 	"github.com/ClareAI/astra-voice-service/internal/core/model/provider"
 	"github.com/ClareAI/astra-voice-service/internal/core/session"
 	"github.com/ClareAI/astra-voice-service/internal/core/task"
+	"github.com/ClareAI/astra-voice-service/internal/domain"
 	"github.com/ClareAI/astra-voice-service/internal/repository"
 	"github.com/ClareAI/astra-voice-service/internal/services/agent"
 	"github.com/ClareAI/astra-voice-service/internal/services/call"
+	"github.com/ClareAI/astra-voice-service/internal/services/callback"
+	"github.com/ClareAI/astra-voice-service/internal/services/campaign"
+	"github.com/ClareAI/astra-voice-service/internal/services/outbound"
+	"github.com/ClareAI/astra-voice-service/internal/services/textchat"
 	"github.com/ClareAI/astra-voice-service/internal/storage"
+	"github.com/ClareAI/astra-voice-service/pkg/audiocue"
 	"github.com/ClareAI/astra-voice-service/pkg/data/mcp"
 	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/metrics"
+	"github.com/ClareAI/astra-voice-service/pkg/objectstore"
+	"github.com/ClareAI/astra-voice-service/pkg/rag"
 	"github.com/ClareAI/astra-voice-service/pkg/redis"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
@@ -41,6 +52,20 @@ type HandlerManager struct {
 	// Management handlers are used internally
 	// LiveKit integration (NEW - optional, only initialized if enabled)
 	livekitRoomManager *livekit.RoomManager
+
+	// SIP trunk user agent (optional, only initialized if enabled)
+	sipUserAgent *sip.UserAgent
+
+	// Twilio Media Streams channel (optional, only initialized if enabled)
+	twilioConfig *twilio.Config
+
+	// Text-mode agent sessions (optional, needs Redis)
+	textChatService *textchat.Service
+
+	// Outbound calls and the campaigns that place them
+	outboundWebhookHandler *OutboundWebhookHandler
+	campaignService        *campaign.Service
+	callbackService        *callback.Service
 }
 
 // NewHandlerManager creates and initializes all handlers and services
@@ -108,7 +133,7 @@ func NewHandlerManager(cfg *whatsappconfig.WhatsAppCallConfig) (*HandlerManager,
 
 	// Initialize Session Manager
 	var sessionManager *session.Manager
-	var taskBus *task.RedisBus
+	var taskBus task.Bus
 	if redisSvc != nil {
 		podID := cfg.InstanceID
 		if podID == "" {
@@ -134,21 +159,42 @@ func NewHandlerManager(cfg *whatsappconfig.WhatsAppCallConfig) (*HandlerManager,
 		zap.String("path", cfg.AudioStoragePath),
 	)
 
-	if cfg.AudioStorageEnabled && cfg.AudioStoragePath != "" {
-		ctx := context.Background()
-		storageType := storage.StorageType(cfg.AudioStorageType)
-		if err := storage.InitAudioCache(ctx, true, storageType, cfg.AudioStoragePath); err != nil {
-			logger.Base().Warn("failed to initialize audio cache, continue without caching",
+	// Object store shared by recordings and LiveKit egress
+	objectStoreConfig := storage.ObjectStoreConfig(storage.StorageType(cfg.AudioStorageType), cfg.AudioStoragePath)
+	objectStoreConfig.Endpoint = cfg.S3Endpoint
+	objectStoreConfig.Region = cfg.S3Region
+	objectStoreConfig.AccessKeyID = cfg.S3AccessKeyID
+	objectStoreConfig.SecretKey = cfg.S3SecretAccessKey
+	objectStoreConfig.ForcePathStyle = cfg.S3ForcePathStyle
+
+	// Uploaded hold music and audio cues share the store even when recording is off
+	var objectStore objectstore.Store
+	if cfg.AudioStoragePath != "" {
+		store, err := objectstore.New(context.Background(), objectStoreConfig)
+		if err != nil {
+			logger.Base().Warn("failed to initialize object store, audio cache and cue uploads disabled",
 				zap.Error(err),
 				zap.String("type", cfg.AudioStorageType),
 				zap.String("path", cfg.AudioStoragePath),
 			)
 		} else {
-			logger.Base().Info("audio cache initialized",
-				zap.String("type", cfg.AudioStorageType),
-				zap.String("path", cfg.AudioStoragePath),
-			)
+			objectStore = store
+			audiocue.InitLibrary(store)
+		}
+	}
+
+	if cfg.AudioStorageEnabled && objectStore != nil {
+		storage.InitAudioCacheWithStore(context.Background(), objectStore, cfg.AudioSpoolPath, 1.5, 1.0)
+		if audioCache := storage.GetAudioCache(); audioCache != nil {
+			audioCache.SetRecordingRepository(repoManager.VoiceRecording())
+			// Assemble recordings interrupted by a previous restart
+			go audioCache.RecoverSpooledRecordings()
 		}
+		logger.Base().Info("audio cache initialized",
+			zap.String("type", cfg.AudioStorageType),
+			zap.String("path", cfg.AudioStoragePath),
+			zap.String("spool_path", cfg.AudioSpoolPath),
+		)
 	} else {
 		logger.Base().Info("audio cache disabled",
 			zap.Bool("enabled", cfg.AudioStorageEnabled),
@@ -209,6 +255,12 @@ func NewHandlerManager(cfg *whatsappconfig.WhatsAppCallConfig) (*HandlerManager,
 				zap.Error(err),
 			)
 		} else {
+			livekitConfig.SetRecordingMode(cfg.LiveKitRecordingMode)
+
+			// Without a dedicated egress bucket, egress uploads to the S3 recording store
+			if cfg.LiveKitGCSBucket == "" && objectStoreConfig.Backend == objectstore.BackendS3 && cfg.AudioStoragePath != "" {
+				livekitConfig.Storage = &objectStoreConfig
+			}
 			livekitRoomManager, err = livekit.NewRoomManager(livekitConfig, service, coreOpenAIHandler)
 			if err != nil {
 				logger.Base().Warn("failed to initialize livekit room manager, disabled",
@@ -217,6 +269,8 @@ func NewHandlerManager(cfg *whatsappconfig.WhatsAppCallConfig) (*HandlerManager,
 			} else {
 				// Start cleanup routine for expired connections
 				go livekitRoomManager.StartCleanupRoutine(context.Background())
+				// Close the room when the agent ends the call
+				service.OnHangUp(domain.ChannelTypeLiveKit, livekitRoomManager.CleanupRoom)
 				logger.Base().Info("livekit integration initialized")
 			}
 		}
@@ -227,6 +281,63 @@ func NewHandlerManager(cfg *whatsappconfig.WhatsAppCallConfig) (*HandlerManager,
 		)
 	}
 
+	// Initialize SIP trunk user agent (only if enabled)
+	var sipUserAgent *sip.UserAgent
+	if cfg.SIPEnabled {
+		sipUserAgent = newSIPUserAgent(cfg, service, repoManager)
+	} else {
+		logger.Base().Info("sip trunk disabled")
+	}
+
+	// Initialize Twilio Media Streams channel (only if enabled)
+	var twilioConfig *twilio.Config
+	if cfg.TwilioMediaStreamsEnabled {
+		twilioConfig = newTwilioConfig(cfg)
+	} else {
+		logger.Base().Info("twilio media streams disabled")
+	}
+
+	// Initialize text-mode agent sessions (preview history lives in Redis)
+	var textChatService *textchat.Service
+	if redisSvc != nil && agentService != nil {
+		ragProcessor := rag.NewAgentRAGProcessor(agentService, rag.NewDefaultTranslator())
+		chatClient := textchat.NewChatClient(cfg.OpenAIAPIKey, cfg.OpenAIBaseURL, cfg.TextModel)
+		textChatService = textchat.NewService(agentService, composioService, ragProcessor, redisSvc, repoManager, chatClient)
+	} else {
+		logger.Base().Info("text sessions disabled, redis not available")
+	}
+
+	// Persist outbound call state so Wati webhooks can be resolved on any pod
+	outboundCalls := outbound.NewService(repoManager.OutboundCall(), cfg.InstanceID)
+	outboundWebhookHandler := NewOutboundWebhookHandler(service, watiClient, repoManager, taskBus, outboundCalls)
+	outboundCalls.OnExpired(outboundWebhookHandler.handleExpiredAttempt)
+	service.OnConnectionEnded(outboundCalls.HandleConnectionEnded)
+	if err := outboundWebhookHandler.StartTaskProcessor(context.Background()); err != nil {
+		logger.Base().Error("failed to subscribe to forwarded outbound call webhooks", zap.Error(err))
+	}
+	go outboundCalls.StartTimeoutRoutine(context.Background())
+
+	// Initialize outbound calling campaigns; they dial through the outbound webhook handler
+	// and settle contacts from its call events and from connection cleanup
+	campaignService := campaign.NewService(repoManager.Campaign(), agentService, outboundWebhookHandler)
+	outboundWebhookHandler.campaigns = campaignService
+	service.OnConnectionEnded(campaignService.HandleConnectionEnded)
+	if cfg.CampaignWorkerEnabled {
+		go campaignService.StartWorker(context.Background())
+	} else {
+		logger.Base().Info("campaign worker disabled")
+	}
+
+	// Initialize call backs requested through the schedule_callback tool; they are placed
+	// as outbound calls too
+	callbackService := callback.NewService(repoManager.Callback(), agentService, outboundWebhookHandler)
+	coreOpenAIHandler.ToolManager.Callbacks = callbackService
+	if cfg.CallbackWorkerEnabled {
+		go callbackService.StartWorker(context.Background())
+	} else {
+		logger.Base().Info("callback worker disabled")
+	}
+
 	// Start automatic cleanup routine for inactive connections
 	// This monitors conversation activity and cleans up connections that have been
 	// inactive (no new messages) for more than the specified timeout
@@ -246,15 +357,81 @@ func NewHandlerManager(cfg *whatsappconfig.WhatsAppCallConfig) (*HandlerManager,
 		composioService:    composioService,
 		taskBus:            taskBus,
 		livekitRoomManager: livekitRoomManager,
+		sipUserAgent:       sipUserAgent,
+		twilioConfig:       twilioConfig,
+		textChatService:    textChatService,
+
+		outboundWebhookHandler: outboundWebhookHandler,
+		campaignService:        campaignService,
+		callbackService:        callbackService,
 	}, nil
 }
 
+// newSIPUserAgent starts the SIP trunk user agent, returning nil if it cannot start
+func newSIPUserAgent(cfg *whatsappconfig.WhatsAppCallConfig, service *call.WhatsAppCallService, repoManager repository.RepositoryManager) *sip.UserAgent {
+	sipConfig, err := sip.NewConfig(cfg.SIPListenAddr, cfg.SIPAgentID)
+	if err != nil {
+		logger.Base().Warn("failed to create sip config, disabled", zap.Error(err))
+		return nil
+	}
+	sipConfig.Transport = cfg.SIPTransport
+	sipConfig.PublicHost = cfg.SIPPublicHost
+	sipConfig.RTPPortMin = cfg.SIPRTPPortMin
+	sipConfig.RTPPortMax = cfg.SIPRTPPortMax
+	sipConfig.Registrar = cfg.SIPRegistrar
+	sipConfig.Username = cfg.SIPUsername
+	sipConfig.Password = cfg.SIPPassword
+	sipConfig.Domain = cfg.SIPDomain
+	sipConfig.TenantID = cfg.SIPTenantID
+	sipConfig.VoiceLanguage = cfg.SIPVoiceLanguage
+	sipConfig.SetRoutes(cfg.SIPRoutes)
+	sipConfig.SetCodecs(cfg.SIPCodecs)
+	if err := sipConfig.SetTrustedNetworks(cfg.SIPTrustedCIDRs); err != nil {
+		logger.Base().Warn("invalid sip trusted networks, disabled", zap.Error(err))
+		return nil
+	}
+
+	userAgent, err := sip.NewUserAgent(sipConfig, service, repoManager)
+	if err != nil {
+		logger.Base().Warn("failed to create sip user agent, disabled", zap.Error(err))
+		return nil
+	}
+	if err := userAgent.Start(context.Background()); err != nil {
+		logger.Base().Warn("failed to start sip user agent, disabled", zap.Error(err))
+		return nil
+	}
+	logger.Base().Info("sip trunk initialized",
+		zap.String("listen_addr", cfg.SIPListenAddr),
+		zap.String("registrar", cfg.SIPRegistrar),
+	)
+	return userAgent
+}
+
+// newTwilioConfig builds the Twilio Media Streams configuration, returning nil if it is invalid
+func newTwilioConfig(cfg *whatsappconfig.WhatsAppCallConfig) *twilio.Config {
+	twilioConfig, err := twilio.NewConfig(cfg.TwilioAuthToken, cfg.TwilioAgentID)
+	if err != nil {
+		logger.Base().Warn("failed to create twilio config, disabled", zap.Error(err))
+		return nil
+	}
+	twilioConfig.PublicURL = cfg.TwilioPublicURL
+	twilioConfig.TenantID = cfg.TwilioTenantID
+	twilioConfig.VoiceLanguage = cfg.TwilioVoiceLanguage
+	twilioConfig.SetRoutes(cfg.TwilioRoutes)
+	logger.Base().Info("twilio media streams initialized", zap.String("public_url", cfg.TwilioPublicURL))
+	return twilioConfig
+}
+
 // SetupAllRoutes sets up all routes with middleware
 func (hm *HandlerManager) SetupAllRoutes(router *mux.Router) {
 	// Apply global middleware
 	router.Use(CORSMiddleware)
+	router.Use(TracingMiddleware)
 	router.Use(GlobalLoggingMiddleware)
 
+	// Prometheus metrics
+	router.Handle("/metrics", metrics.Handler()).Methods("GET")
+
 	// Setup CRUD API routes
 	hm.SetupAPIRoutes(router)
 
@@ -278,6 +455,17 @@ func (hm *HandlerManager) SetupAllRoutes(router *mux.Router) {
 		hm.SetupLiveKitRoutes(router)
 	}
 
+	// Setup Twilio Media Streams routes (only if enabled)
+	if hm.twilioConfig != nil {
+		hm.SetupTwilioRoutes(router)
+	}
+
+	// Setup WebSocket audio routes
+	hm.SetupWSAudioRoutes(router)
+
+	// Setup WHIP routes
+	hm.SetupWHIPRoutes(router)
+
 	logger.Base().Info("all application routes registered")
 }
 
@@ -299,9 +487,20 @@ func (hm *HandlerManager) SetupAPIRoutes(router *mux.Router) {
 	tenantHandler := NewTenantHandler(hm.repoManager.VoiceTenant())
 	tenantHandler.SetupTenantRoutes(apiRouter)
 
-	voiceConversationHandler := NewVoiceConversationHandler(hm.repoManager.VoiceConversation(), hm.repoManager.VoiceMessage())
+	voiceConversationHandler := NewVoiceConversationHandler(hm.repoManager.VoiceConversation(), hm.repoManager.VoiceMessage(), hm.repoManager.VoiceRecording())
 	voiceConversationHandler.SetupVoiceConversationRoutes(apiRouter)
 
+	if hm.textChatService != nil {
+		textSessionHandler := NewTextSessionHandler(hm.textChatService)
+		textSessionHandler.SetupTextSessionRoutes(apiRouter)
+	}
+
+	campaignHandler := NewCampaignHandler(hm.campaignService)
+	campaignHandler.SetupCampaignRoutes(apiRouter)
+
+	callbackHandler := NewCallbackHandler(hm.callbackService)
+	callbackHandler.SetupCallbackRoutes(apiRouter)
+
 	// Setup CORS middleware for all API routes
 	router.PathPrefix("/api/").HandlerFunc(handleCORS).Methods("OPTIONS")
 
@@ -372,8 +571,7 @@ func (hm *HandlerManager) SetupWatiRoutes(router *mux.Router) {
 
 // SetupOutboundWebhookRoutes sets up outbound call webhook routes
 func (hm *HandlerManager) SetupOutboundWebhookRoutes(router *mux.Router) {
-	outboundWebhookHandler := NewOutboundWebhookHandler(hm.service, hm.watiClient, hm.repoManager, hm.taskBus)
-	outboundWebhookHandler.SetupOutboundWebhookRoutes(router)
+	hm.outboundWebhookHandler.SetupOutboundWebhookRoutes(router)
 
 	logger.Base().Info("outbound webhook routes registered")
 }
@@ -389,6 +587,41 @@ func (hm *HandlerManager) SetupLiveKitRoutes(router *mux.Router) {
 	logger.Base().Info("livekit routes registered with CORS")
 }
 
+// SetupTwilioRoutes sets up Twilio voice webhook and Media Streams routes
+func (hm *HandlerManager) SetupTwilioRoutes(router *mux.Router) {
+	twilioHandler := NewTwilioHandler(hm.twilioConfig, hm.service, hm.repoManager)
+	twilioHandler.SetupTwilioRoutes(router)
+
+	logger.Base().Info("twilio routes registered")
+}
+
+// SetupWSAudioRoutes sets up the plain WebSocket audio channel
+func (hm *HandlerManager) SetupWSAudioRoutes(router *mux.Router) {
+	wsAudioHandler := NewWSAudioHandler(hm.service, hm.repoManager, hm.taskBus)
+	wsAudioHandler.SetupWSAudioRoutes(router)
+
+	logger.Base().Info("websocket audio routes registered")
+}
+
+// SetupWHIPRoutes sets up WHIP signalling for web calls
+func (hm *HandlerManager) SetupWHIPRoutes(router *mux.Router) {
+	whipHandler := NewWHIPHandler(hm.service, hm.repoManager, hm.taskBus)
+	whipHandler.SetupWHIPRoutes(router)
+	if err := whipHandler.StartTaskProcessor(context.Background()); err != nil {
+		logger.Base().Error("failed to subscribe to forwarded whip candidates", zap.Error(err))
+	}
+
+	logger.Base().Info("whip routes registered")
+}
+
+// Close releases resources held beyond a single request; the SIP user agent hangs up its calls
+// and unregisters from the carrier
+func (hm *HandlerManager) Close() {
+	if hm.sipUserAgent != nil {
+		hm.sipUserAgent.Close()
+	}
+}
+
 // GetRepoManager returns the repository manager
 func (hm *HandlerManager) GetRepoManager() repository.RepositoryManager {
 	return hm.repoManager
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ClareAI/astra-voice-service/internal/services/textchat"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// TextSessionHandler serves text chat sessions with voice agents, for iterating on
+// prompts without calling
+type TextSessionHandler struct {
+	service *textchat.Service
+}
+
+// NewTextSessionHandler creates a new text session handler
+func NewTextSessionHandler(service *textchat.Service) *TextSessionHandler {
+	return &TextSessionHandler{service: service}
+}
+
+// SetupTextSessionRoutes registers text session routes on the API router
+func (h *TextSessionHandler) SetupTextSessionRoutes(router *mux.Router) {
+	router.HandleFunc("/text-sessions", h.CreateSession).Methods("POST")
+	router.HandleFunc("/text-sessions/{id}", h.GetSession).Methods("GET")
+	router.HandleFunc("/text-sessions/{id}", h.EndSession).Methods("DELETE")
+	router.HandleFunc("/text-sessions/{id}/messages", h.SendMessage).Methods("POST")
+
+	logger.Base().Info("text session routes registered")
+}
+
+// CreateSession godoc
+// @Summary Start a text session
+// @Description Start a text chat with a voice agent (draft config unless published is set); returns the agent's greeting. Streams server-sent events with ?stream=true.
+// @Tags text-sessions
+// @Accept json
+// @Produce json
+// @Param request body textchat.CreateSessionRequest true "Session request"
+// @Success 201 {object} object "Session and greeting"
+// @Failure 400 {string} string "Bad request"
+// @Router /api/text-sessions [post]
+func (h *TextSessionHandler) CreateSession(w http.ResponseWriter, r *http.Request) {
+	var req textchat.CreateSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.AgentID == "" {
+		http.Error(w, "agentId is required", http.StatusBadRequest)
+		return
+	}
+
+	if wantsStream(r) {
+		stream := newEventStream(w)
+		session, reply, err := h.service.CreateSession(r.Context(), req, stream.event)
+		if err != nil {
+			logger.Base().Warn("Failed to create text session", zap.String("agent_id", req.AgentID), zap.Error(err))
+			stream.send("error", map[string]string{"message": err.Error()})
+			return
+		}
+		stream.send("done", map[string]interface{}{"session": session, "reply": reply})
+		return
+	}
+
+	session, reply, err := h.service.CreateSession(r.Context(), req, nil)
+	if err != nil {
+		logger.Base().Warn("Failed to create text session", zap.String("agent_id", req.AgentID), zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{"session": session, "reply": reply})
+}
+
+// GetSession godoc
+// @Summary Get a text session
+// @Description Get a text session and its history
+// @Tags text-sessions
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} object "Session and messages"
+// @Failure 404 {string} string "Session not found"
+// @Router /api/text-sessions/{id} [get]
+func (h *TextSessionHandler) GetSession(w http.ResponseWriter, r *http.Request) {
+	session, history, err := h.service.GetSession(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeTextSessionError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"session": session, "messages": history})
+}
+
+// SendMessage godoc
+// @Summary Send a message in a text session
+// @Description Run one user turn and return the agent's reply with the tools it called. Streams server-sent events with ?stream=true.
+// @Tags text-sessions
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID"
+// @Param request body object true "Message with text"
+// @Success 200 {object} textchat.Reply "Agent reply"
+// @Failure 404 {string} string "Session not found"
+// @Router /api/text-sessions/{id}/messages [post]
+func (h *TextSessionHandler) SendMessage(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Text == "" {
+		http.Error(w, "text is required", http.StatusBadRequest)
+		return
+	}
+	sessionID := mux.Vars(r)["id"]
+
+	if wantsStream(r) {
+		stream := newEventStream(w)
+		reply, err := h.service.SendMessage(r.Context(), sessionID, req.Text, stream.event)
+		if err != nil {
+			logger.Base().Warn("Text session turn failed", zap.String("session_id", sessionID), zap.Error(err))
+			stream.send("error", map[string]string{"message": err.Error()})
+			return
+		}
+		stream.send("done", reply)
+		return
+	}
+
+	reply, err := h.service.SendMessage(r.Context(), sessionID, req.Text, nil)
+	if err != nil {
+		logger.Base().Warn("Text session turn failed", zap.String("session_id", sessionID), zap.Error(err))
+		writeTextSessionError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reply)
+}
+
+// EndSession godoc
+// @Summary End a text session
+// @Description End the session's conversation and drop its preview history
+// @Tags text-sessions
+// @Param id path string true "Session ID"
+// @Success 204 "Session ended"
+// @Failure 404 {string} string "Session not found"
+// @Router /api/text-sessions/{id} [delete]
+func (h *TextSessionHandler) EndSession(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.EndSession(r.Context(), mux.Vars(r)["id"]); err != nil {
+		writeTextSessionError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeTextSessionError maps service errors to HTTP statuses
+func writeTextSessionError(w http.ResponseWriter, err error) {
+	if errors.Is(err, textchat.ErrSessionNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+// wantsStream reports whether the client asked for server-sent events
+func wantsStream(r *http.Request) bool {
+	return r.URL.Query().Get("stream") == "true" || strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// eventStream writes server-sent events
+type eventStream struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+func newEventStream(w http.ResponseWriter) *eventStream {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	return &eventStream{w: w, flusher: flusher}
+}
+
+// event forwards a turn's progress
+func (s *eventStream) event(e textchat.Event) {
+	s.send(e.Type, e)
+}
+
+// send writes one event
+func (s *eventStream) send(name string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", name, payload)
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ClareAI/astra-voice-service/internal/adapters/twilio"
+	"github.com/ClareAI/astra-voice-service/internal/domain"
+	"github.com/ClareAI/astra-voice-service/internal/repository"
+	"github.com/ClareAI/astra-voice-service/internal/services/agent"
+	"github.com/ClareAI/astra-voice-service/internal/services/call"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// TwilioHandler answers Twilio voice webhooks and serves their Media Streams
+type TwilioHandler struct {
+	config       *twilio.Config
+	service      *call.WhatsAppCallService
+	repoManager  repository.RepositoryManager
+	agentService *agent.AgentService
+	upgrader     websocket.Upgrader
+}
+
+// NewTwilioHandler creates a new Twilio handler
+func NewTwilioHandler(config *twilio.Config, service *call.WhatsAppCallService, repoManager repository.RepositoryManager) *TwilioHandler {
+	agentService, err := agent.GetAgentService()
+	if err != nil {
+		logger.Base().Error("Failed to get agent service")
+	}
+
+	return &TwilioHandler{
+		config:       config,
+		service:      service,
+		repoManager:  repoManager,
+		agentService: agentService,
+		upgrader: websocket.Upgrader{
+			// Twilio is not a browser; requests are authenticated by signature instead
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// SetupTwilioRoutes registers Twilio routes
+func (h *TwilioHandler) SetupTwilioRoutes(router *mux.Router) {
+	// POST /twilio/voice - Voice webhook, answers with TwiML connecting a media stream
+	router.HandleFunc(twilio.VoicePath, h.HandleVoice).Methods("POST")
+
+	// GET /twilio/media-stream - Media Streams WebSocket
+	router.HandleFunc(twilio.StreamPath, h.HandleMediaStream).Methods("GET")
+
+	logger.Base().Info("Twilio routes registered")
+}
+
+// HandleVoice answers an incoming call by connecting it to a bidirectional media stream.
+// The agent comes from the agent_id query parameter of the webhook URL or the routes.
+// POST /twilio/voice
+func (h *TwilioHandler) HandleVoice(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form body", http.StatusBadRequest)
+		return
+	}
+	if !h.validSignature(r) {
+		http.Error(w, "Invalid signature", http.StatusForbidden)
+		return
+	}
+
+	callSid := r.PostForm.Get("CallSid")
+	from := r.PostForm.Get("From")
+	to := r.PostForm.Get("To")
+	agentID := r.URL.Query().Get(twilio.ParamAgentID)
+	if agentID == "" {
+		agentID = h.config.AgentFor(to)
+	}
+
+	if h.agentService != nil {
+		agentConfig, err := h.agentService.GetAgentConfigWithChannelType(context.Background(), agentID, domain.ChannelTypeTwilio)
+		if err != nil || agentConfig == nil {
+			logger.Base().Warn("Rejecting Twilio call, agent not found",
+				zap.String("call_sid", callSid),
+				zap.String("agent_id", agentID),
+				zap.Error(err))
+			h.writeTwiML(w, twilio.RejectTwiML)
+			return
+		}
+		agentID = agentConfig.ID
+	}
+
+	logger.Base().Info("Answering Twilio call",
+		zap.String("call_sid", callSid),
+		zap.String("from", from),
+		zap.String("to", to),
+		zap.String("agent_id", agentID))
+
+	h.writeTwiML(w, func() ([]byte, error) {
+		return twilio.ConnectTwiML(h.config.StreamURL(r), map[string]string{
+			twilio.ParamAgentID:    agentID,
+			twilio.ParamFrom:       from,
+			twilio.ParamTo:         to,
+			twilio.ParamCallerName: r.PostForm.Get("CallerName"),
+		})
+	})
+}
+
+// HandleMediaStream runs a call over a Media Streams WebSocket until it ends
+// GET /twilio/media-stream
+func (h *TwilioHandler) HandleMediaStream(w http.ResponseWriter, r *http.Request) {
+	if !h.validSignature(r) {
+		http.Error(w, "Invalid signature", http.StatusForbidden)
+		return
+	}
+
+	ws, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logger.Base().Error("Failed to upgrade Twilio media stream", zap.Error(err))
+		return
+	}
+
+	stream, err := twilio.NewStream(ws, h.config, h.service, h.repoManager)
+	if err != nil {
+		logger.Base().Error("Failed to create Twilio stream", zap.Error(err))
+		ws.Close()
+		return
+	}
+	stream.Run()
+}
+
+// validSignature checks X-Twilio-Signature; without an auth token every request is rejected
+func (h *TwilioHandler) validSignature(r *http.Request) bool {
+	if h.config.AuthToken == "" {
+		logger.Base().Warn("Rejecting Twilio request, no auth token configured", zap.String("remote_addr", r.RemoteAddr))
+		return false
+	}
+	requestURL := h.config.RequestURL(r)
+	if twilio.ValidateSignature(h.config.AuthToken, requestURL, r.PostForm, r.Header.Get(twilio.SignatureHeader)) {
+		return true
+	}
+	logger.Base().Warn("Rejecting Twilio request with invalid signature",
+		zap.String("url", requestURL),
+		zap.String("remote_addr", r.RemoteAddr))
+	return false
+}
+
+// writeTwiML writes a TwiML response
+func (h *TwilioHandler) writeTwiML(w http.ResponseWriter, build func() ([]byte, error)) {
+	body, err := build()
+	if err != nil {
+		logger.Base().Error("Failed to build TwiML", zap.Error(err))
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
@@ -17,13 +17,15 @@ import (
 type VoiceConversationHandler struct {
 	conversationRepo *repository.VoiceConversationRepository
 	messageRepo      *repository.VoiceMessageRepository
+	recordingRepo    *repository.VoiceRecordingRepository
 }
 
 // NewVoiceConversationHandler creates a new voice conversation handler
-func NewVoiceConversationHandler(conversationRepo *repository.VoiceConversationRepository, messageRepo *repository.VoiceMessageRepository) *VoiceConversationHandler {
+func NewVoiceConversationHandler(conversationRepo *repository.VoiceConversationRepository, messageRepo *repository.VoiceMessageRepository, recordingRepo *repository.VoiceRecordingRepository) *VoiceConversationHandler {
 	return &VoiceConversationHandler{
 		conversationRepo: conversationRepo,
 		messageRepo:      messageRepo,
+		recordingRepo:    recordingRepo,
 	}
 }
 
@@ -380,6 +382,12 @@ func (h *VoiceConversationHandler) DeleteVoiceConversation(w http.ResponseWriter
 		logger.Base().Error("Warning: Failed to delete messages for conversation", zap.String("id", conversation.ID))
 	}
 
+	// Recording files stay in storage; only their registrations are removed
+	err = h.recordingRepo.DeleteByConversationID(r.Context(), conversation.ID)
+	if err != nil {
+		logger.Base().Error("Warning: Failed to delete recordings for conversation", zap.String("id", conversation.ID))
+	}
+
 	// Delete conversation (implement this method in repository)
 	err = h.conversationRepo.Delete(r.Context(), conversation.ID)
 	if err != nil {
@@ -438,6 +446,53 @@ func (h *VoiceConversationHandler) GetVoiceConversationMessages(w http.ResponseW
 	})
 }
 
+// GetVoiceConversationRecordings godoc
+// @Summary Get conversation recordings
+// @Description Retrieve the recording files (Opus, WAV, MP3) produced for a voice conversation
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Param id path string true "Conversation ID (UUID) or external conversation ID"
+// @Success 200 {object} map[string]interface{} "Conversation recordings" example({"conversation_id": "uuid", "recordings": [], "total": 0})
+// @Failure 404 {object} map[string]string "Conversation not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/voice-conversations/{id}/recordings [get]
+func (h *VoiceConversationHandler) GetVoiceConversationRecordings(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	conversation, err := h.conversationRepo.GetByID(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if conversation == nil {
+		// Try by external conversation ID
+		conversation, err = h.conversationRepo.GetByExternalConversationID(r.Context(), id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if conversation == nil {
+			http.Error(w, "Voice conversation not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	recordings, err := h.recordingRepo.GetByConversationID(r.Context(), conversation.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"conversation_id": conversation.ID,
+		"recordings":      recordings,
+		"total":           len(recordings),
+	})
+}
+
 // CheckVoiceConversationExists godoc
 // @Summary Check if conversation exists
 // @Description Check whether a voice conversation with the specified ID or external conversation ID exists
@@ -475,8 +530,66 @@ func (h *VoiceConversationHandler) CheckVoiceConversationExists(w http.ResponseW
 	}
 }
 
+// GetVoiceLatencyStats godoc
+// @Summary Get turn latency statistics
+// @Description Get p50/p95 voice-to-voice latency per model provider for a voice agent
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Param voice_agent_id query string true "Voice agent ID"
+// @Param start_time query string false "Start time (RFC3339), defaults to 7 days ago"
+// @Param end_time query string false "End time (RFC3339), defaults to now"
+// @Success 200 {object} map[string]interface{} "Latency statistics"
+// @Failure 400 {object} map[string]string "Invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/voice-conversations/latency-stats [get]
+func (h *VoiceConversationHandler) GetVoiceLatencyStats(w http.ResponseWriter, r *http.Request) {
+	voiceAgentID := r.URL.Query().Get("voice_agent_id")
+	if voiceAgentID == "" {
+		http.Error(w, "voice_agent_id parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	startTime := time.Now().AddDate(0, 0, -7)
+	endTime := time.Now()
+	var err error
+
+	if startTimeStr := r.URL.Query().Get("start_time"); startTimeStr != "" {
+		startTime, err = time.Parse(time.RFC3339, startTimeStr)
+		if err != nil {
+			http.Error(w, "Invalid start_time format, use RFC3339", http.StatusBadRequest)
+			return
+		}
+	}
+	if endTimeStr := r.URL.Query().Get("end_time"); endTimeStr != "" {
+		endTime, err = time.Parse(time.RFC3339, endTimeStr)
+		if err != nil {
+			http.Error(w, "Invalid end_time format, use RFC3339", http.StatusBadRequest)
+			return
+		}
+	}
+
+	stats, err := h.messageRepo.GetLatencyStats(r.Context(), voiceAgentID, startTime, endTime)
+	if err != nil {
+		logger.Base().Error("Failed to get latency stats", zap.String("voice_agent_id", voiceAgentID), zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"voice_agent_id": voiceAgentID,
+		"start_time":     startTime,
+		"end_time":       endTime,
+		"providers":      stats,
+	})
+}
+
 // SetupVoiceConversationRoutes sets up all voice conversation-related routes
 func (h *VoiceConversationHandler) SetupVoiceConversationRoutes(router *mux.Router) {
+	// Latency stats must be registered before /voice-conversations/{id}
+	router.HandleFunc("/voice-conversations/latency-stats", h.GetVoiceLatencyStats).Methods("GET")
+
 	// Voice conversation CRUD routes
 	router.HandleFunc("/voice-conversations", h.CreateVoiceConversation).Methods("POST")
 	router.HandleFunc("/voice-conversations", h.GetVoiceConversations).Methods("GET")
@@ -488,5 +601,8 @@ func (h *VoiceConversationHandler) SetupVoiceConversationRoutes(router *mux.Rout
 	// Voice conversation messages routes
 	router.HandleFunc("/voice-conversations/{id}/messages", h.GetVoiceConversationMessages).Methods("GET")
 
+	// Voice conversation recordings routes
+	router.HandleFunc("/voice-conversations/{id}/recordings", h.GetVoiceConversationRecordings).Methods("GET")
+
 	logger.Base().Info("Voice conversation routes registered")
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/ClareAI/astra-voice-service/internal/services/agent"
 	"github.com/ClareAI/astra-voice-service/internal/services/call"
 	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/tracing"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 )
@@ -747,7 +748,7 @@ func (h *WatiWebhookHandler) processCallOffer(tenantID, callID, offerSDP string,
 
 	// CRITICAL: Wait for Wati API accept to return 200 OK before starting audio
 	logger.Base().Info("Calling Wati API accept and waiting for 200 OK...")
-	if err := h.watiClient.AcceptCallWithTenant(tenantID, callID, sdpAnswer); err != nil {
+	if err := h.watiClient.AcceptCallWithTenant(tracing.CallContext(connection.ID), tenantID, callID, sdpAnswer); err != nil {
 		logger.Base().Error("Wati API failed: %v", zap.Error(err))
 		logger.Base().Info("NOT starting audio - no 200 OK from accept")
 		return
@@ -827,7 +828,7 @@ func (h *WatiWebhookHandler) handleManualAccept(w http.ResponseWriter, r *http.R
 	}
 
 	// Accept call via Wati using the provided tenant ID
-	if err := h.watiClient.AcceptCallWithTenant(request.TenantID, callID, request.SDP); err != nil {
+	if err := h.watiClient.AcceptCallWithTenant(r.Context(), request.TenantID, callID, request.SDP); err != nil {
 		logger.Base().Error("Failed to accept call manually: %v", zap.Error(err))
 		http.Error(w, "Failed to accept call", http.StatusInternalServerError)
 		return
@@ -877,7 +878,7 @@ func (h *WatiWebhookHandler) handleManualTerminate(w http.ResponseWriter, r *htt
 	}
 
 	// Terminate call via Wati using the provided tenant ID
-	if err := h.watiClient.TerminateCallWithTenant(request.TenantID, callID); err != nil {
+	if err := h.watiClient.TerminateCallWithTenant(r.Context(), request.TenantID, callID); err != nil {
 		logger.Base().Error("Failed to terminate call manually: %v", zap.Error(err))
 		http.Error(w, "Failed to terminate call", http.StatusInternalServerError)
 		return
@@ -1088,6 +1089,8 @@ func (h *WatiWebhookHandler) handleWebNewCall(w http.ResponseWriter, r *http.Req
 		Language       string `json:"language"`
 		Accent         string `json:"accent"`
 		ModelProvider  string `json:"modelProvider"`
+
+		Variables map[string]interface{} `json:"variables"` // Per-call variables declared by the agent
 	}
 
 	if err := json.Unmarshal(body, &request); err != nil {
@@ -1148,12 +1151,24 @@ func (h *WatiWebhookHandler) handleWebNewCall(w http.ResponseWriter, r *http.Req
 	agentID := request.AgentId
 	voiceLanguage := "en"
 	var textAgentID string
+	var variables map[string]string
 
 	if agentID != "" {
 		agent, _ := h.agentService.GetAgentConfigWithChannelType(context.Background(), agentID, channelType)
 		if agent != nil {
 			voiceLanguage = agent.Language
 			textAgentID = agent.TextAgentID
+
+			var err error
+			if variables, err = config.ResolveCallVariables(agent.CallVariables(false), request.Variables); err != nil {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"code":    400,
+					"message": err.Error(),
+				})
+				return
+			}
 		} else {
 			http.Error(w, "Agent not found", http.StatusBadRequest)
 			return
@@ -1191,6 +1206,7 @@ func (h *WatiWebhookHandler) handleWebNewCall(w http.ResponseWriter, r *http.Req
 		Accent:        request.Accent,
 		CountryCode:   "US",
 		ContactName:   request.ContactName,
+		Variables:     variables,
 		RepoManager:   h.repoManager, // Add repository manager for database operations
 		ModelProvider: modelProvider,
 	}
@@ -0,0 +1,401 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	httpadapter "github.com/ClareAI/astra-voice-service/internal/adapters/http"
+	webrtcadapter "github.com/ClareAI/astra-voice-service/internal/adapters/webrtc"
+	"github.com/ClareAI/astra-voice-service/internal/config"
+	"github.com/ClareAI/astra-voice-service/internal/core/model/provider"
+	"github.com/ClareAI/astra-voice-service/internal/core/task"
+	"github.com/ClareAI/astra-voice-service/internal/domain"
+	"github.com/ClareAI/astra-voice-service/internal/repository"
+	"github.com/ClareAI/astra-voice-service/internal/services/agent"
+	"github.com/ClareAI/astra-voice-service/internal/services/call"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+const (
+	whipPath = "/whip"
+
+	contentTypeSDP     = "application/sdp"
+	contentTypeSDPFrag = "application/trickle-ice-sdpfrag"
+
+	maxSDPSize = 64 * 1024
+)
+
+// WHIPHandler serves web calls over WHIP (RFC 9725): the client POSTs its SDP offer and
+// gets the answer back with the session's resource URL, which takes trickled ICE
+// candidates (PATCH) and hangs up (DELETE). Requests carry the agent JWT from
+// /api/agents/{id}/jwt as a bearer token.
+type WHIPHandler struct {
+	service      *call.WhatsAppCallService
+	repoManager  repository.RepositoryManager
+	taskBus      task.Bus
+	agentService *agent.AgentService
+}
+
+// NewWHIPHandler creates a new WHIP handler
+func NewWHIPHandler(service *call.WhatsAppCallService, repoManager repository.RepositoryManager, taskBus task.Bus) *WHIPHandler {
+	agentService, err := agent.GetAgentService()
+	if err != nil {
+		logger.Base().Error("Failed to get agent service")
+	}
+
+	return &WHIPHandler{
+		service:      service,
+		repoManager:  repoManager,
+		taskBus:      taskBus,
+		agentService: agentService,
+	}
+}
+
+// SetupWHIPRoutes registers WHIP routes
+func (h *WHIPHandler) SetupWHIPRoutes(router *mux.Router) {
+	// POST /whip - Create a session from an SDP offer
+	router.HandleFunc(whipPath, h.HandleCreate).Methods("POST")
+
+	// PATCH /whip/{id} - Trickle ICE candidates
+	router.HandleFunc(whipPath+"/{id}", h.HandleTrickle).Methods("PATCH")
+
+	// DELETE /whip/{id} - End the session
+	router.HandleFunc(whipPath+"/{id}", h.HandleDelete).Methods("DELETE")
+
+	router.HandleFunc(whipPath, h.handleCORS).Methods("OPTIONS")
+	router.HandleFunc(whipPath+"/{id}", h.handleCORS).Methods("OPTIONS")
+}
+
+// HandleCreate starts a web call from a WHIP offer. Optional query parameters mirror the
+// /wati/web-new-call body: callId, language, accent, contactName, from, modelProvider and
+// variables (a JSON object).
+// POST /whip
+func (h *WHIPHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	setWHIPCORSHeaders(w)
+
+	mapping, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+	if !hasContentType(r, contentTypeSDP) {
+		http.Error(w, "Content-Type must be "+contentTypeSDP, http.StatusUnsupportedMediaType)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSDPSize))
+	if err != nil || len(body) == 0 {
+		http.Error(w, "SDP offer is required", http.StatusBadRequest)
+		return
+	}
+	offerSDP := string(body)
+
+	tenantID, agentID := mapping.TenantID, mapping.AgentID
+	if tenantID == "" {
+		tenantID = config.DefaultTenantID
+	}
+
+	// Usage gating: check tenant allowance before proceeding
+	if tenantID != config.DefaultTenantID && tenantID != config.DefaultWatiTenantID && h.agentService != nil {
+		if allowed, msg := h.agentService.CheckTenantUsageAllowed(context.Background(), tenantID); !allowed {
+			logger.Base().Warn("WHIP usage not allowed for tenant", zap.String("tenant_id", tenantID), zap.String("message", msg))
+			http.Error(w, fmt.Sprintf("Usage not allowed: %s", msg), http.StatusForbidden)
+			return
+		}
+	}
+
+	query := r.URL.Query()
+	voiceLanguage := config.DefaultLanguage
+	var textAgentID string
+	var declaredVariables []config.CallVariable
+	if h.agentService != nil {
+		agentConfig, err := h.agentService.GetAgentConfigWithChannelType(context.Background(), agentID, domain.ChannelTypeWeb)
+		if err != nil || agentConfig == nil {
+			http.Error(w, "Agent not found", http.StatusNotFound)
+			return
+		}
+		agentID = agentConfig.ID
+		textAgentID = agentConfig.TextAgentID
+		declaredVariables = agentConfig.CallVariables(false)
+		if agentConfig.Language != "" {
+			voiceLanguage = agentConfig.Language
+		}
+	}
+	var rawVariables map[string]interface{}
+	if raw := query.Get("variables"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &rawVariables); err != nil {
+			http.Error(w, "variables must be a JSON object", http.StatusBadRequest)
+			return
+		}
+	}
+	variables, err := config.ResolveCallVariables(declaredVariables, rawVariables)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if language := query.Get("language"); language != "" {
+		voiceLanguage = language
+	}
+	modelProvider := provider.ProviderTypeOpenAI
+	if query.Get("modelProvider") == string(provider.ProviderTypeGemini) {
+		modelProvider = provider.ProviderTypeGemini
+	}
+
+	webrtcProcessor := h.service.GetWebRTCProcessor()
+	if webrtcProcessor == nil {
+		http.Error(w, "WebRTC processor not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	callID := query.Get("callId")
+	if callID == "" {
+		callID = fmt.Sprintf("whip-%d", time.Now().UnixNano())
+	}
+	connectionID := fmt.Sprintf("%s_%s_%d", domain.ChannelTypeWeb, callID, time.Now().UnixNano())
+	connection := &call.WhatsAppCallConnection{
+		ID:            connectionID,
+		CallID:        callID,
+		From:          query.Get("from"),
+		CreatedAt:     time.Now(),
+		LastActivity:  time.Now(),
+		IsActive:      true,
+		ChannelType:   domain.ChannelTypeWeb,
+		StopKeepalive: make(chan struct{}),
+		RemoteSDP:     offerSDP,
+		TenantID:      tenantID,
+		AgentID:       agentID,
+		TextAgentID:   textAgentID,
+		VoiceLanguage: voiceLanguage,
+		Accent:        query.Get("accent"),
+		ContactName:   query.Get("contactName"),
+		Variables:     variables,
+		RepoManager:   h.repoManager,
+		ModelProvider: modelProvider,
+	}
+
+	h.service.AddConnection(connection)
+	if err := connection.InitializeVoiceConversation(); err != nil {
+		logger.Base().Warn("Failed to initialize voice conversation", zap.String("connection_id", connectionID), zap.Error(err))
+		// Continue anyway, AddMessage will create it as fallback
+	}
+
+	sdpAnswer, err := webrtcProcessor.ProcessSDPOffer(connectionID, offerSDP)
+	if err != nil {
+		logger.Base().Error("Failed to process WHIP offer", zap.String("connection_id", connectionID), zap.Error(err))
+		h.service.NotifyCleanup(context.Background(), connectionID)
+		http.Error(w, "Invalid SDP offer", http.StatusBadRequest)
+		return
+	}
+	connection.SDPAnswer = sdpAnswer
+	connection.LocalSDP = sdpAnswer
+
+	// Same path as /wati/web-new-call
+	if h.taskBus != nil {
+		payload, _ := json.Marshal(map[string]string{"callId": callID})
+		if err := h.taskBus.Publish(r.Context(), task.SessionTask{
+			Type:         task.TaskTypeWebCall,
+			ConnectionID: connectionID,
+			Payload:      payload,
+		}); err != nil {
+			logger.Base().Error("Failed to publish web-call task", zap.String("connection_id", connectionID), zap.Error(err))
+		}
+	} else {
+		connection.HasInboundAudio = true
+		go h.service.InitializeAIConnection(connection)
+	}
+
+	logger.Base().Info("WHIP session created",
+		zap.String("connection_id", connectionID),
+		zap.String("tenant_id", tenantID),
+		zap.String("agent_id", agentID))
+
+	w.Header().Set("Location", whipPath+"/"+url.PathEscape(connectionID))
+	w.Header().Set("Content-Type", contentTypeSDP)
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(sdpAnswer))
+}
+
+// HandleTrickle adds ICE candidates the client gathered after its offer. Candidates for
+// a session on another pod are forwarded to it over the task bus.
+// PATCH /whip/{id}
+func (h *WHIPHandler) HandleTrickle(w http.ResponseWriter, r *http.Request) {
+	setWHIPCORSHeaders(w)
+
+	sessionID, connection, ok := h.session(w, r)
+	if !ok {
+		return
+	}
+	if !hasContentType(r, contentTypeSDPFrag) {
+		http.Error(w, "Content-Type must be "+contentTypeSDPFrag, http.StatusUnsupportedMediaType)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSDPSize))
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	if connection == nil {
+		if h.taskBus == nil {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		// The owning pod applies them; failures are only logged there
+		if err := h.taskBus.Publish(r.Context(), task.SessionTask{
+			Type:         task.TaskTypeWHIPTrickle,
+			ConnectionID: sessionID,
+			Payload:      body,
+		}); err != nil {
+			logger.Base().Error("Failed to forward trickled candidates", zap.String("connection_id", sessionID), zap.Error(err))
+			http.Error(w, "Failed to forward candidates", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	webrtcProcessor := h.service.GetWebRTCProcessor()
+	if webrtcProcessor == nil {
+		http.Error(w, "WebRTC processor not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	err = webrtcProcessor.AddICECandidates(connection.ID, string(body))
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, webrtcadapter.ErrICERestartUnsupported):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	case errors.Is(err, webrtcadapter.ErrUnknownPeerConnection):
+		http.Error(w, "Session not found", http.StatusNotFound)
+	default:
+		logger.Base().Warn("Failed to apply trickled candidates", zap.String("connection_id", connection.ID), zap.Error(err))
+		http.Error(w, "Invalid SDP fragment", http.StatusBadRequest)
+	}
+}
+
+// HandleDelete hangs up the session; the cleanup broadcast reaches the pod that holds it
+// DELETE /whip/{id}
+func (h *WHIPHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	setWHIPCORSHeaders(w)
+
+	sessionID, _, ok := h.session(w, r)
+	if !ok {
+		return
+	}
+	logger.Base().Info("WHIP session ended by client", zap.String("connection_id", sessionID))
+	if err := h.service.NotifyCleanup(r.Context(), sessionID); err != nil {
+		logger.Base().Error("Failed to broadcast cleanup", zap.String("connection_id", sessionID), zap.Error(err))
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// StartTaskProcessor subscribes to trickled candidates forwarded by other pods for WHIP
+// sessions on this pod
+func (h *WHIPHandler) StartTaskProcessor(ctx context.Context) error {
+	if h.taskBus == nil {
+		return nil
+	}
+	return h.taskBus.Subscribe(ctx, h.handleForwardedTask)
+}
+
+// handleForwardedTask applies forwarded candidates if the session is on this pod
+func (h *WHIPHandler) handleForwardedTask(t task.SessionTask) {
+	if t.Type != task.TaskTypeWHIPTrickle {
+		return
+	}
+	if h.service.GetConnection(t.ConnectionID) == nil {
+		// Not on this pod
+		return
+	}
+	webrtcProcessor := h.service.GetWebRTCProcessor()
+	if webrtcProcessor == nil {
+		return
+	}
+	if err := webrtcProcessor.AddICECandidates(t.ConnectionID, string(t.Payload)); err != nil {
+		logger.Base().Warn("Failed to apply forwarded trickled candidates", zap.String("connection_id", t.ConnectionID), zap.Error(err))
+	}
+}
+
+// authenticate decodes the bearer agent JWT, writing 401 when it is missing or invalid
+func (h *WHIPHandler) authenticate(w http.ResponseWriter, r *http.Request) (*httpadapter.VoiceAgentMapping, bool) {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || token == "" {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "Bearer token required", http.StatusUnauthorized)
+		return nil, false
+	}
+	mapping, err := httpadapter.DecodeJWTFromAPIKey(token)
+	if err != nil {
+		logger.Base().Warn("Rejecting WHIP request with invalid token", zap.String("remote_addr", r.RemoteAddr), zap.Error(err))
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return nil, false
+	}
+	return mapping, true
+}
+
+// session resolves the WHIP session of the request's resource URL. A session held by
+// another pod is found through the session registry and returned without a connection.
+// The token must be for the agent the session was created with.
+func (h *WHIPHandler) session(w http.ResponseWriter, r *http.Request) (string, *call.WhatsAppCallConnection, bool) {
+	mapping, ok := h.authenticate(w, r)
+	if !ok {
+		return "", nil, false
+	}
+	sessionID := mux.Vars(r)["id"]
+
+	var agentID string
+	var channelType domain.ChannelType
+	connection, _ := h.service.GetConnection(sessionID).(*call.WhatsAppCallConnection)
+	if connection != nil {
+		agentID, channelType = connection.AgentID, connection.ChannelType
+	} else {
+		info, err := h.service.LookupSession(r.Context(), sessionID)
+		if err != nil {
+			logger.Base().Error("Failed to look up WHIP session", zap.String("connection_id", sessionID), zap.Error(err))
+			http.Error(w, "Session lookup failed", http.StatusServiceUnavailable)
+			return "", nil, false
+		}
+		if info != nil {
+			agentID, channelType = info.AgentID, domain.ChannelType(info.ChannelType)
+		}
+	}
+
+	if channelType != domain.ChannelTypeWeb {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return "", nil, false
+	}
+	if agentID != mapping.AgentID {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return "", nil, false
+	}
+	return sessionID, connection, true
+}
+
+// handleCORS answers preflight requests from browser WHIP clients
+func (h *WHIPHandler) handleCORS(w http.ResponseWriter, r *http.Request) {
+	setWHIPCORSHeaders(w)
+	w.Header().Set("Accept-Post", contentTypeSDP)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func setWHIPCORSHeaders(w http.ResponseWriter) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, PATCH, DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match")
+	w.Header().Set("Access-Control-Expose-Headers", "Location")
+}
+
+// hasContentType reports whether the request body has the given media type
+func hasContentType(r *http.Request, mediaType string) bool {
+	contentType, _, _ := strings.Cut(r.Header.Get("Content-Type"), ";")
+	return strings.EqualFold(strings.TrimSpace(contentType), mediaType)
+}
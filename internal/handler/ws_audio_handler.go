@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/ClareAI/astra-voice-service/internal/adapters/wsaudio"
+	"github.com/ClareAI/astra-voice-service/internal/core/task"
+	"github.com/ClareAI/astra-voice-service/internal/repository"
+	"github.com/ClareAI/astra-voice-service/internal/services/call"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// WSAudioHandler serves calls over a plain WebSocket carrying PCM16 or Opus audio
+type WSAudioHandler struct {
+	service     *call.WhatsAppCallService
+	repoManager repository.RepositoryManager
+	taskBus     task.Bus
+	upgrader    websocket.Upgrader
+}
+
+// NewWSAudioHandler creates a new WebSocket audio handler
+func NewWSAudioHandler(service *call.WhatsAppCallService, repoManager repository.RepositoryManager, taskBus task.Bus) *WSAudioHandler {
+	return &WSAudioHandler{
+		service:     service,
+		repoManager: repoManager,
+		taskBus:     taskBus,
+		upgrader: websocket.Upgrader{
+			// Calls are authenticated by the agent JWT in the start message, not by origin
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// SetupWSAudioRoutes registers WebSocket audio routes
+func (h *WSAudioHandler) SetupWSAudioRoutes(router *mux.Router) {
+	// GET /ws/audio - WebSocket audio call
+	router.HandleFunc(wsaudio.Path, h.HandleAudio).Methods("GET")
+}
+
+// HandleAudio runs a call over an audio WebSocket until it ends
+// GET /ws/audio
+func (h *WSAudioHandler) HandleAudio(w http.ResponseWriter, r *http.Request) {
+	ws, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logger.Base().Error("Failed to upgrade audio WebSocket", zap.Error(err))
+		return
+	}
+	wsaudio.NewSession(ws, h.service, h.repoManager, h.taskBus).Run()
+}
@@ -14,7 +14,11 @@ import (
 
 // AgentPromptGenerator generates prompts based on agent configuration
 type AgentPromptGenerator struct {
-	Agent *config.AgentConfig
+	Agent     *config.AgentConfig
+	Variables map[string]string // Per-call variables, rendered as {{.Variables.name}}
+
+	// Summary of an earlier call with the contact when this call is a call back
+	PreviousCallSummary string
 }
 
 // NewAgentPromptGenerator creates a new agent-based prompt generator
@@ -63,6 +67,19 @@ func (g *AgentPromptGenerator) GenerateOutboundGreeting(contactName, contactNumb
 	)
 }
 
+// GenerateGreetingScript renders the greeting template the model is asked to speak
+func (g *AgentPromptGenerator) GenerateGreetingScript(contactName, contactNumber string, isOutbound bool) string {
+	effectiveConfig := g.getEffectivePromptConfig(isOutbound)
+	if effectiveConfig == nil {
+		return ""
+	}
+	name := "greeting"
+	if isOutbound {
+		name = "outbound_greeting"
+	}
+	return g.renderTemplate(name, effectiveConfig.GreetingTemplate, contactName, contactNumber)
+}
+
 // GenerateSessionInstructions generates comprehensive session-level instructions
 func (g *AgentPromptGenerator) GenerateSessionInstructions(contactNumber, language, accent string, isOutbound bool) string {
 	effectiveConfig := g.getEffectivePromptConfig(isOutbound)
@@ -77,6 +94,8 @@ func (g *AgentPromptGenerator) GenerateSessionInstructions(contactNumber, langua
 		PromptPhoneConversationRules,
 		PromptGreetingRepetitionPrevention,
 		g.generateContactInstructions("", contactNumber),
+		g.generateCallContext(effectiveConfig),
+		g.generatePreviousCallContext(),
 	)
 }
 
@@ -91,6 +110,8 @@ func (g *AgentPromptGenerator) GenerateRealtimeInstruction(contactNumber string)
 		g.generateRealtimeTemplate(contactNumber, effectiveConfig),
 		PromptPhoneConversationRules,
 		g.generateContactInstructions("", contactNumber),
+		g.generateCallContext(effectiveConfig),
+		g.generatePreviousCallContext(),
 	)
 }
 
@@ -138,18 +159,20 @@ func (g *AgentPromptGenerator) renderTemplate(name, tmplStr, contactName, contac
 	if tmplStr == "" {
 		return ""
 	}
-	tmpl, err := template.New(name).Parse(tmplStr)
+	// Variables missing from the call render empty rather than as "<no value>"
+	tmpl, err := template.New(name).Option("missingkey=zero").Parse(tmplStr)
 	if err != nil {
 		return g.replaceVariables(tmplStr, contactName, contactNumber)
 	}
 
 	var result strings.Builder
-	data := map[string]string{
+	data := map[string]interface{}{
 		"ContactName":   contactName,
 		"ContactNumber": contactNumber,
 		"AgentName":     g.Agent.Name,
 		"CompanyName":   g.Agent.CompanyName,
 		"Industry":      g.Agent.Industry,
+		"Variables":     g.Variables,
 	}
 
 	if err := tmpl.Execute(&result, data); err != nil {
@@ -214,6 +237,38 @@ func (g *AgentPromptGenerator) generateContactInstructions(contactName, contactN
 	return joinBlocks(blocks...)
 }
 
+// generateCallContext lists the call's variables with their declared descriptions
+func (g *AgentPromptGenerator) generateCallContext(promptConfig *config.PromptConfig) string {
+	if len(g.Variables) == 0 {
+		return ""
+	}
+	descriptions := make(map[string]string)
+	if promptConfig != nil {
+		for _, v := range promptConfig.CallVariables {
+			descriptions[v.Name] = v.Description
+		}
+	}
+
+	lines := make([]string, 0, len(g.Variables))
+	for name, value := range g.Variables {
+		if description := descriptions[name]; description != "" {
+			lines = append(lines, fmt.Sprintf("- %s (%s): %s", name, description, value))
+		} else {
+			lines = append(lines, fmt.Sprintf("- %s: %s", name, value))
+		}
+	}
+	sort.Strings(lines)
+	return fmt.Sprintf(PromptCallContextInstruction, strings.Join(lines, "\n"))
+}
+
+// generatePreviousCallContext adds the summary of the call this call follows up on
+func (g *AgentPromptGenerator) generatePreviousCallContext() string {
+	if g.PreviousCallSummary == "" {
+		return ""
+	}
+	return fmt.Sprintf(PromptPreviousCallSummaryInstruction, g.PreviousCallSummary)
+}
+
 func (g *AgentPromptGenerator) generateLanguageContext(webhookLanguage string, promptConfig *config.PromptConfig) string {
 	if promptConfig == nil {
 		return ""
@@ -352,6 +407,9 @@ func (g *AgentPromptGenerator) replaceVariables(template, contactName, contactNu
 	r = strings.ReplaceAll(r, "{{.AgentName}}", g.Agent.Name)
 	r = strings.ReplaceAll(r, "{{.CompanyName}}", g.Agent.CompanyName)
 	r = strings.ReplaceAll(r, "{{.Industry}}", g.Agent.Industry)
+	for name, value := range g.Variables {
+		r = strings.ReplaceAll(r, "{{.Variables."+name+"}}", value)
+	}
 	return r
 }
 
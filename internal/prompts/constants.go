@@ -262,6 +262,22 @@ Step 5: Then respond using that accent
 - If they ask to be contacted, confirm you'll reach them at this number
 - If conversation requires sharing this number with other systems (via function calls), use this exact number`
 
+	PromptCallContextInstruction = `
+📋 CALL CONTEXT:
+%s
+ℹ️ IMPORTANT NOTES:
+- These details were provided for this call - you already know them
+- NEVER ask the user for these details; confirm them if needed
+- Use these exact values when a function call needs them`
+
+	PromptPreviousCallSummaryInstruction = `
+🔁 CALL BACK:
+You are calling the user back as they asked during an earlier call. Summary of that call:
+%s
+ℹ️ IMPORTANT NOTES:
+- Remind the user briefly that they asked for this call back
+- Continue from where the earlier call left off; do not make the user repeat themselves`
+
 	// Formatting and Hint strings
 	PromptCurrentAccentOverride      = "🎯 CURRENT ACCENT: %s\n(Language: %s)"
 	PromptInitialLanguageHint        = "🎯 INITIAL LANGUAGE: %s (from webhook) - Start with this language for your first greeting ONLY. Afterward, adapt to the user."
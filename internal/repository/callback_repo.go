@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/internal/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CallbackRepository handles database operations for callback jobs
+type CallbackRepository struct {
+	db *gorm.DB
+}
+
+// NewCallbackRepository creates a new callback repository
+func NewCallbackRepository(db *gorm.DB) *CallbackRepository {
+	return &CallbackRepository{db: db}
+}
+
+// Replace creates a scheduled callback job, cancelling any other scheduled callback of the
+// same agent to the same contact, so asking again moves the call back rather than adding one
+func (r *CallbackRepository) Replace(ctx context.Context, job *domain.CallbackJob) error {
+	if job.ID == "" {
+		job.ID = uuid.New().String()
+	}
+	now := time.Now()
+	job.Status = domain.CallbackStatusScheduled
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.CallbackJob{}).
+			Where("agent_id = ? AND waid = ? AND status = ?", job.AgentID, job.WAID, domain.CallbackStatusScheduled).
+			Updates(map[string]interface{}{"status": domain.CallbackStatusCancelled, "last_error": "replaced by a newer request", "updated_at": now}).Error; err != nil {
+			return fmt.Errorf("failed to cancel previous callback jobs: %w", err)
+		}
+		if err := tx.Create(job).Error; err != nil {
+			return fmt.Errorf("failed to create callback job: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetByID retrieves a callback job, returning nil if it does not exist
+func (r *CallbackRepository) GetByID(ctx context.Context, id string) (*domain.CallbackJob, error) {
+	var job domain.CallbackJob
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&job).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get callback job: %w", err)
+	}
+	return &job, nil
+}
+
+// List retrieves a page of callback jobs, soonest first
+func (r *CallbackRepository) List(ctx context.Context, filter domain.CallbackFilter) ([]*domain.CallbackJob, error) {
+	query := r.db.WithContext(ctx).Order("scheduled_at ASC, id ASC")
+	if filter.TenantID != "" {
+		query = query.Where("tenant_id = ?", filter.TenantID)
+	}
+	if filter.AgentID != "" {
+		query = query.Where("agent_id = ?", filter.AgentID)
+	}
+	if filter.WAID != "" {
+		query = query.Where("waid = ?", filter.WAID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit).Offset(filter.Offset)
+	}
+
+	var jobs []*domain.CallbackJob
+	if err := query.Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list callback jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// ClaimDue marks up to limit scheduled jobs that are due as dialing and returns them.
+// Rows are locked while claimed so each job is dialled by one pod.
+func (r *CallbackRepository) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*domain.CallbackJob, error) {
+	var claimed []*domain.CallbackJob
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND scheduled_at <= ?", domain.CallbackStatusScheduled, now).
+			Order("scheduled_at ASC, id ASC").
+			Limit(limit).
+			Find(&claimed).Error; err != nil {
+			return fmt.Errorf("failed to select due callback jobs: %w", err)
+		}
+
+		for _, job := range claimed {
+			job.Status = domain.CallbackStatusDialing
+			job.Attempts++
+			job.LastError = ""
+			job.UpdatedAt = now
+			if err := tx.Save(job).Error; err != nil {
+				return fmt.Errorf("failed to claim callback job: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// Transition moves a job to a status if it is in one of the given statuses, applying the
+// given column updates, and reports whether it did
+func (r *CallbackRepository) Transition(ctx context.Context, id string, from []string, to string, updates map[string]interface{}) (bool, error) {
+	values := map[string]interface{}{"status": to, "updated_at": time.Now()}
+	for column, value := range updates {
+		values[column] = value
+	}
+
+	result := r.db.WithContext(ctx).Model(&domain.CallbackJob{}).
+		Where("id = ? AND status IN ?", id, from).
+		Updates(values)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update callback job: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListStale retrieves jobs that entered a status before a time
+func (r *CallbackRepository) ListStale(ctx context.Context, status string, before time.Time) ([]*domain.CallbackJob, error) {
+	var jobs []*domain.CallbackJob
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND updated_at < ?", status, before).
+		Limit(500).
+		Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list stale callback jobs: %w", err)
+	}
+	return jobs, nil
+}
@@ -0,0 +1,321 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/internal/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// inFlightContactStatuses are the statuses that hold a concurrency slot
+var inFlightContactStatuses = []string{domain.ContactStatusDialing, domain.ContactStatusInCall}
+
+// activeContactStatuses are the statuses of a contact with an attempt in progress
+var activeContactStatuses = []string{
+	domain.ContactStatusAwaitingPermission,
+	domain.ContactStatusDialing,
+	domain.ContactStatusInCall,
+}
+
+// openContactStatuses are the statuses a contact can still leave
+var openContactStatuses = []string{
+	domain.ContactStatusPending,
+	domain.ContactStatusAwaitingPermission,
+	domain.ContactStatusDialing,
+	domain.ContactStatusInCall,
+	domain.ContactStatusRetryScheduled,
+}
+
+// CampaignRepository handles database operations for outbound campaigns and their contacts
+type CampaignRepository struct {
+	db *gorm.DB
+}
+
+// NewCampaignRepository creates a new campaign repository
+func NewCampaignRepository(db *gorm.DB) *CampaignRepository {
+	return &CampaignRepository{db: db}
+}
+
+// Create creates a campaign
+func (r *CampaignRepository) Create(ctx context.Context, campaign *domain.OutboundCampaign) error {
+	if campaign.ID == "" {
+		campaign.ID = uuid.New().String()
+	}
+	now := time.Now()
+	campaign.CreatedAt = now
+	campaign.UpdatedAt = now
+
+	if err := r.db.WithContext(ctx).Create(campaign).Error; err != nil {
+		return fmt.Errorf("failed to create campaign: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a campaign, returning nil if it does not exist
+func (r *CampaignRepository) GetByID(ctx context.Context, id string) (*domain.OutboundCampaign, error) {
+	var campaign domain.OutboundCampaign
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&campaign).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
+	}
+	return &campaign, nil
+}
+
+// List retrieves campaigns, newest first, optionally filtered by tenant and status
+func (r *CampaignRepository) List(ctx context.Context, tenantID, status string) ([]*domain.OutboundCampaign, error) {
+	query := r.db.WithContext(ctx).Order("created_at DESC")
+	if tenantID != "" {
+		query = query.Where("tenant_id = ?", tenantID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var campaigns []*domain.OutboundCampaign
+	if err := query.Find(&campaigns).Error; err != nil {
+		return nil, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+	return campaigns, nil
+}
+
+// TransitionStatus moves a campaign to a status if it is in one of the given statuses,
+// reporting whether it did
+func (r *CampaignRepository) TransitionStatus(ctx context.Context, id string, from []string, to string) (bool, error) {
+	now := time.Now()
+	updates := map[string]interface{}{"status": to, "updated_at": now}
+	if to == domain.CampaignStatusCompleted || to == domain.CampaignStatusCancelled {
+		updates["completed_at"] = now
+	}
+
+	result := r.db.WithContext(ctx).Model(&domain.OutboundCampaign{}).
+		Where("id = ? AND status IN ?", id, from).
+		Updates(updates)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update campaign status: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// AddContacts adds pending contacts to a campaign
+func (r *CampaignRepository) AddContacts(ctx context.Context, campaignID string, contacts []*domain.CampaignContact) error {
+	now := time.Now()
+	for _, contact := range contacts {
+		if contact.ID == "" {
+			contact.ID = uuid.New().String()
+		}
+		contact.CampaignID = campaignID
+		contact.Status = domain.ContactStatusPending
+		contact.CreatedAt = now
+		contact.UpdatedAt = now
+	}
+
+	if err := r.db.WithContext(ctx).CreateInBatches(contacts, 500).Error; err != nil {
+		return fmt.Errorf("failed to add campaign contacts: %w", err)
+	}
+	return nil
+}
+
+// ListContacts retrieves a page of a campaign's contacts, optionally filtered by status
+func (r *CampaignRepository) ListContacts(ctx context.Context, campaignID, status string, limit, offset int) ([]*domain.CampaignContact, error) {
+	query := r.db.WithContext(ctx).Where("campaign_id = ?", campaignID).Order("created_at ASC, id ASC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if limit > 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
+
+	var contacts []*domain.CampaignContact
+	if err := query.Find(&contacts).Error; err != nil {
+		return nil, fmt.Errorf("failed to list campaign contacts: %w", err)
+	}
+	return contacts, nil
+}
+
+// CountContactsByStatus counts a campaign's contacts per status
+func (r *CampaignRepository) CountContactsByStatus(ctx context.Context, campaignID string) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	if err := r.db.WithContext(ctx).Model(&domain.CampaignContact{}).
+		Select("status, COUNT(*) AS count").
+		Where("campaign_id = ?", campaignID).
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count campaign contacts: %w", err)
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// CountOpenContacts counts a campaign's contacts that have not reached a final status
+func (r *CampaignRepository) CountOpenContacts(ctx context.Context, campaignID string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.CampaignContact{}).
+		Where("campaign_id = ? AND status IN ?", campaignID, openContactStatuses).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count open campaign contacts: %w", err)
+	}
+	return count, nil
+}
+
+// CancelUndialledContacts cancels a campaign's pending and retry-scheduled contacts
+func (r *CampaignRepository) CancelUndialledContacts(ctx context.Context, campaignID string) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&domain.CampaignContact{}).
+		Where("campaign_id = ? AND status IN ?", campaignID, []string{domain.ContactStatusPending, domain.ContactStatusRetryScheduled}).
+		Updates(map[string]interface{}{"status": domain.ContactStatusCancelled, "next_attempt_at": nil, "updated_at": time.Now()})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to cancel campaign contacts: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// SetNumberLimit creates or replaces the limit of a tenant's business number
+func (r *CampaignRepository) SetNumberLimit(ctx context.Context, limit *domain.CampaignNumberLimit) error {
+	limit.UpdatedAt = time.Now()
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(limit).Error; err != nil {
+		return fmt.Errorf("failed to set campaign number limit: %w", err)
+	}
+	return nil
+}
+
+// ListNumberLimits retrieves the limits of a tenant's business numbers
+func (r *CampaignRepository) ListNumberLimits(ctx context.Context, tenantID string) ([]*domain.CampaignNumberLimit, error) {
+	var limits []*domain.CampaignNumberLimit
+	if err := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID).Order("channel_phone_number ASC").Find(&limits).Error; err != nil {
+		return nil, fmt.Errorf("failed to list campaign number limits: %w", err)
+	}
+	return limits, nil
+}
+
+// ClaimDueContacts marks due contacts of a running campaign as dialing, up to the free
+// concurrency of its tenant and business number, and returns them. Numbers without a
+// limit set allow defaultLimit calls. Claims for the same tenant and business number are
+// serialized so the limit holds across pods.
+func (r *CampaignRepository) ClaimDueContacts(ctx context.Context, campaign *domain.OutboundCampaign, defaultLimit int, now time.Time) ([]*domain.CampaignContact, error) {
+	var claimed []*domain.CampaignContact
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		lockKey := campaign.TenantID + ":" + campaign.ChannelPhoneNumber
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", lockKey).Error; err != nil {
+			return fmt.Errorf("failed to lock business number: %w", err)
+		}
+
+		maxConcurrent := defaultLimit
+		var limit domain.CampaignNumberLimit
+		err := tx.Where("tenant_id = ? AND channel_phone_number = ?", campaign.TenantID, campaign.ChannelPhoneNumber).First(&limit).Error
+		switch {
+		case err == nil:
+			maxConcurrent = limit.MaxConcurrent
+		case err != gorm.ErrRecordNotFound:
+			return fmt.Errorf("failed to get business number limit: %w", err)
+		}
+
+		var inFlight int64
+		if err := tx.Model(&domain.CampaignContact{}).
+			Joins("JOIN outbound_campaigns ON outbound_campaigns.id = campaign_contacts.campaign_id").
+			Where("outbound_campaigns.tenant_id = ? AND outbound_campaigns.channel_phone_number = ?", campaign.TenantID, campaign.ChannelPhoneNumber).
+			Where("campaign_contacts.status IN ?", inFlightContactStatuses).
+			Count(&inFlight).Error; err != nil {
+			return fmt.Errorf("failed to count calls in progress: %w", err)
+		}
+		free := maxConcurrent - int(inFlight)
+		if free <= 0 {
+			return nil
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("campaign_id = ?", campaign.ID).
+			Where("status = ? OR (status = ? AND next_attempt_at <= ?)", domain.ContactStatusPending, domain.ContactStatusRetryScheduled, now).
+			Order("created_at ASC, id ASC").
+			Limit(free).
+			Find(&claimed).Error; err != nil {
+			return fmt.Errorf("failed to select due contacts: %w", err)
+		}
+
+		for _, contact := range claimed {
+			contact.Status = domain.ContactStatusDialing
+			contact.Attempts++
+			contact.LastAttemptAt = &now
+			contact.NextAttemptAt = nil
+			contact.ConnectionID = ""
+			contact.CallID = ""
+			contact.LastError = ""
+			contact.UpdatedAt = now
+			if err := tx.Save(contact).Error; err != nil {
+				return fmt.Errorf("failed to claim contact: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// TransitionContact saves a contact if it is still in one of the given statuses,
+// reporting whether it did
+func (r *CampaignRepository) TransitionContact(ctx context.Context, contact *domain.CampaignContact, from []string) (bool, error) {
+	contact.UpdatedAt = time.Now()
+	result := r.db.WithContext(ctx).Model(&domain.CampaignContact{}).
+		Where("id = ? AND status IN ?", contact.ID, from).
+		Select("*").
+		Updates(contact)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update campaign contact: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetActiveContactByConnectionID retrieves the contact whose attempt in progress uses a
+// connection, returning nil if there is none
+func (r *CampaignRepository) GetActiveContactByConnectionID(ctx context.Context, connectionID string) (*domain.CampaignContact, error) {
+	return r.getActiveContact(ctx, "connection_id = ?", connectionID)
+}
+
+// GetActiveContactByCallID retrieves the contact whose attempt in progress is a WhatsApp
+// call, returning nil if there is none
+func (r *CampaignRepository) GetActiveContactByCallID(ctx context.Context, callID string) (*domain.CampaignContact, error) {
+	return r.getActiveContact(ctx, "call_id = ?", callID)
+}
+
+func (r *CampaignRepository) getActiveContact(ctx context.Context, condition, value string) (*domain.CampaignContact, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var contact domain.CampaignContact
+	if err := r.db.WithContext(ctx).
+		Where(condition, value).
+		Where("status IN ?", activeContactStatuses).
+		Order("updated_at DESC").
+		First(&contact).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get campaign contact: %w", err)
+	}
+	return &contact, nil
+}
+
+// ListStaleContacts retrieves contacts that entered a status before a time
+func (r *CampaignRepository) ListStaleContacts(ctx context.Context, status string, before time.Time) ([]*domain.CampaignContact, error) {
+	var contacts []*domain.CampaignContact
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND updated_at < ?", status, before).
+		Limit(500).
+		Find(&contacts).Error; err != nil {
+		return nil, fmt.Errorf("failed to list stale campaign contacts: %w", err)
+	}
+	return contacts, nil
+}
@@ -75,15 +75,22 @@ func AutoMigrate(db *gorm.DB) error {
 		&domain.VoiceAgent{},
 		&domain.VoiceConversation{},
 		&domain.VoiceMessage{},
+		&domain.VoiceRecording{},
+		&domain.OutboundCampaign{},
+		&domain.CampaignContact{},
+		&domain.CampaignNumberLimit{},
+		&domain.OutboundCallAttempt{},
+		&domain.CallbackJob{},
 	)
 }
 
 // AutoMigrateAPIDB runs database migrations for API database models only
-// This is used when a separate API database is configured for voice_conversations and voice_messages
+// This is used when a separate API database is configured for voice_conversations, voice_messages and voice_recordings
 func AutoMigrateAPIDB(db *gorm.DB) error {
 	return db.AutoMigrate(
 		&domain.VoiceConversation{},
 		&domain.VoiceMessage{},
+		&domain.VoiceRecording{},
 	)
 }
 
@@ -131,7 +138,7 @@ func NewRepositoryManager() (RepositoryManager, error) {
 			return nil, fmt.Errorf("failed to ping API database: %w", err)
 		}
 
-		// Run migrations for API database (VoiceConversation and VoiceMessage tables)
+		// Run migrations for API database (VoiceConversation, VoiceMessage and VoiceRecording tables)
 		if err := AutoMigrateAPIDB(apiDB); err != nil {
 			return nil, fmt.Errorf("failed to run API database auto migration: %w", err)
 		}
@@ -64,6 +64,10 @@ type RepositoryManager interface {
 	VoiceAgent() VoiceAgentRepository
 	VoiceConversation() *VoiceConversationRepository
 	VoiceMessage() *VoiceMessageRepository
+	VoiceRecording() *VoiceRecordingRepository
+	Campaign() *CampaignRepository
+	OutboundCall() *OutboundCallRepository
+	Callback() *CallbackRepository
 
 	// Transaction support
 	WithTx(ctx context.Context, fn func(ctx context.Context, repos RepositoryManager) error) error
@@ -83,11 +87,15 @@ type GormRepositoryManager struct {
 	voiceAgentRepo        *GormVoiceAgentRepository
 	voiceConversationRepo *VoiceConversationRepository
 	voiceMessageRepo      *VoiceMessageRepository
+	voiceRecordingRepo    *VoiceRecordingRepository
+	campaignRepo          *CampaignRepository
+	outboundCallRepo      *OutboundCallRepository
+	callbackRepo          *CallbackRepository
 }
 
 // NewGormRepositoryManager creates a new GORM repository manager
 // db: main database connection for voice_tenant and voice_agent
-// apiDB: API database connection for voice_conversation and voice_message (can be nil, will fallback to main db)
+// apiDB: API database connection for voice_conversation, voice_message and voice_recording (can be nil, will fallback to main db)
 func NewGormRepositoryManager(db *gorm.DB, apiDB *gorm.DB) *GormRepositoryManager {
 	// Use apiDB for voice conversations and messages if provided, otherwise fallback to main db
 	conversationDB := apiDB
@@ -102,6 +110,10 @@ func NewGormRepositoryManager(db *gorm.DB, apiDB *gorm.DB) *GormRepositoryManage
 		voiceAgentRepo:        NewGormVoiceAgentRepository(db),
 		voiceConversationRepo: NewVoiceConversationRepository(conversationDB),
 		voiceMessageRepo:      NewVoiceMessageRepository(conversationDB),
+		voiceRecordingRepo:    NewVoiceRecordingRepository(conversationDB),
+		campaignRepo:          NewCampaignRepository(db),
+		outboundCallRepo:      NewOutboundCallRepository(db),
+		callbackRepo:          NewCallbackRepository(db),
 	}
 }
 
@@ -125,6 +137,26 @@ func (m *GormRepositoryManager) VoiceMessage() *VoiceMessageRepository {
 	return m.voiceMessageRepo
 }
 
+// VoiceRecording returns the voice recording repository
+func (m *GormRepositoryManager) VoiceRecording() *VoiceRecordingRepository {
+	return m.voiceRecordingRepo
+}
+
+// Campaign returns the outbound campaign repository
+func (m *GormRepositoryManager) Campaign() *CampaignRepository {
+	return m.campaignRepo
+}
+
+// OutboundCall returns the outbound call attempt repository
+func (m *GormRepositoryManager) OutboundCall() *OutboundCallRepository {
+	return m.outboundCallRepo
+}
+
+// Callback returns the callback job repository
+func (m *GormRepositoryManager) Callback() *CallbackRepository {
+	return m.callbackRepo
+}
+
 // WithTx executes a function within a database transaction
 // Note: This only creates a transaction for the main database.
 // API database operations will not be part of this transaction.
@@ -143,6 +175,10 @@ func (m *GormRepositoryManager) WithTx(ctx context.Context, fn func(ctx context.
 			voiceAgentRepo:        NewGormVoiceAgentRepository(tx),
 			voiceConversationRepo: NewVoiceConversationRepository(conversationDB),
 			voiceMessageRepo:      NewVoiceMessageRepository(conversationDB),
+			voiceRecordingRepo:    NewVoiceRecordingRepository(conversationDB),
+			campaignRepo:          NewCampaignRepository(tx),
+			outboundCallRepo:      NewOutboundCallRepository(tx),
+			callbackRepo:          NewCallbackRepository(tx),
 		}
 		return fn(ctx, txManager)
 	})
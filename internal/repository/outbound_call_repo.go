@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/internal/domain"
+	"gorm.io/gorm"
+)
+
+// OutboundCallRepository handles database operations for outbound call attempts
+type OutboundCallRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboundCallRepository creates a new outbound call repository
+func NewOutboundCallRepository(db *gorm.DB) *OutboundCallRepository {
+	return &OutboundCallRepository{db: db}
+}
+
+// Create creates an outbound call attempt
+func (r *OutboundCallRepository) Create(ctx context.Context, attempt *domain.OutboundCallAttempt) error {
+	now := time.Now()
+	attempt.StateChangedAt = now
+	attempt.CreatedAt = now
+	attempt.UpdatedAt = now
+
+	if err := r.db.WithContext(ctx).Create(attempt).Error; err != nil {
+		return fmt.Errorf("failed to create outbound call attempt: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves an outbound call attempt, returning nil if it does not exist
+func (r *OutboundCallRepository) GetByID(ctx context.Context, id string) (*domain.OutboundCallAttempt, error) {
+	return r.first(r.db.WithContext(ctx).Where("id = ?", id))
+}
+
+// GetByCallID retrieves the latest outbound call attempt for a WhatsApp call, returning nil
+// if there is none
+func (r *OutboundCallRepository) GetByCallID(ctx context.Context, callID string) (*domain.OutboundCallAttempt, error) {
+	return r.first(r.db.WithContext(ctx).Where("call_id = ?", callID).Order("created_at DESC"))
+}
+
+// GetAwaitingPermission retrieves the latest attempt waiting for a user's call permission,
+// optionally for one business number, returning nil if there is none
+func (r *OutboundCallRepository) GetAwaitingPermission(ctx context.Context, waid, channelPhoneNumber string) (*domain.OutboundCallAttempt, error) {
+	query := r.db.WithContext(ctx).
+		Where("waid = ? AND state = ?", waid, domain.OutboundCallStatePermissionRequested).
+		Order("created_at DESC")
+	if channelPhoneNumber != "" {
+		query = query.Where("channel_phone_number = ?", channelPhoneNumber)
+	}
+	return r.first(query)
+}
+
+func (r *OutboundCallRepository) first(query *gorm.DB) (*domain.OutboundCallAttempt, error) {
+	var attempt domain.OutboundCallAttempt
+	if err := query.First(&attempt).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get outbound call attempt: %w", err)
+	}
+	return &attempt, nil
+}
+
+// Transition moves an attempt to a state if it is in one of the given states, applying
+// the given column updates, and reports whether it did
+func (r *OutboundCallRepository) Transition(ctx context.Context, id string, from []string, to string, updates map[string]interface{}) (bool, error) {
+	now := time.Now()
+	values := map[string]interface{}{"state": to, "state_changed_at": now, "updated_at": now}
+	for column, value := range updates {
+		values[column] = value
+	}
+	if to == domain.OutboundCallStateAnswered {
+		values["answered_at"] = now
+	}
+	if domain.IsOutboundCallFinal(to) {
+		values["ended_at"] = now
+	}
+
+	result := r.db.WithContext(ctx).Model(&domain.OutboundCallAttempt{}).
+		Where("id = ? AND state IN ?", id, from).
+		Updates(values)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update outbound call attempt: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListStale retrieves attempts that entered a state before a time
+func (r *OutboundCallRepository) ListStale(ctx context.Context, state string, before time.Time) ([]*domain.OutboundCallAttempt, error) {
+	var attempts []*domain.OutboundCallAttempt
+	if err := r.db.WithContext(ctx).
+		Where("state = ? AND state_changed_at < ?", state, before).
+		Limit(500).
+		Find(&attempts).Error; err != nil {
+		return nil, fmt.Errorf("failed to list stale outbound call attempts: %w", err)
+	}
+	return attempts, nil
+}
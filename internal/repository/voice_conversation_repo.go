@@ -210,6 +210,37 @@ func (r *VoiceMessageRepository) GetByConversationID(ctx context.Context, conver
 	return messages, nil
 }
 
+// GetLatencyStats aggregates p50/p95 turn latencies per model provider for a voice agent
+func (r *VoiceMessageRepository) GetLatencyStats(ctx context.Context, voiceAgentID string, startTime, endTime time.Time) ([]*domain.VoiceLatencyStats, error) {
+	if voiceAgentID == "" {
+		return nil, fmt.Errorf("voice agent ID cannot be empty")
+	}
+
+	var stats []*domain.VoiceLatencyStats
+	if err := r.db.WithContext(ctx).
+		Table("voice_messages AS m").
+		Select(`c.voice_agent_id AS voice_agent_id,
+			m.model_provider AS model_provider,
+			COUNT(*) AS turn_count,
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY m.voice_to_voice_ms), 0) AS voice_to_voice_p50_ms,
+			COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY m.voice_to_voice_ms), 0) AS voice_to_voice_p95_ms,
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY m.first_audio_latency_ms), 0) AS first_audio_p50_ms,
+			COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY m.first_audio_latency_ms), 0) AS first_audio_p95_ms,
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY m.response_latency_ms), 0) AS response_latency_p50_ms,
+			COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY m.response_latency_ms), 0) AS response_latency_p95_ms,
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY m.tool_duration_ms), 0) AS tool_duration_p50_ms,
+			COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY m.tool_duration_ms), 0) AS tool_duration_p95_ms,
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY m.rag_duration_ms), 0) AS rag_duration_p50_ms,
+			COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY m.rag_duration_ms), 0) AS rag_duration_p95_ms`).
+		Joins("JOIN voice_conversations AS c ON c.id = m.conversation_id").
+		Where("c.voice_agent_id = ? AND m.created_at BETWEEN ? AND ? AND m.voice_to_voice_ms IS NOT NULL", voiceAgentID, startTime, endTime).
+		Group("c.voice_agent_id, m.model_provider").
+		Scan(&stats).Error; err != nil {
+		return nil, fmt.Errorf("failed to aggregate voice message latency: %w", err)
+	}
+	return stats, nil
+}
+
 // DeleteByConversationID deletes all voice messages for a conversation
 func (r *VoiceMessageRepository) DeleteByConversationID(ctx context.Context, conversationID string) error {
 	if err := r.db.WithContext(ctx).
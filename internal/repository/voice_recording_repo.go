@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/internal/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// VoiceRecordingRepository handles database operations for voice conversation recordings
+type VoiceRecordingRepository struct {
+	db *gorm.DB
+}
+
+// NewVoiceRecordingRepository creates a new voice recording repository
+func NewVoiceRecordingRepository(db *gorm.DB) *VoiceRecordingRepository {
+	return &VoiceRecordingRepository{db: db}
+}
+
+// Create registers a recording artifact for a conversation
+func (r *VoiceRecordingRepository) Create(ctx context.Context, recording *domain.VoiceRecording) error {
+	if recording.ConversationID == "" {
+		return fmt.Errorf("conversation ID cannot be empty")
+	}
+	if recording.ID == "" {
+		recording.ID = uuid.New().String()
+	}
+	if recording.CreatedAt.IsZero() {
+		recording.CreatedAt = time.Now()
+	}
+
+	if err := r.db.WithContext(ctx).Create(recording).Error; err != nil {
+		return fmt.Errorf("failed to create voice recording: %w", err)
+	}
+	return nil
+}
+
+// GetByConversationID retrieves all recordings for a conversation
+func (r *VoiceRecordingRepository) GetByConversationID(ctx context.Context, conversationID string) ([]*domain.VoiceRecording, error) {
+	var recordings []*domain.VoiceRecording
+	if err := r.db.WithContext(ctx).
+		Where("conversation_id = ?", conversationID).
+		Order("created_at ASC").
+		Find(&recordings).Error; err != nil {
+		return nil, fmt.Errorf("failed to get voice recordings: %w", err)
+	}
+	return recordings, nil
+}
+
+// DeleteByConversationID deletes all recording records for a conversation
+func (r *VoiceRecordingRepository) DeleteByConversationID(ctx context.Context, conversationID string) error {
+	if err := r.db.WithContext(ctx).
+		Where("conversation_id = ?", conversationID).
+		Delete(&domain.VoiceRecording{}).Error; err != nil {
+		return fmt.Errorf("failed to delete voice recordings: %w", err)
+	}
+	return nil
+}
@@ -514,6 +514,74 @@ func (s *AgentService) convertToDraftAgentConfigWithTenant(voiceAgent *domain.Vo
 	return agentConfig
 }
 
+// DSPConfigFromData converts stored DSP settings to config.DSPConfig with defaults applied
+func DSPConfigFromData(data *domain.DSPConfigData) *config.DSPConfig {
+	if data == nil {
+		return nil
+	}
+	dspConfig := &config.DSPConfig{
+		Enabled:          data.Enabled,
+		HighPassHz:       data.HighPassHz,
+		NoiseSuppression: data.NoiseSuppression,
+		SuppressionDb:    data.SuppressionDb,
+		AGC:              data.AGC,
+		TargetLevelDb:    data.TargetLevelDb,
+		MaxGainDb:        data.MaxGainDb,
+		Limiter:          data.Limiter,
+	}
+	dspConfig.SetDefaults()
+	return dspConfig
+}
+
+// callVariablesFromData converts the declared per-call variables
+func callVariablesFromData(data []domain.CallVariableData) []config.CallVariable {
+	if len(data) == 0 {
+		return nil
+	}
+	variables := make([]config.CallVariable, 0, len(data))
+	for _, v := range data {
+		variables = append(variables, config.CallVariable{
+			Name:        strings.TrimSpace(v.Name),
+			Type:        strings.ToLower(strings.TrimSpace(v.Type)),
+			Required:    v.Required,
+			Description: v.Description,
+			Enum:        v.Enum,
+		})
+	}
+	return variables
+}
+
+// audioCueConfigFromData converts stored cue settings, recording the agent that owns the uploads
+func audioCueConfigFromData(data *domain.AudioCueData, voiceAgent *domain.VoiceAgent) *config.AudioCueConfig {
+	cueConfig := &config.AudioCueConfig{
+		HoldMusic:          data.HoldMusic,
+		SilenceThresholdMs: data.SilenceThresholdMs,
+		DefaultMode:        data.DefaultMode,
+		Mix:                data.Mix,
+		Ambience:           data.Ambience,
+		AmbienceGainDb:     data.AmbienceGainDb,
+		HoldMusicGainDb:    data.HoldMusicGainDb,
+		DuckingDb:          data.DuckingDb,
+		TenantID:           voiceAgent.VoiceTenantID,
+		AgentID:            voiceAgent.ID,
+	}
+	if len(data.Fillers) > 0 {
+		// Language codes are matched case-insensitively
+		cueConfig.Fillers = make(map[string]string, len(data.Fillers))
+		for language, cue := range data.Fillers {
+			cueConfig.Fillers[strings.ToLower(language)] = cue
+		}
+	}
+	if len(data.Tools) > 0 {
+		cueConfig.Tools = make(map[string]config.ToolCueConfig, len(data.Tools))
+		for toolName, tool := range data.Tools {
+			cueConfig.Tools[toolName] = config.ToolCueConfig{Mode: tool.Mode, Cue: tool.Cue}
+		}
+	}
+	cueConfig.SetDefaults()
+	return cueConfig
+}
+
 // DraftConfigSuffix is the suffix used for draft agent configuration keys in cache
 const DraftConfigSuffix = ":" + config.AgentConfigModeDraft
 
@@ -592,6 +660,29 @@ func (s *AgentService) convertConfigDataToConfig(voiceAgent *domain.VoiceAgent,
 			agentConfig.SilenceConfig.SetDefaults()
 		}
 
+		// Convert VAD config (local voice activity detection stays off unless configured)
+		if configData.VADConfig != nil {
+			agentConfig.VADConfig = &config.VADConfig{
+				Enabled:             configData.VADConfig.Enabled,
+				EnergyThresholdDb:   configData.VADConfig.EnergyThresholdDb,
+				NoiseMarginDb:       configData.VADConfig.NoiseMarginDb,
+				MaxSpectralFlatness: configData.VADConfig.MaxSpectralFlatness,
+				MaxZeroCrossingRate: configData.VADConfig.MaxZeroCrossingRate,
+				OnsetMs:             configData.VADConfig.OnsetMs,
+				HangoverMs:          configData.VADConfig.HangoverMs,
+				PreRollMs:           configData.VADConfig.PreRollMs,
+			}
+			agentConfig.VADConfig.SetDefaults()
+		}
+
+		// Convert DSP config (nil falls back to the tenant setting when the call starts)
+		agentConfig.DSPConfig = DSPConfigFromData(configData.DSPConfig)
+
+		// Convert audio cue config (nil keeps the bundled hold music for every tool)
+		if configData.AudioCues != nil {
+			agentConfig.AudioCues = audioCueConfigFromData(configData.AudioCues, voiceAgent)
+		}
+
 		// Convert prompt config
 		if configData.PromptConfig != nil {
 			agentConfig.PromptConfig = &config.PromptConfig{
@@ -602,8 +693,10 @@ func (s *AgentService) convertConfigDataToConfig(voiceAgent *domain.VoiceAgent,
 				ExampleDialogues:      configData.PromptConfig.ExampleDialogues,
 				LanguageInstructions:  configData.PromptConfig.LanguageInstructions,
 				CustomVariables:       configData.PromptConfig.CustomVariables,
+				CallVariables:         callVariablesFromData(configData.PromptConfig.CallVariables),
 				AutoLanguageSwitching: configData.PromptConfig.AutoLanguageSwitching,
 				AutoAccentAdaptation:  configData.PromptConfig.AutoAccentAdaptation,
+				CacheGreeting:         configData.PromptConfig.CacheGreeting,
 			}
 		}
 
@@ -689,8 +782,10 @@ func (s *AgentService) convertConfigDataToConfig(voiceAgent *domain.VoiceAgent,
 				ExampleDialogues:      configData.OutboundPromptConfig.ExampleDialogues,
 				LanguageInstructions:  configData.OutboundPromptConfig.LanguageInstructions,
 				CustomVariables:       configData.OutboundPromptConfig.CustomVariables,
+				CallVariables:         callVariablesFromData(configData.OutboundPromptConfig.CallVariables),
 				AutoLanguageSwitching: configData.OutboundPromptConfig.AutoLanguageSwitching,
 				AutoAccentAdaptation:  configData.OutboundPromptConfig.AutoAccentAdaptation,
+				CacheGreeting:         configData.OutboundPromptConfig.CacheGreeting,
 			}
 		}
 
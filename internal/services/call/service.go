@@ -23,9 +23,12 @@ import (
 	"github.com/ClareAI/astra-voice-service/internal/services/agent"
 	"github.com/ClareAI/astra-voice-service/pkg/data/api"
 	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/metrics"
 	"github.com/ClareAI/astra-voice-service/pkg/pubsub"
+	"github.com/ClareAI/astra-voice-service/pkg/tracing"
 	"github.com/ClareAI/astra-voice-service/pkg/twilio"
 	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
@@ -54,12 +57,20 @@ type WhatsAppCallService struct {
 	sessionManager *session.Manager
 	taskBus        task.Bus
 	watiClient     *httpadapter.WatiClient
+
+	// Called after a connection is cleaned up, with whether the call was connected
+	connectionEndedHooks []func(connectionID string, connected bool)
+
+	// Channel-specific teardown run when the agent hangs up, e.g. closing a LiveKit room
+	hangUpHooks map[domain.ChannelType]func(connectionID string)
 }
 
 // NewWhatsAppCallService creates a new WhatsApp Call service
 func NewWhatsAppCallService(config *whatsappconfig.WhatsAppCallConfig, defaultHandler provider.ModelHandler, factory provider.ProviderFactory, sessionManager *session.Manager, taskBus task.Bus, watiClient *httpadapter.WatiClient) *WhatsAppCallService {
 	// Create minimal event bus
 	eventBus := event.NewEventBus()
+	eventBus.Use(event.MetricsMiddleware)
+	eventBus.Use(event.TracingMiddleware)
 
 	service := &WhatsAppCallService{
 		config:         config,
@@ -70,6 +81,7 @@ func NewWhatsAppCallService(config *whatsappconfig.WhatsAppCallConfig, defaultHa
 		sessionManager: sessionManager,
 		taskBus:        taskBus,
 		watiClient:     watiClient,
+		hangUpHooks:    make(map[domain.ChannelType]func(connectionID string)),
 	}
 
 	// Initialize session broadcast subscriber if manager is available
@@ -94,6 +106,7 @@ func NewWhatsAppCallService(config *whatsappconfig.WhatsAppCallConfig, defaultHa
 			logger.Base().Info("Default model logic triggered connection close", zap.String("connection_id", connID))
 			service.CleanupConnection(connID)
 		})
+		defaultHandler.SetOnHangUp(service.hangUpFromModel)
 	}
 
 	// Initialize PubSub service for usage event publishing
@@ -147,6 +160,9 @@ func NewWhatsAppCallService(config *whatsappconfig.WhatsAppCallConfig, defaultHa
 	// Initialize WebRTC processor
 	service.webrtcProcessor = webrtcadapter.NewProcessor(service)
 
+	// Report active connections to Prometheus at scrape time
+	metrics.SetConnectionSource(service.activeConnectionCounts)
+
 	return service
 }
 
@@ -173,9 +189,11 @@ func (s *WhatsAppCallService) initializeAIConnectionWithSignalControl(connection
 		providerType = provider.ProviderTypeGemini
 	}
 
+	channel := string(connection.ChannelType)
 	modelHandler, err := s.GetModelHandler(providerType)
 	if err != nil {
 		logger.Base().Error("Failed to get model handler", zap.Error(err))
+		metrics.ObserveCallSetup(channel, string(providerType), "model_handler", err)
 		s.cleanupConnection(connection.ID)
 		return
 	}
@@ -186,9 +204,15 @@ func (s *WhatsAppCallService) initializeAIConnectionWithSignalControl(connection
 	}
 
 	// Initialize model connection
+	_, span := tracing.Start(tracing.CallContext(connection.ID), "model.connect",
+		attribute.String("model.provider", string(providerType)),
+		attribute.String("voice.language", connection.VoiceLanguage),
+	)
 	modelConn, err := modelHandler.InitializeConnectionWithLanguage(connection.ID, connection.VoiceLanguage, connection.Accent)
+	tracing.End(span, err)
 	if err != nil {
 		logger.Base().Error("Failed to establish model connection", zap.String("connection_id", connection.ID), zap.Error(err))
+		metrics.ObserveCallSetup(channel, string(providerType), "model_connection", err)
 		s.cleanupConnection(connection.ID)
 		return
 	}
@@ -209,6 +233,7 @@ func (s *WhatsAppCallService) initializeAIConnectionWithSignalControl(connection
 	connection.LastActivity = time.Now()
 
 	logger.Base().Info("Model WebRTC connection established", zap.String("connection_id", connection.ID), zap.String("provider", string(providerType)))
+	metrics.ObserveCallSetup(channel, string(providerType), "model_connection", nil)
 	s.eventBus.Publish(event.AIConnectionInit, &event.AIEventData{
 		ConnectionID: connection.ID,
 	})
@@ -242,6 +267,15 @@ func (s *WhatsAppCallService) AddConnection(connection *WhatsAppCallConnection)
 	s.connections[connection.ID] = connection
 	s.mutex.Unlock()
 
+	// Open the call-level trace; all spans for this connection share its trace ID
+	tracing.StartCall(context.Background(), connection.ID,
+		attribute.String("call.channel", string(connection.ChannelType)),
+		attribute.String("call.id", connection.CallID),
+		attribute.String("agent.id", connection.AgentID),
+		attribute.String("tenant.id", connection.TenantID),
+		attribute.Bool("call.outbound", connection.IsOutboundCall),
+	)
+
 	// Register session for monitoring if manager is available
 	if s.sessionManager != nil {
 		go func() {
@@ -379,6 +413,9 @@ func (s *WhatsAppCallService) cleanupConnection(connectionID string) {
 
 	// Mark conversation as ended in database
 	s.endConversationInDB(connection)
+	s.notifyConnectionEnded(connectionID, wasConnected)
+	callCtx := tracing.CallContext(connectionID)
+	defer tracing.EndCall(connectionID, attribute.Int("call.duration_seconds", int(durationSeconds)))
 
 	// Close model connection
 	logger.Base().Debug("Checking model connection", zap.Bool("has_webrtc_client", connection.AIWebRTC != nil), zap.Bool("is_model_ready", connection.IsAIReady))
@@ -545,7 +582,7 @@ func (s *WhatsAppCallService) cleanupConnection(connectionID string) {
 
 			// Call asynchronously to avoid blocking cleanup
 			go func() {
-				resp, err := s.leadsService.CreateContact(req)
+				resp, err := s.leadsService.CreateContact(callCtx, req)
 				if err != nil {
 					logger.Base().Error("Failed to create contact lead for conversation", zap.String("conversation_id", conversationID), zap.Error(err))
 				} else {
@@ -556,7 +593,7 @@ func (s *WhatsAppCallService) cleanupConnection(connectionID string) {
 						ConversationID: conversationID,
 						ContactID:      resp.ContactID,
 					}
-					if _, err := s.leadsService.SyncVoiceChat(syncReq); err != nil {
+					if _, err := s.leadsService.SyncVoiceChat(callCtx, syncReq); err != nil {
 						logger.Base().Error("Failed to sync voice chat for conversation", zap.String("conversation_id", conversationID), zap.Error(err))
 					} else {
 						logger.Base().Info("Voice chat synced for conversation", zap.String("conversation_id", conversationID))
@@ -584,6 +621,9 @@ func (s *WhatsAppCallService) endConversationInDB(connection *WhatsAppCallConnec
 			repo := connection.RepoManager.VoiceConversation()
 			conv, err := repo.GetByID(ctx, convID)
 			if err == nil && conv != nil {
+				if reason := connection.GetEndReason(); reason != "" {
+					conv.EndReason = reason
+				}
 				if err := repo.EndConversation(ctx, conv); err != nil {
 					logger.Base().Error("Failed to end voice conversation in DB", zap.String("conversation_id", convID), zap.Error(err))
 				} else {
@@ -658,6 +698,7 @@ func (s *WhatsAppCallService) GetModelHandler(providerType provider.ProviderType
 			handler.SetOnConnectionClose(func(connID string) {
 				s.CleanupConnection(connID)
 			})
+			handler.SetOnHangUp(s.hangUpFromModel)
 			return handler, nil
 		}
 	}
@@ -804,6 +845,7 @@ func (s *WhatsAppCallService) CleanupConnectionByCallID(callID string) {
 
 		// Mark conversation as ended in database
 		s.endConversationInDB(foundConnection)
+		s.notifyConnectionEnded(connectionID, foundConnection.WasConnected())
 
 		// Close model connection
 		if foundConnection.ModelConnection != nil {
@@ -821,6 +863,87 @@ func (s *WhatsAppCallService) CleanupConnectionByCallID(callID string) {
 	}
 }
 
+// OnConnectionEnded registers a hook called after a connection is cleaned up; hooks
+// must be registered before calls are handled
+func (s *WhatsAppCallService) OnConnectionEnded(hook func(connectionID string, connected bool)) {
+	s.connectionEndedHooks = append(s.connectionEndedHooks, hook)
+}
+
+// notifyConnectionEnded runs the connection ended hooks without blocking cleanup
+func (s *WhatsAppCallService) notifyConnectionEnded(connectionID string, connected bool) {
+	for _, hook := range s.connectionEndedHooks {
+		go hook(connectionID, connected)
+	}
+}
+
+// OnHangUp registers the teardown for a channel when the agent ends a call; Wati calls
+// are terminated by the service itself. Hooks must be registered before calls are handled.
+func (s *WhatsAppCallService) OnHangUp(channelType domain.ChannelType, hook func(connectionID string)) {
+	s.hangUpHooks[channelType] = hook
+}
+
+// HangUp ends a call from the agent's side on its channel and records the reason on the
+// conversation. SIP, Twilio and web calls end with the connection cleanup.
+func (s *WhatsAppCallService) HangUp(ctx context.Context, connectionID, reason string) error {
+	s.mutex.RLock()
+	connection := s.connections[connectionID]
+	s.mutex.RUnlock()
+	if connection == nil {
+		return fmt.Errorf("connection not found: %s", connectionID)
+	}
+	connection.SetEndReason(reason)
+
+	logger.Base().Info("📴 Agent is ending the call",
+		zap.String("connection_id", connectionID),
+		zap.String("channel_type", string(connection.ChannelType)),
+		zap.String("reason", reason))
+
+	if s.isWatiCall(connection) {
+		if s.watiClient == nil {
+			logger.Base().Warn("Wati client not configured, cannot terminate call", zap.String("connection_id", connectionID))
+		} else if err := s.watiClient.TerminateCallWithTenant(ctx, connection.GetTenantID(), connection.CallID); err != nil {
+			logger.Base().Error("Failed to terminate Wati call", zap.String("connection_id", connectionID), zap.String("call_id", connection.CallID), zap.Error(err))
+		}
+	} else if hook, ok := s.hangUpHooks[connection.ChannelType]; ok {
+		hook(connectionID)
+	}
+
+	return s.NotifyCleanup(ctx, connectionID)
+}
+
+// hangUpFromModel ends the call when the model calls end_call
+func (s *WhatsAppCallService) hangUpFromModel(connectionID, reason string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.HangUp(ctx, connectionID, reason); err != nil {
+		logger.Base().Warn("Failed to hang up call", zap.String("connection_id", connectionID), zap.Error(err))
+	}
+}
+
+// isWatiCall reports whether the call runs through Wati: WhatsApp calls and outbound
+// test calls, which are placed as WhatsApp calls
+func (s *WhatsAppCallService) isWatiCall(connection *WhatsAppCallConnection) bool {
+	if connection.CallID == "" {
+		return false
+	}
+	switch connection.ChannelType {
+	case domain.ChannelTypeWhatsApp:
+		return true
+	case domain.ChannelTypeTest:
+		return connection.IsOutboundCall
+	}
+	return false
+}
+
+// LookupSession returns the registered info of a session held by any pod, or nil when
+// it is unknown or sessions are not shared between pods
+func (s *WhatsAppCallService) LookupSession(ctx context.Context, sessionID string) (*session.SessionInfo, error) {
+	if s.sessionManager == nil {
+		return nil, nil
+	}
+	return s.sessionManager.Lookup(ctx, sessionID)
+}
+
 // NotifyCleanup broadcasts a cleanup request to all pods via session manager
 func (s *WhatsAppCallService) NotifyCleanup(ctx context.Context, sessionID string) error {
 	// Always cleanup locally first for immediate effect on the current pod
@@ -880,6 +1003,29 @@ func (s *WhatsAppCallService) GetTURNCredentials() []webrtcadapter.TURNCredentia
 	return webrtcCreds
 }
 
+// activeConnectionCounts groups active connections by channel and provider for metrics
+func (s *WhatsAppCallService) activeConnectionCounts() map[metrics.ConnectionLabels]int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	counts := make(map[metrics.ConnectionLabels]int)
+	for _, connection := range s.connections {
+		counts[metrics.ConnectionLabels{
+			Channel:  string(connection.ChannelType),
+			Provider: connectionProviderLabel(connection),
+		}]++
+	}
+	return counts
+}
+
+// connectionProviderLabel returns the model provider of a connection, defaulting to OpenAI
+func connectionProviderLabel(connection *WhatsAppCallConnection) string {
+	if connection.ModelProvider == "" {
+		return string(provider.ProviderTypeOpenAI)
+	}
+	return string(connection.ModelProvider)
+}
+
 // uniqueNonEmpty returns deduplicated non-empty strings in order.
 func uniqueNonEmpty(values []string) []string {
 	seen := make(map[string]struct{}, len(values))
@@ -899,6 +1045,12 @@ func uniqueNonEmpty(values []string) []string {
 
 // handleSessionTask processes asynchronous session initialization tasks
 func (s *WhatsAppCallService) handleSessionTask(t task.SessionTask) {
+	switch t.Type {
+	case task.TaskTypeInboundCall, task.TaskTypeWebCall, task.TaskTypeOutboundCall, task.TaskTypeLiveKitRoom:
+	default:
+		// Forwarded tasks such as WHIP trickle candidates have their own processors
+		return
+	}
 	logger.Base().Info("Processing session task", zap.String("type", string(t.Type)), zap.String("conn_id", t.ConnectionID))
 
 	// Find the local connection
@@ -913,6 +1065,14 @@ func (s *WhatsAppCallService) handleSessionTask(t task.SessionTask) {
 		return
 	}
 
+	// Only the owning pod joins the call's trace; it ends the trace on cleanup
+	ctx := tracing.AttachCall(t.Context(), t.ConnectionID)
+	ctx, span := tracing.Start(ctx, "session_task "+string(t.Type),
+		attribute.String("task.type", string(t.Type)),
+		attribute.String("connection.id", t.ConnectionID),
+	)
+	defer span.End()
+
 	switch t.Type {
 	case task.TaskTypeInboundCall:
 		// Recover payload (raw webhook body)
@@ -926,7 +1086,7 @@ func (s *WhatsAppCallService) handleSessionTask(t task.SessionTask) {
 		sdpData, err := event.ParseSDP()
 		if err == nil && sdpData != nil && sdpData.Type == "offer" {
 			// Pass the parsed SDP data to avoid re-parsing
-			s.handleAsyncInboundCall(event.TenantID, event.CallID, sdpData.SDP, connection)
+			s.handleAsyncInboundCall(ctx, event.TenantID, event.CallID, sdpData.SDP, connection)
 		} else {
 			// No SDP, just init AI
 			s.initializeAIConnection(connection)
@@ -949,9 +1109,12 @@ func (s *WhatsAppCallService) handleSessionTask(t task.SessionTask) {
 }
 
 // handleAsyncInboundCall contains logic extracted from handler to be run by worker
-func (s *WhatsAppCallService) handleAsyncInboundCall(tenantID, callID, offerSDP string, connection *WhatsAppCallConnection) {
+func (s *WhatsAppCallService) handleAsyncInboundCall(ctx context.Context, tenantID, callID, offerSDP string, connection *WhatsAppCallConnection) {
 	// 1. Generate SDP Answer
+	_, span := tracing.Start(ctx, "webrtc.sdp_negotiation")
 	sdpAnswer, err := s.webrtcProcessor.ProcessSDPOffer(connection.ID, offerSDP)
+	tracing.End(span, err)
+	metrics.ObserveCallSetup(string(connection.ChannelType), connectionProviderLabel(connection), "sdp_negotiation", err)
 	if err != nil {
 		logger.Base().Error("Failed to generate SDP answer", zap.Error(err))
 		return
@@ -963,7 +1126,9 @@ func (s *WhatsAppCallService) handleAsyncInboundCall(tenantID, callID, offerSDP
 	// 2. Accept call via Wati API
 	if s.watiClient != nil {
 		logger.Base().Info("Calling Wati API accept (asynchronous worker)...")
-		if err := s.watiClient.AcceptCallWithTenant(tenantID, callID, sdpAnswer); err != nil {
+		err := s.watiClient.AcceptCallWithTenant(ctx, tenantID, callID, sdpAnswer)
+		metrics.ObserveCallSetup(string(connection.ChannelType), connectionProviderLabel(connection), "wati_accept", err)
+		if err != nil {
 			logger.Base().Error("Wati API accept failed in worker", zap.Error(err))
 			return
 		}
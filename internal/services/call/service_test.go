@@ -0,0 +1,39 @@
+package call
+
+import (
+	"testing"
+
+	"github.com/ClareAI/astra-voice-service/internal/domain"
+)
+
+func TestIsWatiCall(t *testing.T) {
+	tests := []struct {
+		name     string
+		channel  domain.ChannelType
+		callID   string
+		outbound bool
+		wati     bool
+	}{
+		{"WhatsApp call", domain.ChannelTypeWhatsApp, "wacid.1", false, true},
+		{"WhatsApp call before Wati assigned an ID", domain.ChannelTypeWhatsApp, "", true, false},
+		{"outbound test call", domain.ChannelTypeTest, "wacid.2", true, true},
+		{"browser test call", domain.ChannelTypeTest, "wacid.3", false, false},
+		{"SIP call", domain.ChannelTypeSIP, "sip-call", false, false},
+		{"Twilio call", domain.ChannelTypeTwilio, "CA123", true, false},
+		{"LiveKit call", domain.ChannelTypeLiveKit, "room", false, false},
+		{"web call", domain.ChannelTypeWeb, "web", false, false},
+	}
+	s := &WhatsAppCallService{}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			connection := &WhatsAppCallConnection{
+				CallID:         test.callID,
+				ChannelType:    test.channel,
+				IsOutboundCall: test.outbound,
+			}
+			if got := s.isWatiCall(connection); got != test.wati {
+				t.Errorf("isWatiCall = %v, want %v", got, test.wati)
+			}
+		})
+	}
+}
@@ -12,14 +12,22 @@ import (
 	modelprovider "github.com/ClareAI/astra-voice-service/internal/core/model/provider"
 	"github.com/ClareAI/astra-voice-service/internal/domain"
 	"github.com/ClareAI/astra-voice-service/internal/repository"
+	"github.com/ClareAI/astra-voice-service/internal/services/agent"
 	"github.com/ClareAI/astra-voice-service/internal/storage"
+	"github.com/ClareAI/astra-voice-service/pkg/audiocue"
+	"github.com/ClareAI/astra-voice-service/pkg/dsp"
 	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/mixer"
 	"github.com/ClareAI/astra-voice-service/pkg/pubsub"
+	"github.com/ClareAI/astra-voice-service/pkg/vad"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"layeh.com/gopus"
 )
 
+// dspTenantLookupTimeout bounds the tenant lookup for the DSP fallback during call setup
+const dspTenantLookupTimeout = 2 * time.Second
+
 // WhatsApp Call API webhook structures based on https://developers.facebook.com/docs/whatsapp/cloud-api/calling/reference
 
 // ConversationMessage is an alias for config.ConversationMessage
@@ -86,16 +94,15 @@ type WebhookMetadata struct {
 
 // WhatsAppCallConnection represents an active WhatsApp call connection
 type WhatsAppCallConnection struct {
-	ID                  string
-	CallID              string
-	From                string
-	To                  string
-	PermissionMessageID string // For tracking permission request
-	CreatedAt           time.Time
-	LastActivity        time.Time
-	IsActive            bool
-	AtomicClosed        int32              // Atomic closed state (0=active, 1=closed)
-	ChannelType         domain.ChannelType // Channel type (whatsapp, livekit, test)
+	ID           string
+	CallID       string
+	From         string
+	To           string
+	CreatedAt    time.Time
+	LastActivity time.Time
+	IsActive     bool
+	AtomicClosed int32              // Atomic closed state (0=active, 1=closed)
+	ChannelType  domain.ChannelType // Channel type (whatsapp, livekit, test)
 
 	// WebRTC related fields
 	LocalSDP  string
@@ -131,6 +138,18 @@ type WhatsAppCallConnection struct {
 	// Contact information
 	ContactName string // Contact name from Wati webhook
 
+	// Per-call variables passed by the caller of the API, validated against the agent's declarations
+	Variables map[string]string
+
+	// Summary of an earlier call when this outbound call is a call back the user asked for
+	PreviousCallSummary string
+
+	// LiveKit calls are only recorded in-process when enabled (egress may record them instead)
+	InProcessRecording bool
+
+	// Why the agent ended the call through the end_call tool; empty when the caller hung up
+	EndReason string
+
 	// Agent configuration
 	AgentID     string // Agent ID for this connection
 	TextAgentID string // Text Agent ID for MCP calls
@@ -153,6 +172,18 @@ type WhatsAppCallConnection struct {
 	// Opus decoder for converting WhatsApp audio to PCM16 for model ingestion (per-connection)
 	OpusDecoder *gopus.Decoder // Dedicated decoder for this connection to avoid concurrent access issues
 
+	// Local voice activity detector gating caller audio to the model (nil when disabled)
+	VAD *vad.Detector
+
+	// Inbound DSP chain applied to decoded caller audio (nil when disabled)
+	DSP *dsp.Chain
+
+	// Audio played while tools run (nil plays the bundled hold music)
+	AudioCues *config.AudioCueConfig
+
+	// Mixer in front of WAOutputTrack when the agent mixes cues/ambience (nil otherwise)
+	OutputMixer *mixer.Mixer
+
 	// Sync
 	StopKeepalive chan struct{}
 	Mutex         sync.RWMutex
@@ -211,6 +242,7 @@ func (c *WhatsAppCallConnection) ensureVoiceConversation(startedAt *time.Time) (
 			StartedAt:              startTime,
 			EndedAt:                startTime, // Will be updated when conversation ends
 			Source:                 source,
+			Variables:              domain.StringMapJSONB(c.Variables),
 		}
 
 		if err := c.RepoManager.VoiceConversation().Create(ctx, voiceConversation); err != nil {
@@ -224,9 +256,52 @@ func (c *WhatsAppCallConnection) ensureVoiceConversation(startedAt *time.Time) (
 	conversationID = voiceConversation.ID
 	c.Mutex.Unlock()
 
+	// Resolve the recording owner and the tenant's export formats once per conversation
+	if c.NeedsAudioCaching() {
+		if audioCache := storage.GetAudioCache(); audioCache != nil {
+			tenantID, formats := c.tenantRecordingSettings(ctx)
+			audioCache.SetRecordingOwner(c.ID, tenantID, c.AgentID)
+			audioCache.SetRecordingFormats(c.ID, storage.ParseRecordingFormats(formats))
+		}
+	}
+
 	return conversationID, nil
 }
 
+// tenantRecordingSettings returns the connection's tenant ID and the recording formats
+// configured on the tenant
+func (c *WhatsAppCallConnection) tenantRecordingSettings(ctx context.Context) (string, []string) {
+	tenantID, tenant := c.voiceTenant(ctx)
+	if tenant == nil {
+		return tenantID, nil
+	}
+	return tenantID, tenant.RecordingFormats()
+}
+
+// voiceTenant resolves the connection's tenant ID (via the agent if needed) and loads the tenant.
+// The tenant is nil if it cannot be loaded.
+func (c *WhatsAppCallConnection) voiceTenant(ctx context.Context) (string, *domain.VoiceTenant) {
+	tenantID := c.GetTenantID()
+	if tenantID == "" && c.AgentID != "" {
+		voiceAgent, err := c.RepoManager.VoiceAgent().GetByID(ctx, c.AgentID)
+		if err != nil {
+			logger.Base().Warn("Failed to resolve tenant for connection", zap.String("connection_id", c.ID), zap.Error(err))
+			return "", nil
+		}
+		tenantID = voiceAgent.VoiceTenantID
+	}
+	if tenantID == "" {
+		return "", nil
+	}
+
+	tenant, err := c.RepoManager.VoiceTenant().GetByTenantID(ctx, tenantID)
+	if err != nil {
+		logger.Base().Warn("Failed to load tenant settings", zap.String("tenant_id", tenantID), zap.Error(err))
+		return tenantID, nil
+	}
+	return tenantID, tenant
+}
+
 // InitializeVoiceConversation initializes or retrieves the VoiceConversation for this connection
 // This should be called when the connection is created to start recording the conversation from the beginning
 // If not called, AddMessage will create it as a fallback when the first message arrives
@@ -242,6 +317,15 @@ func (c *WhatsAppCallConnection) AddMessage(role, content string) string {
 
 // AddMessageWithConfidence adds a message with confidence score to the conversation history and stores it in the database
 func (c *WhatsAppCallConnection) AddMessageWithConfidence(role, content string, confidence float64) string {
+	return c.addMessage(role, content, confidence, nil)
+}
+
+// AddMessageWithLatency adds a message together with the turn latency that produced it
+func (c *WhatsAppCallConnection) AddMessageWithLatency(role, content string, latency *modelprovider.TurnLatency) string {
+	return c.addMessage(role, content, 0, latency)
+}
+
+func (c *WhatsAppCallConnection) addMessage(role, content string, confidence float64, latency *modelprovider.TurnLatency) string {
 	c.Mutex.Lock()
 	defer c.Mutex.Unlock()
 
@@ -288,6 +372,15 @@ func (c *WhatsAppCallConnection) AddMessageWithConfidence(role, content string,
 				Confidence:     confidence,
 				CreatedAt:      message.Timestamp,
 			}
+			if latency != nil {
+				voiceMessage.ModelProvider = latency.Provider
+				voiceMessage.CommitLatencyMs = latency.CommitLatencyMs()
+				voiceMessage.ResponseLatencyMs = latency.ResponseLatencyMs()
+				voiceMessage.FirstAudioLatencyMs = latency.FirstAudioLatencyMs()
+				voiceMessage.VoiceToVoiceMs = latency.VoiceToVoiceMs()
+				voiceMessage.ToolDurationMs = latency.ToolDuration.Milliseconds()
+				voiceMessage.RAGDurationMs = latency.RAGDuration.Milliseconds()
+			}
 
 			if err := c.RepoManager.VoiceMessage().Create(ctx, voiceMessage); err != nil {
 				// Log error but don't fail the operation
@@ -382,6 +475,53 @@ func (c *WhatsAppCallConnection) SyncHistoryToAI() error {
 	return c.ModelConnection.AddConversationHistory(modelMessages)
 }
 
+// ForwardDTMF tells the model which phone key the caller pressed, so IVR-style prompts
+// ("press 1 for sales") work on telephony channels
+func (c *WhatsAppCallConnection) ForwardDTMF(digit string) {
+	logger.Base().Info("DTMF received", zap.String("connection_id", c.ID), zap.String("digit", digit))
+	text := fmt.Sprintf("[DTMF] The caller pressed the %q key on their phone keypad.", digit)
+	if err := c.sendUserTurn(text); err != nil {
+		logger.Base().Warn("Failed to forward DTMF to model", zap.String("connection_id", c.ID), zap.Error(err))
+	}
+}
+
+// InjectUserText adds typed user input to the conversation and asks the model to reply,
+// for channels that accept text next to audio
+func (c *WhatsAppCallConnection) InjectUserText(text string) error {
+	if err := c.sendUserTurn(text); err != nil {
+		return err
+	}
+	c.AddMessage("user", text)
+	return nil
+}
+
+// sendUserTurn adds a user message to the model's conversation and requests a response
+func (c *WhatsAppCallConnection) sendUserTurn(text string) error {
+	c.Mutex.RLock()
+	modelConn := c.ModelConnection
+	providerType := c.ModelProvider
+	c.Mutex.RUnlock()
+	if modelConn == nil {
+		return fmt.Errorf("model connection not available")
+	}
+
+	message := modelprovider.ConversationMessage{
+		Role:      "user",
+		Content:   text,
+		Timestamp: time.Now(),
+	}
+	if err := modelConn.AddConversationHistory([]modelprovider.ConversationMessage{message}); err != nil {
+		return err
+	}
+	// OpenAI only answers conversation items when asked to
+	if providerType != modelprovider.ProviderTypeGemini {
+		if err := modelConn.SendEvent(map[string]interface{}{"type": "response.create"}); err != nil {
+			return fmt.Errorf("failed to request response: %w", err)
+		}
+	}
+	return nil
+}
+
 // GetFrom returns the caller's phone number
 func (c *WhatsAppCallConnection) GetFrom() string {
 	return c.From
@@ -402,6 +542,11 @@ func (c *WhatsAppCallConnection) GetContactName() string {
 	return c.ContactName
 }
 
+// GetVariables returns the per-call variables
+func (c *WhatsAppCallConnection) GetVariables() map[string]string {
+	return c.Variables
+}
+
 // GetAgentID returns the agent ID for this connection
 func (c *WhatsAppCallConnection) GetAgentID() string {
 	return c.AgentID
@@ -459,7 +604,7 @@ func (c *WhatsAppCallConnection) GetChannelTypeString() string {
 
 // NeedsAudioCaching returns whether this channel needs audio caching
 func (c *WhatsAppCallConnection) NeedsAudioCaching() bool {
-	return c.ChannelType != domain.ChannelTypeLiveKit
+	return c.ChannelType != domain.ChannelTypeLiveKit || c.InProcessRecording
 }
 
 // GetConversationID returns the conversation ID for this connection
@@ -469,6 +614,20 @@ func (c *WhatsAppCallConnection) GetConversationID() string {
 	return c.ConversationID
 }
 
+// GetEndReason returns why the agent ended the call, if it did
+func (c *WhatsAppCallConnection) GetEndReason() string {
+	c.Mutex.RLock()
+	defer c.Mutex.RUnlock()
+	return c.EndReason
+}
+
+// SetEndReason records why the agent ended the call
+func (c *WhatsAppCallConnection) SetEndReason(reason string) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	c.EndReason = reason
+}
+
 // GetWAOutputTrack returns the WhatsApp output track
 func (c *WhatsAppCallConnection) GetWAOutputTrack() webrtcadapter.OpusWriter {
 	if c == nil {
@@ -505,6 +664,106 @@ func (c *WhatsAppCallConnection) GetOpusDecoder() *gopus.Decoder {
 	return c.OpusDecoder
 }
 
+// SetVADConfig creates the connection's voice activity detector from the agent config.
+// A nil or disabled config removes it, so all caller audio is forwarded.
+func (c *WhatsAppCallConnection) SetVADConfig(vadConfig *config.VADConfig) {
+	if c == nil {
+		return
+	}
+	detector := vadConfig.NewDetector(config.DefaultSampleRate)
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	c.VAD = detector
+}
+
+// GetVAD returns the connection's voice activity detector, or nil if VAD is disabled
+func (c *WhatsAppCallConnection) GetVAD() *vad.Detector {
+	if c == nil {
+		return nil
+	}
+	c.Mutex.RLock()
+	defer c.Mutex.RUnlock()
+	return c.VAD
+}
+
+// SetDSPConfig creates the connection's inbound DSP chain from the agent config.
+// Without an agent-level config the tenant's audio_dsp setting applies; the tenant lookup
+// runs on the call-setup path, so it is bounded by dspTenantLookupTimeout.
+func (c *WhatsAppCallConnection) SetDSPConfig(ctx context.Context, dspConfig *config.DSPConfig) {
+	if c == nil {
+		return
+	}
+	if dspConfig == nil && c.RepoManager != nil {
+		lookupCtx, cancel := context.WithTimeout(ctx, dspTenantLookupTimeout)
+		_, tenant := c.voiceTenant(lookupCtx)
+		cancel()
+		if tenant != nil {
+			dspConfig = agent.DSPConfigFromData(tenant.AudioDSPConfig())
+		}
+	}
+	chain := dspConfig.NewChain(config.DefaultSampleRate)
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	c.DSP = chain
+}
+
+// GetDSP returns the connection's inbound DSP chain, or nil if DSP is disabled
+func (c *WhatsAppCallConnection) GetDSP() *dsp.Chain {
+	if c == nil {
+		return nil
+	}
+	c.Mutex.RLock()
+	defer c.Mutex.RUnlock()
+	return c.DSP
+}
+
+// SetAudioCueConfig sets the agent's tool-call audio cues and starts loading the
+// uploaded cues so they are ready by the first tool call.
+func (c *WhatsAppCallConnection) SetAudioCueConfig(cueConfig *config.AudioCueConfig) {
+	if c == nil {
+		return
+	}
+	if library := audiocue.GetLibrary(); library != nil && cueConfig != nil {
+		for _, name := range cueConfig.CueNames() {
+			library.Preload(audiocue.Key(cueConfig.TenantID, cueConfig.AgentID, name))
+		}
+	}
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	c.AudioCues = cueConfig
+}
+
+// GetAudioCueConfig returns the agent's tool-call audio cues, or nil for the defaults
+func (c *WhatsAppCallConnection) GetAudioCueConfig() *config.AudioCueConfig {
+	if c == nil {
+		return nil
+	}
+	c.Mutex.RLock()
+	defer c.Mutex.RUnlock()
+	return c.AudioCues
+}
+
+// SetOutputMixer records the mixer feeding the output track; further sources
+// (e.g. a supervisor's voice) can be added to it while the call runs
+func (c *WhatsAppCallConnection) SetOutputMixer(outputMixer *mixer.Mixer) {
+	if c == nil {
+		return
+	}
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	c.OutputMixer = outputMixer
+}
+
+// GetOutputMixer returns the mixer feeding the output track, or nil if output is not mixed
+func (c *WhatsAppCallConnection) GetOutputMixer() *mixer.Mixer {
+	if c == nil {
+		return nil
+	}
+	c.Mutex.RLock()
+	defer c.Mutex.RUnlock()
+	return c.OutputMixer
+}
+
 // GetAIWebRTC returns the legacy WebRTC client for this connection (backward compatibility)
 func (c *WhatsAppCallConnection) GetAIWebRTC() *webrtcadapter.Client {
 	if c == nil {
@@ -0,0 +1,90 @@
+package callback
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/internal/config"
+	"github.com/ClareAI/astra-voice-service/pkg/clock"
+)
+
+// workingHoursLookahead bounds how many days ahead working hours are searched
+const workingHoursLookahead = 8
+
+// clockRange is a working range in minutes after midnight
+type clockRange struct {
+	start, end int
+}
+
+// nextWorkingTime returns the first time at or after t inside the agent's working hours,
+// and whether t itself had to move. Without working hours any time works; with hours that
+// have no open day in the lookahead t is kept, so a bad config cannot block call backs.
+func nextWorkingTime(wh *config.WorkingHours, t time.Time) (time.Time, bool) {
+	if wh == nil || len(wh.Schedule) == 0 {
+		return t, false
+	}
+	location := time.UTC
+	if wh.Timezone != "" {
+		if loc, err := time.LoadLocation(wh.Timezone); err == nil {
+			location = loc
+		}
+	}
+
+	days := make(map[time.Weekday][]clockRange, len(wh.Schedule))
+	for key, value := range wh.Schedule {
+		day, ok := clock.ParseWeekday(key)
+		if !ok {
+			continue
+		}
+		days[day] = append(days[day], parseRanges(value)...)
+	}
+
+	local := t.In(location)
+	for offset := 0; offset < workingHoursLookahead; offset++ {
+		date := local.AddDate(0, 0, offset)
+		year, month, day := date.Date()
+
+		var best *time.Time
+		for _, r := range days[date.Weekday()] {
+			// Wall-clock times, so ranges keep their hours on DST transition days
+			start := time.Date(year, month, day, r.start/60, r.start%60, 0, 0, location)
+			end := time.Date(year, month, day, r.end/60, r.end%60, 0, 0, location)
+			if !local.Before(end) {
+				continue
+			}
+			candidate := start
+			if local.After(start) {
+				candidate = local
+			}
+			if best == nil || candidate.Before(*best) {
+				best = &candidate
+			}
+		}
+		if best != nil {
+			return best.In(t.Location()), !best.Equal(t)
+		}
+	}
+	return t, false
+}
+
+// parseRanges parses a working hours value such as "09:00-12:00, 13:00-17:00"; "closed",
+// empty values and malformed ranges yield no hours
+func parseRanges(value string) []clockRange {
+	var ranges []clockRange
+	for _, part := range strings.Split(value, ",") {
+		from, to, ok := strings.Cut(strings.TrimSpace(part), "-")
+		if !ok {
+			continue
+		}
+		start, err := clock.ParseMinutes(strings.TrimSpace(from))
+		if err != nil {
+			continue
+		}
+		end, err := clock.ParseMinutes(strings.TrimSpace(to))
+		if err != nil || end <= start {
+			continue
+		}
+		ranges = append(ranges, clockRange{start: start, end: end})
+	}
+	return ranges
+}
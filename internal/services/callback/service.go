@@ -0,0 +1,267 @@
+package callback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ClareAI/astra-voice-service/internal/config"
+	"github.com/ClareAI/astra-voice-service/internal/domain"
+	"github.com/ClareAI/astra-voice-service/internal/repository"
+	"github.com/ClareAI/astra-voice-service/internal/services/agent"
+	"github.com/ClareAI/astra-voice-service/internal/services/campaign"
+	"github.com/ClareAI/astra-voice-service/pkg/clock"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Callback limits
+const (
+	DefaultTimezone = "UTC"
+
+	// maxLead bounds how far ahead a call back may be scheduled
+	maxLead = 30 * 24 * time.Hour
+
+	// pastTolerance accepts requested times slightly in the past, e.g. "now" said a minute ago
+	pastTolerance = 5 * time.Minute
+
+	// maxSummaryLength bounds the summary, as it is added to the prompt of the call back
+	maxSummaryLength = 2000
+)
+
+var (
+	// ErrCallbackNotFound is returned for unknown callback jobs
+	ErrCallbackNotFound = errors.New("callback not found")
+
+	// ErrInvalidCallback wraps validation failures of callback requests
+	ErrInvalidCallback = errors.New("invalid callback")
+
+	// ErrInvalidTransition is returned when a callback job cannot be changed in its status
+	ErrInvalidTransition = errors.New("invalid callback status transition")
+)
+
+// Service schedules the call backs callers ask for and places them when due
+type Service struct {
+	repo         *repository.CallbackRepository
+	agentService *agent.AgentService
+	dialer       campaign.Dialer
+}
+
+// NewService creates a callback service. agentService is optional.
+func NewService(repo *repository.CallbackRepository, agentService *agent.AgentService, dialer campaign.Dialer) *Service {
+	return &Service{
+		repo:         repo,
+		agentService: agentService,
+		dialer:       dialer,
+	}
+}
+
+// Schedule stores a call back at the requested time, moved into the agent's working hours.
+// A scheduled call back of the same agent to the same contact is replaced.
+func (s *Service) Schedule(ctx context.Context, req *domain.ScheduleCallbackRequest) (*domain.CallbackJob, error) {
+	if req.WAID == "" {
+		return nil, fmt.Errorf("%w: no phone number to call back", ErrInvalidCallback)
+	}
+	if req.AgentID == "" {
+		return nil, fmt.Errorf("%w: no agent to call back with", ErrInvalidCallback)
+	}
+
+	// Call backs use the published agent config, as production outbound calls do
+	var agentConfig *config.AgentConfig
+	if s.agentService != nil {
+		var err error
+		agentConfig, err = s.agentService.GetAgentConfigWithChannelType(ctx, req.AgentID, domain.ChannelTypeWhatsApp)
+		if err != nil || agentConfig == nil {
+			return nil, fmt.Errorf("%w: agent %s not found", ErrInvalidCallback, req.AgentID)
+		}
+	}
+	var workingHours *config.WorkingHours
+	if agentConfig != nil && agentConfig.BusinessRules != nil {
+		workingHours = agentConfig.BusinessRules.WorkingHours
+	}
+
+	timezone := strings.TrimSpace(req.Timezone)
+	if timezone == "" && workingHours != nil {
+		timezone = workingHours.Timezone
+	}
+	if timezone == "" {
+		timezone = DefaultTimezone
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalidCallback, timezone)
+	}
+
+	now := time.Now()
+	requested, err := resolveRequestedTime(req, location, now)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCallback, err)
+	}
+	if requested.Before(now.Add(-pastTolerance)) {
+		return nil, fmt.Errorf("%w: %s has already passed", ErrInvalidCallback, requested.Format("Monday 2006-01-02 15:04 MST"))
+	}
+	if requested.After(now.Add(maxLead)) {
+		return nil, fmt.Errorf("%w: call backs can be scheduled at most %d days ahead", ErrInvalidCallback, int(maxLead.Hours()/24))
+	}
+	if requested.Before(now) {
+		requested = now
+	}
+	scheduled, _ := nextWorkingTime(workingHours, requested)
+
+	summary := strings.TrimSpace(req.Summary)
+	if len(summary) > maxSummaryLength {
+		// Cut on a rune boundary so the summary stays valid UTF-8
+		cut := maxSummaryLength
+		for cut > 0 && !utf8.RuneStart(summary[cut]) {
+			cut--
+		}
+		summary = summary[:cut]
+	}
+
+	job := &domain.CallbackJob{
+		TenantID:             req.TenantID,
+		AgentID:              req.AgentID,
+		ChannelType:          req.ChannelType,
+		WAID:                 req.WAID,
+		ChannelPhoneNumber:   req.ChannelPhoneNumber,
+		ContactName:          req.ContactName,
+		VoiceLanguage:        req.VoiceLanguage,
+		Accent:               req.Accent,
+		Timezone:             timezone,
+		RequestedAt:          requested,
+		ScheduledAt:          scheduled,
+		Reason:               strings.TrimSpace(req.Reason),
+		Summary:              summary,
+		SourceConnectionID:   req.SourceConnectionID,
+		SourceConversationID: req.SourceConversationID,
+		Variables:            domain.StringMapJSONB(outboundVariables(agentConfig, req.Variables)),
+	}
+	if agentConfig != nil {
+		job.AgentID = agentConfig.ID
+	}
+	if job.TenantID == "" && s.agentService != nil {
+		if tenantID, err := s.agentService.GetTenantIDByAgentID(job.AgentID); err == nil {
+			job.TenantID = tenantID
+		}
+	}
+
+	if err := s.repo.Replace(ctx, job); err != nil {
+		return nil, err
+	}
+	logger.Base().Info("Callback scheduled",
+		zap.String("callback_id", job.ID),
+		zap.String("agent_id", job.AgentID),
+		zap.String("waid", job.WAID),
+		zap.Time("requested_at", job.RequestedAt),
+		zap.Time("scheduled_at", job.ScheduledAt))
+	return job, nil
+}
+
+// GetCallback returns a callback job
+func (s *Service) GetCallback(ctx context.Context, id string) (*domain.CallbackJob, error) {
+	job, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, ErrCallbackNotFound
+	}
+	return job, nil
+}
+
+// ListCallbacks lists callback jobs, soonest first
+func (s *Service) ListCallbacks(ctx context.Context, filter domain.CallbackFilter) ([]*domain.CallbackJob, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// CancelCallback cancels a call back that has not been placed yet
+func (s *Service) CancelCallback(ctx context.Context, id string) error {
+	job, err := s.GetCallback(ctx, id)
+	if err != nil {
+		return err
+	}
+	cancelled, err := s.repo.Transition(ctx, job.ID, []string{domain.CallbackStatusScheduled}, domain.CallbackStatusCancelled, nil)
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		return fmt.Errorf("%w: callback is %s", ErrInvalidTransition, job.Status)
+	}
+	logger.Base().Info("Callback cancelled", zap.String("callback_id", job.ID))
+	return nil
+}
+
+// resolveRequestedTime turns the requested date and time, local to location, into a time.
+// Without a date the next occurrence of the time is used.
+func resolveRequestedTime(req *domain.ScheduleCallbackRequest, location *time.Location, now time.Time) (time.Time, error) {
+	if req.InMinutes < 0 {
+		return time.Time{}, fmt.Errorf("in_minutes cannot be negative")
+	}
+	if req.InMinutes > 0 {
+		return now.Add(time.Duration(req.InMinutes) * time.Minute), nil
+	}
+
+	timeOfDay := strings.TrimSpace(req.Time)
+	if timeOfDay == "" {
+		return time.Time{}, fmt.Errorf("a time or in_minutes is required")
+	}
+	minutes, err := clock.ParseMinutes(timeOfDay)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	local := now.In(location)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+	at := func(day time.Time) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), minutes/60, minutes%60, 0, 0, location)
+	}
+
+	date := strings.ToLower(strings.TrimSpace(req.Date))
+	switch date {
+	case "":
+		if t := at(today); !t.Before(now) {
+			return t, nil
+		}
+		return at(today.AddDate(0, 0, 1)), nil
+	case "today":
+		return at(today), nil
+	case "tomorrow":
+		return at(today.AddDate(0, 0, 1)), nil
+	}
+	if weekday, ok := clock.ParseWeekday(date); ok {
+		// The same weekday as today means next week
+		days := (int(weekday) - int(today.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return at(today.AddDate(0, 0, days)), nil
+	}
+	day, err := time.ParseInLocation(time.DateOnly, date, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected today, tomorrow, a weekday or YYYY-MM-DD", req.Date)
+	}
+	return at(day), nil
+}
+
+// outboundVariables keeps the source call's variables the agent accepts on outbound calls,
+// so the call back is not rejected for variables only inbound calls declare
+func outboundVariables(agentConfig *config.AgentConfig, values map[string]string) map[string]string {
+	if agentConfig == nil || len(values) == 0 {
+		return values
+	}
+	declared := agentConfig.CallVariables(true)
+	if len(declared) == 0 {
+		return values
+	}
+
+	kept := make(map[string]string, len(declared))
+	for _, v := range declared {
+		if value, ok := values[v.Name]; ok {
+			kept[v.Name] = value
+		}
+	}
+	return kept
+}
@@ -0,0 +1,167 @@
+package callback
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/internal/config"
+	"github.com/ClareAI/astra-voice-service/internal/domain"
+	"github.com/ClareAI/astra-voice-service/internal/services/campaign"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Worker timing
+const (
+	// pollInterval is how often the worker looks for due call backs
+	pollInterval = 30 * time.Second
+
+	// claimBatch bounds how many call backs one poll places
+	claimBatch = 20
+
+	// dialTimeout fails call backs whose worker stopped while dialing, e.g. after a restart
+	dialTimeout = 5 * time.Minute
+
+	// maxAttempts bounds how often a call back is tried when placing the call fails
+	maxAttempts = 3
+
+	// retryDelay is how long a failed call back waits before it is tried again
+	retryDelay = 15 * time.Minute
+)
+
+// StartWorker places due call backs until ctx is done. Every pod may run a worker; jobs
+// are claimed in the database so each is dialled once.
+func (s *Service) StartWorker(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	logger.Base().Info("Started callback worker", zap.Duration("poll_interval", pollInterval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Base().Info("Callback worker stopped")
+			return
+		case <-ticker.C:
+			s.expireStaleJobs(ctx)
+			s.dialDueJobs(ctx)
+		}
+	}
+}
+
+// dialDueJobs claims and places due call backs
+func (s *Service) dialDueJobs(ctx context.Context) {
+	jobs, err := s.repo.ClaimDue(ctx, time.Now(), claimBatch)
+	if err != nil {
+		logger.Base().Error("Failed to claim due callbacks", zap.Error(err))
+		return
+	}
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			return
+		}
+		s.dial(ctx, job)
+	}
+}
+
+// dial places a claimed call back with the summary of the call it was asked for in
+func (s *Service) dial(ctx context.Context, job *domain.CallbackJob) {
+	if s.dialer == nil {
+		s.fail(ctx, job, "outbound calling is not configured")
+		return
+	}
+
+	contactName := job.ContactName
+	if contactName == "" {
+		contactName = job.WAID
+	}
+	result, err := s.dialer.Dial(ctx, campaign.DialRequest{
+		AgentID:             job.AgentID,
+		TenantID:            job.TenantID,
+		WAID:                job.WAID,
+		ChannelPhoneNumber:  job.ChannelPhoneNumber,
+		VoiceLanguage:       job.VoiceLanguage,
+		Accent:              job.Accent,
+		ContactName:         contactName,
+		Variables:           job.Variables,
+		PreviousCallSummary: previousCallSummary(job),
+	})
+	if err != nil {
+		logger.Base().Warn("Callback call failed",
+			zap.String("callback_id", job.ID),
+			zap.Int("attempt", job.Attempts),
+			zap.Error(err))
+		if errors.Is(err, campaign.ErrPermissionDenied) || job.Attempts >= maxAttempts {
+			s.fail(ctx, job, err.Error())
+			return
+		}
+		s.retry(ctx, job, err.Error())
+		return
+	}
+
+	now := time.Now()
+	if _, err := s.repo.Transition(ctx, job.ID, []string{domain.CallbackStatusDialing}, domain.CallbackStatusPlaced, map[string]interface{}{
+		"connection_id": result.ConnectionID,
+		"placed_at":     now,
+	}); err != nil {
+		logger.Base().Error("Failed to update callback", zap.String("callback_id", job.ID), zap.Error(err))
+		return
+	}
+	logger.Base().Info("Callback call placed",
+		zap.String("callback_id", job.ID),
+		zap.String("connection_id", result.ConnectionID),
+		zap.Bool("awaiting_permission", result.AwaitingPermission),
+		zap.Int("attempt", job.Attempts))
+}
+
+// retry schedules another attempt of a call back, inside the agent's working hours
+func (s *Service) retry(ctx context.Context, job *domain.CallbackJob, lastError string) {
+	var workingHours *config.WorkingHours
+	if s.agentService != nil {
+		if agentConfig, err := s.agentService.GetAgentConfigWithChannelType(ctx, job.AgentID, domain.ChannelTypeWhatsApp); err == nil && agentConfig != nil && agentConfig.BusinessRules != nil {
+			workingHours = agentConfig.BusinessRules.WorkingHours
+		}
+	}
+	next, _ := nextWorkingTime(workingHours, time.Now().Add(retryDelay))
+
+	if _, err := s.repo.Transition(ctx, job.ID, []string{domain.CallbackStatusDialing}, domain.CallbackStatusScheduled, map[string]interface{}{
+		"scheduled_at": next,
+		"last_error":   lastError,
+	}); err != nil {
+		logger.Base().Error("Failed to reschedule callback", zap.String("callback_id", job.ID), zap.Error(err))
+	}
+}
+
+// fail gives up on a call back
+func (s *Service) fail(ctx context.Context, job *domain.CallbackJob, lastError string) {
+	if _, err := s.repo.Transition(ctx, job.ID, []string{domain.CallbackStatusDialing}, domain.CallbackStatusFailed, map[string]interface{}{
+		"last_error": lastError,
+	}); err != nil {
+		logger.Base().Error("Failed to update callback", zap.String("callback_id", job.ID), zap.Error(err))
+	}
+}
+
+// expireStaleJobs fails call backs left dialing by a worker that stopped
+func (s *Service) expireStaleJobs(ctx context.Context) {
+	jobs, err := s.repo.ListStale(ctx, domain.CallbackStatusDialing, time.Now().Add(-dialTimeout))
+	if err != nil {
+		logger.Base().Error("Failed to list stale callbacks", zap.Error(err))
+		return
+	}
+	for _, job := range jobs {
+		s.fail(ctx, job, "timed out while dialing")
+	}
+}
+
+// previousCallSummary is the context the agent gets about the call the call back was asked for
+func previousCallSummary(job *domain.CallbackJob) string {
+	summary := job.Summary
+	if job.Reason != "" {
+		if summary != "" {
+			summary += "\n"
+		}
+		summary += "Reason for the call back: " + job.Reason
+	}
+	return summary
+}
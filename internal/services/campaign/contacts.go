@@ -0,0 +1,135 @@
+package campaign
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/ClareAI/astra-voice-service/internal/domain"
+)
+
+// Column names accepted for a contact's phone number and name in CSV uploads
+var (
+	phoneColumns = []string{"waid", "phone", "phone_number", "number"}
+	nameColumns  = []string{"name", "contact_name"}
+)
+
+// ParseContacts reads a contact list upload. CSV needs a header row with a phone column
+// (waid, phone, phone_number or number) and optionally a name column; every other column
+// becomes a per-contact variable. JSON is an array of contacts or {"contacts": [...]}.
+func ParseContacts(contentType string, body io.Reader) ([]domain.CampaignContactInput, error) {
+	if strings.Contains(contentType, "csv") {
+		return parseCSVContacts(body)
+	}
+	return parseJSONContacts(body)
+}
+
+// parseCSVContacts reads contacts from CSV with a header row
+func parseCSVContacts(body io.Reader) ([]domain.CampaignContactInput, error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+	}
+	phoneIndex := columnIndex(header, phoneColumns)
+	if phoneIndex < 0 {
+		return nil, errors.New("CSV header has no phone column (waid, phone, phone_number or number)")
+	}
+	nameIndex := columnIndex(header, nameColumns)
+
+	var contacts []domain.CampaignContactInput
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV row %d: %w", row, err)
+		}
+
+		contact := domain.CampaignContactInput{Variables: map[string]interface{}{}}
+		for i, value := range record {
+			if i >= len(header) || header[i] == "" {
+				continue
+			}
+			value = strings.TrimSpace(value)
+			switch i {
+			case phoneIndex:
+				contact.WAID = value
+			case nameIndex:
+				contact.Name = value
+			default:
+				if value != "" {
+					contact.Variables[header[i]] = value
+				}
+			}
+		}
+		// Skip blank lines
+		if contact.WAID == "" && contact.Name == "" && len(contact.Variables) == 0 {
+			continue
+		}
+		if contact.WAID == "" {
+			return nil, fmt.Errorf("CSV row %d: phone number is required", row)
+		}
+		contacts = append(contacts, contact)
+	}
+	return contacts, nil
+}
+
+// parseJSONContacts reads contacts from a JSON array or {"contacts": [...]}
+func parseJSONContacts(body io.Reader) ([]domain.CampaignContactInput, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contacts: %w", err)
+	}
+
+	var contacts []domain.CampaignContactInput
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "{") {
+		var wrapper struct {
+			Contacts []domain.CampaignContactInput `json:"contacts"`
+		}
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return nil, fmt.Errorf("invalid contacts JSON: %w", err)
+		}
+		contacts = wrapper.Contacts
+	} else if err := json.Unmarshal(data, &contacts); err != nil {
+		return nil, fmt.Errorf("invalid contacts JSON: %w", err)
+	}
+
+	for i, contact := range contacts {
+		if strings.TrimSpace(contact.WAID) == "" {
+			return nil, fmt.Errorf("contact %d: waid is required", i)
+		}
+	}
+	return contacts, nil
+}
+
+// columnIndex returns the index of the first header matching one of names, or -1
+func columnIndex(header, names []string) int {
+	for _, name := range names {
+		for i, column := range header {
+			if column == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// sanitizePhone trims non-digit characters from the start and end of a phone number,
+// as outbound call requests do
+func sanitizePhone(s string) string {
+	return strings.TrimFunc(s, func(r rune) bool {
+		return !unicode.IsDigit(r)
+	})
+}
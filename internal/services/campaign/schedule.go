@@ -0,0 +1,95 @@
+package campaign
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/internal/domain"
+	"github.com/ClareAI/astra-voice-service/pkg/clock"
+)
+
+// validateSchedule checks a campaign's timezone and schedule windows
+func validateSchedule(timezone string, windows domain.ScheduleWindows) error {
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("invalid timezone %q", timezone)
+	}
+	for i, window := range windows {
+		start, err := clock.ParseMinutes(window.Start)
+		if err != nil {
+			return fmt.Errorf("schedule window %d: %w", i, err)
+		}
+		end, err := clock.ParseMinutes(window.End)
+		if err != nil {
+			return fmt.Errorf("schedule window %d: %w", i, err)
+		}
+		if start == end {
+			return fmt.Errorf("schedule window %d: start and end must differ", i)
+		}
+		for _, day := range window.Days {
+			if _, ok := clock.ParseWeekday(day); !ok {
+				return fmt.Errorf("schedule window %d: unknown day %q", i, day)
+			}
+		}
+	}
+	return nil
+}
+
+// withinSchedule reports whether a campaign may place calls at t
+func withinSchedule(campaign *domain.OutboundCampaign, t time.Time) bool {
+	if campaign.StartAt != nil && t.Before(*campaign.StartAt) {
+		return false
+	}
+	if campaign.EndAt != nil && !t.Before(*campaign.EndAt) {
+		return false
+	}
+	if len(campaign.Schedule) == 0 {
+		return true
+	}
+
+	location, err := time.LoadLocation(campaign.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	local := t.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	today := local.Weekday()
+	yesterday := (today + 6) % 7
+
+	for _, window := range campaign.Schedule {
+		start, err := clock.ParseMinutes(window.Start)
+		if err != nil {
+			continue
+		}
+		end, err := clock.ParseMinutes(window.End)
+		if err != nil {
+			continue
+		}
+		if start < end {
+			if minute >= start && minute < end && onDay(window, today) {
+				return true
+			}
+			continue
+		}
+		// The window spans midnight; its early hours belong to the previous day
+		if minute >= start && onDay(window, today) {
+			return true
+		}
+		if minute < end && onDay(window, yesterday) {
+			return true
+		}
+	}
+	return false
+}
+
+// onDay reports whether a window applies on a weekday
+func onDay(window domain.ScheduleWindow, day time.Weekday) bool {
+	if len(window.Days) == 0 {
+		return true
+	}
+	for _, name := range window.Days {
+		if weekday, ok := clock.ParseWeekday(name); ok && weekday == day {
+			return true
+		}
+	}
+	return false
+}
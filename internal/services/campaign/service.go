@@ -0,0 +1,430 @@
+package campaign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/internal/config"
+	"github.com/ClareAI/astra-voice-service/internal/domain"
+	"github.com/ClareAI/astra-voice-service/internal/repository"
+	"github.com/ClareAI/astra-voice-service/internal/services/agent"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Campaign defaults
+const (
+	DefaultTimezone             = "UTC"
+	DefaultMaxConcurrent        = 1 // Calls in progress per business number without a limit set
+	DefaultMaxAttempts          = 3
+	DefaultRetryIntervalMinutes = 60
+	maxAttemptsLimit            = 10
+)
+
+var (
+	// ErrCampaignNotFound is returned for unknown campaigns
+	ErrCampaignNotFound = errors.New("campaign not found")
+
+	// ErrInvalidCampaign wraps validation failures of campaign requests
+	ErrInvalidCampaign = errors.New("invalid campaign")
+
+	// ErrInvalidTransition is returned when a campaign cannot move to the requested status
+	ErrInvalidTransition = errors.New("invalid campaign status transition")
+
+	// ErrPermissionDenied is returned by a Dialer when the contact has not allowed calls
+	// and permission cannot be requested
+	ErrPermissionDenied = errors.New("call permission denied")
+)
+
+// DialRequest is an outbound call to place, e.g. for a campaign contact
+type DialRequest struct {
+	AgentID            string
+	TenantID           string
+	WAID               string
+	ChannelPhoneNumber string
+	VoiceLanguage      string
+	Accent             string
+	ContactName        string
+	Variables          map[string]interface{} // The contact's per-call variables
+
+	// Summary of an earlier call, for calls back the contact asked for
+	PreviousCallSummary string
+}
+
+// DialResult identifies a placed call
+type DialResult struct {
+	ConnectionID       string
+	CallID             string
+	AwaitingPermission bool // a call permission request was sent instead of calling
+}
+
+// Dialer places outbound calls
+type Dialer interface {
+	Dial(ctx context.Context, req DialRequest) (*DialResult, error)
+}
+
+// Service manages outbound campaigns and tracks the outcome of their calls
+type Service struct {
+	repo         *repository.CampaignRepository
+	agentService *agent.AgentService
+	dialer       Dialer
+}
+
+// NewService creates a campaign service. agentService is optional.
+func NewService(repo *repository.CampaignRepository, agentService *agent.AgentService, dialer Dialer) *Service {
+	return &Service{
+		repo:         repo,
+		agentService: agentService,
+		dialer:       dialer,
+	}
+}
+
+// CreateCampaign creates a draft campaign
+func (s *Service) CreateCampaign(ctx context.Context, req *domain.CreateCampaignRequest) (*domain.OutboundCampaign, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidCampaign)
+	}
+	if req.VoiceAgentID == "" {
+		return nil, fmt.Errorf("%w: voice_agent_id is required", ErrInvalidCampaign)
+	}
+
+	campaign := &domain.OutboundCampaign{
+		Name:                 strings.TrimSpace(req.Name),
+		TenantID:             req.TenantID,
+		VoiceAgentID:         req.VoiceAgentID,
+		ChannelPhoneNumber:   sanitizePhone(req.ChannelPhoneNumber),
+		VoiceLanguage:        req.VoiceLanguage,
+		Accent:               req.Accent,
+		Status:               domain.CampaignStatusDraft,
+		Timezone:             req.Timezone,
+		Schedule:             req.Schedule,
+		StartAt:              req.StartAt,
+		EndAt:                req.EndAt,
+		MaxAttempts:          req.MaxAttempts,
+		RetryIntervalMinutes: req.RetryIntervalMinutes,
+	}
+	if campaign.VoiceLanguage == "" {
+		campaign.VoiceLanguage = config.DefaultLanguage
+	}
+	if campaign.Timezone == "" {
+		campaign.Timezone = DefaultTimezone
+	}
+	if campaign.MaxAttempts <= 0 {
+		campaign.MaxAttempts = DefaultMaxAttempts
+	}
+	if campaign.MaxAttempts > maxAttemptsLimit {
+		return nil, fmt.Errorf("%w: max_attempts cannot exceed %d", ErrInvalidCampaign, maxAttemptsLimit)
+	}
+	if campaign.RetryIntervalMinutes <= 0 {
+		campaign.RetryIntervalMinutes = DefaultRetryIntervalMinutes
+	}
+	if err := validateSchedule(campaign.Timezone, campaign.Schedule); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCampaign, err)
+	}
+	if campaign.StartAt != nil && campaign.EndAt != nil && !campaign.EndAt.After(*campaign.StartAt) {
+		return nil, fmt.Errorf("%w: end_at must be after start_at", ErrInvalidCampaign)
+	}
+
+	// Calls use the published agent config, as production outbound calls do
+	if s.agentService != nil {
+		agentConfig, err := s.agentService.GetAgentConfigWithChannelType(ctx, campaign.VoiceAgentID, domain.ChannelTypeWhatsApp)
+		if err != nil || agentConfig == nil {
+			return nil, fmt.Errorf("%w: agent %s not found", ErrInvalidCampaign, campaign.VoiceAgentID)
+		}
+		campaign.VoiceAgentID = agentConfig.ID
+		if campaign.TenantID == "" {
+			if tenantID, err := s.agentService.GetTenantIDByAgentID(campaign.VoiceAgentID); err == nil {
+				campaign.TenantID = tenantID
+			}
+		}
+	}
+
+	if err := s.repo.Create(ctx, campaign); err != nil {
+		return nil, err
+	}
+	logger.Base().Info("Campaign created",
+		zap.String("campaign_id", campaign.ID),
+		zap.String("agent_id", campaign.VoiceAgentID),
+		zap.String("tenant_id", campaign.TenantID))
+	return campaign, nil
+}
+
+// AddContacts adds contacts to a campaign that has not finished, skipping numbers repeated
+// within the upload, and returns how many were added
+func (s *Service) AddContacts(ctx context.Context, campaignID string, inputs []domain.CampaignContactInput) (int, error) {
+	campaign, err := s.getCampaign(ctx, campaignID)
+	if err != nil {
+		return 0, err
+	}
+	if campaign.Status == domain.CampaignStatusCompleted || campaign.Status == domain.CampaignStatusCancelled {
+		return 0, fmt.Errorf("%w: campaign is %s", ErrInvalidTransition, campaign.Status)
+	}
+
+	// Variables are checked against the agent up front so bad rows fail the upload rather
+	// than the call
+	var declaredVariables []config.CallVariable
+	if s.agentService != nil {
+		if agentConfig, err := s.agentService.GetAgentConfigWithChannelType(ctx, campaign.VoiceAgentID, domain.ChannelTypeWhatsApp); err == nil && agentConfig != nil {
+			declaredVariables = agentConfig.CallVariables(true)
+		}
+	}
+
+	seen := make(map[string]bool, len(inputs))
+	contacts := make([]*domain.CampaignContact, 0, len(inputs))
+	for i, input := range inputs {
+		waid := sanitizePhone(input.WAID)
+		if waid == "" {
+			return 0, fmt.Errorf("%w: contact %d has no phone number", ErrInvalidCampaign, i)
+		}
+		if seen[waid] {
+			continue
+		}
+		seen[waid] = true
+		if _, err := config.ResolveCallVariables(declaredVariables, input.Variables); err != nil {
+			return 0, fmt.Errorf("%w: contact %d: %v", ErrInvalidCampaign, i, err)
+		}
+
+		contact := &domain.CampaignContact{
+			WAID: waid,
+			Name: strings.TrimSpace(input.Name),
+		}
+		if len(input.Variables) > 0 {
+			contact.Variables = domain.JSONB(input.Variables)
+		}
+		contacts = append(contacts, contact)
+	}
+	if len(contacts) == 0 {
+		return 0, nil
+	}
+
+	if err := s.repo.AddContacts(ctx, campaign.ID, contacts); err != nil {
+		return 0, err
+	}
+	logger.Base().Info("Campaign contacts added", zap.String("campaign_id", campaign.ID), zap.Int("count", len(contacts)))
+	return len(contacts), nil
+}
+
+// GetCampaign returns a campaign with its contact counts
+func (s *Service) GetCampaign(ctx context.Context, campaignID string) (*domain.CampaignWithStats, error) {
+	campaign, err := s.getCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.repo.CountContactsByStatus(ctx, campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.CampaignWithStats{OutboundCampaign: *campaign, ContactCounts: counts}, nil
+}
+
+// SetNumberLimit sets how many campaign calls may be in progress from a tenant's business number
+func (s *Service) SetNumberLimit(ctx context.Context, limit *domain.CampaignNumberLimit) error {
+	if limit.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidCampaign)
+	}
+	if limit.MaxConcurrent <= 0 {
+		return fmt.Errorf("%w: max_concurrent must be positive", ErrInvalidCampaign)
+	}
+	limit.ChannelPhoneNumber = sanitizePhone(limit.ChannelPhoneNumber)
+	if err := s.repo.SetNumberLimit(ctx, limit); err != nil {
+		return err
+	}
+	logger.Base().Info("Campaign number limit set",
+		zap.String("tenant_id", limit.TenantID),
+		zap.String("channel_phone_number", limit.ChannelPhoneNumber),
+		zap.Int("max_concurrent", limit.MaxConcurrent))
+	return nil
+}
+
+// ListNumberLimits lists a tenant's business number limits
+func (s *Service) ListNumberLimits(ctx context.Context, tenantID string) ([]*domain.CampaignNumberLimit, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidCampaign)
+	}
+	return s.repo.ListNumberLimits(ctx, tenantID)
+}
+
+// ListCampaigns lists campaigns, optionally filtered by tenant and status
+func (s *Service) ListCampaigns(ctx context.Context, tenantID, status string) ([]*domain.OutboundCampaign, error) {
+	return s.repo.List(ctx, tenantID, status)
+}
+
+// ListContacts lists a page of a campaign's contacts with their dispositions
+func (s *Service) ListContacts(ctx context.Context, campaignID, status string, limit, offset int) ([]*domain.CampaignContact, error) {
+	if _, err := s.getCampaign(ctx, campaignID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListContacts(ctx, campaignID, status, limit, offset)
+}
+
+// StartCampaign starts dialing a draft campaign
+func (s *Service) StartCampaign(ctx context.Context, campaignID string) error {
+	open, err := s.repo.CountOpenContacts(ctx, campaignID)
+	if err != nil {
+		return err
+	}
+	if open == 0 {
+		return fmt.Errorf("%w: campaign has no contacts to call", ErrInvalidCampaign)
+	}
+	return s.transition(ctx, campaignID, []string{domain.CampaignStatusDraft}, domain.CampaignStatusRunning)
+}
+
+// PauseCampaign stops placing new calls; calls in progress continue
+func (s *Service) PauseCampaign(ctx context.Context, campaignID string) error {
+	return s.transition(ctx, campaignID, []string{domain.CampaignStatusRunning}, domain.CampaignStatusPaused)
+}
+
+// ResumeCampaign resumes a paused campaign
+func (s *Service) ResumeCampaign(ctx context.Context, campaignID string) error {
+	return s.transition(ctx, campaignID, []string{domain.CampaignStatusPaused}, domain.CampaignStatusRunning)
+}
+
+// CancelCampaign cancels a campaign and its undialled contacts; calls in progress continue
+func (s *Service) CancelCampaign(ctx context.Context, campaignID string) error {
+	from := []string{domain.CampaignStatusDraft, domain.CampaignStatusRunning, domain.CampaignStatusPaused}
+	if err := s.transition(ctx, campaignID, from, domain.CampaignStatusCancelled); err != nil {
+		return err
+	}
+	cancelled, err := s.repo.CancelUndialledContacts(ctx, campaignID)
+	if err != nil {
+		return err
+	}
+	logger.Base().Info("Campaign contacts cancelled", zap.String("campaign_id", campaignID), zap.Int64("count", cancelled))
+	return nil
+}
+
+// HandleCallAccepted records that a campaign call was answered
+func (s *Service) HandleCallAccepted(ctx context.Context, callID string) {
+	contact := s.activeContact(s.repo.GetActiveContactByCallID(ctx, callID))
+	if contact == nil || contact.Status != domain.ContactStatusDialing {
+		return
+	}
+	contact.Status = domain.ContactStatusInCall
+	contact.Disposition = domain.DispositionAnswered
+	if _, err := s.repo.TransitionContact(ctx, contact, []string{domain.ContactStatusDialing}); err != nil {
+		logger.Base().Error("Failed to update campaign contact", zap.String("contact_id", contact.ID), zap.Error(err))
+	}
+}
+
+// HandleCallRejected records that a campaign call was rejected
+func (s *Service) HandleCallRejected(ctx context.Context, callID string) {
+	if contact := s.activeContact(s.repo.GetActiveContactByCallID(ctx, callID)); contact != nil {
+		s.finishAttempt(ctx, contact, domain.DispositionRejected, "")
+	}
+}
+
+// HandlePermissionGranted records that a contact allowed calls and the call was placed
+func (s *Service) HandlePermissionGranted(ctx context.Context, connectionID, callID string) {
+	contact := s.activeContact(s.repo.GetActiveContactByConnectionID(ctx, connectionID))
+	if contact == nil || contact.Status != domain.ContactStatusAwaitingPermission {
+		return
+	}
+	contact.Status = domain.ContactStatusDialing
+	contact.CallID = callID
+	if _, err := s.repo.TransitionContact(ctx, contact, []string{domain.ContactStatusAwaitingPermission}); err != nil {
+		logger.Base().Error("Failed to update campaign contact", zap.String("contact_id", contact.ID), zap.Error(err))
+	}
+}
+
+// HandlePermissionDenied records that a contact declined calls
+func (s *Service) HandlePermissionDenied(ctx context.Context, connectionID string) {
+	if contact := s.activeContact(s.repo.GetActiveContactByConnectionID(ctx, connectionID)); contact != nil {
+		s.finishAttempt(ctx, contact, domain.DispositionPermissionDenied, "")
+	}
+}
+
+// HandleConnectionEnded settles the attempt that used a connection once it is cleaned up;
+// connected calls were answered, others were not
+func (s *Service) HandleConnectionEnded(connectionID string, connected bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	contact := s.activeContact(s.repo.GetActiveContactByConnectionID(ctx, connectionID))
+	if contact == nil {
+		return
+	}
+	disposition := domain.DispositionNoAnswer
+	if connected || contact.Status == domain.ContactStatusInCall {
+		disposition = domain.DispositionAnswered
+	}
+	s.finishAttempt(ctx, contact, disposition, "")
+}
+
+// finishAttempt records the outcome of a contact's attempt, scheduling a retry for
+// unanswered and rejected calls while attempts remain
+func (s *Service) finishAttempt(ctx context.Context, contact *domain.CampaignContact, disposition, lastError string) {
+	from := contact.Status
+	campaign, err := s.repo.GetByID(ctx, contact.CampaignID)
+	if err != nil || campaign == nil {
+		logger.Base().Error("Failed to load campaign for contact", zap.String("contact_id", contact.ID), zap.Error(err))
+		return
+	}
+
+	contact.Disposition = disposition
+	contact.LastError = lastError
+	contact.Status = domain.ContactStatusDone
+	retryable := disposition == domain.DispositionNoAnswer || disposition == domain.DispositionRejected
+	if retryable && contact.Attempts < campaign.MaxAttempts && campaign.Status != domain.CampaignStatusCancelled {
+		next := time.Now().Add(time.Duration(campaign.RetryIntervalMinutes) * time.Minute)
+		contact.Status = domain.ContactStatusRetryScheduled
+		contact.NextAttemptAt = &next
+	}
+
+	updated, err := s.repo.TransitionContact(ctx, contact, []string{from})
+	if err != nil {
+		logger.Base().Error("Failed to update campaign contact", zap.String("contact_id", contact.ID), zap.Error(err))
+		return
+	}
+	if updated {
+		logger.Base().Info("Campaign call finished",
+			zap.String("campaign_id", contact.CampaignID),
+			zap.String("contact_id", contact.ID),
+			zap.String("disposition", disposition),
+			zap.String("status", contact.Status),
+			zap.Int("attempts", contact.Attempts))
+	}
+}
+
+// transition moves a campaign between statuses
+func (s *Service) transition(ctx context.Context, campaignID string, from []string, to string) error {
+	campaign, err := s.getCampaign(ctx, campaignID)
+	if err != nil {
+		return err
+	}
+	updated, err := s.repo.TransitionStatus(ctx, campaign.ID, from, to)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return fmt.Errorf("%w: campaign is %s", ErrInvalidTransition, campaign.Status)
+	}
+	logger.Base().Info("Campaign status changed",
+		zap.String("campaign_id", campaign.ID),
+		zap.String("from", campaign.Status),
+		zap.String("to", to))
+	return nil
+}
+
+// getCampaign loads a campaign, returning ErrCampaignNotFound if it does not exist
+func (s *Service) getCampaign(ctx context.Context, campaignID string) (*domain.OutboundCampaign, error) {
+	campaign, err := s.repo.GetByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign == nil {
+		return nil, ErrCampaignNotFound
+	}
+	return campaign, nil
+}
+
+// activeContact logs lookup failures and returns the contact, if any
+func (s *Service) activeContact(contact *domain.CampaignContact, err error) *domain.CampaignContact {
+	if err != nil {
+		logger.Base().Error("Failed to look up campaign contact", zap.Error(err))
+		return nil
+	}
+	return contact
+}
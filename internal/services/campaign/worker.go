@@ -0,0 +1,164 @@
+package campaign
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/internal/domain"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Worker timing
+const (
+	// pollInterval is how often the worker looks for due contacts
+	pollInterval = 15 * time.Second
+
+	// callTimeout settles answered calls whose end was never reported, e.g. after a restart
+	callTimeout = 2 * time.Hour
+)
+
+// StartWorker dials due contacts of running campaigns until ctx is done. Every pod may run
+// a worker; contacts are claimed in the database so each is dialled once.
+func (s *Service) StartWorker(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	logger.Base().Info("Started campaign worker", zap.Duration("poll_interval", pollInterval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Base().Info("Campaign worker stopped")
+			return
+		case <-ticker.C:
+			s.expireStaleAttempts(ctx)
+			s.dialDueContacts(ctx)
+		}
+	}
+}
+
+// dialDueContacts places calls for every running campaign inside its schedule
+func (s *Service) dialDueContacts(ctx context.Context) {
+	campaigns, err := s.repo.List(ctx, "", domain.CampaignStatusRunning)
+	if err != nil {
+		logger.Base().Error("Failed to list running campaigns", zap.Error(err))
+		return
+	}
+
+	now := time.Now()
+	for _, campaign := range campaigns {
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Past its end, a campaign stops calling and finishes
+		if campaign.EndAt != nil && !now.Before(*campaign.EndAt) {
+			if _, err := s.repo.CancelUndialledContacts(ctx, campaign.ID); err != nil {
+				logger.Base().Error("Failed to cancel contacts of ended campaign", zap.String("campaign_id", campaign.ID), zap.Error(err))
+				continue
+			}
+			s.completeIfDone(ctx, campaign)
+			continue
+		}
+		if s.completeIfDone(ctx, campaign) || !withinSchedule(campaign, now) {
+			continue
+		}
+
+		contacts, err := s.repo.ClaimDueContacts(ctx, campaign, DefaultMaxConcurrent, now)
+		if err != nil {
+			logger.Base().Error("Failed to claim campaign contacts", zap.String("campaign_id", campaign.ID), zap.Error(err))
+			continue
+		}
+		for _, contact := range contacts {
+			s.dial(ctx, campaign, contact)
+		}
+	}
+}
+
+// completeIfDone completes a campaign whose contacts all reached a final status
+func (s *Service) completeIfDone(ctx context.Context, campaign *domain.OutboundCampaign) bool {
+	open, err := s.repo.CountOpenContacts(ctx, campaign.ID)
+	if err != nil {
+		logger.Base().Error("Failed to count open campaign contacts", zap.String("campaign_id", campaign.ID), zap.Error(err))
+		return false
+	}
+	if open > 0 {
+		return false
+	}
+
+	completed, err := s.repo.TransitionStatus(ctx, campaign.ID, []string{domain.CampaignStatusRunning}, domain.CampaignStatusCompleted)
+	if err != nil {
+		logger.Base().Error("Failed to complete campaign", zap.String("campaign_id", campaign.ID), zap.Error(err))
+		return false
+	}
+	if completed {
+		logger.Base().Info("Campaign completed", zap.String("campaign_id", campaign.ID))
+	}
+	return true
+}
+
+// dial places a claimed contact's call
+func (s *Service) dial(ctx context.Context, campaign *domain.OutboundCampaign, contact *domain.CampaignContact) {
+	if s.dialer == nil {
+		s.finishAttempt(ctx, contact, domain.DispositionFailed, "outbound calling is not configured")
+		return
+	}
+
+	contactName := contact.Name
+	if contactName == "" {
+		contactName = contact.WAID
+	}
+	result, err := s.dialer.Dial(ctx, DialRequest{
+		AgentID:            campaign.VoiceAgentID,
+		TenantID:           campaign.TenantID,
+		WAID:               contact.WAID,
+		ChannelPhoneNumber: campaign.ChannelPhoneNumber,
+		VoiceLanguage:      campaign.VoiceLanguage,
+		Accent:             campaign.Accent,
+		ContactName:        contactName,
+		Variables:          contact.Variables,
+	})
+	if err != nil {
+		logger.Base().Warn("Campaign call failed",
+			zap.String("campaign_id", campaign.ID),
+			zap.String("contact_id", contact.ID),
+			zap.Error(err))
+		disposition := domain.DispositionFailed
+		if errors.Is(err, ErrPermissionDenied) {
+			disposition = domain.DispositionPermissionDenied
+		}
+		s.finishAttempt(ctx, contact, disposition, err.Error())
+		return
+	}
+
+	contact.ConnectionID = result.ConnectionID
+	contact.CallID = result.CallID
+	if result.AwaitingPermission {
+		contact.Status = domain.ContactStatusAwaitingPermission
+	}
+	if _, err := s.repo.TransitionContact(ctx, contact, []string{domain.ContactStatusDialing}); err != nil {
+		logger.Base().Error("Failed to update campaign contact", zap.String("contact_id", contact.ID), zap.Error(err))
+		return
+	}
+	logger.Base().Info("Campaign call placed",
+		zap.String("campaign_id", campaign.ID),
+		zap.String("contact_id", contact.ID),
+		zap.String("connection_id", result.ConnectionID),
+		zap.Bool("awaiting_permission", result.AwaitingPermission),
+		zap.Int("attempt", contact.Attempts))
+}
+
+// expireStaleAttempts settles answered calls whose end was never reported; calls that were
+// not answered are settled by connection cleanup
+func (s *Service) expireStaleAttempts(ctx context.Context) {
+	contacts, err := s.repo.ListStaleContacts(ctx, domain.ContactStatusInCall, time.Now().Add(-callTimeout))
+	if err != nil {
+		logger.Base().Error("Failed to list stale campaign contacts", zap.String("status", domain.ContactStatusInCall), zap.Error(err))
+		return
+	}
+	for _, contact := range contacts {
+		s.finishAttempt(ctx, contact, domain.DispositionAnswered, "timed out waiting for call status")
+	}
+}
@@ -0,0 +1,218 @@
+package outbound
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/internal/domain"
+	"github.com/ClareAI/astra-voice-service/internal/repository"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidTransition is returned when an attempt's current state does not allow a transition
+var ErrInvalidTransition = errors.New("invalid outbound call state transition")
+
+// expiryInterval is how often stale attempts are looked for
+const expiryInterval = 30 * time.Second
+
+// stateTimeouts bound how long an attempt may stay in a state before it is expired, and
+// the state it is expired to
+var stateTimeouts = []struct {
+	state   string
+	timeout time.Duration
+	to      string
+	reason  string
+}{
+	{domain.OutboundCallStateCreated, 2 * time.Minute, domain.OutboundCallStateFailed, "call was not placed"},
+	{domain.OutboundCallStatePermissionRequested, 24 * time.Hour, domain.OutboundCallStatePermissionDenied, "permission request expired"},
+	{domain.OutboundCallStatePermissionGranted, 2 * time.Minute, domain.OutboundCallStateFailed, "call was not placed after permission was granted"},
+	{domain.OutboundCallStateDialing, 3 * time.Minute, domain.OutboundCallStateFailed, "no call status received"},
+	{domain.OutboundCallStateRinging, 3 * time.Minute, domain.OutboundCallStateEnded, "not answered"},
+	{domain.OutboundCallStateAnswered, 4 * time.Hour, domain.OutboundCallStateEnded, "call end was not reported"},
+}
+
+// Service persists outbound call attempts and their state transitions so every pod sees
+// the same call state
+type Service struct {
+	repo  *repository.OutboundCallRepository
+	podID string
+
+	hooksMu      sync.RWMutex
+	expiredHooks []func(ctx context.Context, attempt *domain.OutboundCallAttempt)
+}
+
+// NewService creates an outbound call state service for the pod with the given ID
+func NewService(repo *repository.OutboundCallRepository, podID string) *Service {
+	return &Service{
+		repo:  repo,
+		podID: podID,
+	}
+}
+
+// PodID returns the ID of this pod
+func (s *Service) PodID() string {
+	return s.podID
+}
+
+// OnExpired registers a hook run after an attempt is expired by the timeout routine, with
+// the attempt as it was before expiry
+func (s *Service) OnExpired(hook func(ctx context.Context, attempt *domain.OutboundCallAttempt)) {
+	s.hooksMu.Lock()
+	defer s.hooksMu.Unlock()
+	s.expiredHooks = append(s.expiredHooks, hook)
+}
+
+// Create records a new attempt owned by this pod
+func (s *Service) Create(ctx context.Context, attempt *domain.OutboundCallAttempt) error {
+	attempt.State = domain.OutboundCallStateCreated
+	attempt.PodID = s.podID
+	return s.repo.Create(ctx, attempt)
+}
+
+// Get retrieves an attempt, returning nil if it does not exist
+func (s *Service) Get(ctx context.Context, id string) (*domain.OutboundCallAttempt, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// GetByCallID retrieves the attempt of a WhatsApp call, returning nil if there is none
+func (s *Service) GetByCallID(ctx context.Context, callID string) (*domain.OutboundCallAttempt, error) {
+	if callID == "" {
+		return nil, nil
+	}
+	return s.repo.GetByCallID(ctx, callID)
+}
+
+// GetAwaitingPermission retrieves the attempt waiting for a user's call permission,
+// returning nil if there is none
+func (s *Service) GetAwaitingPermission(ctx context.Context, waid, channelPhoneNumber string) (*domain.OutboundCallAttempt, error) {
+	return s.repo.GetAwaitingPermission(ctx, waid, channelPhoneNumber)
+}
+
+// Transition moves an attempt to a state, returning ErrInvalidTransition if its current
+// state does not allow it, e.g. because another pod already moved it
+func (s *Service) Transition(ctx context.Context, id, to, lastError string) error {
+	updates := map[string]interface{}{}
+	if lastError != "" {
+		updates["last_error"] = lastError
+	}
+	return s.transition(ctx, id, to, updates)
+}
+
+// MarkDialing records that this pod placed an attempt's call
+func (s *Service) MarkDialing(ctx context.Context, id, callID string) error {
+	return s.transition(ctx, id, domain.OutboundCallStateDialing, map[string]interface{}{
+		"call_id": callID,
+		"pod_id":  s.podID,
+	})
+}
+
+// Fail moves an attempt to failed if it has not reached a final state
+func (s *Service) Fail(ctx context.Context, id string, cause error) {
+	if err := s.Transition(ctx, id, domain.OutboundCallStateFailed, cause.Error()); err != nil && !errors.Is(err, ErrInvalidTransition) {
+		logger.Base().Error("Failed to record failed outbound call", zap.String("attempt_id", id), zap.Error(err))
+	}
+}
+
+func (s *Service) transition(ctx context.Context, id, to string, updates map[string]interface{}) error {
+	moved, err := s.repo.Transition(ctx, id, domain.OutboundCallStatesBefore(to), to, updates)
+	if err != nil {
+		return err
+	}
+	if !moved {
+		current := "missing"
+		if attempt, err := s.repo.GetByID(ctx, id); err == nil && attempt != nil {
+			current = attempt.State
+		}
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, current, to)
+	}
+
+	logger.Base().Info("Outbound call state changed", zap.String("attempt_id", id), zap.String("state", to))
+	return nil
+}
+
+// HandleConnectionEnded settles the attempt that used a connection once it is cleaned up:
+// placed calls end, calls that were never placed fail
+func (s *Service) HandleConnectionEnded(connectionID string, connected bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	attempt, err := s.repo.GetByID(ctx, connectionID)
+	if err != nil {
+		logger.Base().Error("Failed to load outbound call attempt", zap.String("attempt_id", connectionID), zap.Error(err))
+		return
+	}
+	if attempt == nil || domain.IsOutboundCallFinal(attempt.State) {
+		return
+	}
+
+	switch attempt.State {
+	case domain.OutboundCallStateDialing, domain.OutboundCallStateRinging, domain.OutboundCallStateAnswered:
+		err = s.Transition(ctx, attempt.ID, domain.OutboundCallStateEnded, "")
+	case domain.OutboundCallStatePermissionRequested:
+		// Waiting for permission does not need a connection
+		return
+	default:
+		err = s.Transition(ctx, attempt.ID, domain.OutboundCallStateFailed, "connection closed before the call was placed")
+	}
+	if err != nil && !errors.Is(err, ErrInvalidTransition) {
+		logger.Base().Error("Failed to settle outbound call attempt", zap.String("attempt_id", attempt.ID), zap.Error(err))
+	}
+}
+
+// StartTimeoutRoutine expires attempts stuck in a state until ctx is done. Every pod may
+// run it; transitions are guarded so each attempt is expired once.
+func (s *Service) StartTimeoutRoutine(ctx context.Context) {
+	ticker := time.NewTicker(expiryInterval)
+	defer ticker.Stop()
+
+	logger.Base().Info("Started outbound call timeout routine", zap.Duration("interval", expiryInterval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Base().Info("Outbound call timeout routine stopped")
+			return
+		case <-ticker.C:
+			s.expireStaleAttempts(ctx)
+		}
+	}
+}
+
+// expireStaleAttempts moves attempts that outstayed their state's timeout
+func (s *Service) expireStaleAttempts(ctx context.Context) {
+	now := time.Now()
+	for _, rule := range stateTimeouts {
+		attempts, err := s.repo.ListStale(ctx, rule.state, now.Add(-rule.timeout))
+		if err != nil {
+			logger.Base().Error("Failed to list stale outbound calls", zap.String("state", rule.state), zap.Error(err))
+			continue
+		}
+		for _, attempt := range attempts {
+			moved, err := s.repo.Transition(ctx, attempt.ID, []string{rule.state}, rule.to, map[string]interface{}{"last_error": rule.reason})
+			if err != nil {
+				logger.Base().Error("Failed to expire outbound call", zap.String("attempt_id", attempt.ID), zap.Error(err))
+				continue
+			}
+			if !moved {
+				continue
+			}
+
+			logger.Base().Warn("Outbound call expired",
+				zap.String("attempt_id", attempt.ID),
+				zap.String("call_id", attempt.CallID),
+				zap.String("state", attempt.State),
+				zap.String("reason", rule.reason))
+
+			s.hooksMu.RLock()
+			hooks := s.expiredHooks
+			s.hooksMu.RUnlock()
+			for _, hook := range hooks {
+				hook(ctx, attempt)
+			}
+		}
+	}
+}
@@ -0,0 +1,475 @@
+package textchat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/internal/config"
+	"github.com/ClareAI/astra-voice-service/internal/core/tool"
+	"github.com/ClareAI/astra-voice-service/internal/domain"
+	"github.com/ClareAI/astra-voice-service/internal/prompts"
+	"github.com/ClareAI/astra-voice-service/internal/repository"
+	"github.com/ClareAI/astra-voice-service/internal/services/agent"
+	"github.com/ClareAI/astra-voice-service/pkg/data/mcp"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/rag"
+	"github.com/ClareAI/astra-voice-service/pkg/redis"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// sessionTTL is how long an idle session and its preview history are kept
+	sessionTTL = 24 * time.Hour
+
+	// maxToolRounds bounds the tool calls the model may chain within one turn
+	maxToolRounds = 5
+
+	// externalIDPrefix marks text sessions among voice conversations
+	externalIDPrefix = "text_"
+)
+
+// ErrSessionNotFound is returned for unknown or expired sessions
+var ErrSessionNotFound = errors.New("text session not found")
+
+// Session is a text chat with a voice agent. Its ID is the ID of the test-source
+// VoiceConversation its turns are stored in.
+type Session struct {
+	ID            string    `json:"id"`
+	AgentID       string    `json:"agentId"`
+	TextAgentID   string    `json:"textAgentId,omitempty"`
+	TenantID      string    `json:"tenantId,omitempty"`
+	Published     bool      `json:"published"` // published agent config instead of the draft
+	Language      string    `json:"language"`
+	Accent        string    `json:"accent,omitempty"`
+	ContactName   string    `json:"contactName,omitempty"`
+	ContactNumber string    `json:"contactNumber,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+
+	Variables map[string]string `json:"variables,omitempty"` // Per-call variables, as a call would carry
+}
+
+// Sessions implement tool.ToolConnection so agent tools run as they do on a call
+
+func (s *Session) GetFrom() string           { return s.ContactNumber }
+func (s *Session) GetContactName() string    { return s.ContactName }
+func (s *Session) GetTenantID() string       { return s.TenantID }
+func (s *Session) GetBusinessNumber() string { return "" }
+func (s *Session) GetAgentID() string        { return s.AgentID }
+func (s *Session) GetTextAgentID() string    { return s.TextAgentID }
+func (s *Session) GetChannelType() string    { return string(s.channelType()) }
+
+func (s *Session) GetVariables() map[string]string { return s.Variables }
+
+// channelType selects the agent config: test channels use the draft
+func (s *Session) channelType() domain.ChannelType {
+	if s.Published {
+		return domain.ChannelTypeWeb
+	}
+	return domain.ChannelTypeTest
+}
+
+// CreateSessionRequest starts a session
+type CreateSessionRequest struct {
+	AgentID       string `json:"agentId"`
+	Published     bool   `json:"published"`
+	Language      string `json:"language"`
+	Accent        string `json:"accent"`
+	ContactName   string `json:"contactName"`
+	ContactNumber string `json:"contactNumber"`
+
+	Variables map[string]interface{} `json:"variables,omitempty"` // Per-call variables declared by the agent
+}
+
+// Reply is the agent's answer to a turn
+type Reply struct {
+	Text      string       `json:"text"`
+	ToolCalls []ToolResult `json:"toolCalls,omitempty"`
+}
+
+// ToolResult is a tool the agent called while answering
+type ToolResult struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+	Result    string `json:"result,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Event reports progress of a streamed turn
+type Event struct {
+	Type string      `json:"type"` // EventDelta or EventToolCall
+	Text string      `json:"text,omitempty"`
+	Tool *ToolResult `json:"tool,omitempty"`
+}
+
+// Event types
+const (
+	EventDelta    = "delta"     // a fragment of the agent's reply
+	EventToolCall = "tool_call" // a tool finished
+)
+
+// Service runs text sessions against the same agent config, instructions, tools and
+// knowledge base as voice calls, through a text chat model
+type Service struct {
+	agentService *agent.AgentService
+	toolManager  *tool.ToolManager
+	ragProcessor *rag.AgentRAGProcessor
+	redisSvc     *redis.RedisService
+	repoManager  repository.RepositoryManager
+	client       *ChatClient
+}
+
+// NewService creates the text session service. ragProcessor and repoManager are optional.
+func NewService(agentService *agent.AgentService, composioService *mcp.ComposioService, ragProcessor *rag.AgentRAGProcessor, redisSvc *redis.RedisService, repoManager repository.RepositoryManager, client *ChatClient) *Service {
+	s := &Service{
+		agentService: agentService,
+		ragProcessor: ragProcessor,
+		redisSvc:     redisSvc,
+		repoManager:  repoManager,
+		client:       client,
+	}
+
+	// A tool manager of our own, resolving session IDs where voice resolves connection IDs
+	s.toolManager = tool.NewToolManager()
+	s.toolManager.ComposioService = composioService
+	s.toolManager.ConnectionGetter = func(sessionID string) tool.ToolConnection {
+		session, err := s.loadSession(context.Background(), sessionID)
+		if err != nil {
+			return nil
+		}
+		return session
+	}
+	return s
+}
+
+// CreateSession starts a session and returns the agent's greeting
+func (s *Service) CreateSession(ctx context.Context, req CreateSessionRequest, onEvent func(Event)) (*Session, *Reply, error) {
+	if req.AgentID == "" {
+		return nil, nil, errors.New("agentId is required")
+	}
+	session := &Session{
+		AgentID:       req.AgentID,
+		Published:     req.Published,
+		Language:      req.Language,
+		Accent:        req.Accent,
+		ContactName:   req.ContactName,
+		ContactNumber: req.ContactNumber,
+		CreatedAt:     time.Now(),
+	}
+	agentConfig, err := s.agentService.GetAgentConfigWithChannelType(ctx, req.AgentID, session.channelType())
+	if err != nil || agentConfig == nil {
+		return nil, nil, fmt.Errorf("agent %s not found: %w", req.AgentID, err)
+	}
+	session.AgentID = agentConfig.ID
+	session.TextAgentID = agentConfig.TextAgentID
+	if session.Variables, err = config.ResolveCallVariables(agentConfig.CallVariables(false), req.Variables); err != nil {
+		return nil, nil, err
+	}
+	if session.Language == "" {
+		session.Language = agentConfig.Language
+	}
+	if session.Language == "" {
+		session.Language = config.DefaultLanguage
+	}
+	if s.repoManager != nil {
+		if voiceAgent, err := s.repoManager.VoiceAgent().GetByID(ctx, session.AgentID); err == nil && voiceAgent != nil {
+			session.TenantID = voiceAgent.VoiceTenantID
+		}
+	}
+
+	session.ID, err = s.createConversation(ctx, session)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.saveSession(ctx, session); err != nil {
+		return nil, nil, err
+	}
+
+	// The agent opens the conversation as it does on an inbound call
+	generator := prompts.NewAgentPromptGenerator(agentConfig)
+	generator.Variables = session.Variables
+	messages := []chatMessage{
+		{Role: config.MessageRoleSystem, Content: s.instructions(generator, session)},
+		{Role: config.MessageRoleSystem, Content: generator.GenerateGreetingInstruction(session.ContactName, session.ContactNumber, session.Language, session.Accent)},
+	}
+	greeting, err := s.client.complete(ctx, messages, nil, deltaHandler(onEvent))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate greeting: %w", err)
+	}
+
+	s.recordTurn(ctx, session, []redis.PreviewMessage{{Role: config.MessageRoleAssistant, Content: greeting.Content}})
+	logger.Base().Info("Text session created",
+		zap.String("session_id", session.ID),
+		zap.String("agent_id", session.AgentID),
+		zap.Bool("published", session.Published))
+	return session, &Reply{Text: greeting.Content}, nil
+}
+
+// SendMessage runs one user turn and returns the agent's reply
+func (s *Service) SendMessage(ctx context.Context, sessionID, text string, onEvent func(Event)) (*Reply, error) {
+	if text == "" {
+		return nil, errors.New("text is required")
+	}
+	session, err := s.loadSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	agentConfig, err := s.agentService.GetAgentConfigWithChannelType(ctx, session.AgentID, session.channelType())
+	if err != nil || agentConfig == nil {
+		return nil, fmt.Errorf("agent %s not found: %w", session.AgentID, err)
+	}
+	history, err := s.redisSvc.GetPreviewHistory(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// New entries of the preview history: knowledge context, the user's message, tool
+	// results and the reply
+	var turn []redis.PreviewMessage
+	if s.ragProcessor != nil {
+		if shouldCallRAG, ragContext, _ := s.ragProcessor.ProcessUserInputWithChannelType(text, sessionID, session.AgentID, session.channelType()); shouldCallRAG {
+			turn = append(turn, redis.PreviewMessage{Role: config.MessageRoleSystem, Content: ragContext + knowledgeGuidelines, Name: "knowledge"})
+		}
+	}
+	turn = append(turn, redis.PreviewMessage{Role: config.MessageRoleUser, Content: text})
+
+	generator := prompts.NewAgentPromptGenerator(agentConfig)
+	generator.Variables = session.Variables
+	messages := []chatMessage{{Role: config.MessageRoleSystem, Content: s.instructions(generator, session)}}
+	for _, message := range append(history, turn...) {
+		messages = append(messages, chatMessage{Role: message.Role, Content: message.Content, Name: message.Name})
+	}
+	tools := s.toolDefinitions(ctx, session)
+
+	reply := &Reply{}
+	for round := 0; ; round++ {
+		// Past the limit the model must answer without tools
+		if round == maxToolRounds {
+			tools = nil
+		}
+		answer, err := s.client.complete(ctx, messages, tools, deltaHandler(onEvent))
+		if err != nil {
+			return nil, err
+		}
+		if len(answer.ToolCalls) == 0 {
+			reply.Text = answer.Content
+			break
+		}
+
+		messages = append(messages, *answer)
+		for _, call := range answer.ToolCalls {
+			result := s.executeTool(session, call)
+			reply.ToolCalls = append(reply.ToolCalls, result)
+			if onEvent != nil {
+				onEvent(Event{Type: EventToolCall, Tool: &result})
+			}
+
+			content := result.Result
+			if result.Error != "" {
+				content = fmt.Sprintf(`{"success": false, "error": %q}`, result.Error)
+			}
+			messages = append(messages, chatMessage{Role: "tool", Content: content, ToolCallID: call.ID})
+			// The preview history has no tool messages; later turns see the result as a note
+			turn = append(turn, redis.PreviewMessage{
+				Role:    config.MessageRoleSystem,
+				Content: fmt.Sprintf("Tool %s was called with %s and returned: %s", call.Function.Name, call.Function.Arguments, content),
+				Name:    "tool_result",
+			})
+		}
+	}
+
+	turn = append(turn, redis.PreviewMessage{Role: config.MessageRoleAssistant, Content: reply.Text})
+	s.recordTurn(ctx, session, turn)
+	return reply, nil
+}
+
+// GetSession returns a session and its history
+func (s *Service) GetSession(ctx context.Context, sessionID string) (*Session, []redis.PreviewMessage, error) {
+	session, err := s.loadSession(ctx, sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	history, err := s.redisSvc.GetPreviewHistory(ctx, sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return session, history, nil
+}
+
+// EndSession closes the session's conversation and drops its preview history
+func (s *Service) EndSession(ctx context.Context, sessionID string) error {
+	if _, err := s.loadSession(ctx, sessionID); err != nil {
+		return err
+	}
+	if s.repoManager != nil {
+		conversation, err := s.repoManager.VoiceConversation().GetByID(ctx, sessionID)
+		if err == nil && conversation != nil {
+			if err := s.repoManager.VoiceConversation().EndConversation(ctx, conversation); err != nil {
+				logger.Base().Warn("Failed to end text session conversation", zap.String("session_id", sessionID), zap.Error(err))
+			}
+		}
+	}
+	if err := s.redisSvc.ClearPreviewHistory(ctx, sessionID); err != nil {
+		logger.Base().Warn("Failed to clear preview history", zap.String("session_id", sessionID), zap.Error(err))
+	}
+	return s.redisSvc.DelValue(ctx, s.redisSvc.GenerateKey(redis.PREVIEW_SESSION, sessionID))
+}
+
+// instructions builds the session instructions a voice call with the same contact gets
+func (s *Service) instructions(generator *prompts.AgentPromptGenerator, session *Session) string {
+	return generator.GenerateSessionInstructions(session.ContactNumber, session.Language, session.Accent, false)
+}
+
+// toolDefinitions lists the agent's MCP tools in Chat Completions format
+func (s *Service) toolDefinitions(ctx context.Context, session *Session) []interface{} {
+	mcpAgentID := session.AgentID
+	if session.TextAgentID != "" {
+		mcpAgentID = session.TextAgentID
+	}
+	mode := config.AgentConfigModeDraft
+	if session.Published {
+		mode = config.AgentConfigModePublished
+	}
+	definitions, err := s.toolManager.GetMcpToolDefinitions(ctx, mcpAgentID, mode, mcp.ModalityText)
+	if err != nil {
+		logger.Base().Warn("Failed to fetch tools for text session", zap.String("session_id", session.ID), zap.Error(err))
+		return nil
+	}
+
+	// Realtime tools are flat; Chat Completions nests the function
+	tools := make([]interface{}, 0, len(definitions))
+	for _, definition := range definitions {
+		flat, ok := definition.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		tools = append(tools, map[string]interface{}{
+			"type": "function",
+			"function": map[string]interface{}{
+				"name":        flat["name"],
+				"description": flat["description"],
+				"parameters":  flat["parameters"],
+			},
+		})
+	}
+	return tools
+}
+
+// executeTool runs a tool call through the tool manager
+func (s *Service) executeTool(session *Session, call toolCall) ToolResult {
+	result := ToolResult{Name: call.Function.Name, Arguments: call.Function.Arguments}
+	output, err := s.toolManager.ExecuteTool(call.Function.Name, call.Function.Arguments, session.ID, mcp.ModalityText)
+	if err != nil {
+		logger.Base().Warn("Text session tool call failed", zap.String("session_id", session.ID), zap.String("tool", call.Function.Name), zap.Error(err))
+		result.Error = err.Error()
+		return result
+	}
+	result.Result = output
+	return result
+}
+
+// createConversation creates the session's test-source conversation and returns its ID
+func (s *Service) createConversation(ctx context.Context, session *Session) (string, error) {
+	if s.repoManager == nil {
+		return uuid.New().String(), nil
+	}
+	conversation := &domain.VoiceConversation{
+		ExternalConversationID: externalIDPrefix + uuid.New().String(),
+		VoiceAgentID:           session.AgentID,
+		ContactName:            session.ContactName,
+		ContactNumber:          session.ContactNumber,
+		StartedAt:              session.CreatedAt,
+		EndedAt:                session.CreatedAt,
+		Source:                 domain.ConversationSourceTest,
+		Variables:              domain.StringMapJSONB(session.Variables),
+	}
+	if err := s.repoManager.VoiceConversation().Create(ctx, conversation); err != nil {
+		return "", err
+	}
+	return conversation.ID, nil
+}
+
+// recordTurn appends a turn to the preview history and stores its user and assistant
+// messages in the conversation
+func (s *Service) recordTurn(ctx context.Context, session *Session, turn []redis.PreviewMessage) {
+	if err := s.redisSvc.AppendPreviewHistory(ctx, session.ID, turn, sessionTTL); err != nil {
+		logger.Base().Error("Failed to append preview history", zap.String("session_id", session.ID), zap.Error(err))
+	}
+	// Keep the session alive as long as its history
+	if err := s.saveSession(ctx, session); err != nil {
+		logger.Base().Warn("Failed to refresh text session", zap.String("session_id", session.ID), zap.Error(err))
+	}
+	if s.repoManager == nil {
+		return
+	}
+
+	for _, message := range turn {
+		if message.Role == config.MessageRoleSystem {
+			continue
+		}
+		confidence := 0.0
+		if message.Role != config.MessageRoleUser {
+			confidence = 100.0
+		}
+		voiceMessage := &domain.VoiceMessage{
+			ID:             uuid.New().String(),
+			ConversationID: session.ID,
+			Role:           message.Role,
+			Content:        message.Content,
+			Confidence:     confidence,
+			CreatedAt:      time.Now(),
+		}
+		if err := s.repoManager.VoiceMessage().Create(ctx, voiceMessage); err != nil {
+			logger.Base().Error("Failed to store text session message", zap.String("session_id", session.ID), zap.Error(err))
+		}
+	}
+}
+
+// saveSession stores the session, refreshing its expiry
+func (s *Service) saveSession(ctx context.Context, session *Session) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal text session: %w", err)
+	}
+	if err := s.redisSvc.SetValue(ctx, s.redisSvc.GenerateKey(redis.PREVIEW_SESSION, session.ID), string(data), sessionTTL); err != nil {
+		return fmt.Errorf("failed to store text session: %w", err)
+	}
+	return nil
+}
+
+// loadSession reads a session from Redis
+func (s *Service) loadSession(ctx context.Context, sessionID string) (*Session, error) {
+	data, err := s.redisSvc.GetValue(ctx, s.redisSvc.GenerateKey(redis.PREVIEW_SESSION, sessionID))
+	if errors.Is(err, redis.ErrKeyNotExist) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load text session: %w", err)
+	}
+	var session Session
+	if err := json.Unmarshal([]byte(data), &session); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal text session: %w", err)
+	}
+	return &session, nil
+}
+
+// deltaHandler adapts an event callback to the model's text deltas
+func deltaHandler(onEvent func(Event)) func(string) {
+	if onEvent == nil {
+		return nil
+	}
+	return func(text string) {
+		onEvent(Event{Type: EventDelta, Text: text})
+	}
+}
+
+// knowledgeGuidelines follow injected knowledge base context, as on voice calls
+const knowledgeGuidelines = `
+
+📚 KNOWLEDGE GUIDELINES:
+- Base your answers on the knowledge above
+- If knowledge is insufficient, say so honestly
+- Maintain language consistency`
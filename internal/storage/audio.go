@@ -1,18 +1,23 @@
 package storage
 
 import (
-	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	"github.com/ClareAI/astra-voice-service/pkg/gcs"
+	"github.com/ClareAI/astra-voice-service/internal/domain"
+	"github.com/ClareAI/astra-voice-service/internal/repository"
 	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/metrics"
+	"github.com/ClareAI/astra-voice-service/pkg/objectstore"
 	"github.com/pion/rtp"
 	"go.uber.org/zap"
 )
@@ -43,36 +48,83 @@ type StorageType string
 const (
 	StorageTypeLocal StorageType = "local"
 	StorageTypeGCS   StorageType = "gcs"
+	StorageTypeS3    StorageType = "s3"
 )
 
-// AudioCacheService handles asynchronous audio caching to local or GCS storage
+// ObjectStoreConfig returns the object store configuration for a storage type and path.
+// Local paths are resolved under /tmp; for GCS and S3 the path is the bucket name.
+// S3 credentials and endpoint must be filled in by the caller.
+func ObjectStoreConfig(storageType StorageType, storagePath string) objectstore.Config {
+	return objectstore.Config{
+		Backend: objectstore.Backend(storageType),
+		Bucket:  storagePath,
+		RootDir: filepath.Join(tmpStoragePath, storagePath),
+	}
+}
+
+// AudioCacheService handles asynchronous audio caching to the configured object store
 type AudioCacheService struct {
 	storageType StorageType
-	gcsClient   *gcs.GCSClient
+	store       objectstore.Store
 	enabled     bool
-	storagePath string // Local directory or GCS bucket name
 	ctx         context.Context
 	cancel      context.CancelFunc
 
 	// Audio processing configuration
+	// Volume multipliers are applied to decoded WAV/MP3 exports only; the stereo Opus mux
+	// copies frames without re-encoding
 	leftChannelVolume  float64 // Volume multiplier for left channel (WhatsApp input)
 	rightChannelVolume float64 // Volume multiplier for right channel (model output)
 
-	// Connection-based chunks (merged mode - all audio in one file)
+	// Recent raw chunks per connection, kept for GetAudioDataRange (bounded by recordingRecentWindow)
 	chunks map[string](map[string][]*audioChunk) // connectionID -> ordered chunks
 	mu     sync.RWMutex
 
+	// Recordings being written incrementally to spool files
+	streams  map[string]*recordingStream // connectionID -> stream, guarded by mu
+	spoolDir string
+
 	// Reference counting for cleanup (ensure both input and output streams finish)
 	refCounts sync.Map // map[string]*int32 - lock-free atomic operations
 
-	// Base timestamps for each audio type per connection
-	baseTimestamps sync.Map // map[string]*connectionBaseTimestamps
-
 	// Cleanup timer for old temporary files
 	cleanupTicker *time.Ticker
 
+	// Flush timer moving recorded audio from memory to the spool files
+	flushTicker *time.Ticker
+
 	// Connection ID to Conversation ID mapping
 	connectionToConversation sync.Map // map[string]string
+
+	// Extra export formats per connection, resolved from tenant configuration
+	recordingFormats sync.Map // map[string][]RecordingFormat
+
+	// Tenant and agent owning each connection's recording, attached to stored objects
+	recordingOwners sync.Map // map[string]recordingOwner
+
+	// Registers produced recording files against their conversation (optional)
+	recordingRepo *repository.VoiceRecordingRepository
+}
+
+// recordingOwner identifies the tenant and agent a recording belongs to
+type recordingOwner struct {
+	tenantID string
+	agentID  string
+}
+
+// recordingTarget identifies where a recording's files are stored and registered
+type recordingTarget struct {
+	name     string // Conversation ID, or the connection ID when the conversation is unknown
+	register bool   // Whether files are registered in voice_recordings
+	metadata objectstore.Metadata
+}
+
+// recordingInfo describes a stored recording file for registration
+type recordingInfo struct {
+	format     RecordingFormat
+	channels   int
+	sampleRate int
+	duration   time.Duration
 }
 
 // audioChunk represents a chunk of audio with timestamp for ordering
@@ -94,60 +146,94 @@ func NewAudioCacheServiceWithVolume(ctx context.Context, storageType StorageType
 		return &AudioCacheService{enabled: false}, nil
 	}
 
+	store, err := objectstore.New(ctx, ObjectStoreConfig(storageType, storagePath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s object store: %v", storageType, err)
+	}
+
+	return NewAudioCacheServiceWithStore(ctx, store, filepath.Join(tmpStoragePath, storagePath, "spool"), leftVolume, rightVolume), nil
+}
+
+// NewAudioCacheServiceWithStore creates a new audio cache service storing recordings in store.
+// spoolDir holds in-progress recordings; it should be local to the pod and survive restarts.
+func NewAudioCacheServiceWithStore(ctx context.Context, store objectstore.Store, spoolDir string, leftVolume, rightVolume float64) *AudioCacheService {
 	serviceCtx, cancel := context.WithCancel(ctx)
 
 	service := &AudioCacheService{
-		storageType:        storageType,
+		storageType:        StorageType(store.Backend()),
+		store:              store,
 		enabled:            true,
-		storagePath:        storagePath,
 		ctx:                serviceCtx,
 		cancel:             cancel,
 		leftChannelVolume:  leftVolume,
 		rightChannelVolume: rightVolume,
 		chunks:             make(map[string]map[string][]*audioChunk),
+		streams:            make(map[string]*recordingStream),
+		spoolDir:           spoolDir,
 		// connectionToConversation is sync.Map, no initialization needed
 	}
 
-	// Initialize storage backend
-	switch storageType {
-	case StorageTypeGCS:
-		// Create GCS client
-		gcsClient, err := gcs.NewGCSClient(ctx, storagePath)
-		if err != nil {
-			cancel()
-			return nil, fmt.Errorf("failed to create GCS client: %v", err)
-		}
-		service.gcsClient = gcsClient
-		logger.Base().Info("☁ Audio cache service started (GCS bucket: )", zap.String("storagepath", storagePath))
-
-	case StorageTypeLocal:
-		// Create local storage directory if it doesn't exist
-		storagePath = filepath.Join(tmpStoragePath, storagePath)
-		if err := os.MkdirAll(storagePath, 0755); err != nil {
-			cancel()
-			return nil, fmt.Errorf("failed to create local storage directory: %v", err)
-		}
-		logger.Base().Info("💾 Audio cache service started (Local path: )", zap.String("storagepath", storagePath))
+	logger.Base().Info("Audio cache service started", zap.String("storage_type", string(service.storageType)), zap.String("spool_dir", spoolDir), zap.String("mode", "merged"))
 
-	default:
-		cancel()
-		return nil, fmt.Errorf("unsupported storage type: %s", storageType)
-	}
-
-	logger.Base().Info("Audio cache service started", zap.String("storage_type", string(storageType)), zap.String("mode", "merged"))
-
-	// Start cleanup timer
+	// Start cleanup and spool flush timers
 	service.startCleanupTimer()
+	service.startFlushTimer()
 
-	return service, nil
+	return service
 }
 
 // SetConversationID sets the conversation ID for a connection
 func (s *AudioCacheService) SetConversationID(connectionID, conversationID string) {
 	s.connectionToConversation.Store(connectionID, conversationID)
+	s.updateStreamMeta(connectionID, func(meta *recordingMeta) {
+		meta.ConversationID = conversationID
+	})
 	logger.Base().Info("Set conversation ID for connection", zap.String("conversation_id", conversationID), zap.String("connection_id", connectionID))
 }
 
+// SetRecordingFormats sets the extra formats exported for a connection's recording
+func (s *AudioCacheService) SetRecordingFormats(connectionID string, formats []RecordingFormat) {
+	if len(formats) == 0 {
+		s.recordingFormats.Delete(connectionID)
+	} else {
+		s.recordingFormats.Store(connectionID, formats)
+	}
+	s.updateStreamMeta(connectionID, func(meta *recordingMeta) {
+		meta.Formats = formats
+	})
+}
+
+// SetRecordingOwner sets the tenant and agent a connection's recording belongs to.
+// They are attached as metadata to the stored objects and used in the object names.
+func (s *AudioCacheService) SetRecordingOwner(connectionID, tenantID, agentID string) {
+	s.recordingOwners.Store(connectionID, recordingOwner{tenantID: tenantID, agentID: agentID})
+	s.updateStreamMeta(connectionID, func(meta *recordingMeta) {
+		meta.TenantID = tenantID
+		meta.AgentID = agentID
+	})
+}
+
+// SetRecordingRepository enables registering produced recordings against their conversation
+func (s *AudioCacheService) SetRecordingRepository(repo *repository.VoiceRecordingRepository) {
+	s.recordingRepo = repo
+}
+
+// updateStreamMeta updates the persisted metadata of a connection's active recording
+func (s *AudioCacheService) updateStreamMeta(connectionID string, fn func(meta *recordingMeta)) {
+	if !s.enabled {
+		return
+	}
+	s.mu.RLock()
+	stream, exists := s.streams[connectionID]
+	s.mu.RUnlock()
+	if !exists {
+		return
+	}
+	if err := stream.updateMeta(fn); err != nil {
+		logger.Base().Warn("Failed to update recording metadata", zap.String("connection_id", connectionID), zap.Error(err))
+	}
+}
+
 // GetConversationID gets the conversation ID for a connection
 func (s *AudioCacheService) GetConversationID(connectionID string) string {
 	if conversationID, exists := s.connectionToConversation.Load(connectionID); exists {
@@ -181,7 +267,43 @@ func (s *AudioCacheService) CacheAudioRTP(connectionID string, audioType AudioTy
 		s.chunks[connectionID] = make(map[string][]*audioChunk)
 	}
 	s.chunks[connectionID][string(audioType)] = append(s.chunks[connectionID][string(audioType)], chunk)
+	stream := s.getOrCreateStreamLocked(connectionID, chunk.timestamp)
 	s.mu.Unlock()
+
+	if stream != nil {
+		stream.add(audioType, &normalizedPacket, chunk.timestamp)
+	}
+}
+
+// getOrCreateStreamLocked returns the connection's recording stream, starting it on the first
+// packet. Callers must hold s.mu. Returns nil if the spool file cannot be created.
+func (s *AudioCacheService) getOrCreateStreamLocked(connectionID string, startedAt time.Time) *recordingStream {
+	if stream, exists := s.streams[connectionID]; exists {
+		return stream
+	}
+
+	meta := recordingMeta{ConnectionID: connectionID, StartedAt: startedAt}
+	if conversationID, ok := s.connectionToConversation.Load(connectionID); ok {
+		meta.ConversationID = conversationID.(string)
+	}
+	if formats, ok := s.recordingFormats.Load(connectionID); ok {
+		meta.Formats = formats.([]RecordingFormat)
+	}
+	if owner, ok := s.recordingOwners.Load(connectionID); ok {
+		meta.TenantID = owner.(recordingOwner).tenantID
+		meta.AgentID = owner.(recordingOwner).agentID
+	}
+
+	stream, err := newRecordingStream(s.spoolDir, meta)
+	if err != nil {
+		// Retried on the next packet; audio received until then is not recorded
+		logger.Base().Error("Failed to start recording spool", zap.String("connection_id", connectionID), zap.Error(err))
+		s.recordUploadFailure("spool")
+		return nil
+	}
+	s.streams[connectionID] = stream
+	logger.Base().Info("Recording spool started", zap.String("connection_id", connectionID), zap.String("path", stream.spoolPath))
+	return stream
 }
 
 // IncrementReference increments the reference count for a connection to prevent premature cleanup
@@ -222,37 +344,14 @@ func (s *AudioCacheService) CleanupConnection(connectionID string) {
 	// If this was the last reference, queue for upload
 	if newCount == 0 {
 		logger.Base().Info("Queueing", zap.String("connection_id", connectionID))
-		go s.uploadAudio(connectionID)
+		go s.finalizeRecording(connectionID)
 	} else if newCount < 0 {
 		logger.Base().Warn("Negative ref count", zap.Int32("new_count", newCount), zap.String("connection_id", connectionID))
 	}
 }
 
-// setRTPTimestamps sets timestamps for RTP packets based on earliest time
-func (s *AudioCacheService) setRTPTimestamps(chunks []*audioChunk, earliestTime time.Time, delay uint32) []*rtp.Packet {
-	if len(chunks) == 0 {
-		return nil
-	}
-
-	rtpPackets := make([]*rtp.Packet, len(chunks))
-	firstRTPTimestamp := chunks[0].rtpPacket.Timestamp
-	firstTimestamp := uint32(chunks[0].timestamp.Sub(earliestTime).Milliseconds()) * 48
-
-	for i, chunk := range chunks {
-		pkt := *chunk.rtpPacket
-		pkt.SequenceNumber = uint16(i)
-		if i == 0 {
-			pkt.Timestamp = firstTimestamp + delay
-		} else {
-			pkt.Timestamp = uint32(pkt.Timestamp - firstRTPTimestamp + firstTimestamp + delay)
-		}
-		rtpPackets[i] = &pkt
-	}
-
-	return rtpPackets
-}
-
-// cleanupOldCache removes cached chunks older than 30 minutes
+// cleanupOldCache finalizes recordings that have not received audio for 30 minutes,
+// e.g. when a connection was never cleaned up
 func (s *AudioCacheService) cleanupOldCache() {
 	if !s.enabled {
 		return
@@ -260,38 +359,21 @@ func (s *AudioCacheService) cleanupOldCache() {
 
 	cutoffTime := time.Now().Add(-30 * time.Minute)
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	var cleanedConnections []string
-
-	for connectionID, chunks := range s.chunks {
-		// Check if any chunk in this connection is older than cutoff time
-		shouldCleanup := false
-		for _, audioTypeChunks := range chunks {
-			for _, chunk := range audioTypeChunks {
-				if chunk.timestamp.Before(cutoffTime) {
-					shouldCleanup = true
-					break
-				}
-			}
-			if shouldCleanup {
-				break
-			}
+	s.mu.RLock()
+	var staleConnections []string
+	for connectionID, stream := range s.streams {
+		if stream.idleSince().Before(cutoffTime) {
+			staleConnections = append(staleConnections, connectionID)
 		}
+	}
+	s.mu.RUnlock()
 
-		if shouldCleanup {
-			cleanedConnections = append(cleanedConnections, connectionID)
-			// Remove chunks, reference count, base timestamps, and conversation mapping
-			delete(s.chunks, connectionID)
-			s.refCounts.Delete(connectionID)
-			s.baseTimestamps.Delete(connectionID)
-			s.connectionToConversation.Delete(connectionID)
-		}
+	for _, connectionID := range staleConnections {
+		s.finalizeRecording(connectionID)
 	}
 
-	if len(cleanedConnections) > 0 {
-		logger.Base().Info("Cleaned old connections", zap.Int("count", len(cleanedConnections)))
+	if len(staleConnections) > 0 {
+		logger.Base().Info("Cleaned old connections", zap.Int("count", len(staleConnections)))
 	}
 }
 
@@ -321,226 +403,330 @@ func (s *AudioCacheService) startCleanupTimer() {
 	logger.Base().Info("🧹 Started cleanup timer (10min interval)")
 }
 
-// mergeAudioWithFFmpeg merges left and right channel audio files using ffmpeg
-func (s *AudioCacheService) mergeAudioWithFFmpeg(leftPath, rightPath, outputPath string) error {
-	// Ensure output directory exists
-	outputDir := filepath.Dir(outputPath)
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory %s: %v", outputDir, err)
+// startFlushTimer starts the periodic spool flush
+func (s *AudioCacheService) startFlushTimer() {
+	if !s.enabled {
+		return
 	}
 
-	// Build volume filter strings
-	leftVolumeFilter := ""
-	rightVolumeFilter := ""
-
-	if s.leftChannelVolume != 1.0 {
-		leftVolumeFilter = fmt.Sprintf(",volume=%.2f", s.leftChannelVolume)
-	}
-	if s.rightChannelVolume != 1.0 {
-		rightVolumeFilter = fmt.Sprintf(",volume=%.2f", s.rightChannelVolume)
-	}
+	s.flushTicker = time.NewTicker(recordingFlushInterval)
 
-	filterComplex := fmt.Sprintf("[0:a]aresample=48000,pan=mono|c0=c0%s[left]; [1:a]aresample=48000,pan=mono|c0=c0%s[right]; [left][right]join=inputs=2:channel_layout=stereo[aout]",
-		leftVolumeFilter, rightVolumeFilter)
+	go func() {
+		defer s.flushTicker.Stop()
 
-	cmd := exec.Command("ffmpeg",
-		"-i", leftPath,
-		"-i", rightPath,
-		"-filter_complex", filterComplex,
-		"-map", "[aout]",
-		"-c:a", "libopus",
-		"-y", // Overwrite output file
-		outputPath)
+		for {
+			select {
+			case <-s.flushTicker.C:
+				s.flushStreams()
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}()
+}
 
-	// Set timeout for ffmpeg command
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-	defer cancel()
-	cmd = exec.CommandContext(ctx, cmd.Args[0], cmd.Args[1:]...)
+// flushStreams writes settled audio of every active recording to its spool file and
+// trims raw chunks that are no longer needed for GetAudioDataRange
+func (s *AudioCacheService) flushStreams() {
+	now := time.Now().UTC()
 
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Base().Error("FFmpeg merge failed", zap.String("output", string(output)), zap.Error(err))
-		return fmt.Errorf("ffmpeg merge failed: %v", err)
+	s.mu.RLock()
+	streams := make(map[string]*recordingStream, len(s.streams))
+	for connectionID, stream := range s.streams {
+		streams[connectionID] = stream
 	}
+	s.mu.RUnlock()
 
-	logger.Base().Info("FFmpeg completed", zap.String("outputpath", outputPath))
-	return nil
-}
+	for connectionID, stream := range streams {
+		if err := stream.flush(now); err != nil {
+			logger.Base().Error("Failed to flush recording spool", zap.String("connection_id", connectionID), zap.Error(err))
+			s.recordUploadFailure("spool")
+		}
+	}
 
-// uploadAudio prepares and uploads audio for a connection
-func (s *AudioCacheService) uploadAudio(connectionID string) {
-	// Extract data while holding the lock (minimize lock duration)
+	cutoffTime := now.Add(-recordingRecentWindow)
 	s.mu.Lock()
-	chunks, exists := s.chunks[connectionID]
-	if !exists || len(chunks) == 0 {
-		s.mu.Unlock()
-		return
+	for connectionID, connChunks := range s.chunks {
+		for audioType, chunks := range connChunks {
+			keep := 0
+			for keep < len(chunks) && chunks[keep].timestamp.Before(cutoffTime) {
+				keep++
+			}
+			if keep == len(chunks) {
+				delete(connChunks, audioType)
+			} else if keep > 0 {
+				connChunks[audioType] = append([]*audioChunk(nil), chunks[keep:]...)
+			}
+		}
+		if len(connChunks) == 0 {
+			delete(s.chunks, connectionID)
+		}
 	}
+	s.mu.Unlock()
+}
 
-	// Make copies of the chunk slices to avoid holding the lock during processing
-	whatsappChunks := make([]*audioChunk, len(chunks[string(AudioTypeWhatsAppInput)]))
-	copy(whatsappChunks, chunks[string(AudioTypeWhatsAppInput)])
-
-	aiChunks := make([]*audioChunk, len(chunks[string(AudioTypeAIOutput)]))
-	copy(aiChunks, chunks[string(AudioTypeAIOutput)])
-
-	// Get conversation ID while holding lock
-	conversationID := s.GetConversationID(connectionID)
-
-	// Clean up data immediately after extracting
+// finalizeRecording closes a connection's recording stream and assembles the final files
+func (s *AudioCacheService) finalizeRecording(connectionID string) {
+	s.mu.Lock()
+	stream, exists := s.streams[connectionID]
+	delete(s.streams, connectionID)
 	delete(s.chunks, connectionID)
 	s.refCounts.Delete(connectionID)
-	s.baseTimestamps.Delete(connectionID)
 	s.connectionToConversation.Delete(connectionID)
+	s.recordingFormats.Delete(connectionID)
+	s.recordingOwners.Delete(connectionID)
 	s.mu.Unlock()
 
-	// Process audio without holding the lock
-	// 找到最早的接收时间作为基准
-	var earliestTime, latestTime time.Time
-	allChunks := append(whatsappChunks, aiChunks...)
+	if !exists {
+		return
+	}
 
-	for _, chunk := range allChunks {
-		if earliestTime.IsZero() || chunk.timestamp.Before(earliestTime) {
-			earliestTime = chunk.timestamp
-		}
-		if chunk.timestamp.After(latestTime) {
-			latestTime = chunk.timestamp
+	if err := stream.finish(); err != nil {
+		// Whatever already reached the spool file is still assembled
+		logger.Base().Error("Failed to finish recording spool", zap.String("connection_id", connectionID), zap.Error(err))
+		s.recordUploadFailure("spool")
+	}
+	if dropped := stream.droppedPackets(); dropped > 0 {
+		logger.Base().Warn("Dropped unalignable Opus packets from stereo recording", zap.String("connection_id", connectionID), zap.Int("dropped", dropped))
+	}
+
+	s.assembleRecording(stream.spoolPath, stream.metadata())
+}
+
+// assembleRecording builds the final recording files from a spool file, stores them and
+// removes the spool. The spool is kept if the Opus recording could not be stored.
+// The spool is streamed to the object store in bounded chunks rather than read whole.
+func (s *AudioCacheService) assembleRecording(spoolPath string, meta recordingMeta) {
+	file, err := os.Open(spoolPath)
+	if err != nil {
+		logger.Base().Error("Failed to read recording spool", zap.String("path", spoolPath), zap.Error(err))
+		s.recordUploadFailure("read")
+		if os.IsNotExist(err) {
+			os.Remove(metaPath(spoolPath))
 		}
+		return
+	}
+	totalSlots, err := countStereoOggSlots(file)
+	file.Close()
+	if err != nil || totalSlots == 0 {
+		logger.Base().Error("Discarding unreadable recording spool", zap.String("path", spoolPath), zap.Int("slots", totalSlots), zap.Error(err))
+		s.recordUploadFailure("read")
+		removeSpool(spoolPath)
+		return
+	}
+
+	// Recordings without a known conversation are stored under the connection ID and not registered
+	target := recordingTarget{
+		name:     meta.ConversationID,
+		register: meta.ConversationID != "",
+		metadata: objectstore.Metadata{
+			TenantID:       meta.TenantID,
+			AgentID:        meta.AgentID,
+			ConversationID: meta.ConversationID,
+		},
 	}
+	if !target.register {
+		target.name = meta.ConnectionID
+	}
+	duration := time.Duration(totalSlots) * 20 * time.Millisecond
 
-	logger.Base().Info("Audio statistics", zap.Int("whatsapp_chunks", len(whatsappChunks)), zap.Int("ai_chunks", len(aiChunks)), zap.Duration("duration", latestTime.Sub(earliestTime)))
-	logger.Base().Info("Using conversation ID for connection", zap.String("conversation_id", conversationID), zap.String("connection_id", connectionID))
+	logger.Base().Info("Assembling recording", zap.String("conversation_id", target.name), zap.String("connection_id", meta.ConnectionID), zap.Duration("duration", duration))
 
-	// 创建RTP包并设置统一时间戳
-	whatsappRTPPackets := s.setRTPTimestamps(whatsappChunks, earliestTime, 0)
-	aiRTPPackets := s.setRTPTimestamps(aiChunks, earliestTime, delayRtpTimestamp)
-	// Create proper Ogg Opus container using Pion OGG writer
-	encoder := NewOggOpusEncoder()
+	fileName := fmt.Sprintf("conversation_%s_merged.opus", target.name)
 
-	// 创建分离的左右声道文件：确保时序正确
-	// 左声道文件：WhatsApp输入 + 静音填充
-	// 右声道文件：静音填充 + 模型输出
+	// Re-mux the spool so the stored file is a clean, contiguous stream
+	stored := s.storeRecording(target, fileName, recordingInfo{
+		format:     RecordingFormatOpus,
+		channels:   2,
+		sampleRate: opusSampleRate,
+		duration:   duration,
+	}, func(w io.Writer) error {
+		writer, err := newStereoOggWriter(w)
+		if err != nil {
+			return err
+		}
+		return readSpool(spoolPath, totalSlots, func(left, right *stereoTimeline, from, until int) error {
+			return writer.writeSlots(left, right, until, until == totalSlots)
+		})
+	})
+	if !stored {
+		logger.Base().Warn("Keeping recording spool for retry", zap.String("path", spoolPath))
+		return
+	}
 
-	// 计算总时长
-	totalDuration := latestTime.Sub(earliestTime) + 2000*time.Millisecond // 添加2s缓冲
-	if totalDuration < 100*time.Millisecond {
-		totalDuration = 100 * time.Millisecond // 最小时长100ms
+	// Additional formats configured for the tenant (decoded from the same spool)
+	if len(meta.Formats) > 0 {
+		s.exportRecording(target, meta.Formats, spoolPath, totalSlots)
 	}
 
-	logger.Base().Info("Total duration", zap.Duration("duration", totalDuration))
+	removeSpool(spoolPath)
+}
+
+// spoolChunkSlots bounds the slots held in memory while a spool is read back (10s)
+const spoolChunkSlots = 10 * oggPagePackets
 
-	// 创建左声道文件（WhatsApp输入 + 静音填充）
-	leftChannelData, err := encoder.CreateMonoOggOpusFile(whatsappRTPPackets, totalDuration)
+// readSpool reads the first totalSlots slots of a spool file in chunks of spoolChunkSlots,
+// calling fn with timelines holding the slots in [from, until). Slots missing from the
+// file are left empty.
+func readSpool(spoolPath string, totalSlots int, fn func(left, right *stereoTimeline, from, until int) error) error {
+	file, err := os.Open(spoolPath)
 	if err != nil {
-		logger.Base().Error("Left channel failed")
-		return
+		return fmt.Errorf("failed to open recording spool: %w", err)
 	}
+	defer file.Close()
 
-	// 创建右声道文件（静音填充 + 模型输出）
-	rightChannelData, err := encoder.CreateMonoOggOpusFile(aiRTPPackets, totalDuration)
+	reader, err := newStereoOggReader(file)
 	if err != nil {
-		logger.Base().Error("Right channel failed")
-		return
+		return err
 	}
 
-	// Check if we have valid data before proceeding
-	if len(leftChannelData) == 0 || len(rightChannelData) == 0 {
-		logger.Base().Warn("Empty data", zap.String("connection_id", connectionID))
-		return
+	left, right := newStereoTimeline(0), newStereoTimeline(0)
+	for from := 0; from < totalSlots; {
+		until := min(from+spoolChunkSlots, totalSlots)
+		reader.readSlots(left, right, until-from)
+		if err := fn(left, right, from, until); err != nil {
+			return err
+		}
+		left.release(until)
+		right.release(until)
+		from = until
 	}
+	return nil
+}
 
-	logger.Base().Info("Uploading audio", zap.String("connection_id", connectionID), zap.Int("left_bytes", len(leftChannelData)), zap.Int("right_bytes", len(rightChannelData)), zap.Int("total_bytes", len(leftChannelData)+len(rightChannelData)))
-
-	// Generate file paths for separate channels and merged output
-	// Format: whatsappaudio/conversation_{connectionID}_left.opus
-	// Format: whatsappaudio/conversation_{connectionID}_right.opus
-	// Format: whatsappaudio/conversation_{connectionID}_merged.opus
-	var leftPath, rightPath, mergedPath string
-	defer func() {
-		if leftPath != "" {
-			os.Remove(leftPath)
-		}
-		if rightPath != "" {
-			os.Remove(rightPath)
+// removeSpool deletes a spool file and its metadata sidecar
+func removeSpool(spoolPath string) {
+	for _, path := range []string{spoolPath, metaPath(spoolPath)} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			logger.Base().Warn("Failed to remove recording spool", zap.String("path", path), zap.Error(err))
 		}
-		if mergedPath != "" && s.storageType != StorageTypeLocal {
-			os.Remove(mergedPath)
-		}
-	}()
-	leftRelativePath := fmt.Sprintf("whatsappcall/conversation_%s_left.opus", conversationID)
-	rightRelativePath := fmt.Sprintf("whatsappcall/conversation_%s_right.opus", conversationID)
-	mergedRelativePath := fmt.Sprintf("whatsappcall/conversation_%s_merged.opus", conversationID)
-	mergedPath = filepath.Join(tmpStoragePath, s.storagePath, mergedRelativePath)
-
-	// Upload left and right channel files to temp directory
-	leftPath, err = s.uploadToLocal(conversationID, leftChannelData, leftRelativePath)
-	if err != nil {
-		logger.Base().Error("Failed to upload left channel audio to local")
-		return
 	}
-	rightPath, err = s.uploadToLocal(conversationID, rightChannelData, rightRelativePath)
-	if err != nil {
-		logger.Base().Error("Failed to upload right channel audio to local")
+}
+
+// RecoverSpooledRecordings assembles recordings left in the spool directory by a previous
+// process, e.g. after a pod restart in the middle of a call
+func (s *AudioCacheService) RecoverSpooledRecordings() {
+	if !s.enabled {
 		return
 	}
 
-	// Merge left and right channels using ffmpeg
-	err = s.mergeAudioWithFFmpeg(leftPath, rightPath, mergedPath)
+	s.mu.RLock()
+	spoolDir := s.spoolDir
+	s.mu.RUnlock()
+
+	metaFiles, err := filepath.Glob(filepath.Join(spoolDir, "*"+spoolMetaExt))
 	if err != nil {
-		logger.Base().Error("Failed to merge audio channels")
+		logger.Base().Error("Failed to list recording spool", zap.String("dir", spoolDir), zap.Error(err))
 		return
 	}
 
-	// Upload merged file based on storage type
-	switch s.storageType {
-	case StorageTypeGCS:
-		// Read merged file and upload to GCS
-		mergedData, err := os.ReadFile(mergedPath)
+	recovered := 0
+	for _, metaFile := range metaFiles {
+		data, err := os.ReadFile(metaFile)
 		if err != nil {
-			logger.Base().Error("Failed to read merged audio file")
-			return
+			logger.Base().Warn("Failed to read recording metadata", zap.String("path", metaFile), zap.Error(err))
+			continue
 		}
-		s.uploadToGCS(conversationID, mergedData, mergedRelativePath)
-	default:
-		return
+		var meta recordingMeta
+		if err := json.Unmarshal(data, &meta); err != nil {
+			logger.Base().Warn("Failed to decode recording metadata", zap.String("path", metaFile), zap.Error(err))
+			continue
+		}
+
+		// Skip recordings this process is still writing
+		s.mu.RLock()
+		_, active := s.streams[meta.ConnectionID]
+		s.mu.RUnlock()
+		if active {
+			continue
+		}
+
+		spoolPath := strings.TrimSuffix(metaFile, spoolMetaExt) + spoolAudioExt
+		s.assembleRecording(spoolPath, meta)
+		recovered++
+	}
+
+	if recovered > 0 {
+		logger.Base().Info("Recovered spooled recordings", zap.Int("count", recovered))
 	}
 }
 
-// uploadToGCS uploads audio to GCS using the pkg GCS client
-func (s *AudioCacheService) uploadToGCS(conversationID string, data []byte, objectPath string) {
-	logger.Base().Info("💾 Uploading to GCS", zap.String("conversationid", conversationID))
+// storeRecording streams the recording file produced by write to the object store under
+// recordings/{tenant}/{conversation}/{fileName} and, when the conversation is known,
+// registers it against the conversation. Returns whether the file was stored.
+func (s *AudioCacheService) storeRecording(target recordingTarget, fileName string, info recordingInfo, write func(w io.Writer) error) bool {
+	key := objectstore.ObjectKey("recordings", target.metadata, fileName)
+
+	// The file is encoded while it is uploaded, so it is never held in memory as a whole
+	pipeReader, pipeWriter := io.Pipe()
+	encodeErr := make(chan error, 1)
+	go func() {
+		err := write(pipeWriter)
+		pipeWriter.CloseWithError(err)
+		encodeErr <- err
+	}()
+	content := &countingReader{r: pipeReader}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
-	defer cancel()
+	location, err := s.store.Put(ctx, key, content, objectstore.Attributes{
+		ContentType: recordingContentType(info.format),
+		Metadata:    target.metadata,
+	})
+	cancel()
+	// Unblocks the encoder if the store stopped reading early
+	pipeReader.Close()
 
-	reader := bytes.NewReader(data)
-	url, err := s.gcsClient.Upload(ctx, objectPath, reader)
+	if writeErr := <-encodeErr; writeErr != nil && !errors.Is(writeErr, io.ErrClosedPipe) {
+		logger.Base().Error("Failed to encode recording", zap.String("key", key), zap.String("format", string(info.format)), zap.Error(writeErr))
+		s.recordUploadFailure("encode")
+		return false
+	}
 	if err != nil {
-		logger.Base().Error("Failed to upload channel audio to GCS")
-		return
+		logger.Base().Error("Failed to store recording", zap.String("storage_type", string(s.storageType)), zap.String("key", key), zap.Error(err))
+		s.recordUploadFailure("upload")
+		return false
 	}
+	logger.Base().Info("Stored recording", zap.String("location", location), zap.Int64("bytes", content.n), zap.String("conversation_id", target.name))
 
-	logger.Base().Info("Uploaded to GCS", zap.String("url", url), zap.Int("bytes", len(data)), zap.String("conversation_id", conversationID))
-}
+	if !target.register || s.recordingRepo == nil {
+		return true
+	}
 
-// uploadToLocal uploads audio to local filesystem
-func (s *AudioCacheService) uploadToLocal(conversationID string, data []byte, relativePath string) (string, error) {
-	fullPath := filepath.Join(tmpStoragePath, s.storagePath, relativePath)
-	logger.Base().Info("💾 Writing to", zap.String("conversationid", conversationID), zap.String("fullpath", fullPath))
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	dir := filepath.Dir(fullPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		logger.Base().Error("Failed to create directory", zap.String("dir", dir))
-		return "", err
-	}
+	recording := &domain.VoiceRecording{
+		ConversationID: target.name,
+		Format:         string(info.format),
+		Channels:       info.channels,
+		SampleRate:     info.sampleRate,
+		DurationMs:     info.duration.Milliseconds(),
+		SizeBytes:      content.n,
+		StorageType:    string(s.storageType),
+		Location:       location,
+	}
+	if err := s.recordingRepo.Create(ctx, recording); err != nil {
+		logger.Base().Error("Failed to register recording", zap.String("conversation_id", target.name), zap.String("format", recording.Format), zap.Error(err))
+		s.recordUploadFailure("register")
+	}
+	return true
+}
 
-	if err := os.WriteFile(fullPath, data, 0644); err != nil {
-		logger.Base().Error("Failed to write channel audio to local file")
-		return "", err
-	}
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
 
-	logger.Base().Info("Saved to local file", zap.String("path", fullPath), zap.Int("bytes", len(data)), zap.String("conversation_id", conversationID))
-	return fullPath, nil
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// recordUploadFailure counts a failed recording upload at the given stage.
+func (s *AudioCacheService) recordUploadFailure(stage string) {
+	metrics.RecordingUploadFailuresTotal.WithLabelValues(string(s.storageType), stage).Inc()
 }
 
 // GetAudioDataRange retrieves audio data for a specific connection and time range
@@ -603,11 +789,11 @@ func (s *AudioCacheService) Close() error {
 	// Cancel context to stop all operations
 	s.cancel()
 
-	// Upload all pending chunks before cleanup
+	// Finalize all active recordings before cleanup
 	s.mu.Lock()
-	pendingConnections := len(s.chunks)
+	pendingConnections := len(s.streams)
 	connectionIDs := make([]string, 0, pendingConnections)
-	for connectionID := range s.chunks {
+	for connectionID := range s.streams {
 		connectionIDs = append(connectionIDs, connectionID)
 	}
 	s.mu.Unlock()
@@ -617,8 +803,8 @@ func (s *AudioCacheService) Close() error {
 		logger.Base().Info("Uploading pending audio connections before shutdown", zap.Int("pending_connections", pendingConnections))
 
 		for _, connectionID := range connectionIDs {
-			// Upload each connection's audio data
-			s.uploadAudio(connectionID)
+			// Assemble and upload each connection's recording
+			s.finalizeRecording(connectionID)
 		}
 
 		logger.Base().Info("Uploaded pending audio connections", zap.Int("pending_connections", pendingConnections))
@@ -635,11 +821,8 @@ func (s *AudioCacheService) Close() error {
 		return true
 	})
 
-	// Close GCS client if it exists
-	if s.gcsClient != nil {
-		if err := s.gcsClient.Close(); err != nil {
-			logger.Base().Error("Error closing GCS client")
-		}
+	if err := s.store.Close(); err != nil {
+		logger.Base().Error("Error closing object store", zap.Error(err))
 	}
 
 	logger.Base().Info("Audio cache service shut down", zap.Int("uploaded_pending_connections", pendingConnections))
@@ -685,3 +868,9 @@ func InitAudioCacheWithVolume(ctx context.Context, enabled bool, storageType Sto
 	logger.Base().Info("Audio cache initialized with volume", zap.Float64("left_volume", leftVolume), zap.Float64("right_volume", rightVolume))
 	return nil
 }
+
+// InitAudioCacheWithStore initializes the global audio cache instance with an existing object store
+func InitAudioCacheWithStore(ctx context.Context, store objectstore.Store, spoolDir string, leftVolume, rightVolume float64) {
+	SetAudioCache(NewAudioCacheServiceWithStore(ctx, store, spoolDir, leftVolume, rightVolume))
+	logger.Base().Info("Audio cache initialized with object store", zap.String("backend", string(store.Backend())), zap.Float64("left_volume", leftVolume), zap.Float64("right_volume", rightVolume))
+}
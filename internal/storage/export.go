@@ -0,0 +1,259 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/mp3"
+	"go.uber.org/zap"
+	"layeh.com/gopus"
+)
+
+// RecordingFormat is an output format produced for a call recording
+type RecordingFormat string
+
+const (
+	RecordingFormatOpus      RecordingFormat = "opus"       // Stereo Ogg Opus (always produced)
+	RecordingFormatWAVMono   RecordingFormat = "wav_mono"   // PCM16 WAV with caller and agent mixed down
+	RecordingFormatWAVStereo RecordingFormat = "wav_stereo" // PCM16 WAV, caller left / agent right
+	RecordingFormatMP3       RecordingFormat = "mp3"        // Stereo MP3, caller left / agent right
+)
+
+const (
+	exportSampleRate     = 24000 // Covers the model's output bandwidth, half the size of 48kHz
+	exportFrameSamples   = exportSampleRate / 50
+	exportMP3BitrateKbps = 64
+)
+
+// ParseRecordingFormats converts configured format names into export formats.
+// Opus is always produced and unknown names are skipped with a warning.
+func ParseRecordingFormats(names []string) []RecordingFormat {
+	var formats []RecordingFormat
+	seen := make(map[RecordingFormat]bool)
+	for _, name := range names {
+		format := RecordingFormat(strings.ToLower(strings.TrimSpace(name)))
+		switch format {
+		case RecordingFormatOpus:
+			continue
+		case RecordingFormatMP3:
+			if !mp3.Available {
+				logger.Base().Warn("Ignoring mp3 recording format, the service was built without the lame tag")
+				continue
+			}
+			if !seen[format] {
+				seen[format] = true
+				formats = append(formats, format)
+			}
+		case RecordingFormatWAVMono, RecordingFormatWAVStereo:
+			if !seen[format] {
+				seen[format] = true
+				formats = append(formats, format)
+			}
+		default:
+			logger.Base().Warn("Ignoring unknown recording format", zap.String("format", name))
+		}
+	}
+	return formats
+}
+
+// exportRecording decodes both channels of the spool through gopus and streams each
+// requested format next to the Opus recording. Channel volumes are applied to the
+// decoded audio.
+func (s *AudioCacheService) exportRecording(target recordingTarget, formats []RecordingFormat, spoolPath string, totalSlots int) {
+	for _, format := range formats {
+		var fileName string
+		channels := 2
+
+		switch format {
+		case RecordingFormatWAVMono:
+			channels = 1
+			fileName = fmt.Sprintf("conversation_%s_mono.wav", target.name)
+		case RecordingFormatWAVStereo:
+			fileName = fmt.Sprintf("conversation_%s_stereo.wav", target.name)
+		case RecordingFormatMP3:
+			fileName = fmt.Sprintf("conversation_%s.mp3", target.name)
+		default:
+			continue
+		}
+
+		s.storeRecording(target, fileName, recordingInfo{
+			format:     format,
+			channels:   channels,
+			sampleRate: exportSampleRate,
+			duration:   time.Duration(totalSlots) * 20 * time.Millisecond,
+		}, func(w io.Writer) error {
+			return s.encodeExport(w, format, channels, spoolPath, totalSlots)
+		})
+	}
+}
+
+// encodeExport decodes the spool chunk by chunk and writes it to w in the given format,
+// so memory use does not grow with the length of the call
+func (s *AudioCacheService) encodeExport(w io.Writer, format RecordingFormat, channels int, spoolPath string, totalSlots int) error {
+	decoder, err := newStereoPCMDecoder()
+	if err != nil {
+		return err
+	}
+
+	var encoder *mp3.Encoder
+	if format == RecordingFormatMP3 {
+		encoder, err = mp3.NewEncoder(exportSampleRate, channels, exportMP3BitrateKbps)
+		if err != nil {
+			return fmt.Errorf("failed to create mp3 encoder: %w", err)
+		}
+		defer encoder.Close()
+	} else if err := writeWAVHeader(w, totalSlots*exportFrameSamples*channels, exportSampleRate, channels); err != nil {
+		return err
+	}
+
+	err = readSpool(spoolPath, totalSlots, func(left, right *stereoTimeline, from, until int) error {
+		leftPCM, rightPCM := decoder.decode(left, right, from, until)
+		applyGain(leftPCM, s.leftChannelVolume)
+		applyGain(rightPCM, s.rightChannelVolume)
+
+		var pcm []int16
+		if channels == 1 {
+			pcm = mixToMono(leftPCM, rightPCM)
+		} else {
+			pcm = interleaveStereo(leftPCM, rightPCM)
+		}
+
+		if encoder == nil {
+			return binary.Write(w, binary.LittleEndian, pcm)
+		}
+		data, err := encoder.Encode(pcm)
+		if err != nil {
+			return fmt.Errorf("mp3 encode failed: %w", err)
+		}
+		_, err = w.Write(data)
+		return err
+	})
+	if err != nil || encoder == nil {
+		return err
+	}
+
+	data, err := encoder.Flush()
+	if err != nil {
+		return fmt.Errorf("mp3 flush failed: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// recordingContentType returns the MIME type stored with a recording file
+func recordingContentType(format RecordingFormat) string {
+	switch format {
+	case RecordingFormatWAVMono, RecordingFormatWAVStereo:
+		return "audio/wav"
+	case RecordingFormatMP3:
+		return "audio/mpeg"
+	default:
+		return "audio/ogg"
+	}
+}
+
+// stereoPCMDecoder decodes both channel timelines to PCM16 at exportSampleRate.
+// Decoder state carries over between chunks of the same recording.
+type stereoPCMDecoder struct {
+	left  *gopus.Decoder
+	right *gopus.Decoder
+}
+
+func newStereoPCMDecoder() (*stereoPCMDecoder, error) {
+	left, err := gopus.NewDecoder(exportSampleRate, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create opus decoder: %v", err)
+	}
+	right, err := gopus.NewDecoder(exportSampleRate, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create opus decoder: %v", err)
+	}
+	return &stereoPCMDecoder{left: left, right: right}, nil
+}
+
+// decode returns equal-length PCM16 buffers for slots [from, until) with silence in the gaps
+func (d *stereoPCMDecoder) decode(left, right *stereoTimeline, from, until int) ([]int16, []int16) {
+	return decodeChannelPCM(d.left, left, from, until), decodeChannelPCM(d.right, right, from, until)
+}
+
+// decodeChannelPCM decodes one channel's slots in [from, until); undecodable frames become silence
+func decodeChannelPCM(decoder *gopus.Decoder, timeline *stereoTimeline, from, until int) []int16 {
+	pcm := make([]int16, (until-from)*exportFrameSamples)
+	for slot := from; slot < until; slot++ {
+		packet, ok := timeline.slots[slot]
+		if !ok {
+			continue
+		}
+		samples, err := decoder.Decode(appendOpusPacket(nil, packet, false), exportFrameSamples, false)
+		if err != nil {
+			continue
+		}
+		offset := (slot - from) * exportFrameSamples
+		copy(pcm[offset:offset+exportFrameSamples], samples)
+	}
+	return pcm
+}
+
+// applyGain scales samples in place with clipping
+func applyGain(pcm []int16, gain float64) {
+	if gain == 1.0 || gain <= 0 {
+		return
+	}
+	for i, sample := range pcm {
+		pcm[i] = clampInt16(float64(sample) * gain)
+	}
+}
+
+// mixToMono sums both channels with clipping; caller and agent rarely overlap
+func mixToMono(left, right []int16) []int16 {
+	mono := make([]int16, len(left))
+	for i := range mono {
+		mono[i] = clampInt16(float64(left[i]) + float64(right[i]))
+	}
+	return mono
+}
+
+// interleaveStereo interleaves left and right samples (L R L R ...)
+func interleaveStereo(left, right []int16) []int16 {
+	stereo := make([]int16, len(left)*2)
+	for i := range left {
+		stereo[2*i] = left[i]
+		stereo[2*i+1] = right[i]
+	}
+	return stereo
+}
+
+func clampInt16(v float64) int16 {
+	return int16(math.Max(math.MinInt16, math.Min(math.MaxInt16, v)))
+}
+
+// writeWAVHeader writes a canonical 44-byte WAV header for sampleCount interleaved
+// PCM16 samples; the samples follow as little-endian int16
+func writeWAVHeader(w io.Writer, sampleCount, sampleRate, channels int) error {
+	dataSize := sampleCount * 2
+	blockAlign := channels * 2
+
+	var buffer bytes.Buffer
+	buffer.Grow(44)
+	buffer.WriteString("RIFF")
+	binary.Write(&buffer, binary.LittleEndian, uint32(36+dataSize))
+	buffer.WriteString("WAVE")
+	buffer.WriteString("fmt ")
+	binary.Write(&buffer, binary.LittleEndian, uint32(16))                    // fmt chunk size
+	binary.Write(&buffer, binary.LittleEndian, uint16(1))                     // PCM
+	binary.Write(&buffer, binary.LittleEndian, uint16(channels))              // channel count
+	binary.Write(&buffer, binary.LittleEndian, uint32(sampleRate))            // sample rate
+	binary.Write(&buffer, binary.LittleEndian, uint32(sampleRate*blockAlign)) // byte rate
+	binary.Write(&buffer, binary.LittleEndian, uint16(blockAlign))            // block align
+	binary.Write(&buffer, binary.LittleEndian, uint16(16))                    // bits per sample
+	buffer.WriteString("data")
+	binary.Write(&buffer, binary.LittleEndian, uint32(dataSize))
+	_, err := w.Write(buffer.Bytes())
+	return err
+}
@@ -6,8 +6,10 @@ import (
 	"sort"
 	"time"
 
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
 	"github.com/pion/rtp"
 	"github.com/pion/webrtc/v4/pkg/media/oggwriter"
+	"go.uber.org/zap"
 )
 
 // OggOpusEncoder handles encoding PCM samples into Ogg Opus format using Pion
@@ -61,44 +63,21 @@ func (e *OggOpusEncoder) CreateOggOpusFileWithChannels(rtpPackets []*rtp.Packet,
 	return buffer.Bytes(), nil
 }
 
-// CreateStereoOggOpusFile creates a stereo Ogg Opus file with time-aligned left and right channel data
-func (e *OggOpusEncoder) CreateStereoOggOpusFile(leftChannel, rightChannel []*rtp.Packet) ([]byte, error) {
-	// Create a buffer to write the OGG file
-	var buffer bytes.Buffer
-
-	// Create OGG writer for stereo Opus (2 channels)
-	oggWriter, err := oggwriter.NewWith(&buffer, 48000, 2) // 48kHz, stereo
-	if err != nil {
-		return nil, fmt.Errorf("failed to create OGG writer: %v", err)
-	}
-	defer oggWriter.Close()
-
-	// 合并两个声道，按时间戳排序，创建时间对齐的立体声
-	allPackets := make([]*rtp.Packet, 0, len(leftChannel)+len(rightChannel))
-
-	// 添加左声道包（WhatsApp输入）
-	for _, pkt := range leftChannel {
-		if pkt != nil && len(pkt.Payload) > 0 {
-			allPackets = append(allPackets, pkt)
-		}
+// CreateStereoOggOpusFile creates a stereo Ogg Opus file with the left channel (WhatsApp input)
+// and right channel (model output) aligned on a common 20ms timeline. Frames are copied without
+// re-encoding and gaps on either side are filled with silence up to totalDuration.
+func (e *OggOpusEncoder) CreateStereoOggOpusFile(leftChannel, rightChannel []*rtp.Packet, totalDuration time.Duration) ([]byte, error) {
+	if len(leftChannel) == 0 && len(rightChannel) == 0 {
+		return nil, fmt.Errorf("no RTP packets to process")
 	}
 
-	for _, pkt := range rightChannel {
-		if pkt != nil && len(pkt.Payload) > 0 {
-			allPackets = append(allPackets, pkt)
-		}
+	var buffer bytes.Buffer
+	dropped, err := muxStereoOggOpus(&buffer, leftChannel, rightChannel, totalDuration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mux stereo OGG: %v", err)
 	}
-
-	// 按时间戳排序，确保正确的时序
-	sort.Slice(allPackets, func(i, j int) bool {
-		return allPackets[i].Timestamp < allPackets[j].Timestamp
-	})
-
-	// 写入排序后的包
-	for _, rtpPacket := range allPackets {
-		if err := oggWriter.WriteRTP(rtpPacket); err != nil {
-			return nil, fmt.Errorf("failed to write RTP packet: %v", err)
-		}
+	if dropped > 0 {
+		logger.Base().Warn("Dropped unalignable Opus packets from stereo recording", zap.Int("dropped", dropped))
 	}
 
 	return buffer.Bytes(), nil
@@ -0,0 +1,712 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/pion/rtp"
+)
+
+// The stereo recording is an Ogg Opus stream using channel mapping family 1 with two
+// uncoupled mono streams (RFC 7845 §5.1.1): stream 0 is the caller (left), stream 1 the
+// model (right). Opus frames are copied without re-encoding, so no codec is needed.
+
+const (
+	opusSampleRate  = 48000
+	opusSlotSamples = 960 // 20ms grid both channels are aligned to
+	opusPreSkip     = 0   // frames are copied as-is, nothing to discard
+	oggPagePackets  = 50  // ~1s of audio per Ogg page
+	oggMaxSegments  = 255
+	oggVendorString = "astra-voice-service"
+)
+
+// opusSilencePacket is a 20ms CELT fullband mono frame that decodes to digital silence
+var opusSilencePacket = opusPacket{toc: 0xF8, frames: [][]byte{{0xFF, 0xFE}}}
+
+// opusPacket is a parsed Opus packet: the TOC byte (code bits cleared) and its compressed frames
+type opusPacket struct {
+	toc    byte
+	frames [][]byte
+}
+
+// samples returns the packet duration at 48kHz
+func (p opusPacket) samples() int {
+	return opusFrameSamples(p.toc) * len(p.frames)
+}
+
+// opusFrameSamples returns the duration of a single frame for the TOC configuration (RFC 6716 §3.1)
+func opusFrameSamples(toc byte) int {
+	config := toc >> 3
+	switch {
+	case config < 12: // SILK: 10, 20, 40, 60ms
+		return [...]int{480, 960, 1920, 2880}[config%4]
+	case config < 16: // Hybrid: 10, 20ms
+		return [...]int{480, 960}[config%2]
+	default: // CELT: 2.5, 5, 10, 20ms
+		return [...]int{120, 240, 480, 960}[config%4]
+	}
+}
+
+// readOpusFrameLength decodes a 1-2 byte frame length (RFC 6716 §3.2.1)
+func readOpusFrameLength(data []byte) (length int, used int, err error) {
+	if len(data) < 1 {
+		return 0, 0, fmt.Errorf("truncated frame length")
+	}
+	if data[0] < 252 {
+		return int(data[0]), 1, nil
+	}
+	if len(data) < 2 {
+		return 0, 0, fmt.Errorf("truncated frame length")
+	}
+	return int(data[0]) + 4*int(data[1]), 2, nil
+}
+
+// appendOpusFrameLength encodes a frame length (RFC 6716 §3.2.1)
+func appendOpusFrameLength(dst []byte, length int) []byte {
+	if length < 252 {
+		return append(dst, byte(length))
+	}
+	first := 252 + (length & 3)
+	return append(dst, byte(first), byte((length-first)>>2))
+}
+
+// parseOpusPacket splits an Opus packet into its frames (RFC 6716 §3.2)
+func parseOpusPacket(data []byte) (opusPacket, error) {
+	if len(data) < 1 {
+		return opusPacket{}, fmt.Errorf("empty opus packet")
+	}
+	pkt := opusPacket{toc: data[0] &^ 0x03}
+	code := data[0] & 0x03
+	data = data[1:]
+
+	switch code {
+	case 0: // one frame
+		pkt.frames = [][]byte{data}
+
+	case 1: // two equal-size frames
+		if len(data)%2 != 0 {
+			return opusPacket{}, fmt.Errorf("odd payload for code 1 packet")
+		}
+		half := len(data) / 2
+		pkt.frames = [][]byte{data[:half], data[half:]}
+
+	case 2: // two frames, first length explicit
+		length, used, err := readOpusFrameLength(data)
+		if err != nil {
+			return opusPacket{}, err
+		}
+		data = data[used:]
+		if length > len(data) {
+			return opusPacket{}, fmt.Errorf("frame length %d exceeds packet", length)
+		}
+		pkt.frames = [][]byte{data[:length], data[length:]}
+
+	case 3: // arbitrary number of frames
+		if len(data) < 1 {
+			return opusPacket{}, fmt.Errorf("missing frame count byte")
+		}
+		count := int(data[0] & 0x3F)
+		vbr := data[0]&0x80 != 0
+		hasPadding := data[0]&0x40 != 0
+		data = data[1:]
+		if count == 0 {
+			return opusPacket{}, fmt.Errorf("zero frame count")
+		}
+
+		if hasPadding {
+			padding := 0
+			for {
+				if len(data) < 1 {
+					return opusPacket{}, fmt.Errorf("truncated padding length")
+				}
+				b := data[0]
+				data = data[1:]
+				if b == 255 {
+					padding += 254
+					continue
+				}
+				padding += int(b)
+				break
+			}
+			if padding > len(data) {
+				return opusPacket{}, fmt.Errorf("padding exceeds packet")
+			}
+			data = data[:len(data)-padding]
+		}
+
+		lengths := make([]int, count)
+		if vbr {
+			total := 0
+			for i := 0; i < count-1; i++ {
+				length, used, err := readOpusFrameLength(data)
+				if err != nil {
+					return opusPacket{}, err
+				}
+				data = data[used:]
+				lengths[i] = length
+				total += length
+			}
+			if total > len(data) {
+				return opusPacket{}, fmt.Errorf("frame lengths exceed packet")
+			}
+			lengths[count-1] = len(data) - total
+		} else {
+			if len(data)%count != 0 {
+				return opusPacket{}, fmt.Errorf("uneven payload for CBR packet")
+			}
+			for i := range lengths {
+				lengths[i] = len(data) / count
+			}
+		}
+
+		pkt.frames = make([][]byte, count)
+		for i, length := range lengths {
+			pkt.frames[i] = data[:length]
+			data = data[length:]
+		}
+	}
+
+	return pkt, nil
+}
+
+// appendOpusPacket serializes the packet; selfDelimited adds the trailing frame length
+// required for every stream but the last in a multistream packet (RFC 6716 Appendix B)
+func appendOpusPacket(dst []byte, p opusPacket, selfDelimited bool) []byte {
+	if len(p.frames) == 1 {
+		dst = append(dst, p.toc)
+		if selfDelimited {
+			dst = appendOpusFrameLength(dst, len(p.frames[0]))
+		}
+		return append(dst, p.frames[0]...)
+	}
+
+	// Code 3 VBR: explicit lengths for all frames but the last (and the last too when self-delimited)
+	dst = append(dst, p.toc|0x03, 0x80|byte(len(p.frames)))
+	for i, frame := range p.frames {
+		if i < len(p.frames)-1 || selfDelimited {
+			dst = appendOpusFrameLength(dst, len(frame))
+		}
+	}
+	for _, frame := range p.frames {
+		dst = append(dst, frame...)
+	}
+	return dst
+}
+
+// stereoTimeline places one channel's packets onto the 20ms slot grid
+type stereoTimeline struct {
+	slots    map[int]opusPacket
+	minSlot  int // slots below this were already written out
+	maxSlots int // exclusive upper bound, 0 for unbounded
+	last     int // highest occupied slot, -1 when empty
+	dropped  int
+}
+
+// newStereoTimeline creates an empty timeline; maxSlots 0 leaves it unbounded
+func newStereoTimeline(maxSlots int) *stereoTimeline {
+	return &stereoTimeline{slots: make(map[int]opusPacket), maxSlots: maxSlots, last: -1}
+}
+
+// release forgets all slots below until and rejects packets that arrive for them later
+func (t *stereoTimeline) release(until int) {
+	for slot := range t.slots {
+		if slot < until {
+			delete(t.slots, slot)
+		}
+	}
+	if until > t.minSlot {
+		t.minSlot = until
+	}
+}
+
+// buildStereoTimeline parses a channel's RTP packets and assigns them to slots by timestamp.
+// Anything at or beyond maxSlots is dropped and left to silence fill.
+func buildStereoTimeline(packets []*rtp.Packet, maxSlots int) *stereoTimeline {
+	timeline := newStereoTimeline(maxSlots)
+
+	sorted := make([]*rtp.Packet, 0, len(packets))
+	for _, pkt := range packets {
+		if pkt != nil && len(pkt.Payload) > 0 {
+			sorted = append(sorted, pkt)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp < sorted[j].Timestamp
+	})
+
+	for _, pkt := range sorted {
+		timeline.addPacket(pkt.Timestamp, pkt.Payload)
+	}
+
+	return timeline
+}
+
+// addPacket places an Opus packet by its 48kHz timestamp. 20ms packets fill one slot,
+// multi-frame packets of 20ms frames are split across consecutive slots, anything that
+// cannot be aligned to the grid is dropped and left to silence fill.
+func (t *stereoTimeline) addPacket(timestamp uint32, payload []byte) {
+	parsed, err := parseOpusPacket(payload)
+	if err != nil {
+		t.dropped++
+		return
+	}
+	slot := int((timestamp + opusSlotSamples/2) / opusSlotSamples)
+
+	switch {
+	case parsed.samples() == opusSlotSamples:
+		t.place(slot, parsed)
+	case opusFrameSamples(parsed.toc) == opusSlotSamples:
+		for i, frame := range parsed.frames {
+			t.place(slot+i, opusPacket{toc: parsed.toc, frames: [][]byte{frame}})
+		}
+	default:
+		t.dropped++
+	}
+}
+
+// place stores a single-frame packet, absorbing small timestamp jitter by moving into the next free slot
+func (t *stereoTimeline) place(slot int, p opusPacket) {
+	if _, taken := t.slots[slot]; taken {
+		slot++
+	}
+	_, taken := t.slots[slot]
+	if taken || slot < t.minSlot || (t.maxSlots > 0 && slot >= t.maxSlots) {
+		t.dropped++
+		return
+	}
+	t.slots[slot] = p
+	if slot > t.last {
+		t.last = slot
+	}
+}
+
+// oggCRCTable is the CRC-32 table used by Ogg (polynomial 0x04C11DB7, unreflected)
+var oggCRCTable = func() [256]uint32 {
+	var table [256]uint32
+	for i := range table {
+		crc := uint32(i) << 24
+		for j := 0; j < 8; j++ {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ 0x04C11DB7
+			} else {
+				crc <<= 1
+			}
+		}
+		table[i] = crc
+	}
+	return table
+}()
+
+// oggChecksum continues an Ogg page CRC over data
+func oggChecksum(crc uint32, data []byte) uint32 {
+	for _, b := range data {
+		crc = crc<<8 ^ oggCRCTable[byte(crc>>24)^b]
+	}
+	return crc
+}
+
+// Ogg page header types
+const (
+	oggHeaderBOS = 0x02
+	oggHeaderEOS = 0x04
+)
+
+// oggPageWriter writes packets into Ogg pages for a single logical stream
+type oggPageWriter struct {
+	w        io.Writer
+	serial   uint32
+	sequence uint32
+}
+
+// writePage writes whole packets as one page; packets must fit in 255 lacing values
+func (o *oggPageWriter) writePage(packets [][]byte, granule uint64, headerType byte) error {
+	segments := make([]byte, 0, oggMaxSegments)
+	bodySize := 0
+	for _, packet := range packets {
+		for remaining := len(packet); ; remaining -= 255 {
+			if remaining >= 255 {
+				segments = append(segments, 255)
+				continue
+			}
+			segments = append(segments, byte(remaining))
+			break
+		}
+		bodySize += len(packet)
+	}
+	if len(segments) > oggMaxSegments {
+		return fmt.Errorf("ogg page has %d segments (max %d)", len(segments), oggMaxSegments)
+	}
+
+	page := make([]byte, 27, 27+len(segments)+bodySize)
+	copy(page, "OggS")
+	page[4] = 0 // version
+	page[5] = headerType
+	binary.LittleEndian.PutUint64(page[6:], granule)
+	binary.LittleEndian.PutUint32(page[14:], o.serial)
+	binary.LittleEndian.PutUint32(page[18:], o.sequence)
+	page[26] = byte(len(segments))
+	page = append(page, segments...)
+	for _, packet := range packets {
+		page = append(page, packet...)
+	}
+
+	binary.LittleEndian.PutUint32(page[22:], oggChecksum(0, page))
+
+	o.sequence++
+	_, err := o.w.Write(page)
+	return err
+}
+
+// opusHeadStereoUncoupled builds the identification header for two uncoupled mono streams
+func opusHeadStereoUncoupled() []byte {
+	head := make([]byte, 0, 23)
+	head = append(head, "OpusHead"...)
+	head = append(head, 1, 2) // version, channel count
+	head = binary.LittleEndian.AppendUint16(head, opusPreSkip)
+	head = binary.LittleEndian.AppendUint32(head, opusSampleRate)
+	head = binary.LittleEndian.AppendUint16(head, 0) // output gain
+	head = append(head, 1, 2, 0, 0, 1)               // mapping family, streams, coupled streams, channel mapping
+	return head
+}
+
+// opusTags builds the comment header
+func opusTags() []byte {
+	tags := make([]byte, 0, 16+len(oggVendorString))
+	tags = append(tags, "OpusTags"...)
+	tags = binary.LittleEndian.AppendUint32(tags, uint32(len(oggVendorString)))
+	tags = append(tags, oggVendorString...)
+	tags = binary.LittleEndian.AppendUint32(tags, 0) // no user comments
+	return tags
+}
+
+// stereoOggWriter writes two slot timelines as one stereo Ogg Opus stream. Each Ogg packet
+// carries one 20ms frame per channel; slots missing on either side are filled with silence.
+// Slots can be written incrementally across several writeSlots calls.
+type stereoOggWriter struct {
+	ogg     *oggPageWriter
+	granule uint64
+	slots   int // slots written so far
+}
+
+// newStereoOggWriter writes the OpusHead and OpusTags header pages
+func newStereoOggWriter(w io.Writer) (*stereoOggWriter, error) {
+	ogg := &oggPageWriter{w: w, serial: uint32(time.Now().UnixNano())}
+	if err := ogg.writePage([][]byte{opusHeadStereoUncoupled()}, 0, oggHeaderBOS); err != nil {
+		return nil, fmt.Errorf("failed to write OpusHead: %w", err)
+	}
+	if err := ogg.writePage([][]byte{opusTags()}, 0, 0); err != nil {
+		return nil, fmt.Errorf("failed to write OpusTags: %w", err)
+	}
+	return &stereoOggWriter{ogg: ogg, granule: opusPreSkip}, nil
+}
+
+// writeSlots writes all slots up to (excluding) until as complete pages.
+// final marks the last page as end of stream.
+func (sw *stereoOggWriter) writeSlots(left, right *stereoTimeline, until int, final bool) error {
+	pagePackets := make([][]byte, 0, oggPagePackets)
+	pageSegments := 0
+
+	flush := func(headerType byte) error {
+		if err := sw.ogg.writePage(pagePackets, sw.granule, headerType); err != nil {
+			return fmt.Errorf("failed to write audio page: %w", err)
+		}
+		pagePackets, pageSegments = pagePackets[:0], 0
+		return nil
+	}
+
+	for ; sw.slots < until; sw.slots++ {
+		slot := sw.slots
+		leftPacket, ok := left.slots[slot]
+		if !ok {
+			leftPacket = opusSilencePacket
+		}
+		rightPacket, ok := right.slots[slot]
+		if !ok {
+			rightPacket = opusSilencePacket
+		}
+		packet := appendOpusPacket(nil, leftPacket, true)
+		packet = appendOpusPacket(packet, rightPacket, false)
+		packetSegments := len(packet)/255 + 1
+
+		if pageSegments+packetSegments > oggMaxSegments {
+			if err := flush(0); err != nil {
+				return err
+			}
+		}
+
+		pagePackets = append(pagePackets, packet)
+		pageSegments += packetSegments
+		sw.granule += opusSlotSamples
+
+		if len(pagePackets) == oggPagePackets && !(final && sw.slots == until-1) {
+			if err := flush(0); err != nil {
+				return err
+			}
+		}
+	}
+	if final {
+		// An empty EOS page is valid when nothing is left to write
+		return flush(oggHeaderEOS)
+	}
+	if len(pagePackets) > 0 {
+		return flush(0)
+	}
+	return nil
+}
+
+// muxStereoOggOpus writes the caller and model channels as one stereo Ogg Opus stream;
+// audio past totalDuration is cut, matching the length of the call recording
+func muxStereoOggOpus(w io.Writer, left, right []*rtp.Packet, totalDuration time.Duration) (dropped int, err error) {
+	totalSlots := int(totalDuration.Milliseconds() * 48 / opusSlotSamples)
+	if totalSlots <= 0 {
+		return 0, fmt.Errorf("invalid recording duration: %v", totalDuration)
+	}
+
+	leftTimeline := buildStereoTimeline(left, totalSlots)
+	rightTimeline := buildStereoTimeline(right, totalSlots)
+	dropped = leftTimeline.dropped + rightTimeline.dropped
+
+	writer, err := newStereoOggWriter(w)
+	if err != nil {
+		return dropped, err
+	}
+	return dropped, writer.writeSlots(leftTimeline, rightTimeline, totalSlots, true)
+}
+
+// parseSelfDelimitedOpusPacket parses a self-delimited Opus packet (RFC 6716 Appendix B)
+// and returns the remaining bytes that follow it
+func parseSelfDelimitedOpusPacket(data []byte) (opusPacket, []byte, error) {
+	if len(data) < 1 {
+		return opusPacket{}, nil, fmt.Errorf("empty opus packet")
+	}
+	pkt := opusPacket{toc: data[0] &^ 0x03}
+	code := data[0] & 0x03
+	data = data[1:]
+
+	take := func(lengths ...int) error {
+		for _, length := range lengths {
+			if length > len(data) {
+				return fmt.Errorf("frame length %d exceeds packet", length)
+			}
+			pkt.frames = append(pkt.frames, data[:length])
+			data = data[length:]
+		}
+		return nil
+	}
+	readLength := func() (int, error) {
+		length, used, err := readOpusFrameLength(data)
+		if err != nil {
+			return 0, err
+		}
+		data = data[used:]
+		return length, nil
+	}
+
+	switch code {
+	case 0, 1:
+		length, err := readLength()
+		if err != nil {
+			return opusPacket{}, nil, err
+		}
+		lengths := []int{length}
+		if code == 1 {
+			lengths = append(lengths, length)
+		}
+		if err := take(lengths...); err != nil {
+			return opusPacket{}, nil, err
+		}
+
+	case 2:
+		first, err := readLength()
+		if err != nil {
+			return opusPacket{}, nil, err
+		}
+		second, err := readLength()
+		if err != nil {
+			return opusPacket{}, nil, err
+		}
+		if err := take(first, second); err != nil {
+			return opusPacket{}, nil, err
+		}
+
+	case 3:
+		if len(data) < 1 {
+			return opusPacket{}, nil, fmt.Errorf("missing frame count byte")
+		}
+		count := int(data[0] & 0x3F)
+		vbr := data[0]&0x80 != 0
+		hasPadding := data[0]&0x40 != 0
+		data = data[1:]
+		if count == 0 {
+			return opusPacket{}, nil, fmt.Errorf("zero frame count")
+		}
+
+		padding := 0
+		for hasPadding {
+			if len(data) < 1 {
+				return opusPacket{}, nil, fmt.Errorf("truncated padding length")
+			}
+			b := data[0]
+			data = data[1:]
+			if b == 255 {
+				padding += 254
+				continue
+			}
+			padding += int(b)
+			break
+		}
+
+		lengths := make([]int, count)
+		if vbr {
+			for i := range lengths {
+				length, err := readLength()
+				if err != nil {
+					return opusPacket{}, nil, err
+				}
+				lengths[i] = length
+			}
+		} else {
+			length, err := readLength()
+			if err != nil {
+				return opusPacket{}, nil, err
+			}
+			for i := range lengths {
+				lengths[i] = length
+			}
+		}
+		if err := take(lengths...); err != nil {
+			return opusPacket{}, nil, err
+		}
+		if padding > len(data) {
+			return opusPacket{}, nil, fmt.Errorf("padding exceeds packet")
+		}
+		data = data[padding:]
+	}
+
+	return pkt, data, nil
+}
+
+// stereoOggReader reads a file written by stereoOggWriter back one slot at a time, holding
+// no more than a page in memory. Reading stops at the first truncated or corrupt page or
+// packet so partially written files (e.g. a spool interrupted by a crash) yield every
+// complete slot before the damage.
+type stereoOggReader struct {
+	r       *bufio.Reader
+	packets [][]byte // complete packets of the current page not yet returned
+	partial []byte   // packet continued on the next page
+	slots   int      // audio slots returned so far
+	done    bool
+}
+
+// newStereoOggReader reads and checks the OpusHead and OpusTags headers
+func newStereoOggReader(r io.Reader) (*stereoOggReader, error) {
+	reader := &stereoOggReader{r: bufio.NewReader(r)}
+	head, ok := reader.nextPacket()
+	if !ok || !bytes.HasPrefix(head, []byte("OpusHead")) {
+		return nil, fmt.Errorf("not a stereo Ogg Opus recording")
+	}
+	if _, ok := reader.nextPacket(); !ok {
+		return nil, fmt.Errorf("missing OpusTags header")
+	}
+	return reader, nil
+}
+
+// next returns the packets of the following slot; ok is false once no readable slot is left
+func (sr *stereoOggReader) next() (slot int, left, right opusPacket, ok bool) {
+	packet, ok := sr.nextPacket()
+	if !ok {
+		return 0, opusPacket{}, opusPacket{}, false
+	}
+	left, rest, err := parseSelfDelimitedOpusPacket(packet)
+	if err == nil {
+		right, err = parseOpusPacket(rest)
+	}
+	if err != nil {
+		sr.done, sr.packets = true, nil
+		return 0, opusPacket{}, opusPacket{}, false
+	}
+	slot = sr.slots
+	sr.slots++
+	return slot, left, right, true
+}
+
+// readSlots places up to count slots onto the timelines and returns how many were read
+func (sr *stereoOggReader) readSlots(left, right *stereoTimeline, count int) int {
+	for n := 0; n < count; n++ {
+		slot, leftPacket, rightPacket, ok := sr.next()
+		if !ok {
+			return n
+		}
+		left.place(slot, leftPacket)
+		right.place(slot, rightPacket)
+	}
+	return count
+}
+
+// nextPacket returns the next complete Ogg packet, reading pages as needed
+func (sr *stereoOggReader) nextPacket() ([]byte, bool) {
+	for len(sr.packets) == 0 {
+		if sr.done || !sr.readPage() {
+			sr.done = true
+			return nil, false
+		}
+	}
+	packet := sr.packets[0]
+	sr.packets = sr.packets[1:]
+	return packet, true
+}
+
+// readPage reads and verifies one page, queueing the packets it completes.
+// It returns false at the end of the data or at the first damaged page.
+func (sr *stereoOggReader) readPage() bool {
+	header := make([]byte, 27)
+	if _, err := io.ReadFull(sr.r, header); err != nil || string(header[:4]) != "OggS" {
+		return false
+	}
+	lacing := make([]byte, header[26])
+	if _, err := io.ReadFull(sr.r, lacing); err != nil {
+		return false
+	}
+	bodySize := 0
+	for _, l := range lacing {
+		bodySize += int(l)
+	}
+	body := make([]byte, bodySize)
+	if _, err := io.ReadFull(sr.r, body); err != nil {
+		return false
+	}
+
+	expected := binary.LittleEndian.Uint32(header[22:])
+	binary.LittleEndian.PutUint32(header[22:], 0)
+	if oggChecksum(oggChecksum(oggChecksum(0, header), lacing), body) != expected {
+		return false
+	}
+
+	for _, l := range lacing {
+		sr.partial = append(sr.partial, body[:l]...)
+		body = body[l:]
+		if l < 255 {
+			sr.packets = append(sr.packets, sr.partial)
+			sr.partial = nil
+		}
+	}
+	return true
+}
+
+// countStereoOggSlots returns the number of readable slots in a stereo Ogg Opus stream
+func countStereoOggSlots(r io.Reader) (int, error) {
+	reader, err := newStereoOggReader(r)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		if _, _, _, ok := reader.next(); !ok {
+			return reader.slots, nil
+		}
+	}
+}
@@ -9,8 +9,8 @@ import (
 	"strings"
 	"time"
 
-	"github.com/ClareAI/astra-voice-service/pkg/gcs"
 	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/objectstore"
 	"github.com/jung-kurt/gofpdf/v2"
 	"go.uber.org/zap"
 )
@@ -265,20 +265,13 @@ func getFileSize(filePath string) int64 {
 	return info.Size()
 }
 
-// UploadPDFToGCS uploads a PDF file to Google Cloud Storage and returns the public URL
-func UploadPDFToGCS(ctx context.Context, localFilePath, bucketName, objectPath string) (string, error) {
+// UploadPDF uploads a PDF file to the object store under key and returns its location
+func UploadPDF(ctx context.Context, store objectstore.Store, localFilePath, key string, meta objectstore.Metadata) (string, error) {
 	// Check if file exists
 	if _, err := os.Stat(localFilePath); err != nil {
 		return "", fmt.Errorf("PDF file not found: %w", err)
 	}
 
-	// Create GCS client
-	gcsClient, err := gcs.NewGCSClient(ctx, bucketName)
-	if err != nil {
-		return "", fmt.Errorf("failed to create GCS client: %w", err)
-	}
-	defer gcsClient.Close()
-
 	// Open the local file
 	file, err := os.Open(localFilePath)
 	if err != nil {
@@ -286,19 +279,30 @@ func UploadPDFToGCS(ctx context.Context, localFilePath, bucketName, objectPath s
 	}
 	defer file.Close()
 
-	// Upload to GCS
-	url, err := gcsClient.Upload(ctx, objectPath, file)
+	url, err := store.Put(ctx, key, file, objectstore.Attributes{ContentType: "application/pdf", Metadata: meta})
 	if err != nil {
-		return "", fmt.Errorf("failed to upload PDF to GCS: %w", err)
+		return "", fmt.Errorf("failed to upload PDF to %s: %w", store.Backend(), err)
 	}
 
-	logger.Base().Info("Uploaded to GCS", zap.String("url", url))
+	logger.Base().Info("Uploaded PDF", zap.String("backend", string(store.Backend())), zap.String("url", url))
 	return url, nil
 }
 
-// GenerateAndUploadPDFToGCS generates a PDF from text and uploads it to GCS
-func GenerateAndUploadPDFToGCS(ctx context.Context, title, content, filename, bucketName string) (map[string]interface{}, error) {
-	logger.Base().Info("Generating and uploading PDF to GCS: title=, filename=, bucket=", zap.String("title", title), zap.String("bucketname", bucketName), zap.String("filename", filename))
+// UploadPDFToGCS uploads a PDF file to Google Cloud Storage and returns the public URL
+func UploadPDFToGCS(ctx context.Context, localFilePath, bucketName, objectPath string) (string, error) {
+	store, err := objectstore.NewGCSStore(ctx, bucketName)
+	if err != nil {
+		return "", fmt.Errorf("failed to create GCS client: %w", err)
+	}
+	defer store.Close()
+
+	return UploadPDF(ctx, store, localFilePath, objectPath, objectstore.Metadata{})
+}
+
+// GenerateAndUploadPDF generates a PDF from text and uploads it to the object store
+// under reports/{tenant}/{conversation}/{filename}
+func GenerateAndUploadPDF(ctx context.Context, store objectstore.Store, title, content, filename string, meta objectstore.Metadata) (map[string]interface{}, error) {
+	logger.Base().Info("Generating and uploading PDF", zap.String("title", title), zap.String("backend", string(store.Backend())), zap.String("filename", filename))
 
 	// First generate the local PDF
 	metadata, err := GeneratePDFFromText(title, content, filename)
@@ -321,24 +325,38 @@ func GenerateAndUploadPDFToGCS(ctx context.Context, title, content, filename, bu
 		}
 	}()
 
-	// Generate object path for GCS
-	objectPath := fmt.Sprintf("pdf_message/%s", filename)
-
-	// Upload to GCS
-	gcsURL, err := UploadPDFToGCS(ctx, filepath, bucketName, objectPath)
+	url, err := UploadPDF(ctx, store, filepath, objectstore.ObjectKey("reports", meta, filename), meta)
 	if err != nil {
-		logger.Base().Error("Failed to upload to GCS")
-		// Return local metadata even if GCS upload fails
+		logger.Base().Error("Failed to upload PDF", zap.Error(err))
+		// Return local metadata even if the upload fails
 		// Note: defer will clean up the local file automatically
 		return metadata, err
 	}
 
-	// Add GCS URL to metadata
-	metadata["gcs_url"] = gcsURL
-	metadata["gcs_bucket"] = bucketName
-	metadata["public_url"] = gcsURL
+	metadata["object_url"] = url
+	metadata["storage_backend"] = string(store.Backend())
+	metadata["public_url"] = url
+
+	logger.Base().Info("PDF generated and uploaded successfully", zap.String("url", url))
+	return metadata, nil
+}
 
-	logger.Base().Info("PDF generated and uploaded successfully", zap.String("gcsurl", gcsURL))
+// GenerateAndUploadPDFToGCS generates a PDF from text and uploads it to GCS
+func GenerateAndUploadPDFToGCS(ctx context.Context, title, content, filename, bucketName string) (map[string]interface{}, error) {
+	store, err := objectstore.NewGCSStore(ctx, bucketName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCS client: %w", err)
+	}
+	defer store.Close()
+
+	metadata, err := GenerateAndUploadPDF(ctx, store, title, content, filename, objectstore.Metadata{})
+	if err != nil {
+		return metadata, err
+	}
+
+	// Keep the GCS-specific keys existing callers read
+	metadata["gcs_url"] = metadata["object_url"]
+	metadata["gcs_bucket"] = bucketName
 	return metadata, nil
 }
 
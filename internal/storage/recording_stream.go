@@ -0,0 +1,220 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	recordingFlushInterval = 5 * time.Second
+	recordingFlushLag      = 5 * time.Second  // Recent audio kept in memory to absorb jitter and late packets
+	recordingMaxLead       = 30 * time.Second // Packets further ahead of the wall clock are treated as bogus timestamps
+	recordingRecentWindow  = 2 * time.Minute  // Raw chunks kept for GetAudioDataRange (re-transcription)
+	recordingTailPadding   = 2 * time.Second  // Silence appended after the last packet
+	spoolAudioExt          = ".opus"
+	spoolMetaExt           = ".json"
+)
+
+// recordingMeta is persisted next to the spool file so a recording interrupted by a
+// pod restart can still be assembled and registered
+type recordingMeta struct {
+	ConnectionID   string            `json:"connection_id"`
+	ConversationID string            `json:"conversation_id,omitempty"`
+	TenantID       string            `json:"tenant_id,omitempty"`
+	AgentID        string            `json:"agent_id,omitempty"`
+	Formats        []RecordingFormat `json:"formats,omitempty"`
+	StartedAt      time.Time         `json:"started_at"`
+}
+
+// streamChannel maps one direction's RTP timestamps onto the recording timeline
+type streamChannel struct {
+	timeline   *stereoTimeline
+	started    bool
+	ssrc       uint32 // RTP source the timestamps belong to
+	baseRTP    uint32 // RTP timestamp of the first packet
+	baseOffset uint32 // Timeline position of the first packet (48kHz samples)
+	delay      uint32 // Fixed channel offset (48kHz samples)
+}
+
+// recordingStream incrementally writes one connection's stereo recording to a spool file.
+// Only audio newer than recordingFlushLag is held in memory.
+type recordingStream struct {
+	mu           sync.Mutex
+	meta         recordingMeta
+	spoolPath    string
+	file         *os.File
+	writer       *stereoOggWriter
+	left, right  *streamChannel
+	lastPacketAt time.Time
+	closed       bool
+}
+
+// newRecordingStream creates the spool file and writes the Ogg Opus headers
+func newRecordingStream(spoolDir string, meta recordingMeta) (*recordingStream, error) {
+	if err := os.MkdirAll(spoolDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory %s: %v", spoolDir, err)
+	}
+
+	spoolPath := filepath.Join(spoolDir, meta.ConnectionID+spoolAudioExt)
+	file, err := os.OpenFile(spoolPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create spool file: %v", err)
+	}
+
+	writer, err := newStereoOggWriter(file)
+	if err != nil {
+		file.Close()
+		os.Remove(spoolPath)
+		return nil, err
+	}
+
+	stream := &recordingStream{
+		meta:         meta,
+		spoolPath:    spoolPath,
+		file:         file,
+		writer:       writer,
+		left:         &streamChannel{timeline: newStereoTimeline(0)},
+		right:        &streamChannel{timeline: newStereoTimeline(0), delay: delayRtpTimestamp},
+		lastPacketAt: meta.StartedAt,
+	}
+	if err := stream.saveMeta(); err != nil {
+		file.Close()
+		os.Remove(spoolPath)
+		return nil, err
+	}
+	return stream, nil
+}
+
+// metaPath returns the sidecar path for a spool file
+func metaPath(spoolPath string) string {
+	return spoolPath[:len(spoolPath)-len(spoolAudioExt)] + spoolMetaExt
+}
+
+// saveMeta writes the sidecar atomically; callers hold s.mu or own the stream exclusively
+func (s *recordingStream) saveMeta() error {
+	data, err := json.Marshal(s.meta)
+	if err != nil {
+		return fmt.Errorf("failed to encode recording metadata: %v", err)
+	}
+	tmpPath := metaPath(s.spoolPath) + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write recording metadata: %v", err)
+	}
+	return os.Rename(tmpPath, metaPath(s.spoolPath))
+}
+
+// updateMeta applies fn to the metadata and persists it. Updates after finish are ignored,
+// the sidecar may already have been removed by the final assembly.
+func (s *recordingStream) updateMeta(fn func(meta *recordingMeta)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	fn(&s.meta)
+	return s.saveMeta()
+}
+
+// metadata returns a copy of the current metadata
+func (s *recordingStream) metadata() recordingMeta {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.meta
+}
+
+// add places a packet on the timeline. The first packet of each direction is positioned by
+// its reception time, later packets by their RTP timestamp relative to the first one. A new
+// RTP source (e.g. the model after a pre-rendered greeting) starts over from its reception time.
+func (s *recordingStream) add(audioType AudioType, pkt *rtp.Packet, receivedAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+
+	channel := s.left
+	if audioType == AudioTypeAIOutput {
+		channel = s.right
+	}
+
+	if !channel.started || pkt.SSRC != channel.ssrc {
+		channel.started = true
+		channel.ssrc = pkt.SSRC
+		channel.baseRTP = pkt.Timestamp
+		channel.baseOffset = uint32(receivedAt.Sub(s.meta.StartedAt).Milliseconds())*48 + channel.delay
+	}
+	timestamp := pkt.Timestamp - channel.baseRTP + channel.baseOffset
+
+	limit := uint32((receivedAt.Sub(s.meta.StartedAt)+recordingMaxLead).Milliseconds()*48) + channel.delay
+	if timestamp > limit {
+		channel.timeline.dropped++
+		return
+	}
+
+	channel.timeline.addPacket(timestamp, pkt.Payload)
+	s.lastPacketAt = receivedAt
+}
+
+// flush writes every slot older than recordingFlushLag to the spool file and releases it from memory
+func (s *recordingStream) flush(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+
+	until := int((now.Sub(s.meta.StartedAt) - recordingFlushLag).Milliseconds() * 48 / opusSlotSamples)
+	if until <= s.writer.slots {
+		return nil
+	}
+	return s.writeLocked(until, false)
+}
+
+// finish writes the remaining audio, marks the end of stream and closes the spool file
+func (s *recordingStream) finish() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	until := int((s.lastPacketAt.Sub(s.meta.StartedAt) + recordingTailPadding).Milliseconds() * 48 / opusSlotSamples)
+	until = max(until, s.left.timeline.last+1, s.right.timeline.last+1, s.writer.slots)
+
+	err := s.writeLocked(until, true)
+	if closeErr := s.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// writeLocked writes slots up to until and syncs them to disk so they survive a crash
+func (s *recordingStream) writeLocked(until int, final bool) error {
+	if err := s.writer.writeSlots(s.left.timeline, s.right.timeline, until, final); err != nil {
+		return err
+	}
+	s.left.timeline.release(until)
+	s.right.timeline.release(until)
+	return s.file.Sync()
+}
+
+// idleSince returns when the stream last received audio
+func (s *recordingStream) idleSince() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastPacketAt
+}
+
+// droppedPackets returns how many packets could not be placed on the timeline
+func (s *recordingStream) droppedPackets() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.left.timeline.dropped + s.right.timeline.dropped
+}
@@ -0,0 +1,262 @@
+// Package audiocue manages the hold music and short audio cues (typing sounds, spoken
+// fillers such as "one moment please") that callers hear while tools run.
+//
+// Uploaded MP3 or WAV audio is transcoded once to 20ms 48kHz mono Opus frames and
+// stored in the object store, so every instance can play it without re-encoding.
+// Frames are cached in memory and refreshed periodically to pick up replaced uploads.
+package audiocue
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/objectstore"
+	"go.uber.org/zap"
+)
+
+const (
+	// MaxUploadBytes limits the size of uploaded audio
+	MaxUploadBytes = 10 << 20
+	// MaxDuration limits the length of a cue after transcoding
+	MaxDuration = 5 * time.Minute
+
+	// ContentType is the content type of stored cue objects
+	ContentType = "application/x-astra-opus-frames"
+
+	keyPrefix   = "audio-cues"
+	keySuffix   = ".opus"
+	framesMagic = "ACUE1"
+	maxFrames   = int(MaxDuration / (frameDuration * time.Millisecond))
+
+	cacheTTL     = 10 * time.Minute
+	failureRetry = 30 * time.Second
+	loadTimeout  = 30 * time.Second
+)
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// ValidName reports whether name can be used as a cue name (letters, digits, '-' and '_')
+func ValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// Key returns the object key of an agent's cue: audio-cues/{tenantID}/{agentID}/{name}.opus
+func Key(tenantID, agentID, name string) string {
+	if tenantID == "" {
+		tenantID = "shared"
+	}
+	return path.Join(keyPrefix, keySegment(tenantID), keySegment(agentID), keySegment(name)+keySuffix)
+}
+
+// keySegment keeps an ID from escaping its directory
+func keySegment(segment string) string {
+	segment = strings.ReplaceAll(segment, "/", "_")
+	if segment == "." || segment == ".." {
+		return "_"
+	}
+	return segment
+}
+
+// Cue describes a stored audio cue
+type Cue struct {
+	Name       string `json:"name"`
+	Key        string `json:"key"`
+	Location   string `json:"location"`
+	FrameCount int    `json:"frame_count"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+type cacheEntry struct {
+	frames  [][]byte
+	text    string // transcript of a pre-rendered greeting
+	expires time.Time
+	loading bool
+}
+
+// Library stores cues in an object store and caches their frames
+type Library struct {
+	store objectstore.Store
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+var (
+	libraryMu      sync.RWMutex
+	defaultLibrary *Library
+)
+
+// InitLibrary sets up the process-wide cue library on top of store
+func InitLibrary(store objectstore.Store) *Library {
+	library := NewLibrary(store)
+	libraryMu.Lock()
+	defaultLibrary = library
+	libraryMu.Unlock()
+	return library
+}
+
+// GetLibrary returns the process-wide cue library, or nil if no object store is configured
+func GetLibrary() *Library {
+	libraryMu.RLock()
+	defer libraryMu.RUnlock()
+	return defaultLibrary
+}
+
+// NewLibrary creates a cue library on top of store
+func NewLibrary(store objectstore.Store) *Library {
+	return &Library{
+		store:   store,
+		entries: make(map[string]*cacheEntry),
+	}
+}
+
+// Put transcodes audio and stores it as an agent's cue, replacing any cue with the same name
+func (l *Library) Put(ctx context.Context, tenantID, agentID, name string, audio []byte) (*Cue, error) {
+	if !ValidName(name) {
+		return nil, fmt.Errorf("invalid cue name %q", name)
+	}
+	if len(audio) > MaxUploadBytes {
+		return nil, fmt.Errorf("audio exceeds %d bytes", MaxUploadBytes)
+	}
+
+	frames, err := Transcode(audio)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transcode audio: %w", err)
+	}
+	if len(frames) > maxFrames {
+		return nil, fmt.Errorf("audio longer than %s", MaxDuration)
+	}
+
+	key := Key(tenantID, agentID, name)
+	location, err := l.store.Put(ctx, key, bytes.NewReader(encodeFrames(frames)), objectstore.Attributes{
+		ContentType: ContentType,
+		Metadata:    objectstore.Metadata{TenantID: tenantID, AgentID: agentID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store audio cue: %w", err)
+	}
+	l.cache(key, frames, "", cacheTTL)
+
+	return &Cue{
+		Name:       name,
+		Key:        key,
+		Location:   location,
+		FrameCount: len(frames),
+		DurationMs: int64(len(frames) * frameDuration),
+	}, nil
+}
+
+// Delete removes an agent's cue
+func (l *Library) Delete(ctx context.Context, tenantID, agentID, name string) error {
+	key := Key(tenantID, agentID, name)
+	if err := l.store.Delete(ctx, key); err != nil {
+		return fmt.Errorf("failed to delete audio cue: %w", err)
+	}
+	l.mu.Lock()
+	delete(l.entries, key)
+	l.mu.Unlock()
+	return nil
+}
+
+// Frames returns the frames stored under key, loading them if they are not cached
+func (l *Library) Frames(ctx context.Context, key string) ([][]byte, error) {
+	frames, _, err := l.get(ctx, key)
+	return frames, err
+}
+
+// get returns the frames and text stored under key, loading them if they are not cached
+func (l *Library) get(ctx context.Context, key string) ([][]byte, string, error) {
+	l.mu.Lock()
+	entry, ok := l.entries[key]
+	if ok && entry.frames != nil && time.Now().Before(entry.expires) {
+		l.mu.Unlock()
+		return entry.frames, entry.text, nil
+	}
+	l.mu.Unlock()
+
+	frames, text, err := l.fetch(ctx, key)
+	if err != nil {
+		return nil, "", err
+	}
+	l.cache(key, frames, text, cacheTTL)
+	return frames, text, nil
+}
+
+// Lookup returns the cached frames for key without blocking. Missing or expired entries
+// are loaded in the background; until then Lookup returns nil (or the expired frames).
+func (l *Library) Lookup(key string) [][]byte {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[key]
+	if !ok {
+		entry = &cacheEntry{}
+		l.entries[key] = entry
+	}
+	if !entry.loading && !time.Now().Before(entry.expires) {
+		entry.loading = true
+		go l.load(key)
+	}
+	return entry.frames
+}
+
+// Preload starts loading the given keys in the background so the first playback does not miss
+func (l *Library) Preload(keys ...string) {
+	for _, key := range keys {
+		l.Lookup(key)
+	}
+}
+
+// load fetches key into the cache; failures are retried after a short delay
+func (l *Library) load(key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout)
+	defer cancel()
+
+	frames, text, err := l.fetch(ctx, key)
+	if err != nil {
+		logger.Base().Warn("Failed to load audio cue", zap.String("key", key), zap.Error(err))
+		l.mu.Lock()
+		if entry, ok := l.entries[key]; ok {
+			entry.loading = false
+			entry.expires = time.Now().Add(failureRetry)
+		}
+		l.mu.Unlock()
+		return
+	}
+	l.cache(key, frames, text, cacheTTL)
+}
+
+// fetch reads and decodes the object stored under key, returning its frames and, for
+// greetings, the transcript
+func (l *Library) fetch(ctx context.Context, key string) ([][]byte, string, error) {
+	reader, err := l.store.Get(ctx, key)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open audio cue %s: %w", key, err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read audio cue %s: %w", key, err)
+	}
+	if isGreeting(data) {
+		return decodeGreeting(data)
+	}
+	frames, err := decodeFrames(data)
+	return frames, "", err
+}
+
+// cache stores frames for key until ttl elapses
+func (l *Library) cache(key string, frames [][]byte, text string, ttl time.Duration) {
+	l.mu.Lock()
+	l.entries[key] = &cacheEntry{frames: frames, text: text, expires: time.Now().Add(ttl)}
+	l.mu.Unlock()
+}
@@ -0,0 +1,103 @@
+package audiocue
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/ClareAI/astra-voice-service/pkg/objectstore"
+)
+
+const (
+	// MaxGreetingFrames limits a pre-rendered greeting to 30 seconds
+	MaxGreetingFrames = 30 * 1000 / frameDuration
+
+	greetingPrefix = "greetings"
+	greetingMagic  = "AGRT1"
+)
+
+// Greeting is a pre-rendered call greeting: the audio the model spoke and its transcript
+type Greeting struct {
+	Text   string
+	Frames [][]byte
+}
+
+// GreetingKey returns the object key of an agent's pre-rendered greeting. variant lists
+// everything that changes the audio (greeting instruction, language, accent, voice, ...);
+// it is hashed, so any change renders a new greeting.
+func GreetingKey(agentID string, variant ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(variant, "\x00")))
+	return path.Join(greetingPrefix, keySegment(agentID), hex.EncodeToString(sum[:16])+keySuffix)
+}
+
+// PutGreeting stores a pre-rendered greeting under key
+func (l *Library) PutGreeting(ctx context.Context, key, agentID string, greeting *Greeting) error {
+	if greeting == nil || len(greeting.Frames) == 0 {
+		return fmt.Errorf("greeting has no audio")
+	}
+	if len(greeting.Frames) > MaxGreetingFrames {
+		return fmt.Errorf("greeting longer than %d frames", MaxGreetingFrames)
+	}
+
+	_, err := l.store.Put(ctx, key, bytes.NewReader(encodeGreeting(greeting)), objectstore.Attributes{
+		ContentType: ContentType,
+		Metadata:    objectstore.Metadata{AgentID: agentID},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store greeting: %w", err)
+	}
+	l.cache(key, greeting.Frames, greeting.Text, cacheTTL)
+	return nil
+}
+
+// Greeting returns the greeting stored under key, loading it if it is not cached.
+// It returns objectstore.ErrNotFound (wrapped) if the greeting was never rendered.
+func (l *Library) Greeting(ctx context.Context, key string) (*Greeting, error) {
+	frames, text, err := l.get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if text == "" {
+		return nil, fmt.Errorf("%s is not a greeting", key)
+	}
+	return &Greeting{Text: text, Frames: frames}, nil
+}
+
+// encodeGreeting serializes a greeting as a magic header, the length-prefixed transcript
+// and the frames in the audio cue format
+func encodeGreeting(greeting *Greeting) []byte {
+	frames := encodeFrames(greeting.Frames)
+	buf := make([]byte, 0, len(greetingMagic)+4+len(greeting.Text)+len(frames))
+	buf = append(buf, greetingMagic...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(greeting.Text)))
+	buf = append(buf, greeting.Text...)
+	return append(buf, frames...)
+}
+
+func isGreeting(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(greetingMagic))
+}
+
+// decodeGreeting parses the output of encodeGreeting
+func decodeGreeting(data []byte) ([][]byte, string, error) {
+	data = data[len(greetingMagic):]
+	if len(data) < 4 {
+		return nil, "", fmt.Errorf("truncated greeting object")
+	}
+	size := int(binary.BigEndian.Uint32(data))
+	data = data[4:]
+	if len(data) < size {
+		return nil, "", fmt.Errorf("truncated greeting object")
+	}
+	text := string(data[:size])
+	frames, err := decodeFrames(data[size:])
+	if err != nil {
+		return nil, "", err
+	}
+	return frames, text, nil
+}
@@ -0,0 +1,225 @@
+package audiocue
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/ClareAI/astra-voice-service/pkg/resample"
+	"github.com/hajimehoshi/go-mp3"
+	"layeh.com/gopus"
+)
+
+const (
+	// SampleRate is the rate cue frames are encoded at
+	SampleRate = 48000
+	// FrameSamples is the size of one 20ms mono frame
+	FrameSamples = 960
+
+	frameDuration = 20 // ms
+)
+
+// Transcode decodes MP3 or WAV audio and encodes it as 20ms 48kHz mono Opus frames
+func Transcode(audio []byte) ([][]byte, error) {
+	pcm, err := DecodePCM(audio)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeOpusFrames(pcm)
+}
+
+// DecodePCM decodes MP3 or WAV audio to mono PCM16 at 48kHz. WAV is detected from its
+// RIFF header; anything else is decoded as MP3.
+func DecodePCM(audio []byte) ([]int16, error) {
+	var pcm []int16
+	var sampleRate int
+	var err error
+	if isWAV(audio) {
+		pcm, sampleRate, err = decodeWAV(audio)
+	} else {
+		pcm, sampleRate, err = decodeMP3(audio)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(pcm) == 0 {
+		return nil, fmt.Errorf("audio contains no samples")
+	}
+	if sampleRate != SampleRate {
+		pcm, err = resample.Convert(pcm, sampleRate, SampleRate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resample audio: %w", err)
+		}
+	}
+	return pcm, nil
+}
+
+// EncodeOpusFrames encodes mono 48kHz PCM16 as 20ms Opus frames; the last frame is padded with silence
+func EncodeOpusFrames(pcm []int16) ([][]byte, error) {
+	encoder, err := gopus.NewEncoder(SampleRate, 1, gopus.Audio)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init opus encoder: %w", err)
+	}
+
+	frames := make([][]byte, 0, len(pcm)/FrameSamples+1)
+	for offset := 0; offset < len(pcm); offset += FrameSamples {
+		samples := make([]int16, FrameSamples)
+		copy(samples, pcm[offset:min(offset+FrameSamples, len(pcm))])
+
+		frame, err := encoder.Encode(samples, FrameSamples, FrameSamples*2)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode opus frame: %w", err)
+		}
+		frames = append(frames, frame)
+	}
+
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("no opus frames generated")
+	}
+	return frames, nil
+}
+
+// decodeMP3 returns MP3 audio as mono PCM16 and its sample rate
+func decodeMP3(audio []byte) ([]int16, int, error) {
+	decoder, err := mp3.NewDecoder(bytes.NewReader(audio))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to decode MP3: %w", err)
+	}
+
+	raw, err := io.ReadAll(decoder)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read MP3 data: %w", err)
+	}
+	if len(raw) < 2 {
+		return nil, 0, fmt.Errorf("empty MP3 data")
+	}
+	if len(raw)%2 != 0 {
+		raw = raw[:len(raw)-1]
+	}
+
+	// go-mp3 always produces interleaved 16-bit stereo
+	var pcm []int16
+	if len(raw)%4 == 0 {
+		pcm = downmix(raw, 2)
+	} else {
+		pcm = downmix(raw, 1)
+	}
+
+	sampleRate := decoder.SampleRate()
+	if sampleRate <= 0 {
+		return nil, 0, fmt.Errorf("invalid sample rate %d", sampleRate)
+	}
+	return pcm, sampleRate, nil
+}
+
+// isWAV reports whether audio starts with a RIFF/WAVE header
+func isWAV(audio []byte) bool {
+	return len(audio) >= 12 && string(audio[0:4]) == "RIFF" && string(audio[8:12]) == "WAVE"
+}
+
+// decodeWAV returns 16-bit PCM WAV audio as mono PCM16 and its sample rate
+func decodeWAV(audio []byte) ([]int16, int, error) {
+	var channels, sampleRate, bitsPerSample int
+	var data []byte
+
+	// Walk the RIFF chunks after the 12-byte header
+	for offset := 12; offset+8 <= len(audio); {
+		id := string(audio[offset : offset+4])
+		size := int(binary.LittleEndian.Uint32(audio[offset+4:]))
+		body := audio[offset+8:]
+		if size > len(body) {
+			// Streamed WAVs may leave the data size unset; take what is there
+			size = len(body)
+		}
+		body = body[:size]
+
+		switch id {
+		case "fmt ":
+			if len(body) < 16 {
+				return nil, 0, fmt.Errorf("invalid WAV fmt chunk")
+			}
+			format := binary.LittleEndian.Uint16(body[0:])
+			// 1 = PCM, 0xFFFE = WAVE_FORMAT_EXTENSIBLE (assumed to wrap PCM)
+			if format != 1 && format != 0xFFFE {
+				return nil, 0, fmt.Errorf("unsupported WAV format %d, only PCM is supported", format)
+			}
+			channels = int(binary.LittleEndian.Uint16(body[2:]))
+			sampleRate = int(binary.LittleEndian.Uint32(body[4:]))
+			bitsPerSample = int(binary.LittleEndian.Uint16(body[14:]))
+		case "data":
+			data = body
+		}
+		// Chunks are padded to an even size
+		offset += 8 + size + size%2
+	}
+
+	if channels <= 0 || sampleRate <= 0 {
+		return nil, 0, fmt.Errorf("WAV fmt chunk missing")
+	}
+	if bitsPerSample != 16 {
+		return nil, 0, fmt.Errorf("unsupported WAV bit depth %d, only 16-bit is supported", bitsPerSample)
+	}
+	if data == nil {
+		return nil, 0, fmt.Errorf("WAV data chunk missing")
+	}
+	return downmix(data, channels), sampleRate, nil
+}
+
+// downmix averages interleaved little-endian PCM16 channels to mono
+func downmix(raw []byte, channels int) []int16 {
+	frames := len(raw) / (2 * channels)
+	pcm := make([]int16, frames)
+	for i := 0; i < frames; i++ {
+		var sum int32
+		for c := 0; c < channels; c++ {
+			sum += int32(int16(binary.LittleEndian.Uint16(raw[2*(i*channels+c):])))
+		}
+		pcm[i] = int16(sum / int32(channels))
+	}
+	return pcm
+}
+
+// encodeFrames serializes Opus frames as a magic header, the frame count and
+// length-prefixed frames
+func encodeFrames(frames [][]byte) []byte {
+	size := len(framesMagic) + 4
+	for _, frame := range frames {
+		size += 2 + len(frame)
+	}
+	buf := make([]byte, 0, size)
+	buf = append(buf, framesMagic...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(frames)))
+	for _, frame := range frames {
+		buf = binary.BigEndian.AppendUint16(buf, uint16(len(frame)))
+		buf = append(buf, frame...)
+	}
+	return buf
+}
+
+// decodeFrames parses the output of encodeFrames
+func decodeFrames(data []byte) ([][]byte, error) {
+	if len(data) < len(framesMagic)+4 || string(data[:len(framesMagic)]) != framesMagic {
+		return nil, fmt.Errorf("not an audio cue object")
+	}
+	data = data[len(framesMagic):]
+	count := int(binary.BigEndian.Uint32(data))
+	data = data[4:]
+
+	frames := make([][]byte, 0, min(count, maxFrames))
+	for i := 0; i < count; i++ {
+		if len(data) < 2 {
+			return nil, fmt.Errorf("truncated audio cue object")
+		}
+		size := int(binary.BigEndian.Uint16(data))
+		if len(data) < 2+size {
+			return nil, fmt.Errorf("truncated audio cue object")
+		}
+		frames = append(frames, data[2:2+size])
+		data = data[2+size:]
+	}
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("audio cue object has no frames")
+	}
+	return frames, nil
+}
@@ -2,9 +2,11 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/tracing"
 	"go.uber.org/zap"
 	"io"
 	"net/http"
@@ -57,14 +59,15 @@ func NewLeadsService(baseURL string) *LeadsService {
 	return &LeadsService{
 		baseURL: baseURL,
 		client: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout:   10 * time.Second,
+			Transport: tracing.NewTransport(nil),
 		},
 	}
 }
 
 // CreateContact pushes conversation configuration to create a contact
 // It calls POST /api/v2/contacts
-func (s *LeadsService) CreateContact(req CreateContactRequest) (*CreateContactResponse, error) {
+func (s *LeadsService) CreateContact(ctx context.Context, req CreateContactRequest) (*CreateContactResponse, error) {
 	url := fmt.Sprintf("%s/api/v2/contacts", s.baseURL)
 
 	body, err := json.Marshal(req)
@@ -72,7 +75,13 @@ func (s *LeadsService) CreateContact(req CreateContactRequest) (*CreateContactRe
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	resp, err := s.client.Post(url, "application/json", bytes.NewBuffer(body))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -99,7 +108,7 @@ func (s *LeadsService) CreateContact(req CreateContactRequest) (*CreateContactRe
 
 // SyncVoiceChat syncs voice chat data
 // It calls POST /api/v2/voice-agent/sync
-func (s *LeadsService) SyncVoiceChat(req SyncVoiceChatRequest) (*SyncVoiceChatResponse, error) {
+func (s *LeadsService) SyncVoiceChat(ctx context.Context, req SyncVoiceChatRequest) (*SyncVoiceChatResponse, error) {
 	url := fmt.Sprintf("%s/api/v2/voice-agent/sync", s.baseURL)
 
 	body, err := json.Marshal(req)
@@ -107,7 +116,13 @@ func (s *LeadsService) SyncVoiceChat(req SyncVoiceChatRequest) (*SyncVoiceChatRe
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	resp, err := s.client.Post(url, "application/json", bytes.NewBuffer(body))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/tracing"
 	"go.uber.org/zap"
 )
 
@@ -50,7 +51,8 @@ func NewComposioService(baseURL string) *ComposioService {
 	return &ComposioService{
 		BaseURL: baseURL,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: tracing.NewTransport(nil),
 		},
 	}
 }
@@ -81,7 +83,8 @@ func (s *ComposioService) GetAllPlatformActions(ctx context.Context) (map[string
 	req.Header.Set("Content-Type", "application/json")
 
 	client := &http.Client{
-		Timeout: 10 * time.Second,
+		Timeout:   10 * time.Second,
+		Transport: tracing.NewTransport(nil),
 	}
 
 	resp, err := client.Do(req)
@@ -182,7 +185,8 @@ func (s *ComposioService) RegisterTools(ctx context.Context, req RegisterToolsRe
 	httpReq.Header.Set("Content-Type", "application/json")
 
 	client := &http.Client{
-		Timeout: 15 * time.Second,
+		Timeout:   15 * time.Second,
+		Transport: tracing.NewTransport(nil),
 	}
 
 	resp, err := client.Do(httpReq)
@@ -246,7 +250,8 @@ func (s *ComposioService) GetActionByID(ctx context.Context, actionID string) (*
 	req.Header.Set("Content-Type", "application/json")
 
 	client := &http.Client{
-		Timeout: 10 * time.Second,
+		Timeout:   10 * time.Second,
+		Transport: tracing.NewTransport(nil),
 	}
 
 	resp, err := client.Do(req)
@@ -311,7 +316,8 @@ func (s *ComposioService) GetActionTemplates(ctx context.Context) ([]ActionTempl
 	client := s.httpClient
 	if client == nil {
 		client = &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout:   10 * time.Second,
+			Transport: tracing.NewTransport(nil),
 		}
 	}
 
@@ -419,7 +425,8 @@ func (s *ComposioService) CallTool(ctx context.Context, endpoint string, args ma
 	client := s.httpClient
 	if client == nil {
 		client = &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: tracing.NewTransport(nil),
 		}
 	}
 
@@ -509,7 +516,8 @@ func (s *ComposioService) ListTools(ctx context.Context, agentID string, mode st
 
 // CallToolMCP calls a specific tool on the MCP server using JSON-RPC 2.0 protocol
 // agentID is the agent identifier, mode should be "published" for production (active config) or "draft" for preview (draft config)
-func (s *ComposioService) CallToolMCP(ctx context.Context, agentID string, mode string, toolName string, arguments map[string]interface{}, modality string) (*MCPToolCallResult, error) {
+// meta is sent as the request's _meta, e.g. the call's variables
+func (s *ComposioService) CallToolMCP(ctx context.Context, agentID string, mode string, toolName string, arguments map[string]interface{}, meta map[string]interface{}, modality string) (*MCPToolCallResult, error) {
 	if s.BaseURL == "" {
 		return nil, fmt.Errorf("MCP base URL not configured")
 	}
@@ -543,6 +551,10 @@ func (s *ComposioService) CallToolMCP(ctx context.Context, agentID string, mode
 		},
 	}
 
+	if len(meta) > 0 {
+		mcpRequest.Params["_meta"] = meta
+	}
+
 	// Execute the JSON-RPC call
 	response, err := s.executeJSONRPC(ctx, url, mcpRequest)
 	if err != nil {
@@ -584,7 +596,8 @@ func (s *ComposioService) executeJSONRPC(ctx context.Context, url string, reques
 	client := s.httpClient
 	if client == nil {
 		client = &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: tracing.NewTransport(nil),
 		}
 	}
 
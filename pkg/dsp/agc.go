@@ -0,0 +1,78 @@
+package dsp
+
+import "math"
+
+const (
+	// Blocks quieter than this, or not clearly above the noise floor, leave the gain unchanged
+	agcGateDb       = -50
+	agcSpeechMargin = 10
+	// Noise floor tracking: fast fall, slow rise
+	agcFloorFall = 0.3
+	agcFloorRise = 0.01
+	// Per-block smoothing of the gain when it falls (attack) and rises (release)
+	agcAttack  = 0.5
+	agcRelease = 0.05
+)
+
+// AGC normalizes speech towards a target RMS level. The gain only adapts on blocks
+// that stand out from the tracked noise floor, so pauses and background noise are
+// not pumped up.
+type AGC struct {
+	targetDb  float64
+	maxGainDb float64
+	gainDb    float64
+	floorDb   float64
+	hasFloor  bool
+}
+
+// NewAGC creates an AGC; zero values take the defaults
+func NewAGC(targetDb, maxGainDb float64) *AGC {
+	def := DefaultConfig()
+	if targetDb == 0 {
+		targetDb = def.TargetLevelDb
+	}
+	if maxGainDb <= 0 {
+		maxGainDb = def.MaxGainDb
+	}
+	return &AGC{targetDb: targetDb, maxGainDb: maxGainDb}
+}
+
+// Process applies the gain in place, ramping from the previous block's gain to avoid zipper noise
+func (a *AGC) Process(samples []float64) {
+	if len(samples) == 0 {
+		return
+	}
+
+	previous := a.gainDb
+	level := levelDb(samples)
+	if a.isSpeech(level) {
+		desired := math.Min(a.targetDb-level, a.maxGainDb)
+		coeff := agcRelease
+		if desired < a.gainDb {
+			coeff = agcAttack
+		}
+		a.gainDb += coeff * (desired - a.gainDb)
+	}
+
+	from, to := dbToLinear(previous), dbToLinear(a.gainDb)
+	step := (to - from) / float64(len(samples))
+	for i := range samples {
+		samples[i] *= from + step*float64(i+1)
+	}
+}
+
+// isSpeech reports whether a block is loud enough to adapt on, updating the noise floor
+func (a *AGC) isSpeech(level float64) bool {
+	if !a.hasFloor {
+		a.floorDb = level
+		a.hasFloor = true
+	}
+	speech := level > agcGateDb && level > a.floorDb+agcSpeechMargin
+	switch {
+	case level < a.floorDb:
+		a.floorDb += agcFloorFall * (level - a.floorDb)
+	case !speech:
+		a.floorDb += agcFloorRise * (level - a.floorDb)
+	}
+	return speech
+}
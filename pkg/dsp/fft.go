@@ -0,0 +1,47 @@
+package dsp
+
+import "math"
+
+// FFT computes an in-place radix-2 FFT; len(x) must be a power of two
+func FFT(x []complex128) {
+	n := len(x)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	for size := 2; size <= n; size <<= 1 {
+		half := size >> 1
+		step := -2 * math.Pi / float64(size)
+		wStepRe, wStepIm := math.Cos(step), math.Sin(step)
+		wRe, wIm := 1.0, 0.0
+		for k := 0; k < half; k++ {
+			for start := k; start < n; start += size {
+				odd := x[start+half]
+				oddRe := wRe*real(odd) - wIm*imag(odd)
+				oddIm := wRe*imag(odd) + wIm*real(odd)
+				even := x[start]
+				x[start] = complex(real(even)+oddRe, imag(even)+oddIm)
+				x[start+half] = complex(real(even)-oddRe, imag(even)-oddIm)
+			}
+			wRe, wIm = wRe*wStepRe-wIm*wStepIm, wRe*wStepIm+wIm*wStepRe
+		}
+	}
+}
+
+// IFFT computes an in-place inverse FFT, scaled by 1/len(x)
+func IFFT(x []complex128) {
+	for i := range x {
+		x[i] = complex(real(x[i]), -imag(x[i]))
+	}
+	FFT(x)
+	scale := 1 / float64(len(x))
+	for i := range x {
+		x[i] = complex(real(x[i])*scale, -imag(x[i])*scale)
+	}
+}
@@ -0,0 +1,61 @@
+package dsp
+
+import "math"
+
+// HighPass is a second-order Butterworth high-pass filter that removes rumble,
+// handling noise and DC offset below the speech band
+type HighPass struct {
+	b0, b1, b2, a1, a2 float64
+	x1, x2, y1, y2     float64
+}
+
+// NewHighPass creates a high-pass filter with the given cutoff
+func NewHighPass(sampleRate int, cutoffHz float64) *HighPass {
+	// RBJ audio EQ cookbook coefficients with Q = 1/sqrt(2)
+	w0 := 2 * math.Pi * cutoffHz / float64(sampleRate)
+	alpha := math.Sin(w0) / math.Sqrt2 // sin(w0) / (2Q)
+	cosW0 := math.Cos(w0)
+	a0 := 1 + alpha
+
+	return &HighPass{
+		b0: (1 + cosW0) / 2 / a0,
+		b1: -(1 + cosW0) / a0,
+		b2: (1 + cosW0) / 2 / a0,
+		a1: -2 * cosW0 / a0,
+		a2: (1 - alpha) / a0,
+	}
+}
+
+// Process filters samples in place
+func (f *HighPass) Process(samples []float64) {
+	for i, x := range samples {
+		y := f.b0*x + f.b1*f.x1 + f.b2*f.x2 - f.a1*f.y1 - f.a2*f.y2
+		f.x2, f.x1 = f.x1, x
+		f.y2, f.y1 = f.y1, y
+		samples[i] = y
+	}
+}
+
+// Limiter softly compresses peaks above -1 dBFS so gain stages never hard-clip
+type Limiter struct {
+	threshold float64
+}
+
+// NewLimiter creates a soft-knee limiter
+func NewLimiter() *Limiter {
+	return &Limiter{threshold: dbToLinear(-1)}
+}
+
+// Process limits samples in place; output stays within -1..1
+func (l *Limiter) Process(samples []float64) {
+	t := l.threshold
+	knee := 1 - t
+	for i, v := range samples {
+		magnitude := math.Abs(v)
+		if magnitude <= t {
+			continue
+		}
+		limited := t + knee*math.Tanh((magnitude-t)/knee)
+		samples[i] = math.Copysign(limited, v)
+	}
+}
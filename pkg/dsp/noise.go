@@ -0,0 +1,142 @@
+package dsp
+
+import "math"
+
+const (
+	nsWindowMs = 10
+	// Noise estimate: slow rise (about 2dB/s at 200 hops/s), immediate fall
+	nsNoiseRise = 1.0025
+	// Smoothing of the per-bin power used for noise tracking
+	nsPowerSmoothing = 0.8
+	// Weight of the previous hop in the decision-directed a priori SNR
+	nsDecisionDirected = 0.98
+	// Hops averaged to seed the noise estimate
+	nsInitHops = 20
+)
+
+// NoiseSuppressor removes stationary background noise with a Wiener filter.
+// It runs a 50% overlap-add STFT with a sqrt-Hann window, tracks the noise spectrum
+// with minimum statistics, estimates the a priori SNR with the decision-directed
+// method and delays its output by one window (10ms).
+type NoiseSuppressor struct {
+	windowSize int
+	hop        int
+	fftSize    int
+	gainFloor  float64
+
+	window   []float64
+	spectrum []complex128
+	power    []float64
+	noise    []float64
+	clean    []float64 // previous hop's estimated speech power per bin
+	hops     int
+
+	input  []float64 // last windowSize input samples
+	filled int       // samples in input since the last hop
+	output []float64 // overlap-add accumulator, windowSize long
+	ready  []float64 // the previous hop of processed samples, emitted while the next one fills
+}
+
+// NewNoiseSuppressor creates a suppressor attenuating noise-only bins by up to suppressionDb
+func NewNoiseSuppressor(sampleRate int, suppressionDb float64) *NoiseSuppressor {
+	if suppressionDb <= 0 {
+		suppressionDb = DefaultConfig().SuppressionDb
+	}
+	windowSize := sampleRate * nsWindowMs / 1000
+	hop := windowSize / 2
+	fftSize := 1
+	for fftSize < windowSize {
+		fftSize <<= 1
+	}
+	bins := fftSize/2 + 1
+
+	window := make([]float64, windowSize)
+	for i := range window {
+		// Periodic sqrt-Hann: squared windows at 50% overlap sum to one
+		window[i] = math.Sqrt(0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(windowSize)))
+	}
+	return &NoiseSuppressor{
+		windowSize: windowSize,
+		hop:        hop,
+		fftSize:    fftSize,
+		gainFloor:  dbToLinear(-suppressionDb),
+		window:     window,
+		spectrum:   make([]complex128, fftSize),
+		power:      make([]float64, bins),
+		noise:      make([]float64, bins),
+		clean:      make([]float64, bins),
+		input:      make([]float64, windowSize),
+		output:     make([]float64, windowSize),
+		// Starts as one hop of silence so every call returns as many samples as it consumes
+		ready: make([]float64, hop),
+	}
+}
+
+// Process denoises samples in place, delayed by one hop
+func (n *NoiseSuppressor) Process(samples []float64) {
+	for i, x := range samples {
+		out := n.ready[n.filled]
+		n.input[n.windowSize-n.hop+n.filled] = x
+		n.filled++
+		if n.filled == n.hop {
+			n.processHop()
+			n.filled = 0
+		}
+		samples[i] = out
+	}
+}
+
+// processHop transforms the current window, applies the suppression gains and
+// overlap-adds the result, making one hop of output ready
+func (n *NoiseSuppressor) processHop() {
+	for i := range n.spectrum {
+		if i < n.windowSize {
+			n.spectrum[i] = complex(n.input[i]*n.window[i], 0)
+		} else {
+			n.spectrum[i] = 0
+		}
+	}
+	FFT(n.spectrum)
+
+	n.hops++
+	for k := range n.power {
+		re, im := real(n.spectrum[k]), imag(n.spectrum[k])
+		p := re*re + im*im
+		n.power[k] = nsPowerSmoothing*n.power[k] + (1-nsPowerSmoothing)*p
+
+		switch {
+		case n.hops <= nsInitHops:
+			n.noise[k] += (n.power[k] - n.noise[k]) / float64(n.hops)
+		case n.power[k] < n.noise[k]:
+			n.noise[k] = n.power[k]
+		default:
+			n.noise[k] *= nsNoiseRise
+		}
+
+		g := 1.0
+		if n.noise[k] > 0 {
+			posterior := p / n.noise[k]
+			prior := nsDecisionDirected*n.clean[k]/n.noise[k] + (1-nsDecisionDirected)*math.Max(posterior-1, 0)
+			g = math.Max(n.gainFloor, prior/(1+prior))
+		}
+		n.clean[k] = g * g * p
+
+		n.spectrum[k] *= complex(g, 0)
+		if k > 0 && k < n.fftSize/2 {
+			n.spectrum[n.fftSize-k] = complex(real(n.spectrum[k]), -imag(n.spectrum[k]))
+		}
+	}
+	IFFT(n.spectrum)
+
+	for i := 0; i < n.windowSize; i++ {
+		n.output[i] += real(n.spectrum[i]) * n.window[i]
+	}
+
+	// The first hop of the accumulator is complete
+	copy(n.ready, n.output[:n.hop])
+	copy(n.output, n.output[n.hop:])
+	for i := n.windowSize - n.hop; i < n.windowSize; i++ {
+		n.output[i] = 0
+	}
+	copy(n.input, n.input[n.hop:])
+}
@@ -0,0 +1,132 @@
+// Package g711 encodes and decodes ITU-T G.711 μ-law (PCMU) and A-law (PCMA) audio,
+// the codecs of SIP trunks and telephony media streams. Both carry 8 kHz mono audio
+// as one byte per sample.
+package g711
+
+const (
+	// SampleRate is the G.711 sample rate
+	SampleRate = 8000
+
+	ulawBias = 0x84
+	ulawClip = 32635
+)
+
+var (
+	ulawTable [256]int16
+	alawTable [256]int16
+)
+
+func init() {
+	for i := range ulawTable {
+		ulawTable[i] = decodeULaw(byte(i))
+		alawTable[i] = decodeALaw(byte(i))
+	}
+}
+
+// EncodeULaw appends the μ-law encoding of pcm to dst
+func EncodeULaw(dst []byte, pcm []int16) []byte {
+	for _, s := range pcm {
+		dst = append(dst, LinearToULaw(s))
+	}
+	return dst
+}
+
+// DecodeULaw appends the PCM16 samples of μ-law data to dst
+func DecodeULaw(dst []int16, data []byte) []int16 {
+	for _, b := range data {
+		dst = append(dst, ulawTable[b])
+	}
+	return dst
+}
+
+// EncodeALaw appends the A-law encoding of pcm to dst
+func EncodeALaw(dst []byte, pcm []int16) []byte {
+	for _, s := range pcm {
+		dst = append(dst, LinearToALaw(s))
+	}
+	return dst
+}
+
+// DecodeALaw appends the PCM16 samples of A-law data to dst
+func DecodeALaw(dst []int16, data []byte) []int16 {
+	for _, b := range data {
+		dst = append(dst, alawTable[b])
+	}
+	return dst
+}
+
+// LinearToULaw encodes one PCM16 sample
+func LinearToULaw(sample int16) byte {
+	s := int(sample)
+	sign := 0
+	if s < 0 {
+		s = -s
+		sign = 0x80
+	}
+	if s > ulawClip {
+		s = ulawClip
+	}
+	s += ulawBias
+
+	exponent := 7
+	for mask := 0x4000; s&mask == 0 && exponent > 0; mask >>= 1 {
+		exponent--
+	}
+	mantissa := (s >> (exponent + 3)) & 0x0f
+	return ^byte(sign | exponent<<4 | mantissa)
+}
+
+// LinearToALaw encodes one PCM16 sample
+func LinearToALaw(sample int16) byte {
+	s := int(sample) >> 3 // A-law works on 13-bit samples
+	sign := 0x80
+	if s < 0 {
+		s = -s - 1
+		sign = 0
+	}
+
+	var encoded int
+	if s < 32 {
+		encoded = s >> 1
+	} else {
+		exponent := 1
+		for v := s >> 5; v > 1 && exponent < 7; v >>= 1 {
+			exponent++
+		}
+		if s >= 4096 {
+			// Clip to the largest segment
+			encoded = 0x7f
+		} else {
+			encoded = exponent<<4 | (s>>exponent)&0x0f
+		}
+	}
+	return byte(sign|encoded) ^ 0x55
+}
+
+func decodeULaw(b byte) int16 {
+	b = ^b
+	exponent := int(b>>4) & 0x07
+	mantissa := int(b & 0x0f)
+	s := ((mantissa << 3) + ulawBias) << exponent
+	s -= ulawBias
+	if b&0x80 != 0 {
+		return int16(-s)
+	}
+	return int16(s)
+}
+
+func decodeALaw(b byte) int16 {
+	b ^= 0x55
+	exponent := int(b>>4) & 0x07
+	mantissa := int(b & 0x0f)
+	var s int
+	if exponent == 0 {
+		s = mantissa<<4 + 8
+	} else {
+		s = (mantissa<<4 + 0x108) << (exponent - 1)
+	}
+	if b&0x80 == 0 {
+		return int16(-s)
+	}
+	return int16(s)
+}
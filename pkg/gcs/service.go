@@ -28,10 +28,19 @@ func NewGCSClient(ctx context.Context, bucketName string) (*GCSClient, error) {
 }
 
 func (g *GCSClient) Upload(ctx context.Context, objectPath string, content io.Reader) (string, error) {
+	return g.UploadWithAttributes(ctx, objectPath, content, "", nil)
+}
+
+// UploadWithAttributes uploads content with an optional content type and custom object metadata
+func (g *GCSClient) UploadWithAttributes(ctx context.Context, objectPath string, content io.Reader, contentType string, metadata map[string]string) (string, error) {
 	bucket := g.client.Bucket(g.bucketName)
 	obj := bucket.Object(objectPath)
 
 	writer := obj.NewWriter(ctx)
+	writer.ContentType = contentType
+	if len(metadata) > 0 {
+		writer.Metadata = metadata
+	}
 	if _, err := io.Copy(writer, content); err != nil {
 		return "", fmt.Errorf("failed to copy content: %v", err)
 	}
@@ -42,6 +51,21 @@ func (g *GCSClient) Upload(ctx context.Context, objectPath string, content io.Re
 	return fmt.Sprintf("https://storage.googleapis.com/%s/%s", g.bucketName, objectPath), nil
 }
 
+// ErrObjectNotExist is returned by Download for missing objects
+var ErrObjectNotExist = storage.ErrObjectNotExist
+
+// Download opens a reader on an object in the client's bucket
+func (g *GCSClient) Download(ctx context.Context, objectPath string) (io.ReadCloser, error) {
+	reader, err := g.client.Bucket(g.bucketName).Object(objectPath).NewReader(ctx)
+	if err != nil {
+		if err == storage.ErrObjectNotExist {
+			return nil, ErrObjectNotExist
+		}
+		return nil, fmt.Errorf("failed to open object: %v", err)
+	}
+	return reader, nil
+}
+
 func (g *GCSClient) Delete(ctx context.Context, gcsURL string) error {
 
 	// Extract object path from GCS URL (format: gs://bucket-name/object-path)
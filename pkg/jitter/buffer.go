@@ -0,0 +1,348 @@
+// Package jitter reorders inbound Opus RTP packets, detects loss from sequence
+// gaps and reconstructs missing frames with Opus in-band FEC or decoder PLC.
+//
+// The buffer is driven by packet arrival: every pushed packet may release the
+// frames that are ready to decode. In-order packets are released immediately;
+// a missing packet is waited on until the packets queued behind it exceed the
+// target depth, which adapts to the measured interarrival jitter (RFC 3550).
+package jitter
+
+import (
+	"math"
+	"time"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	// maxSequenceJump resets the buffer instead of reporting a huge loss (stream restart)
+	maxSequenceJump = 1000
+	// maxConcealedFrames caps how many frames of a single gap are reconstructed;
+	// longer gaps are skipped rather than filled with synthetic audio
+	maxConcealedFrames = 5
+)
+
+// Config contains buffer depth limits
+type Config struct {
+	ClockRate    int           // RTP clock rate (48000 for Opus)
+	FrameSamples int           // samples per frame, used until a packet duration is known
+	MinDepth     time.Duration // lower bound of the adaptive target depth
+	MaxDepth     time.Duration // upper bound of the adaptive target depth
+	JitterFactor float64       // target depth as a multiple of the measured jitter
+}
+
+// DefaultConfig returns limits suited to 20ms Opus frames over mobile networks
+func DefaultConfig() Config {
+	return Config{
+		ClockRate:    48000,
+		FrameSamples: 960,
+		MinDepth:     20 * time.Millisecond,
+		MaxDepth:     200 * time.Millisecond,
+		JitterFactor: 3,
+	}
+}
+
+// Frame is a frame released by the buffer in sequence order
+type Frame struct {
+	SequenceNumber uint16
+	Timestamp      uint32
+	Packet         *rtp.Packet // nil if the packet was lost
+	Samples        int         // expected duration of a lost frame in samples
+	FEC            []byte      // payload of the following packet, carrying FEC for a lost frame
+}
+
+// Lost reports whether the frame has to be reconstructed
+func (f Frame) Lost() bool {
+	return f.Packet == nil
+}
+
+// Stats describes the stream as seen by the buffer
+type Stats struct {
+	Received     int64
+	Lost         int64
+	Late         int64 // arrived after their slot was released or given up on
+	Duplicate    int64
+	Recovered    int64 // lost frames decoded from the next packet's FEC
+	Concealed    int64 // lost frames synthesized by PLC
+	Skipped      int64 // lost frames beyond the concealment limit
+	Jitter       time.Duration
+	TargetDepth  time.Duration
+	BufferedSize int
+}
+
+// LossRate returns the fraction of expected packets that never arrived in time
+func (s Stats) LossRate() float64 {
+	expected := s.Received + s.Lost
+	if expected == 0 {
+		return 0
+	}
+	return float64(s.Lost) / float64(expected)
+}
+
+type entry struct {
+	packet  *rtp.Packet
+	arrival time.Time
+}
+
+// Buffer reorders packets of a single RTP stream. It is not safe for concurrent use.
+type Buffer struct {
+	cfg Config
+
+	started   bool
+	next      uint64 // extended sequence number expected next
+	highest   uint64 // highest extended sequence number seen
+	hasLast   bool
+	lastTS    uint32 // timestamp of the last released packet
+	lastSize  int    // duration of the last released packet in samples
+	pending   map[uint64]entry
+	jitter    float64 // RFC 3550 interarrival jitter in timestamp units
+	lastTrans float64
+	hasTrans  bool
+	stats     Stats
+}
+
+// NewBuffer creates a jitter buffer; zero-valued config fields take their defaults
+func NewBuffer(cfg Config) *Buffer {
+	def := DefaultConfig()
+	if cfg.ClockRate <= 0 {
+		cfg.ClockRate = def.ClockRate
+	}
+	if cfg.FrameSamples <= 0 {
+		cfg.FrameSamples = def.FrameSamples
+	}
+	if cfg.MinDepth <= 0 {
+		cfg.MinDepth = def.MinDepth
+	}
+	if cfg.MaxDepth < cfg.MinDepth {
+		cfg.MaxDepth = max(def.MaxDepth, cfg.MinDepth)
+	}
+	if cfg.JitterFactor <= 0 {
+		cfg.JitterFactor = def.JitterFactor
+	}
+	return &Buffer{
+		cfg:      cfg,
+		lastSize: cfg.FrameSamples,
+		pending:  make(map[uint64]entry),
+	}
+}
+
+// Push adds a packet and returns the frames that are ready, in sequence order
+func (b *Buffer) Push(packet *rtp.Packet, arrival time.Time) []Frame {
+	if packet == nil {
+		return nil
+	}
+	b.updateJitter(packet.Timestamp, arrival)
+
+	if !b.started {
+		b.started = true
+		b.next = uint64(packet.SequenceNumber)
+		b.highest = b.next
+	}
+
+	seq := b.extend(packet.SequenceNumber)
+	switch {
+	case seq+maxSequenceJump < b.next || seq > b.highest+maxSequenceJump:
+		// The sender restarted its sequence; release what we hold and start over
+		frames := b.Flush()
+		b.next = uint64(packet.SequenceNumber)
+		b.highest = b.next
+		seq = b.next
+		b.stats.Received++
+		b.pending[seq] = entry{packet: packet, arrival: arrival}
+		return append(frames, b.release(arrival)...)
+	case seq < b.next:
+		b.stats.Late++
+		return nil
+	}
+	if _, ok := b.pending[seq]; ok {
+		b.stats.Duplicate++
+		return nil
+	}
+
+	b.stats.Received++
+	b.pending[seq] = entry{packet: packet, arrival: arrival}
+	if seq > b.highest {
+		b.highest = seq
+	}
+	return b.release(arrival)
+}
+
+// Flush releases every buffered packet, concealing gaps, e.g. when the stream ends
+func (b *Buffer) Flush() []Frame {
+	var frames []Frame
+	for len(b.pending) > 0 {
+		frames = append(frames, b.advance()...)
+	}
+	return frames
+}
+
+// Stats returns a snapshot of the stream statistics
+func (b *Buffer) Stats() Stats {
+	stats := b.stats
+	stats.Jitter = b.toDuration(b.jitter)
+	stats.TargetDepth = b.targetDepth()
+	stats.BufferedSize = len(b.pending)
+	return stats
+}
+
+// release emits in-order packets and gives up on missing ones once enough audio is queued behind them
+func (b *Buffer) release(now time.Time) []Frame {
+	var frames []Frame
+	for len(b.pending) > 0 {
+		if _, ok := b.pending[b.next]; ok {
+			frames = append(frames, b.advance()...)
+			continue
+		}
+		if !b.shouldGiveUp(now) {
+			break
+		}
+		frames = append(frames, b.advance()...)
+	}
+	return frames
+}
+
+// shouldGiveUp reports whether the missing packet at b.next has been waited on long enough:
+// either the queued packets exceed the target depth or the oldest one has waited that long
+func (b *Buffer) shouldGiveUp(now time.Time) bool {
+	depth := b.targetDepth()
+	queued := time.Duration(b.highest-b.next) * b.toDuration(float64(b.cfg.FrameSamples))
+	if queued > depth {
+		return true
+	}
+	for _, e := range b.pending {
+		if now.Sub(e.arrival) >= depth {
+			return true
+		}
+	}
+	return false
+}
+
+// advance releases the frame at b.next, or the whole gap up to the next buffered packet
+func (b *Buffer) advance() []Frame {
+	if e, ok := b.pending[b.next]; ok {
+		delete(b.pending, b.next)
+		b.next++
+		if size := opusPacketSamples(e.packet.Payload, b.cfg.ClockRate); size > 0 {
+			b.lastSize = size
+		}
+		b.lastTS = e.packet.Timestamp
+		b.hasLast = true
+		return []Frame{{SequenceNumber: e.packet.SequenceNumber, Timestamp: e.packet.Timestamp, Packet: e.packet}}
+	}
+
+	// Find the end of the gap
+	end := b.next
+	for end <= b.highest {
+		if _, ok := b.pending[end]; ok {
+			break
+		}
+		end++
+	}
+	gap := int(end - b.next)
+	following, hasFollowing := b.pending[end]
+
+	// Lost packets are assumed to have the same duration as the last one received
+	frameSamples := b.lastSize
+
+	var frames []Frame
+	for i := 0; i < gap; i++ {
+		b.stats.Lost++
+		if gap-i > maxConcealedFrames {
+			// Only the frames closest to the next packet are reconstructed
+			b.stats.Skipped++
+			continue
+		}
+		frame := Frame{
+			SequenceNumber: uint16(b.next + uint64(i)),
+			Timestamp:      b.lastTS + uint32(frameSamples*(i+1)),
+			Samples:        frameSamples,
+		}
+		// FEC in the following packet only describes the frame right before it
+		if i == gap-1 && hasFollowing {
+			frame.FEC = following.packet.Payload
+		}
+		frames = append(frames, frame)
+	}
+	b.next = end
+	if b.hasLast {
+		b.lastTS += uint32(frameSamples * gap)
+	}
+	return frames
+}
+
+// extend maps a 16-bit sequence number to the extended sequence closest to the highest seen
+func (b *Buffer) extend(seq uint16) uint64 {
+	cycle := b.highest &^ 0xFFFF
+	candidate := cycle | uint64(seq)
+	switch {
+	case candidate+0x8000 < b.highest:
+		candidate += 0x10000
+	case candidate > b.highest+0x8000 && candidate >= 0x10000:
+		candidate -= 0x10000
+	}
+	return candidate
+}
+
+// updateJitter applies the RFC 3550 interarrival jitter estimator
+func (b *Buffer) updateJitter(timestamp uint32, arrival time.Time) {
+	arrivalUnits := float64(arrival.UnixNano()) * float64(b.cfg.ClockRate) / float64(time.Second)
+	transit := arrivalUnits - float64(timestamp)
+	if b.hasTrans {
+		d := math.Abs(transit - b.lastTrans)
+		// Ignore timestamp jumps from DTX or clock resets
+		if d < float64(b.cfg.ClockRate) {
+			b.jitter += (d - b.jitter) / 16
+		}
+	}
+	b.lastTrans = transit
+	b.hasTrans = true
+}
+
+// targetDepth returns the adaptive buffering target
+func (b *Buffer) targetDepth() time.Duration {
+	depth := time.Duration(b.cfg.JitterFactor * float64(b.toDuration(b.jitter)))
+	if depth < b.cfg.MinDepth {
+		return b.cfg.MinDepth
+	}
+	if depth > b.cfg.MaxDepth {
+		return b.cfg.MaxDepth
+	}
+	return depth
+}
+
+// toDuration converts timestamp units to a duration
+func (b *Buffer) toDuration(units float64) time.Duration {
+	return time.Duration(units / float64(b.cfg.ClockRate) * float64(time.Second))
+}
+
+// opusPacketSamples returns the duration of an Opus packet from its TOC byte (RFC 6716 section 3.1)
+func opusPacketSamples(payload []byte, clockRate int) int {
+	if len(payload) == 0 {
+		return 0
+	}
+	toc := payload[0]
+	config := int(toc >> 3)
+
+	// Frame duration in units of 2.5ms
+	var units int
+	switch {
+	case config < 12: // SILK: 10, 20, 40, 60ms
+		units = []int{4, 8, 16, 24}[config%4]
+	case config < 16: // Hybrid: 10, 20ms
+		units = []int{4, 8}[config%2]
+	default: // CELT: 2.5, 5, 10, 20ms
+		units = []int{1, 2, 4, 8}[config%4]
+	}
+
+	frames := 1
+	switch toc & 0x3 {
+	case 1, 2:
+		frames = 2
+	case 3:
+		if len(payload) < 2 {
+			return 0
+		}
+		frames = int(payload[1] & 0x3F)
+	}
+	return frames * units * clockRate / 400
+}
@@ -0,0 +1,237 @@
+package jitter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"layeh.com/gopus"
+)
+
+// 20ms CELT fullband packet; the TOC byte is all the buffer looks at
+var celt20ms = []byte{0xF8, 0xFF, 0xFE}
+
+// push is one packet of a crafted sequence: the sequence number it carries and the
+// frame index its timestamp and arrival slot are derived from
+type push struct {
+	seq   uint16
+	frame int
+}
+
+func packetAt(seq uint16, frame int) *rtp.Packet {
+	payload := append([]byte(nil), celt20ms...)
+	payload[1] = byte(seq) // distinguishes payloads so FEC sources can be checked
+	return &rtp.Packet{
+		Header:  rtp.Header{SequenceNumber: seq, Timestamp: uint32(1000 + frame*960)},
+		Payload: payload,
+	}
+}
+
+// released describes an expected frame: its sequence number, whether it was lost and,
+// for lost frames, the sequence number of the packet whose FEC it carries (-1 for none)
+type released struct {
+	seq     uint16
+	lost    bool
+	fecFrom int
+}
+
+func TestBufferSequences(t *testing.T) {
+	tests := []struct {
+		name   string
+		pushes []push
+		flush  bool
+		want   []released
+		stats  Stats
+	}{
+		{
+			name:   "in order",
+			pushes: []push{{1, 0}, {2, 1}, {3, 2}, {4, 3}},
+			want:   []released{{1, false, -1}, {2, false, -1}, {3, false, -1}, {4, false, -1}},
+			stats:  Stats{Received: 4},
+		},
+		{
+			name:   "reordered within target depth",
+			pushes: []push{{1, 0}, {3, 2}, {2, 1}, {5, 4}, {4, 3}},
+			want:   []released{{1, false, -1}, {2, false, -1}, {3, false, -1}, {4, false, -1}, {5, false, -1}},
+			stats:  Stats{Received: 5},
+		},
+		{
+			name:   "duplicate of a buffered packet",
+			pushes: []push{{1, 0}, {3, 2}, {3, 2}, {2, 1}},
+			want:   []released{{1, false, -1}, {2, false, -1}, {3, false, -1}},
+			stats:  Stats{Received: 3, Duplicate: 1},
+		},
+		{
+			name:   "duplicate of a released packet is late",
+			pushes: []push{{1, 0}, {2, 1}, {1, 0}, {3, 2}},
+			want:   []released{{1, false, -1}, {2, false, -1}, {3, false, -1}},
+			stats:  Stats{Received: 3, Late: 1},
+		},
+		{
+			name:   "single loss recovered from the next packet's FEC",
+			pushes: []push{{1, 0}, {2, 1}, {4, 3}, {5, 4}, {6, 5}},
+			want:   []released{{1, false, -1}, {2, false, -1}, {3, true, 4}, {4, false, -1}, {5, false, -1}, {6, false, -1}},
+			stats:  Stats{Received: 5, Lost: 1},
+		},
+		{
+			name:   "burst loss conceals frames, FEC only for the last",
+			pushes: []push{{1, 0}, {4, 3}, {5, 4}},
+			want:   []released{{1, false, -1}, {2, true, -1}, {3, true, 4}, {4, false, -1}, {5, false, -1}},
+			stats:  Stats{Received: 3, Lost: 2},
+		},
+		{
+			name:   "long gap skips frames beyond the concealment limit",
+			pushes: []push{{1, 0}, {10, 9}, {11, 10}},
+			want:   []released{{1, false, -1}, {5, true, -1}, {6, true, -1}, {7, true, -1}, {8, true, -1}, {9, true, 10}, {10, false, -1}, {11, false, -1}},
+			stats:  Stats{Received: 3, Lost: 8, Skipped: 3},
+		},
+		{
+			name:   "packet arriving after its gap was given up on",
+			pushes: []push{{1, 0}, {3, 2}, {4, 3}, {2, 1}},
+			want:   []released{{1, false, -1}, {2, true, 3}, {3, false, -1}, {4, false, -1}},
+			stats:  Stats{Received: 3, Lost: 1, Late: 1},
+		},
+		{
+			name:   "sequence wraparound",
+			pushes: []push{{65534, 0}, {0, 2}, {65535, 1}, {1, 3}},
+			want:   []released{{65534, false, -1}, {65535, false, -1}, {0, false, -1}, {1, false, -1}},
+			stats:  Stats{Received: 4},
+		},
+		{
+			name:   "flush conceals a trailing gap",
+			pushes: []push{{1, 0}, {3, 2}},
+			flush:  true,
+			want:   []released{{1, false, -1}, {2, true, 3}, {3, false, -1}},
+			stats:  Stats{Received: 2, Lost: 1},
+		},
+		{
+			name:   "sender restart resets instead of reporting loss",
+			pushes: []push{{100, 0}, {101, 1}, {5000, 2}, {5001, 3}},
+			want:   []released{{100, false, -1}, {101, false, -1}, {5000, false, -1}, {5001, false, -1}},
+			stats:  Stats{Received: 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := NewBuffer(DefaultConfig())
+			start := time.Unix(1700000000, 0)
+
+			var frames []Frame
+			for i, p := range tt.pushes {
+				// Packets arrive on a steady 20ms clock in push order
+				frames = append(frames, buffer.Push(packetAt(p.seq, p.frame), start.Add(time.Duration(i)*20*time.Millisecond))...)
+			}
+			if tt.flush {
+				frames = append(frames, buffer.Flush()...)
+			}
+
+			if len(frames) != len(tt.want) {
+				t.Fatalf("released %d frames %v, want %d", len(frames), sequenceNumbers(frames), len(tt.want))
+			}
+			for i, want := range tt.want {
+				frame := frames[i]
+				if frame.SequenceNumber != want.seq || frame.Lost() != want.lost {
+					t.Fatalf("frame %d: seq %d lost %v, want seq %d lost %v", i, frame.SequenceNumber, frame.Lost(), want.seq, want.lost)
+				}
+				if !frame.Lost() {
+					continue
+				}
+				if frame.Samples != 960 {
+					t.Errorf("frame %d: %d samples, want 960", i, frame.Samples)
+				}
+				switch {
+				case want.fecFrom < 0 && frame.FEC != nil:
+					t.Errorf("frame %d: unexpected FEC payload", i)
+				case want.fecFrom >= 0 && (len(frame.FEC) < 2 || frame.FEC[1] != byte(want.fecFrom)):
+					t.Errorf("frame %d: FEC payload %v, want the payload of seq %d", i, frame.FEC, want.fecFrom)
+				}
+			}
+
+			stats := buffer.Stats()
+			got := Stats{Received: stats.Received, Lost: stats.Lost, Late: stats.Late, Duplicate: stats.Duplicate, Skipped: stats.Skipped}
+			if got != tt.stats {
+				t.Errorf("stats %+v, want %+v", got, tt.stats)
+			}
+		})
+	}
+}
+
+// TestLostFrameTimestamps checks lost frames are placed right after the last released
+// packet using its duration
+func TestLostFrameTimestamps(t *testing.T) {
+	buffer := NewBuffer(DefaultConfig())
+	start := time.Unix(1700000000, 0)
+
+	var frames []Frame
+	for i, p := range []push{{1, 0}, {4, 3}, {5, 4}} {
+		frames = append(frames, buffer.Push(packetAt(p.seq, p.frame), start.Add(time.Duration(i)*20*time.Millisecond))...)
+	}
+	for _, frame := range frames {
+		if want := uint32(1000 + (int(frame.SequenceNumber)-1)*960); frame.Timestamp != want {
+			t.Errorf("seq %d: timestamp %d, want %d", frame.SequenceNumber, frame.Timestamp, want)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	encoder, err := gopus.NewEncoder(48000, 1, gopus.Voip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pcm := make([]int16, 960)
+	for i := range pcm {
+		pcm[i] = int16(8000 * ((i/40)%2*2 - 1))
+	}
+	encoded, err := encoder.Encode(pcm, 960, 1500)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		frame     Frame
+		recovered int64
+		concealed int64
+	}{
+		{"received packet", Frame{Packet: &rtp.Packet{Payload: encoded}}, 0, 0},
+		{"lost with FEC from the next packet", Frame{Samples: 960, FEC: encoded}, 1, 0},
+		{"lost without a following packet", Frame{Samples: 960}, 0, 1},
+		{"lost next to a DTX packet", Frame{Samples: 960, FEC: []byte{0xF8}}, 0, 1},
+		{"lost with unknown duration", Frame{}, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := NewBuffer(DefaultConfig())
+			decoder, err := gopus.NewDecoder(48000, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Prime the decoder so PLC has audio to extend
+			if _, err := decoder.Decode(encoded, 960, false); err != nil {
+				t.Fatal(err)
+			}
+
+			out, err := buffer.Decode(decoder, tt.frame, 5760)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(out) != 960 {
+				t.Errorf("decoded %d samples, want 960", len(out))
+			}
+			stats := buffer.Stats()
+			if stats.Recovered != tt.recovered || stats.Concealed != tt.concealed {
+				t.Errorf("recovered %d concealed %d, want %d and %d", stats.Recovered, stats.Concealed, tt.recovered, tt.concealed)
+			}
+		})
+	}
+}
+
+func sequenceNumbers(frames []Frame) []uint16 {
+	seqs := make([]uint16, len(frames))
+	for i, frame := range frames {
+		seqs[i] = frame.SequenceNumber
+	}
+	return seqs
+}
@@ -0,0 +1,36 @@
+package jitter
+
+import (
+	"layeh.com/gopus"
+)
+
+// minFECPayload skips FEC for DTX/comfort-noise packets, which carry no redundancy
+const minFECPayload = 3
+
+// Decode turns a released frame into PCM. Lost frames are rebuilt from the in-band FEC
+// of the following packet when it is available, otherwise concealed by the decoder's PLC.
+// maxSamples bounds the output of a normal decode.
+func (b *Buffer) Decode(decoder *gopus.Decoder, frame Frame, maxSamples int) ([]int16, error) {
+	if !frame.Lost() {
+		return decoder.Decode(frame.Packet.Payload, maxSamples, false)
+	}
+
+	samples := frame.Samples
+	if samples <= 0 || samples > maxSamples {
+		samples = min(b.cfg.FrameSamples, maxSamples)
+	}
+	if len(frame.FEC) >= minFECPayload {
+		// libopus falls back to PLC itself if the packet carries no FEC data
+		pcm, err := decoder.Decode(frame.FEC, samples, true)
+		if err == nil {
+			b.stats.Recovered++
+			return pcm, nil
+		}
+	}
+	pcm, err := decoder.Decode(nil, samples, false)
+	if err != nil {
+		return nil, err
+	}
+	b.stats.Concealed++
+	return pcm, nil
+}
@@ -0,0 +1,33 @@
+package jitter
+
+import (
+	"github.com/ClareAI/astra-voice-service/pkg/metrics"
+)
+
+// Reporter exports the statistics of one stream to Prometheus
+type Reporter struct {
+	channel string
+	last    Stats
+}
+
+// NewReporter creates a reporter labelled with the call channel
+func NewReporter(channel string) *Reporter {
+	return &Reporter{channel: channel}
+}
+
+// Report adds the counters accumulated since the previous report and samples the jitter
+func (r *Reporter) Report(stats Stats) {
+	add := func(result string, current, previous int64) {
+		if delta := current - previous; delta > 0 {
+			metrics.RTPPacketsTotal.WithLabelValues(r.channel, result).Add(float64(delta))
+		}
+	}
+	add(metrics.RTPReceived, stats.Received, r.last.Received)
+	add(metrics.RTPLost, stats.Lost, r.last.Lost)
+	add(metrics.RTPLate, stats.Late, r.last.Late)
+	add(metrics.RTPDuplicate, stats.Duplicate, r.last.Duplicate)
+	add(metrics.RTPRecovered, stats.Recovered, r.last.Recovered)
+	add(metrics.RTPConcealed, stats.Concealed, r.last.Concealed)
+	metrics.RTPJitterSeconds.WithLabelValues(r.channel).Observe(stats.Jitter.Seconds())
+	r.last = stats
+}
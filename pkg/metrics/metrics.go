@@ -0,0 +1,260 @@
+package metrics
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "astra_voice"
+
+// Call setup outcomes
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Event handler outcomes
+const (
+	HandlerOK    = "ok"
+	HandlerPanic = "panic"
+)
+
+// ToolOther labels tools outside the registered built-in set, e.g. per-agent MCP tools,
+// so tool metrics keep a bounded label set
+const ToolOther = "other"
+
+// Audio packet results
+const (
+	AudioForwarded = "forwarded"
+	AudioDropped   = "dropped"
+	AudioGated     = "gated" // held back by local voice activity detection
+)
+
+// Inbound RTP results
+const (
+	RTPReceived  = "received"
+	RTPLost      = "lost"
+	RTPLate      = "late"
+	RTPDuplicate = "duplicate"
+	RTPRecovered = "fec_recovered"
+	RTPConcealed = "concealed"
+)
+
+// Audio directions
+const (
+	DirectionInbound  = "inbound"  // caller -> model
+	DirectionOutbound = "outbound" // model -> caller
+)
+
+var registry = prometheus.NewRegistry()
+
+var (
+	// CallSetupTotal counts call setup attempts by channel, provider, stage and outcome.
+	CallSetupTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "call_setup_total",
+		Help:      "Call setup attempts by channel, provider, stage and outcome.",
+	}, []string{"channel", "provider", "stage", "outcome"})
+
+	// EventsPublishedTotal counts events published on the in-process event bus.
+	EventsPublishedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "event_bus_published_total",
+		Help:      "Events published on the event bus by type.",
+	}, []string{"event_type"})
+
+	// EventHandlersTotal counts event handler executions by type and status.
+	EventHandlersTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "event_bus_handler_total",
+		Help:      "Event handler executions by type and outcome (ok or panic).",
+	}, []string{"event_type", "status"})
+
+	// EventHandlerDuration observes event handler latency.
+	EventHandlerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "event_bus_handler_duration_seconds",
+		Help:      "Event handler latency by type.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 8),
+	}, []string{"event_type"})
+
+	// ToolCallsTotal counts tool executions by tool and status.
+	ToolCallsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tool_calls_total",
+		Help:      "Tool executions by built-in tool (other for MCP tools) and status.",
+	}, []string{"tool", "status"})
+
+	// ToolCallDuration observes tool execution latency.
+	ToolCallDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "tool_call_duration_seconds",
+		Help:      "Tool execution latency by built-in tool (other for MCP tools).",
+		Buckets:   prometheus.ExponentialBuckets(0.05, 2, 10),
+	}, []string{"tool"})
+
+	// RAGQueriesTotal counts knowledge base queries by status (ok, error, timeout).
+	RAGQueriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rag_queries_total",
+		Help:      "Knowledge base queries by status.",
+	}, []string{"status"})
+
+	// WatiRequestDuration observes Wati API latency by operation.
+	WatiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "wati_request_duration_seconds",
+		Help:      "Wati API request latency by operation.",
+		Buckets:   prometheus.ExponentialBuckets(0.05, 2, 10),
+	}, []string{"operation"})
+
+	// WatiRequestErrorsTotal counts failed Wati API requests by operation.
+	WatiRequestErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "wati_request_errors_total",
+		Help:      "Failed Wati API requests by operation.",
+	}, []string{"operation"})
+
+	// AudioPacketsTotal counts audio packets by channel, direction and result.
+	AudioPacketsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "audio_packets_total",
+		Help:      "Audio packets by channel, direction and result (forwarded, dropped or gated).",
+	}, []string{"channel", "direction", "result"})
+
+	// RTPPacketsTotal counts inbound RTP packets by channel and jitter buffer result.
+	RTPPacketsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rtp_packets_total",
+		Help:      "Inbound RTP packets by channel and jitter buffer result (received, lost, late, duplicate, fec_recovered, concealed).",
+	}, []string{"channel", "result"})
+
+	// RTPJitterSeconds observes the interarrival jitter of inbound RTP streams.
+	RTPJitterSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "rtp_jitter_seconds",
+		Help:      "Interarrival jitter of inbound RTP streams by channel, sampled periodically.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 10),
+	}, []string{"channel"})
+
+	// AudioDSPFrameDuration observes the time the inbound DSP chain spends per frame.
+	AudioDSPFrameDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "audio_dsp_frame_duration_seconds",
+		Help:      "Inbound audio DSP processing time per frame by channel.",
+		Buckets:   prometheus.ExponentialBuckets(0.00002, 2, 10),
+	}, []string{"channel"})
+
+	// RecordingUploadFailuresTotal counts failed call recording uploads by storage and stage.
+	RecordingUploadFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "recording_upload_failures_total",
+		Help:      "Failed call recording uploads by storage type and stage.",
+	}, []string{"storage", "stage"})
+)
+
+// ConnectionLabels identifies a group of active connections.
+type ConnectionLabels struct {
+	Channel  string
+	Provider string
+}
+
+var (
+	activeConnectionsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "active_connections"),
+		"Active call connections by channel and model provider.",
+		[]string{"channel", "provider"}, nil,
+	)
+	connectionSourceMu sync.RWMutex
+	connectionSource   func() map[ConnectionLabels]int
+)
+
+// activeConnectionsCollector reads active connections at scrape time so the gauge never drifts.
+type activeConnectionsCollector struct{}
+
+func (activeConnectionsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- activeConnectionsDesc
+}
+
+func (activeConnectionsCollector) Collect(ch chan<- prometheus.Metric) {
+	connectionSourceMu.RLock()
+	source := connectionSource
+	connectionSourceMu.RUnlock()
+	if source == nil {
+		return
+	}
+	for labels, count := range source() {
+		ch <- prometheus.MustNewConstMetric(activeConnectionsDesc, prometheus.GaugeValue, float64(count), labels.Channel, labels.Provider)
+	}
+}
+
+// SetConnectionSource registers the function used to count active connections at scrape time.
+func SetConnectionSource(fn func() map[ConnectionLabels]int) {
+	connectionSourceMu.Lock()
+	defer connectionSourceMu.Unlock()
+	connectionSource = fn
+}
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		activeConnectionsCollector{},
+		CallSetupTotal,
+		EventsPublishedTotal,
+		EventHandlersTotal,
+		EventHandlerDuration,
+		ToolCallsTotal,
+		ToolCallDuration,
+		RAGQueriesTotal,
+		WatiRequestDuration,
+		WatiRequestErrorsTotal,
+		AudioPacketsTotal,
+		AudioDSPFrameDuration,
+		RTPPacketsTotal,
+		RTPJitterSeconds,
+		RecordingUploadFailuresTotal,
+	)
+}
+
+// Handler returns the HTTP handler serving the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// ObserveCallSetup records the outcome of a call setup stage.
+func ObserveCallSetup(channel, provider, stage string, err error) {
+	outcome := OutcomeSuccess
+	if err != nil {
+		outcome = OutcomeFailure
+	}
+	CallSetupTotal.WithLabelValues(channel, provider, stage, outcome).Inc()
+}
+
+// ObserveToolCall records a tool execution. Intended to be deferred with a pointer to the named error result.
+func ObserveToolCall(tool string, start time.Time, err *error) {
+	status := "success"
+	if err != nil && *err != nil {
+		status = "error"
+	}
+	ToolCallsTotal.WithLabelValues(tool, status).Inc()
+	ToolCallDuration.WithLabelValues(tool).Observe(time.Since(start).Seconds())
+}
+
+// ObserveWatiRequest records a Wati API request. Intended to be deferred with a pointer to the named error result.
+func ObserveWatiRequest(operation string, start time.Time, err *error) {
+	WatiRequestDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if err != nil && *err != nil {
+		WatiRequestErrorsTotal.WithLabelValues(operation).Inc()
+	}
+}
+
+// IncAudioPacket records a single audio packet.
+func IncAudioPacket(channel, direction, result string) {
+	AudioPacketsTotal.WithLabelValues(channel, direction, result).Inc()
+}
@@ -0,0 +1,282 @@
+// Package mixer combines several audio sources into one outbound Opus stream.
+//
+// Each source (model speech, background ambience, hold music, a supervisor's voice)
+// is decoded to PCM, scaled by its gain and summed every 20ms. While a ducking source
+// such as speech plays, ducked sources are attenuated with a smooth attack and release.
+// The mix is limited, re-encoded to Opus and written to the sink at a steady 20ms
+// cadence; ticks where no source has audio write nothing, like DTX.
+package mixer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ClareAI/astra-voice-service/pkg/dsp"
+	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"go.uber.org/zap"
+	"layeh.com/gopus"
+)
+
+const (
+	// SampleRate is the mixing and output rate
+	SampleRate = 48000
+	// FrameSamples is one 20ms mono frame
+	FrameSamples = 960
+	// FrameDuration is the output cadence
+	FrameDuration = 20 * time.Millisecond
+
+	defaultMaxBufferMs = 2000
+	maxPacketBytes     = 1275 // largest Opus packet for one frame
+)
+
+// Sink receives the mixed Opus frames (a WhatsApp or LiveKit output track)
+type Sink interface {
+	WriteOpusFrame(opusPayload []byte) error
+}
+
+// Config tunes the mixer
+type Config struct {
+	Bitrate       int     // Opus bitrate in bits/s
+	DuckingDb     float64 // attenuation of ducked sources while a ducking source plays
+	DuckAttackMs  int     // time to reach the ducked level
+	DuckReleaseMs int     // time to recover after the ducking source stops
+}
+
+// DefaultConfig returns settings suited to speech over background audio
+func DefaultConfig() Config {
+	return Config{
+		Bitrate:       32000,
+		DuckingDb:     12,
+		DuckAttackMs:  40,
+		DuckReleaseMs: 500,
+	}
+}
+
+// Mixer mixes its sources and writes the result to a sink every 20ms
+type Mixer struct {
+	cfg     Config
+	sink    Sink
+	encoder *gopus.Encoder
+	limiter *dsp.Limiter
+
+	mu      sync.Mutex
+	sources []*Source
+
+	// Mixing state, only touched by the mixing goroutine
+	duckGain    float64
+	duckTarget  float64
+	attackCoef  float64
+	releaseCoef float64
+	mix         []float64
+	frame       []float64
+	pcm         []int16
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// New creates a mixer writing to sink; zero config fields take the defaults
+func New(sink Sink, cfg Config) (*Mixer, error) {
+	if sink == nil {
+		return nil, fmt.Errorf("mixer sink is required")
+	}
+	def := DefaultConfig()
+	if cfg.Bitrate <= 0 {
+		cfg.Bitrate = def.Bitrate
+	}
+	if cfg.DuckingDb <= 0 {
+		cfg.DuckingDb = def.DuckingDb
+	}
+	if cfg.DuckAttackMs <= 0 {
+		cfg.DuckAttackMs = def.DuckAttackMs
+	}
+	if cfg.DuckReleaseMs <= 0 {
+		cfg.DuckReleaseMs = def.DuckReleaseMs
+	}
+
+	encoder, err := gopus.NewEncoder(SampleRate, 1, gopus.Audio)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init opus encoder: %w", err)
+	}
+	encoder.SetBitrate(cfg.Bitrate)
+
+	frameMs := float64(FrameDuration / time.Millisecond)
+	return &Mixer{
+		cfg:         cfg,
+		sink:        sink,
+		encoder:     encoder,
+		limiter:     dsp.NewLimiter(),
+		duckGain:    1,
+		duckTarget:  dbToLinear(-cfg.DuckingDb),
+		attackCoef:  1 - math.Exp(-frameMs/float64(cfg.DuckAttackMs)),
+		releaseCoef: 1 - math.Exp(-frameMs/float64(cfg.DuckReleaseMs)),
+		mix:         make([]float64, FrameSamples),
+		frame:       make([]float64, FrameSamples),
+		pcm:         make([]int16, FrameSamples),
+		stop:        make(chan struct{}),
+	}, nil
+}
+
+// AddSource adds a source, replacing any source with the same name
+func (m *Mixer) AddSource(name string, cfg SourceConfig) *Source {
+	source := newSource(name, cfg)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, existing := range m.sources {
+		if existing.name == name {
+			existing.close()
+			m.sources[i] = source
+			return source
+		}
+	}
+	m.sources = append(m.sources, source)
+	return source
+}
+
+// Source returns the named source, or nil
+func (m *Mixer) Source(name string) *Source {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, source := range m.sources {
+		if source.name == name {
+			return source
+		}
+	}
+	return nil
+}
+
+// RemoveSource removes and closes the named source
+func (m *Mixer) RemoveSource(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, source := range m.sources {
+		if source.name == name {
+			source.close()
+			m.sources = append(m.sources[:i], m.sources[i+1:]...)
+			return
+		}
+	}
+}
+
+// Start runs the mixing loop until ctx is done or Close is called
+func (m *Mixer) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(FrameDuration)
+		defer ticker.Stop()
+
+		var writeErrors int
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-m.stop:
+				return
+			case <-ticker.C:
+				payload, ok, err := m.tick()
+				if err != nil {
+					logger.Base().Error("Failed to encode mixed audio", zap.Error(err))
+					continue
+				}
+				if !ok {
+					continue
+				}
+				if err := m.sink.WriteOpusFrame(payload); err != nil {
+					if writeErrors < 3 {
+						logger.Base().Error("Failed to write mixed audio", zap.Error(err))
+					}
+					writeErrors++
+				}
+			}
+		}
+	}()
+}
+
+// Close stops the mixing loop and closes every source
+func (m *Mixer) Close() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+	m.mu.Lock()
+	for _, source := range m.sources {
+		source.close()
+	}
+	m.sources = nil
+	m.mu.Unlock()
+}
+
+// tick mixes one frame; ok is false when no source had audio
+func (m *Mixer) tick() (payload []byte, ok bool, err error) {
+	m.mu.Lock()
+	sources := append([]*Source(nil), m.sources...)
+	m.mu.Unlock()
+
+	for i := range m.mix {
+		m.mix[i] = 0
+	}
+
+	// Speech first, so the ducking decision applies to this frame
+	var ducking, active bool
+	var ducked []*Source
+	for _, source := range sources {
+		if source.cfg.Ducked {
+			ducked = append(ducked, source)
+			continue
+		}
+		if !source.next(m.frame) {
+			continue
+		}
+		active = true
+		ducking = ducking || source.cfg.Ducks
+		for i, v := range m.frame {
+			m.mix[i] += v
+		}
+	}
+
+	from := m.duckGain
+	if ducking {
+		m.duckGain += m.attackCoef * (m.duckTarget - m.duckGain)
+	} else {
+		m.duckGain += m.releaseCoef * (1 - m.duckGain)
+	}
+	step := (m.duckGain - from) / FrameSamples
+
+	for _, source := range ducked {
+		if !source.next(m.frame) {
+			continue
+		}
+		active = true
+		// Ramp the gain across the frame to avoid zipper noise
+		for i, v := range m.frame {
+			m.mix[i] += v * (from + step*float64(i+1))
+		}
+	}
+
+	if !active {
+		return nil, false, nil
+	}
+
+	m.limiter.Process(m.mix)
+	for i, v := range m.mix {
+		m.pcm[i] = toInt16(v)
+	}
+	payload, err = m.encoder.Encode(m.pcm, FrameSamples, maxPacketBytes)
+	if err != nil {
+		return nil, false, err
+	}
+	return payload, true, nil
+}
+
+// toInt16 converts a normalized sample, saturating instead of wrapping
+func toInt16(v float64) int16 {
+	v = math.Round(v * 32768)
+	if v > math.MaxInt16 {
+		return math.MaxInt16
+	}
+	if v < math.MinInt16 {
+		return math.MinInt16
+	}
+	return int16(v)
+}
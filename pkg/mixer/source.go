@@ -0,0 +1,211 @@
+package mixer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"layeh.com/gopus"
+)
+
+// ErrSourceClosed is returned when writing to a source that was removed from its mixer
+var ErrSourceClosed = errors.New("mixer source closed")
+
+// SourceConfig describes how a source is mixed
+type SourceConfig struct {
+	GainDb      float64 // applied to every frame; 0 keeps the level
+	Ducks       bool    // while this source plays, ducked sources are attenuated (speech)
+	Ducked      bool    // attenuated while a ducking source plays (ambience, hold music)
+	StartFrames int     // frames buffered before playback starts, absorbing arrival jitter
+	MaxBufferMs int     // older audio is dropped beyond this much buffered audio
+}
+
+// Source is one input of a Mixer. Streamed audio (Opus or PCM) is queued and played
+// in order; a loop plays repeatedly while nothing is queued. Source implements
+// WriteOpusFrame, so it can stand in for an output track. It is safe for concurrent use.
+type Source struct {
+	name string
+	cfg  SourceConfig
+
+	mu      sync.Mutex
+	gain    float64
+	decoder *gopus.Decoder
+	queue   []int16 // decoded samples waiting to be mixed
+	playing bool    // StartFrames reached since the queue last ran dry
+	waited  int     // ticks spent filling the start buffer
+	loop    [][]byte
+	loopPos int
+	closed  bool
+}
+
+func newSource(name string, cfg SourceConfig) *Source {
+	if cfg.StartFrames <= 0 {
+		cfg.StartFrames = 1
+	}
+	if cfg.MaxBufferMs <= 0 {
+		cfg.MaxBufferMs = defaultMaxBufferMs
+	}
+	return &Source{name: name, cfg: cfg, gain: dbToLinear(cfg.GainDb)}
+}
+
+// Name returns the source name
+func (s *Source) Name() string {
+	return s.name
+}
+
+// WriteOpusFrame decodes an Opus packet and queues it for mixing
+func (s *Source) WriteOpusFrame(payload []byte) error {
+	if len(payload) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrSourceClosed
+	}
+	pcm, err := s.decode(payload)
+	if err != nil {
+		return err
+	}
+	s.enqueue(pcm)
+	return nil
+}
+
+// WritePCM queues mono 48kHz PCM16 samples for mixing
+func (s *Source) WritePCM(samples []int16) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrSourceClosed
+	}
+	s.enqueue(samples)
+	return nil
+}
+
+// Loop plays Opus frames repeatedly whenever nothing is queued; nil stops the loop.
+// Setting the same frames again keeps the loop position.
+func (s *Source) Loop(frames [][]byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(frames) > 0 && len(s.loop) > 0 && &frames[0] == &s.loop[0] {
+		return
+	}
+	s.loop = frames
+	s.loopPos = 0
+}
+
+// Clear drops queued audio, e.g. when the model is interrupted
+func (s *Source) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queue = s.queue[:0]
+	s.playing = false
+	s.waited = 0
+}
+
+// SetGainDb changes the source gain
+func (s *Source) SetGainDb(db float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gain = dbToLinear(db)
+}
+
+// Buffered returns the amount of queued audio in milliseconds
+func (s *Source) Buffered() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queue) * 1000 / SampleRate
+}
+
+// next writes the source's next frame, scaled by its gain, to out and reports whether
+// the source had anything to play
+func (s *Source) next(out []float64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+
+	switch {
+	case !s.playing && len(s.queue) > 0:
+		// Start once the buffer is full, or after waiting as long as filling it should take
+		s.waited++
+		if len(s.queue) >= s.cfg.StartFrames*FrameSamples || s.waited >= s.cfg.StartFrames {
+			s.playing = true
+			s.waited = 0
+		}
+	case s.playing && len(s.queue) == 0:
+		// Underrun: rebuild the start buffer before playing on
+		s.playing = false
+	}
+	if s.playing {
+		n := min(len(s.queue), FrameSamples)
+		for i := 0; i < FrameSamples; i++ {
+			if i < n {
+				out[i] = float64(s.queue[i]) / 32768 * s.gain
+			} else {
+				out[i] = 0
+			}
+		}
+		s.queue = append(s.queue[:0], s.queue[n:]...)
+		return true
+	}
+
+	if len(s.loop) == 0 {
+		return false
+	}
+	pcm, err := s.decode(s.loop[s.loopPos%len(s.loop)])
+	s.loopPos = (s.loopPos + 1) % len(s.loop)
+	if err != nil || len(pcm) == 0 {
+		return false
+	}
+	for i := 0; i < FrameSamples; i++ {
+		if i < len(pcm) {
+			out[i] = float64(pcm[i]) / 32768 * s.gain
+		} else {
+			out[i] = 0
+		}
+	}
+	return true
+}
+
+// decode turns an Opus packet into mono PCM, creating the decoder on first use
+func (s *Source) decode(payload []byte) ([]int16, error) {
+	if s.decoder == nil {
+		decoder, err := gopus.NewDecoder(SampleRate, 1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create opus decoder: %w", err)
+		}
+		s.decoder = decoder
+	}
+	// 120ms is the longest Opus packet
+	pcm, err := s.decoder.Decode(payload, SampleRate/1000*120, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode opus frame: %w", err)
+	}
+	return pcm, nil
+}
+
+// enqueue appends samples, dropping the oldest audio beyond the buffer limit
+func (s *Source) enqueue(samples []int16) {
+	s.queue = append(s.queue, samples...)
+	limit := s.cfg.MaxBufferMs * SampleRate / 1000
+	if excess := len(s.queue) - limit; excess > 0 {
+		s.queue = append(s.queue[:0], s.queue[excess:]...)
+	}
+}
+
+// close stops the source; further writes fail
+func (s *Source) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.queue = nil
+	s.loop = nil
+}
+
+// dbToLinear converts decibels to an amplitude ratio
+func dbToLinear(db float64) float64 {
+	return math.Pow(10, db/20)
+}
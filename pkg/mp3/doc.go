@@ -0,0 +1,10 @@
+// Package mp3 encodes PCM16 audio to MP3 using libmp3lame.
+//
+// The encoder needs cgo and the lame headers, so it is only built with the lame build tag
+// (go build -tags lame). Without it every encoder returns ErrUnavailable.
+package mp3
+
+import "errors"
+
+// ErrUnavailable is returned when the binary was built without the lame tag
+var ErrUnavailable = errors.New("mp3 encoding not available: built without the lame tag")
@@ -0,0 +1,132 @@
+//go:build lame
+
+package mp3
+
+/*
+#cgo LDFLAGS: -lmp3lame -lm
+#include <lame/lame.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// samplesPerChunk bounds the PCM handed to LAME per call (per channel)
+const samplesPerChunk = 1152 * 16
+
+// Encoder wraps a LAME encoder instance. It is not safe for concurrent use.
+type Encoder struct {
+	gfp      C.lame_t
+	channels int
+}
+
+// NewEncoder creates an MP3 encoder for interleaved PCM16 at the given sample rate,
+// channel count (1 or 2) and constant bitrate in kbps
+func NewEncoder(sampleRate, channels, bitrateKbps int) (*Encoder, error) {
+	if channels < 1 || channels > 2 {
+		return nil, fmt.Errorf("unsupported channel count: %d (must be 1 or 2)", channels)
+	}
+
+	gfp := C.lame_init()
+	if gfp == nil {
+		return nil, fmt.Errorf("failed to initialize lame encoder")
+	}
+
+	mode := C.MPEG_mode(C.MONO)
+	if channels == 2 {
+		// Caller and agent are recorded on separate channels, keep them independent
+		mode = C.MPEG_mode(C.STEREO)
+	}
+
+	C.lame_set_in_samplerate(gfp, C.int(sampleRate))
+	C.lame_set_out_samplerate(gfp, C.int(sampleRate))
+	C.lame_set_num_channels(gfp, C.int(channels))
+	C.lame_set_mode(gfp, mode)
+	C.lame_set_brate(gfp, C.int(bitrateKbps))
+	C.lame_set_quality(gfp, 5) // 0 best/slowest, 9 worst/fastest
+	if rc := C.lame_init_params(gfp); rc < 0 {
+		C.lame_close(gfp)
+		return nil, fmt.Errorf("failed to configure lame encoder: %d", int(rc))
+	}
+
+	return &Encoder{gfp: gfp, channels: channels}, nil
+}
+
+// Encode encodes interleaved PCM16 samples and returns the MP3 data produced so far
+func (e *Encoder) Encode(pcm []int16) ([]byte, error) {
+	if e.gfp == nil {
+		return nil, fmt.Errorf("encoder is closed")
+	}
+
+	var out []byte
+	for len(pcm) > 0 {
+		n := min(len(pcm), samplesPerChunk*e.channels)
+		chunk := pcm[:n]
+		pcm = pcm[n:]
+
+		samples := n / e.channels
+		// Worst case output size recommended by the LAME API docs
+		buf := make([]byte, samples*5/4+7200)
+		bufPtr := (*C.uchar)(unsafe.Pointer(&buf[0]))
+		pcmPtr := (*C.short)(unsafe.Pointer(&chunk[0]))
+
+		var rc C.int
+		if e.channels == 2 {
+			rc = C.lame_encode_buffer_interleaved(e.gfp, pcmPtr, C.int(samples), bufPtr, C.int(len(buf)))
+		} else {
+			rc = C.lame_encode_buffer(e.gfp, pcmPtr, nil, C.int(samples), bufPtr, C.int(len(buf)))
+		}
+		if rc < 0 {
+			return nil, fmt.Errorf("lame encode failed: %d", int(rc))
+		}
+		out = append(out, buf[:rc]...)
+	}
+
+	return out, nil
+}
+
+// Flush returns the final MP3 frames buffered inside the encoder
+func (e *Encoder) Flush() ([]byte, error) {
+	if e.gfp == nil {
+		return nil, fmt.Errorf("encoder is closed")
+	}
+
+	buf := make([]byte, 7200)
+	rc := C.lame_encode_flush(e.gfp, (*C.uchar)(unsafe.Pointer(&buf[0])), C.int(len(buf)))
+	if rc < 0 {
+		return nil, fmt.Errorf("lame flush failed: %d", int(rc))
+	}
+	return buf[:rc], nil
+}
+
+// Close releases the encoder
+func (e *Encoder) Close() {
+	if e.gfp != nil {
+		C.lame_close(e.gfp)
+		e.gfp = nil
+	}
+}
+
+// Available reports whether MP3 encoding is built in
+const Available = true
+
+// EncodePCM encodes a complete interleaved PCM16 buffer into an MP3 file
+func EncodePCM(pcm []int16, sampleRate, channels, bitrateKbps int) ([]byte, error) {
+	encoder, err := NewEncoder(sampleRate, channels, bitrateKbps)
+	if err != nil {
+		return nil, err
+	}
+	defer encoder.Close()
+
+	data, err := encoder.Encode(pcm)
+	if err != nil {
+		return nil, err
+	}
+	tail, err := encoder.Flush()
+	if err != nil {
+		return nil, err
+	}
+	return append(data, tail...), nil
+}
@@ -0,0 +1,32 @@
+//go:build !lame
+
+package mp3
+
+// Available reports whether MP3 encoding is built in
+const Available = false
+
+// Encoder is a stand-in for the LAME encoder in builds without the lame tag
+type Encoder struct{}
+
+// NewEncoder returns ErrUnavailable; build with the lame tag to encode MP3
+func NewEncoder(sampleRate, channels, bitrateKbps int) (*Encoder, error) {
+	return nil, ErrUnavailable
+}
+
+// Encode returns ErrUnavailable
+func (e *Encoder) Encode(pcm []int16) ([]byte, error) {
+	return nil, ErrUnavailable
+}
+
+// Flush returns ErrUnavailable
+func (e *Encoder) Flush() ([]byte, error) {
+	return nil, ErrUnavailable
+}
+
+// Close does nothing
+func (e *Encoder) Close() {}
+
+// EncodePCM returns ErrUnavailable; build with the lame tag to encode MP3
+func EncodePCM(pcm []int16, sampleRate, channels, bitrateKbps int) ([]byte, error) {
+	return nil, ErrUnavailable
+}
@@ -0,0 +1,57 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ClareAI/astra-voice-service/pkg/gcs"
+)
+
+// GCSStore stores objects in a Google Cloud Storage bucket
+type GCSStore struct {
+	client *gcs.GCSClient
+	bucket string
+}
+
+// NewGCSStore creates a GCS store using application default credentials
+func NewGCSStore(ctx context.Context, bucket string) (*GCSStore, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("GCS bucket is required")
+	}
+	client, err := gcs.NewGCSClient(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+	return &GCSStore{client: client, bucket: bucket}, nil
+}
+
+// Put uploads content with its content type and metadata and returns the object URL
+func (g *GCSStore) Put(ctx context.Context, key string, content io.Reader, attrs Attributes) (string, error) {
+	return g.client.UploadWithAttributes(ctx, key, content, attrs.ContentType, attrs.Metadata.Map())
+}
+
+// Get opens a reader on the object
+func (g *GCSStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	reader, err := g.client.Download(ctx, key)
+	if errors.Is(err, gcs.ErrObjectNotExist) {
+		return nil, ErrNotFound
+	}
+	return reader, err
+}
+
+// Delete removes the object from the bucket
+func (g *GCSStore) Delete(ctx context.Context, key string) error {
+	return g.client.Delete(ctx, fmt.Sprintf("gs://%s/%s", g.bucket, key))
+}
+
+// Backend returns BackendGCS
+func (g *GCSStore) Backend() Backend {
+	return BackendGCS
+}
+
+// Close closes the GCS client
+func (g *GCSStore) Close() error {
+	return g.client.Close()
+}
@@ -0,0 +1,106 @@
+package objectstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// localMetaSuffix names the sidecar holding an object's attributes
+const localMetaSuffix = ".meta.json"
+
+// LocalStore stores objects as files below a root directory
+type LocalStore struct {
+	rootDir string
+}
+
+// NewLocalStore creates a local filesystem store rooted at rootDir
+func NewLocalStore(rootDir string) (*LocalStore, error) {
+	if rootDir == "" {
+		return nil, fmt.Errorf("local store root directory is required")
+	}
+	if err := os.MkdirAll(rootDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create local storage directory: %v", err)
+	}
+	return &LocalStore{rootDir: rootDir}, nil
+}
+
+// localAttributes is the JSON sidecar written next to each object
+type localAttributes struct {
+	ContentType string            `json:"content_type,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// Put writes content to {rootDir}/{key} with a metadata sidecar and returns the file path
+func (l *LocalStore) Put(ctx context.Context, key string, content io.Reader, attrs Attributes) (string, error) {
+	fullPath := filepath.Join(l.rootDir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	// Write to a temp file first so readers never see a partial object
+	tmpPath := fullPath + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %v", err)
+	}
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to write file: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to close file: %v", err)
+	}
+	if err := os.Rename(tmpPath, fullPath); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to move file into place: %v", err)
+	}
+
+	sidecar, err := json.Marshal(localAttributes{ContentType: attrs.ContentType, Metadata: attrs.Metadata.Map()})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode object metadata: %v", err)
+	}
+	if err := os.WriteFile(fullPath+localMetaSuffix, sidecar, 0644); err != nil {
+		return "", fmt.Errorf("failed to write object metadata: %v", err)
+	}
+
+	return fullPath, nil
+}
+
+// Get opens the object file
+func (l *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	file, err := os.Open(filepath.Join(l.rootDir, filepath.FromSlash(key)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to open object: %v", err)
+	}
+	return file, nil
+}
+
+// Delete removes the object file and its metadata sidecar
+func (l *LocalStore) Delete(ctx context.Context, key string) error {
+	fullPath := filepath.Join(l.rootDir, filepath.FromSlash(key))
+	for _, path := range []string{fullPath, fullPath + localMetaSuffix} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete object: %v", err)
+		}
+	}
+	return nil
+}
+
+// Backend returns BackendLocal
+func (l *LocalStore) Backend() Backend {
+	return BackendLocal
+}
+
+// Close is a no-op for the local store
+func (l *LocalStore) Close() error {
+	return nil
+}
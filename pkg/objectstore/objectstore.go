@@ -0,0 +1,121 @@
+// Package objectstore stores call recordings, reports and other artifacts in a
+// pluggable backend (local filesystem, Google Cloud Storage or S3-compatible).
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// Backend identifies an object store implementation
+type Backend string
+
+const (
+	BackendLocal Backend = "local"
+	BackendGCS   Backend = "gcs"
+	BackendS3    Backend = "s3"
+)
+
+// ErrNotFound is returned by Get when no object is stored under the key
+var ErrNotFound = errors.New("object not found")
+
+// Metadata identifies who an object belongs to. It is attached to every stored object
+// and drives the object naming.
+type Metadata struct {
+	TenantID       string
+	AgentID        string
+	ConversationID string
+}
+
+// Map returns the non-empty metadata as object metadata key/values. Keys use dashes
+// so they survive as S3 x-amz-meta-* headers through proxies.
+func (m Metadata) Map() map[string]string {
+	values := make(map[string]string, 3)
+	if m.TenantID != "" {
+		values["tenant-id"] = m.TenantID
+	}
+	if m.AgentID != "" {
+		values["agent-id"] = m.AgentID
+	}
+	if m.ConversationID != "" {
+		values["conversation-id"] = m.ConversationID
+	}
+	return values
+}
+
+// Attributes are stored alongside an object's content
+type Attributes struct {
+	ContentType string
+	Metadata    Metadata
+}
+
+// Store is an object storage backend
+type Store interface {
+	// Put stores content under key and returns the object's location (URL or file path)
+	Put(ctx context.Context, key string, content io.Reader, attrs Attributes) (string, error)
+	// Get opens the object stored under key; the caller closes the reader
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object stored under key; missing objects are not an error
+	Delete(ctx context.Context, key string) error
+	// Backend returns the implementation type
+	Backend() Backend
+	// Close releases the underlying client
+	Close() error
+}
+
+// Config selects and configures a Store
+type Config struct {
+	Backend Backend
+	Bucket  string // GCS/S3 bucket name
+	RootDir string // Local filesystem root
+
+	// S3-compatible settings
+	Endpoint       string // e.g. https://s3.eu-west-1.amazonaws.com or http://minio:9000
+	Region         string
+	AccessKeyID    string
+	SecretKey      string
+	ForcePathStyle bool // Required by MinIO and most self-hosted S3 servers
+}
+
+// New creates the store selected by cfg.Backend
+func New(ctx context.Context, cfg Config) (Store, error) {
+	switch cfg.Backend {
+	case BackendLocal:
+		return NewLocalStore(cfg.RootDir)
+	case BackendGCS:
+		return NewGCSStore(ctx, cfg.Bucket)
+	case BackendS3:
+		return NewS3Store(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported object store backend: %s", cfg.Backend)
+	}
+}
+
+// ObjectKey builds the canonical key for an object:
+// {category}/{tenantID}/{conversationID}/{name}. Objects without a tenant are stored
+// under "shared" and the conversation segment is omitted when unknown.
+func ObjectKey(category string, meta Metadata, name string) string {
+	tenant := meta.TenantID
+	if tenant == "" {
+		tenant = "shared"
+	}
+	segments := []string{sanitizeSegment(category), sanitizeSegment(tenant)}
+	if meta.ConversationID != "" {
+		segments = append(segments, sanitizeSegment(meta.ConversationID))
+	}
+	segments = append(segments, sanitizeSegment(name))
+	return path.Join(segments...)
+}
+
+// sanitizeSegment keeps a key segment from escaping its directory
+func sanitizeSegment(segment string) string {
+	segment = strings.ReplaceAll(segment, "/", "_")
+	if segment == "." || segment == ".." {
+		return "_"
+	}
+	return segment
+}
@@ -0,0 +1,147 @@
+package objectstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/s3utils"
+)
+
+const (
+	s3DefaultRegion = "us-east-1"
+
+	// s3PartSize bounds the memory an upload of unknown length holds: content is
+	// streamed as a multipart upload one part at a time
+	s3PartSize = 16 << 20
+)
+
+// S3Store stores objects in an S3-compatible bucket (AWS S3, MinIO, R2, ...)
+type S3Store struct {
+	client         *minio.Client
+	transport      *http.Transport
+	endpoint       *url.URL
+	bucket         string
+	forcePathStyle bool
+}
+
+// NewS3Store creates an S3-compatible store. The endpoint defaults to AWS S3 for the region.
+func NewS3Store(cfg Config) (*S3Store, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("S3 bucket is required")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretKey == "" {
+		return nil, fmt.Errorf("S3 access key and secret are required")
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = s3DefaultRegion
+	}
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint: %s", endpoint)
+	}
+	if strings.Trim(parsed.Path, "/") != "" {
+		return nil, fmt.Errorf("S3 endpoint must not have a path: %s", endpoint)
+	}
+
+	secure := parsed.Scheme == "https"
+	transport, err := minio.DefaultTransport(secure)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 transport: %w", err)
+	}
+	lookup := minio.BucketLookupDNS
+	if cfg.ForcePathStyle {
+		lookup = minio.BucketLookupPath
+	}
+	client, err := minio.New(parsed.Host, &minio.Options{
+		Creds:        credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretKey, ""),
+		Secure:       secure,
+		Region:       region,
+		BucketLookup: lookup,
+		Transport:    transport,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	return &S3Store{
+		client:         client,
+		transport:      transport,
+		endpoint:       parsed,
+		bucket:         cfg.Bucket,
+		forcePathStyle: cfg.ForcePathStyle,
+	}, nil
+}
+
+// Put streams content to the bucket and returns the object URL
+func (s *S3Store) Put(ctx context.Context, key string, content io.Reader, attrs Attributes) (string, error) {
+	_, err := s.client.PutObject(ctx, s.bucket, key, content, -1, minio.PutObjectOptions{
+		ContentType:  attrs.ContentType,
+		UserMetadata: attrs.Metadata.Map(),
+		PartSize:     s3PartSize,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload object: %w", err)
+	}
+	return s.objectURL(key), nil
+}
+
+// Get downloads the object; the body is streamed to the caller
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	object, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download object: %w", err)
+	}
+	// Reads are lazy; Stat looks the object up first so a missing key surfaces here
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to download object: %w", err)
+	}
+	return object, nil
+}
+
+// Delete removes the object from the bucket
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	if err := s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+	return nil
+}
+
+// Backend returns BackendS3
+func (s *S3Store) Backend() Backend {
+	return BackendS3
+}
+
+// Close releases idle HTTP connections
+func (s *S3Store) Close() error {
+	s.transport.CloseIdleConnections()
+	return nil
+}
+
+// objectURL returns the path-style or virtual-hosted-style URL of an object
+func (s *S3Store) objectURL(key string) string {
+	u := *s.endpoint
+	if s.forcePathStyle {
+		u.Path = "/" + s.bucket + "/" + key
+	} else {
+		u.Host = s.bucket + "." + u.Host
+		u.Path = "/" + key
+	}
+	u.RawPath = s3utils.EncodePath(u.Path)
+	return u.String()
+}
@@ -10,6 +10,9 @@ import (
 	"github.com/ClareAI/astra-voice-service/internal/config"
 	"github.com/ClareAI/astra-voice-service/internal/domain"
 	"github.com/ClareAI/astra-voice-service/pkg/logger"
+	"github.com/ClareAI/astra-voice-service/pkg/metrics"
+	"github.com/ClareAI/astra-voice-service/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
@@ -122,8 +125,10 @@ func (p *AgentRAGProcessor) ProcessUserInputWithChannelType(userInput, connectio
 	logger.Base().Info("🌐 Translated query for agent", zap.String("agent_id", agentID), zap.String("englishquery", englishQuery))
 
 	// ✅ Call agent-specific RAG with timeout control (3 seconds)
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	ctx, cancel := context.WithTimeout(tracing.CallContext(connectionID), 3*time.Second)
 	defer cancel()
+	ctx, span := tracing.Start(ctx, "rag.query", attribute.String("agent.id", agentID))
+	defer span.End()
 
 	// Channel to receive RAG result
 	type ragResult struct {
@@ -144,9 +149,12 @@ func (p *AgentRAGProcessor) ProcessUserInputWithChannelType(userInput, connectio
 	case result := <-resultChan:
 		if result.err != nil {
 			logger.Base().Error("RAG query failed for agent", zap.Error(result.err))
+			metrics.RAGQueriesTotal.WithLabelValues("error").Inc()
+			span.RecordError(result.err)
 			return false, "", userInput
 		}
 		ragAnswer = result.answer
+		metrics.RAGQueriesTotal.WithLabelValues("ok").Inc()
 		answerPreview := ragAnswer
 		if len(answerPreview) > 100 {
 			answerPreview = answerPreview[:100] + "..."
@@ -155,6 +163,8 @@ func (p *AgentRAGProcessor) ProcessUserInputWithChannelType(userInput, connectio
 
 	case <-ctx.Done():
 		logger.Base().Info("⏰ RAG query timeout (3s) for agent , proceeding without RAG context", zap.String("agent_id", agentID))
+		metrics.RAGQueriesTotal.WithLabelValues("timeout").Inc()
+		span.SetAttributes(attribute.Bool("rag.timeout", true))
 		return false, "", userInput
 	}
 
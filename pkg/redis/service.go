@@ -14,6 +14,7 @@ type KeyType string
 const (
 	USAGE_CONFIG         KeyType = "astra_tenant_usage_config"
 	PREVIEW_CONVERSATION KeyType = "astra_preview_conversation"
+	PREVIEW_SESSION      KeyType = "astra_preview_session"
 )
 
 type RedisConfig struct {
@@ -0,0 +1,142 @@
+package resample
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// Quality trades filter length (CPU and latency) for stopband attenuation and passband width
+type Quality int
+
+// Percentages are of the lower Nyquist frequency: the cutoff (-6dB) is the spec's rolloff,
+// the response is flat within 0.1dB below it and the stopband starts at the Nyquist frequency.
+const (
+	// Fast suits live speech: cutoff at 85%, flat to ~74%, at least 70dB stopband rejection
+	Fast Quality = iota
+	// Default is transparent for telephony and music on hold: cutoff at 91%, flat to ~84%, 80dB
+	Default
+	// High is for offline work such as recording export: cutoff at 95%, flat to ~91%, 90dB
+	High
+)
+
+// filterSpec describes the windowed-sinc prototype of a quality level
+type filterSpec struct {
+	zeroCrossings int     // sinc lobes on each side, counted at the lower of the two rates
+	beta          float64 // Kaiser window shape
+	rolloff       float64 // cutoff as a fraction of the lower Nyquist frequency
+}
+
+func (q Quality) spec() filterSpec {
+	switch q {
+	case Fast:
+		return filterSpec{zeroCrossings: 16, beta: 6, rolloff: 0.85}
+	case High:
+		return filterSpec{zeroCrossings: 64, beta: 10, rolloff: 0.95}
+	default:
+		return filterSpec{zeroCrossings: 32, beta: 8.6, rolloff: 0.91}
+	}
+}
+
+// maxCoefficients bounds the polyphase table of unusual rate pairs (e.g. 44100 -> 47999)
+const maxCoefficients = 1 << 20
+
+// polyphase is a filter bank with one branch per output phase. Banks are immutable and
+// shared between resamplers with the same rates and quality.
+type polyphase struct {
+	up, down int         // output/input rate ratio in lowest terms
+	halfTaps int         // input samples each side of the output position
+	phases   [][]float64 // phases[p][j] weights input floor(t)-halfTaps+1+j for t = floor(t)+p/up
+}
+
+type bankKey struct {
+	from, to int
+	quality  Quality
+}
+
+var banks sync.Map // map[bankKey]*polyphase
+
+// bank returns the filter bank for a rate pair, designing it on first use
+func bank(from, to int, quality Quality) (*polyphase, error) {
+	key := bankKey{from: from, to: to, quality: quality}
+	if cached, ok := banks.Load(key); ok {
+		return cached.(*polyphase), nil
+	}
+	designed, err := design(from, to, quality.spec())
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := banks.LoadOrStore(key, designed)
+	return actual.(*polyphase), nil
+}
+
+// design builds the polyphase bank. Each output sample at input position t is
+// sum_k x[k]*h(t-k), where h is a Kaiser-windowed sinc low-pass at the lower Nyquist
+// frequency, stretched by the rate ratio when downsampling.
+func design(from, to int, spec filterSpec) (*polyphase, error) {
+	g := gcd(from, to)
+	up, down := to/g, from/g
+
+	// scale < 1 widens the kernel so it cuts off at the output Nyquist frequency
+	scale := math.Min(1, float64(to)/float64(from))
+	halfTaps := int(math.Ceil(float64(spec.zeroCrossings) / scale))
+	taps := 2 * halfTaps
+	if up*taps > maxCoefficients {
+		return nil, fmt.Errorf("resampling %d Hz to %d Hz needs %d filter phases", from, to, up)
+	}
+
+	cutoff := spec.rolloff * scale
+	support := float64(halfTaps)
+	norm := besselI0(spec.beta)
+
+	phases := make([][]float64, up)
+	for p := range phases {
+		coefs := make([]float64, taps)
+		var sum float64
+		for j := range coefs {
+			x := float64(p)/float64(up) + float64(halfTaps-1-j)
+			if math.Abs(x) >= support {
+				continue
+			}
+			r := x / support
+			window := besselI0(spec.beta*math.Sqrt(1-r*r)) / norm
+			coefs[j] = cutoff * sinc(cutoff*x) * window
+			sum += coefs[j]
+		}
+		// Unity gain at DC for every phase, so constant input has no ripple
+		for j := range coefs {
+			coefs[j] /= sum
+		}
+		phases[p] = coefs
+	}
+
+	return &polyphase{up: up, down: down, halfTaps: halfTaps, phases: phases}, nil
+}
+
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
+
+// besselI0 is the zeroth order modified Bessel function of the first kind
+func besselI0(x float64) float64 {
+	sum, term := 1.0, 1.0
+	half := x / 2
+	for k := 1; k < 64; k++ {
+		term *= half / float64(k)
+		sum += term * term
+		if term*term < sum*1e-16 {
+			break
+		}
+	}
+	return sum
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
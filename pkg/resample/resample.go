@@ -0,0 +1,162 @@
+// Package resample converts PCM16 audio between sample rates (48k, 24k, 16k, 8k,
+// 44.1k, ...) with a polyphase windowed-sinc filter.
+//
+// Linear interpolation folds everything above the new Nyquist frequency back into the
+// audible band, which is clearly audible on 48k -> 8k telephony paths. A Resampler
+// band-limits with a Kaiser-windowed sinc instead, and keeps its filter history between
+// calls so a stream can be converted frame by frame without clicks at frame edges.
+package resample
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Resampler converts one mono stream. It is not safe for concurrent use.
+type Resampler struct {
+	from, to int
+	bank     *polyphase
+
+	history  []float64 // input samples still needed by upcoming outputs
+	start    int64     // absolute input index of history[0]
+	pos      int64     // integer part of the next output position, in input samples
+	phase    int       // fractional part of the next output position, in 1/up steps
+	inputs   int64     // input samples received since the last reset
+	produced int64     // output samples returned since the last reset
+}
+
+// New creates a resampler from one rate to another
+func New(from, to int, quality Quality) (*Resampler, error) {
+	if from <= 0 || to <= 0 {
+		return nil, fmt.Errorf("invalid sample rates %d -> %d", from, to)
+	}
+	r := &Resampler{from: from, to: to}
+	if from != to {
+		b, err := bank(from, to, quality)
+		if err != nil {
+			return nil, err
+		}
+		r.bank = b
+	}
+	r.Reset()
+	return r, nil
+}
+
+// Latency is how far output lags input while streaming: the filter needs this much
+// audio after a sample before it can produce the matching output
+func (r *Resampler) Latency() time.Duration {
+	if r.bank == nil {
+		return 0
+	}
+	return time.Duration(r.bank.halfTaps) * time.Second / time.Duration(r.from)
+}
+
+// Process converts the next block of the stream. Output is delayed by Latency, so
+// the first calls return fewer samples than the rate ratio implies; Flush returns the rest.
+func (r *Resampler) Process(in []int16) []int16 {
+	if r.bank == nil {
+		return append([]int16(nil), in...)
+	}
+	for _, s := range in {
+		r.history = append(r.history, float64(s)/32768)
+	}
+	r.inputs += int64(len(in))
+	return r.drain(nil, r.start+int64(len(r.history)))
+}
+
+// Flush returns the outputs still held back by the filter, treating the stream as
+// ended, and resets the resampler
+func (r *Resampler) Flush() []int16 {
+	if r.bank == nil {
+		return nil
+	}
+	// Zeros after the end let the last outputs see their full window
+	remaining := outputCount(r.inputs, r.bank) - r.produced
+	for i := 0; i < r.bank.halfTaps; i++ {
+		r.history = append(r.history, 0)
+	}
+	out := r.drain(nil, r.start+int64(len(r.history)))
+	// Only outputs positioned inside the real input belong to the stream
+	if int64(len(out)) > remaining {
+		out = out[:max(remaining, 0)]
+	}
+	r.Reset()
+	return out
+}
+
+// Reset clears the stream state so the resampler can start a new stream
+func (r *Resampler) Reset() {
+	r.history = r.history[:0]
+	r.pos, r.phase, r.inputs, r.produced = 0, 0, 0, 0
+	if r.bank == nil {
+		r.start = 0
+		return
+	}
+	// Silence before the stream lets the first outputs see their full window
+	r.start = -int64(r.bank.halfTaps - 1)
+	for i := 0; i < r.bank.halfTaps-1; i++ {
+		r.history = append(r.history, 0)
+	}
+}
+
+// drain appends every output whose window ends before available to out
+func (r *Resampler) drain(out []int16, available int64) []int16 {
+	b := r.bank
+	taps := 2 * b.halfTaps
+	for r.pos+int64(b.halfTaps) < available {
+		first := int(r.pos - int64(b.halfTaps) + 1 - r.start)
+		window := r.history[first : first+taps]
+		var acc float64
+		for j, c := range b.phases[r.phase] {
+			acc += c * window[j]
+		}
+		out = append(out, toInt16(acc))
+		r.produced++
+
+		r.phase += b.down
+		r.pos += int64(r.phase / b.up)
+		r.phase %= b.up
+	}
+
+	// Drop input no future output can reach
+	if drop := int(r.pos - int64(b.halfTaps) + 1 - r.start); drop > 0 {
+		drop = min(drop, len(r.history))
+		r.history = append(r.history[:0], r.history[drop:]...)
+		r.start += int64(drop)
+	}
+	return out
+}
+
+// outputCount is the number of outputs positioned inside the first n input samples
+func outputCount(n int64, b *polyphase) int64 {
+	return (n*int64(b.up) + int64(b.down) - 1) / int64(b.down)
+}
+
+// Convert resamples a complete clip with the Default quality; the output has no
+// leading delay and ceil(len(in)*to/from) samples
+func Convert(in []int16, from, to int) ([]int16, error) {
+	return ConvertQuality(in, from, to, Default)
+}
+
+// ConvertQuality resamples a complete clip with the given quality
+func ConvertQuality(in []int16, from, to int, quality Quality) ([]int16, error) {
+	r, err := New(from, to, quality)
+	if err != nil {
+		return nil, err
+	}
+	out := r.Process(in)
+	return append(out, r.Flush()...), nil
+}
+
+// toInt16 converts a normalized sample, saturating instead of wrapping
+func toInt16(v float64) int16 {
+	v = math.Round(v * 32768)
+	if v > math.MaxInt16 {
+		return math.MaxInt16
+	}
+	if v < math.MinInt16 {
+		return math.MinInt16
+	}
+	return int16(v)
+}
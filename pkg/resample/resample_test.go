@@ -0,0 +1,204 @@
+package resample
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// tone generates seconds of a sine at freq Hz with the given peak amplitude (0..1)
+func tone(rate int, freq, amplitude, seconds float64) []int16 {
+	out := make([]int16, int(float64(rate)*seconds))
+	for i := range out {
+		out[i] = toInt16(amplitude * math.Sin(2*math.Pi*freq*float64(i)/float64(rate)))
+	}
+	return out
+}
+
+// rmsDb returns the RMS level of samples in dBFS, skipping edge samples at both ends
+func rmsDb(samples []int16, edge int) float64 {
+	samples = samples[edge : len(samples)-edge]
+	var sum float64
+	for _, s := range samples {
+		v := float64(s) / 32768
+		sum += v * v
+	}
+	return 10 * math.Log10(sum/float64(len(samples))+1e-20)
+}
+
+var ratePairs = []struct{ from, to int }{
+	{48000, 16000},
+	{48000, 8000},
+	{48000, 24000},
+	{16000, 48000},
+	{8000, 48000},
+	{44100, 48000},
+	{48000, 44100},
+}
+
+func TestPassbandFlatness(t *testing.T) {
+	qualities := []struct {
+		name    string
+		quality Quality
+		flatTo  float64 // fraction of the lower Nyquist frequency
+	}{
+		{"fast", Fast, 0.70},
+		{"default", Default, 0.80},
+		{"high", High, 0.88},
+	}
+
+	for _, q := range qualities {
+		for _, pair := range ratePairs {
+			nyquist := float64(min(pair.from, pair.to)) / 2
+			for _, fraction := range []float64{0.05, 0.25, 0.5, q.flatTo} {
+				freq := fraction * nyquist
+				in := tone(pair.from, freq, 0.5, 0.5)
+				out, err := ConvertQuality(in, pair.from, pair.to, q.quality)
+				if err != nil {
+					t.Fatalf("%s %d->%d: %v", q.name, pair.from, pair.to, err)
+				}
+				gain := rmsDb(out, pair.to/20) - rmsDb(in, pair.from/20)
+				if math.Abs(gain) > 0.1 {
+					t.Errorf("%s %d->%d: %.0f Hz gain %.3f dB, want within ±0.1 dB", q.name, pair.from, pair.to, freq, gain)
+				}
+			}
+		}
+	}
+}
+
+func TestStopbandRejection(t *testing.T) {
+	qualities := []struct {
+		name        string
+		quality     Quality
+		rejectionDb float64
+	}{
+		{"fast", Fast, 70},
+		{"default", Default, 80},
+		{"high", High, 90},
+	}
+
+	for _, q := range qualities {
+		for _, pair := range ratePairs {
+			if pair.to >= pair.from {
+				continue // only downsampling can alias
+			}
+			outNyquist := float64(pair.to) / 2
+			for _, fraction := range []float64{1.1, 1.3, 1.6, 1.9} {
+				freq := fraction * outNyquist
+				if freq >= float64(pair.from)/2 {
+					continue
+				}
+				in := tone(pair.from, freq, 0.5, 0.5)
+				out, err := ConvertQuality(in, pair.from, pair.to, q.quality)
+				if err != nil {
+					t.Fatalf("%s %d->%d: %v", q.name, pair.from, pair.to, err)
+				}
+				rejection := rmsDb(in, pair.from/20) - rmsDb(out, pair.to/20)
+				if rejection < q.rejectionDb {
+					t.Errorf("%s %d->%d: %.0f Hz rejected by %.1f dB, want at least %.0f dB", q.name, pair.from, pair.to, freq, rejection, q.rejectionDb)
+				}
+			}
+		}
+	}
+}
+
+func TestLatency(t *testing.T) {
+	tests := []struct {
+		from, to int
+		quality  Quality
+		want     time.Duration
+	}{
+		{48000, 48000, Default, 0},
+		{48000, 16000, Fast, time.Millisecond},
+		{48000, 16000, Default, 2 * time.Millisecond},
+		{48000, 8000, Default, 4 * time.Millisecond},
+		{16000, 48000, Default, 2 * time.Millisecond},
+		{24000, 48000, High, 64 * time.Second / 24000},
+	}
+
+	for _, tt := range tests {
+		r, err := New(tt.from, tt.to, tt.quality)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := r.Latency(); got != tt.want {
+			t.Errorf("%d->%d quality %d: latency %v, want %v", tt.from, tt.to, tt.quality, got, tt.want)
+		}
+	}
+}
+
+// TestStreamingDelay checks that streamed output lags by exactly Latency and that an
+// impulse lands on the output sample matching its input position
+func TestStreamingDelay(t *testing.T) {
+	for _, pair := range ratePairs {
+		r, err := New(pair.from, pair.to, Default)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		frame := pair.from / 50
+		in := make([]int16, 10*frame)
+		impulseAt := 3*frame + frame/2
+		in[impulseAt] = 16384
+
+		var out []int16
+		for i := 0; i < len(in); i += frame {
+			out = append(out, r.Process(in[i:i+frame])...)
+		}
+		lagSamples := int(r.Latency().Seconds() * float64(pair.to))
+		expected := len(in) * pair.to / pair.from
+		if missing := expected - len(out); missing < lagSamples-1 || missing > lagSamples+1 {
+			t.Errorf("%d->%d: %d outputs held back while streaming, want %d", pair.from, pair.to, missing, lagSamples)
+		}
+
+		out = append(out, r.Flush()...)
+		if len(out) != (len(in)*pair.to+pair.from-1)/pair.from {
+			t.Errorf("%d->%d: %d outputs after flush, want %d", pair.from, pair.to, len(out), (len(in)*pair.to+pair.from-1)/pair.from)
+		}
+
+		peak := 0
+		for i, s := range out {
+			if abs(s) > abs(out[peak]) {
+				peak = i
+			}
+		}
+		want := float64(impulseAt) * float64(pair.to) / float64(pair.from)
+		if math.Abs(float64(peak)-want) > 1 {
+			t.Errorf("%d->%d: impulse peak at output %d, want %.1f", pair.from, pair.to, peak, want)
+		}
+	}
+}
+
+// TestStreamingMatchesConvert checks frame-by-frame conversion has no discontinuities at frame edges
+func TestStreamingMatchesConvert(t *testing.T) {
+	for _, pair := range ratePairs {
+		in := tone(pair.from, 440, 0.5, 0.2)
+		whole, err := Convert(in, pair.from, pair.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r, _ := New(pair.from, pair.to, Default)
+		var streamed []int16
+		for frame := pair.from / 50; len(in) > 0; in = in[min(frame, len(in)):] {
+			streamed = append(streamed, r.Process(in[:min(frame, len(in))])...)
+		}
+		streamed = append(streamed, r.Flush()...)
+
+		if len(streamed) != len(whole) {
+			t.Fatalf("%d->%d: streamed %d samples, converted %d", pair.from, pair.to, len(streamed), len(whole))
+		}
+		for i := range whole {
+			if streamed[i] != whole[i] {
+				t.Fatalf("%d->%d: sample %d differs: %d vs %d", pair.from, pair.to, i, streamed[i], whole[i])
+			}
+		}
+	}
+}
+
+func abs(v int16) int {
+	if v < 0 {
+		return -int(v)
+	}
+	return int(v)
+}
@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// callTrace is the root span of a single call and the context derived from it
+type callTrace struct {
+	ctx  context.Context
+	span trace.Span
+}
+
+var calls sync.Map // connectionID -> *callTrace
+
+// StartCall opens the call-level root span for a connection. Every span created from
+// CallContext(connectionID) shares its trace ID. A span already present in ctx (e.g. the
+// webhook request) is linked rather than used as the parent so the call keeps its own trace.
+// Calling StartCall again for the same connection returns the existing context.
+func StartCall(ctx context.Context, connectionID string, attrs ...attribute.KeyValue) context.Context {
+	if existing, ok := calls.Load(connectionID); ok {
+		return existing.(*callTrace).ctx
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	opts := []trace.SpanStartOption{
+		trace.WithNewRoot(),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(append(attrs, attribute.String("connection.id", connectionID))...),
+	}
+	if parent := trace.SpanContextFromContext(ctx); parent.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: parent}))
+	}
+
+	callCtx, span := Tracer().Start(context.Background(), "call", opts...)
+	actual, loaded := calls.LoadOrStore(connectionID, &callTrace{ctx: callCtx, span: span})
+	if loaded {
+		// Lost a race with another StartCall for the same connection
+		span.End()
+		return actual.(*callTrace).ctx
+	}
+	return callCtx
+}
+
+// AttachCall registers a remote call context (e.g. extracted from a task) for a connection
+// that has no local call span yet. The span stays owned by the pod that started it.
+func AttachCall(ctx context.Context, connectionID string) context.Context {
+	if existing, ok := calls.Load(connectionID); ok {
+		return existing.(*callTrace).ctx
+	}
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	actual, _ := calls.LoadOrStore(connectionID, &callTrace{ctx: ctx})
+	return actual.(*callTrace).ctx
+}
+
+// CallContext returns the call-level context for a connection, or context.Background()
+func CallContext(connectionID string) context.Context {
+	if connectionID != "" {
+		if existing, ok := calls.Load(connectionID); ok {
+			return existing.(*callTrace).ctx
+		}
+	}
+	return context.Background()
+}
+
+// EndCall ends the call span for a connection and forgets its context
+func EndCall(connectionID string, attrs ...attribute.KeyValue) {
+	existing, ok := calls.LoadAndDelete(connectionID)
+	if !ok {
+		return
+	}
+	ct := existing.(*callTrace)
+	if ct.span != nil {
+		ct.span.SetAttributes(attrs...)
+		ct.span.End()
+	}
+}
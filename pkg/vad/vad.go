@@ -0,0 +1,334 @@
+// Package vad implements a lightweight voice activity detector for PCM16 audio.
+//
+// Each frame is classified from its energy relative to an adaptive noise floor, its
+// zero-crossing rate and its spectral flatness. A detector gates a stream of frames:
+// it opens once speech has lasted for the onset time, releases the buffered pre-roll
+// so the start of the utterance is not clipped, and stays open for the hangover time
+// after speech ends so trailing syllables and the pause the model needs to detect the
+// end of a turn are still delivered.
+package vad
+
+import (
+	"math"
+
+	"github.com/ClareAI/astra-voice-service/pkg/dsp"
+)
+
+const (
+	// analysisRate is the rate frames are decimated to before feature extraction
+	analysisRate = 12000
+	// analysisWindowMs is the length of a single analysis window
+	analysisWindowMs = 20
+	// fftSize covers one decimated analysis window (240 samples at 12kHz)
+	fftSize = 256
+
+	// Speech band used for spectral flatness
+	bandLowHz  = 250
+	bandHighHz = 4000
+
+	minEnergyDb = -100.0
+)
+
+// Config contains detector thresholds and timings
+type Config struct {
+	SampleRate          int     // input sample rate in Hz
+	EnergyThresholdDb   float64 // absolute level (dBFS) a frame must reach to count as speech
+	NoiseMarginDb       float64 // required distance above the tracked noise floor
+	MaxSpectralFlatness float64 // 0 (tonal) .. 1 (white noise)
+	MaxZeroCrossingRate float64 // crossings per sample at the analysis rate
+	OnsetMs             int     // speech needed before the gate opens
+	HangoverMs          int     // silence tolerated before the gate closes
+	PreRollMs           int     // audio released ahead of the onset
+}
+
+// DefaultConfig returns thresholds tuned for 48kHz telephony audio
+func DefaultConfig() Config {
+	return Config{
+		SampleRate:          48000,
+		EnergyThresholdDb:   -50,
+		NoiseMarginDb:       9,
+		MaxSpectralFlatness: 0.45,
+		MaxZeroCrossingRate: 0.35,
+		OnsetMs:             40,
+		HangoverMs:          800,
+		PreRollMs:           300,
+	}
+}
+
+// withDefaults fills unset fields from DefaultConfig
+func (c Config) withDefaults() Config {
+	def := DefaultConfig()
+	if c.SampleRate <= 0 {
+		c.SampleRate = def.SampleRate
+	}
+	if c.EnergyThresholdDb == 0 {
+		c.EnergyThresholdDb = def.EnergyThresholdDb
+	}
+	if c.NoiseMarginDb <= 0 {
+		c.NoiseMarginDb = def.NoiseMarginDb
+	}
+	if c.MaxSpectralFlatness <= 0 {
+		c.MaxSpectralFlatness = def.MaxSpectralFlatness
+	}
+	if c.MaxZeroCrossingRate <= 0 {
+		c.MaxZeroCrossingRate = def.MaxZeroCrossingRate
+	}
+	if c.OnsetMs <= 0 {
+		c.OnsetMs = def.OnsetMs
+	}
+	if c.HangoverMs <= 0 {
+		c.HangoverMs = def.HangoverMs
+	}
+	if c.PreRollMs <= 0 {
+		c.PreRollMs = def.PreRollMs
+	}
+	return c
+}
+
+// Features describes a single analysis window
+type Features struct {
+	EnergyDb         float64
+	ZeroCrossingRate float64
+	SpectralFlatness float64
+}
+
+// Detector gates a stream of PCM16 frames. It is not safe for concurrent use.
+type Detector struct {
+	cfg Config
+
+	decimation      int
+	windowSamples   int // analysis window length at the input rate
+	onsetSamples    int
+	hangoverSamples int
+	preRollLimit    int
+
+	noiseFloorDb float64
+	hasFloor     bool
+
+	speaking       bool
+	speechRun      int // consecutive speech samples while the gate is closed
+	silenceRun     int // consecutive non-speech samples while the gate is open
+	preRoll        [][]int16
+	preRollSamples int
+
+	window   []float64
+	spectrum []complex128
+}
+
+// New creates a detector; zero-valued config fields take their defaults
+func New(cfg Config) *Detector {
+	cfg = cfg.withDefaults()
+
+	decimation := cfg.SampleRate / analysisRate
+	if decimation < 1 {
+		decimation = 1
+	}
+	samplesPerMs := cfg.SampleRate / 1000
+
+	window := make([]float64, fftSize)
+	for i := range window {
+		window[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(fftSize-1))
+	}
+
+	return &Detector{
+		cfg:             cfg,
+		decimation:      decimation,
+		windowSamples:   samplesPerMs * analysisWindowMs,
+		onsetSamples:    samplesPerMs * cfg.OnsetMs,
+		hangoverSamples: samplesPerMs * cfg.HangoverMs,
+		preRollLimit:    samplesPerMs * cfg.PreRollMs,
+		window:          window,
+		spectrum:        make([]complex128, fftSize),
+	}
+}
+
+// Config returns the effective configuration
+func (d *Detector) Config() Config {
+	return d.cfg
+}
+
+// Speaking reports whether the gate is currently open
+func (d *Detector) Speaking() bool {
+	return d.speaking
+}
+
+// Reset closes the gate and drops buffered audio, keeping the learned noise floor
+func (d *Detector) Reset() {
+	d.speaking = false
+	d.speechRun = 0
+	d.silenceRun = 0
+	d.preRoll = nil
+	d.preRollSamples = 0
+}
+
+// Process feeds one frame and returns the frames to forward, oldest first.
+// It returns nil while the gate is closed. Frames are retained for pre-roll,
+// so callers must not reuse the slice they pass in.
+func (d *Detector) Process(frame []int16) [][]int16 {
+	if len(frame) == 0 {
+		return nil
+	}
+	speech := d.IsSpeech(frame)
+
+	if d.speaking {
+		if speech {
+			d.silenceRun = 0
+		} else {
+			d.silenceRun += len(frame)
+			if d.silenceRun > d.hangoverSamples {
+				d.speaking = false
+				d.speechRun = 0
+				d.pushPreRoll(frame)
+				return nil
+			}
+		}
+		return [][]int16{frame}
+	}
+
+	d.pushPreRoll(frame)
+	if !speech {
+		d.speechRun = 0
+		return nil
+	}
+	d.speechRun += len(frame)
+	if d.speechRun < d.onsetSamples {
+		return nil
+	}
+
+	// Onset confirmed: release the pre-roll, which ends with the current frame
+	d.speaking = true
+	d.silenceRun = 0
+	frames := d.preRoll
+	d.preRoll = nil
+	d.preRollSamples = 0
+	return frames
+}
+
+// IsSpeech classifies a frame and updates the noise floor from non-speech windows.
+// A frame counts as speech if any of its analysis windows does.
+func (d *Detector) IsSpeech(frame []int16) bool {
+	speech := false
+	for start := 0; start < len(frame); start += d.windowSamples {
+		end := start + d.windowSamples
+		if end > len(frame) {
+			end = len(frame)
+		}
+		features := d.Analyze(frame[start:end])
+		if d.classify(features) {
+			speech = true
+		} else {
+			d.updateNoiseFloor(features.EnergyDb)
+		}
+	}
+	return speech
+}
+
+// Analyze extracts the features of a single analysis window
+func (d *Detector) Analyze(samples []int16) Features {
+	// Decimate with a box filter; enough to keep the speech band for feature extraction
+	n := len(samples) / d.decimation
+	if n > fftSize {
+		n = fftSize
+	}
+	if n == 0 {
+		return Features{EnergyDb: minEnergyDb, SpectralFlatness: 1}
+	}
+
+	var sumSquares float64
+	for _, s := range samples {
+		v := float64(s) / 32768
+		sumSquares += v * v
+	}
+	energyDb := minEnergyDb
+	if rms := math.Sqrt(sumSquares / float64(len(samples))); rms > 0 {
+		energyDb = math.Max(20*math.Log10(rms), minEnergyDb)
+	}
+
+	var crossings int
+	var prev float64
+	for i := 0; i < fftSize; i++ {
+		var v float64
+		if i < n {
+			for j := 0; j < d.decimation; j++ {
+				v += float64(samples[i*d.decimation+j])
+			}
+			v /= float64(d.decimation) * 32768
+			if i > 0 && (v >= 0) != (prev >= 0) {
+				crossings++
+			}
+			prev = v
+		}
+		d.spectrum[i] = complex(v*d.window[i], 0)
+	}
+
+	return Features{
+		EnergyDb:         energyDb,
+		ZeroCrossingRate: float64(crossings) / float64(n),
+		SpectralFlatness: d.spectralFlatness(),
+	}
+}
+
+// classify applies the configured thresholds to a window's features
+func (d *Detector) classify(f Features) bool {
+	if f.EnergyDb < d.cfg.EnergyThresholdDb {
+		return false
+	}
+	if d.hasFloor && f.EnergyDb < d.noiseFloorDb+d.cfg.NoiseMarginDb {
+		return false
+	}
+	return f.ZeroCrossingRate <= d.cfg.MaxZeroCrossingRate && f.SpectralFlatness <= d.cfg.MaxSpectralFlatness
+}
+
+// updateNoiseFloor tracks the noise level: it drops quickly and rises slowly
+func (d *Detector) updateNoiseFloor(energyDb float64) {
+	if !d.hasFloor {
+		d.noiseFloorDb = energyDb
+		d.hasFloor = true
+		return
+	}
+	alpha := 0.02
+	if energyDb < d.noiseFloorDb {
+		alpha = 0.3
+	}
+	d.noiseFloorDb += alpha * (energyDb - d.noiseFloorDb)
+}
+
+// pushPreRoll buffers a frame while the gate is closed, keeping the pre-roll plus the onset
+func (d *Detector) pushPreRoll(frame []int16) {
+	d.preRoll = append(d.preRoll, frame)
+	d.preRollSamples += len(frame)
+
+	limit := d.preRollLimit + d.onsetSamples
+	for len(d.preRoll) > 1 && d.preRollSamples-len(d.preRoll[0]) >= limit {
+		d.preRollSamples -= len(d.preRoll[0])
+		d.preRoll[0] = nil
+		d.preRoll = d.preRoll[1:]
+	}
+}
+
+// spectralFlatness returns the ratio of the geometric to the arithmetic mean of the
+// power spectrum in the speech band, computed from d.spectrum
+func (d *Detector) spectralFlatness() float64 {
+	dsp.FFT(d.spectrum)
+
+	binHz := float64(d.cfg.SampleRate/d.decimation) / float64(fftSize)
+	low := int(bandLowHz / binHz)
+	high := int(bandHighHz / binHz)
+	if high > fftSize/2 {
+		high = fftSize / 2
+	}
+
+	var logSum, sum float64
+	count := 0
+	for k := low; k <= high; k++ {
+		re, im := real(d.spectrum[k]), imag(d.spectrum[k])
+		power := re*re + im*im + 1e-12
+		logSum += math.Log(power)
+		sum += power
+		count++
+	}
+	if count == 0 || sum <= 0 {
+		return 1
+	}
+	return math.Exp(logSum/float64(count)) / (sum / float64(count))
+}